@@ -0,0 +1,286 @@
+// Command spooled-contract generates payload contracts from Go structs.
+//
+// Annotate payload types with a //spooled:contract directive naming their
+// queue, and run the command from the package with go generate:
+//
+//	//go:generate go run github.com/spooled-cloud/spooled-sdk-go/cmd/spooled-contract -out ../contracts -ts
+//
+//	//spooled:contract queue=emails
+//	type EmailPayload struct {
+//		To      string   `json:"to"`
+//		CC      []string `json:"cc,omitempty"`
+//	}
+//
+// It writes a JSON Schema per type to <out>/<Type>.schema.json and, with -ts,
+// TypeScript types for Node workers to <out>/payloads.ts.
+//
+// With -check dir, nothing is written. Instead, each type's contract is
+// checked against the schema of the same name in dir, typically the copy a
+// consumer was built against, and the command fails if the consumer could
+// not read payloads the producer now sends.
+//
+// Usage:
+//
+//	spooled-contract [-out dir] [-ts] [-check dir] [package dir]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/contract"
+)
+
+// directive marks a payload type in its doc comment.
+const directive = "//spooled:contract"
+
+// payloadType is an annotated type.
+type payloadType struct {
+	Name  string
+	Queue string
+}
+
+func main() {
+	out := flag.String("out", "contracts", "directory to write contracts to")
+	ts := flag.Bool("ts", false, "also write TypeScript types to payloads.ts")
+	check := flag.String("check", "", "check contracts against the schemas in `dir` instead of writing")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if err := run(dir, *out, *ts, *check); err != nil {
+		fmt.Fprintf(os.Stderr, "spooled-contract: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, out string, ts bool, check string) error {
+	types, err := findTypes(dir)
+	if err != nil {
+		return err
+	}
+	if len(types) == 0 {
+		return fmt.Errorf("no types in %s are annotated with %s", dir, directive)
+	}
+	contracts, err := derive(dir, types)
+	if err != nil {
+		return err
+	}
+
+	if check != "" {
+		return checkContracts(contracts, check)
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	for _, c := range contracts {
+		data, err := c.JSONSchema()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(out, c.Name+".schema.json"), data, 0o644); err != nil {
+			return err
+		}
+	}
+	if ts {
+		return os.WriteFile(filepath.Join(out, "payloads.ts"), contract.TypeScript(contracts...), 0o644)
+	}
+	return nil
+}
+
+// findTypes returns the annotated types declared in dir, sorted by name.
+func findTypes(dir string) ([]payloadType, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var types []payloadType
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				queue, ok, err := queueOf(doc)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", fset.Position(spec.Pos()), spec.Name.Name, err)
+				}
+				if !ok {
+					continue
+				}
+				if file.Name.Name == "main" {
+					return nil, fmt.Errorf("%s: payload types must be in an importable package, not main", fset.Position(spec.Pos()))
+				}
+				if spec.TypeParams != nil {
+					return nil, fmt.Errorf("%s: generic type %s cannot be a payload contract", fset.Position(spec.Pos()), spec.Name.Name)
+				}
+				types = append(types, payloadType{Name: spec.Name.Name, Queue: queue})
+			}
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types, nil
+}
+
+// queueOf parses the directive in doc, if any.
+func queueOf(doc *ast.CommentGroup) (string, bool, error) {
+	if doc == nil {
+		return "", false, nil
+	}
+	for _, c := range doc.List {
+		args, ok := strings.CutPrefix(c.Text, directive)
+		if !ok || (args != "" && args[0] != ' ') {
+			continue
+		}
+		for _, arg := range strings.Fields(args) {
+			if queue, ok := strings.CutPrefix(arg, "queue="); ok && queue != "" {
+				return queue, true, nil
+			}
+		}
+		return "", false, errors.New(directive + " needs queue=<name>")
+	}
+	return "", false, nil
+}
+
+var program = template.Must(template.New("program").Parse(`// ` + contract.GeneratedHeader + `
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/contract"
+	payloads {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	var contracts []*contract.Contract
+	for _, p := range []struct {
+		queue string
+		value any
+	}{
+{{- range .Types}}
+		{ {{- printf "%q" .Queue}}, payloads.{{.Name}}{}},
+{{- end}}
+	} {
+		c, err := contract.New(p.queue, p.value)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		contracts = append(contracts, c)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(contracts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// derive builds and runs a program that imports the package in dir and
+// derives its contracts, so they follow the types exactly as compiled.
+func derive(dir string, types []payloadType) ([]*contract.Contract, error) {
+	list := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	list.Dir = dir
+	list.Stderr = os.Stderr
+	importPath, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+
+	// The program lives in the package's directory to build in its module.
+	tmp, err := os.MkdirTemp(dir, ".spooled-contract-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var src bytes.Buffer
+	if err := program.Execute(&src, map[string]any{
+		"ImportPath": strings.TrimSpace(string(importPath)),
+		"Types":      types,
+	}); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "main.go"), src.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(tmp))
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("deriving contracts: %w", err)
+	}
+	var contracts []*contract.Contract
+	if err := json.Unmarshal(output, &contracts); err != nil {
+		return nil, fmt.Errorf("deriving contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// checkContracts checks each contract against the consumer schema of the
+// same name in dir.
+func checkContracts(contracts []*contract.Contract, dir string) error {
+	failed := false
+	for _, c := range contracts {
+		path := filepath.Join(dir, c.Name+".schema.json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		consumer, err := contract.Load(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if consumer.Queue != "" && consumer.Queue != c.Queue {
+			fmt.Fprintf(os.Stderr, "%s: consumer reads queue %q, producer sends to %q\n", c.Name, consumer.Queue, c.Queue)
+			failed = true
+		}
+		if err := contract.Compatible(c.Schema, consumer.Schema); err != nil {
+			var incompatible *contract.IncompatibleError
+			if !errors.As(err, &incompatible) {
+				return err
+			}
+			for _, v := range incompatible.Violations {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", c.Name, v)
+			}
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("contracts do not match their consumers")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package httpx
+
+// APIVersionHeader carries the pinned API version on requests and the
+// version the server applied on responses.
+const APIVersionHeader = "Spooled-Version"
+
+// APIVersion returns the pinned API version, or "" if requests use the
+// organization's default version.
+func (t *Transport) APIVersion() string {
+	return t.apiVersion
+}
+
+// observeAPIVersion warns once if the server applied a different version
+// than the pinned one, e.g. because it no longer supports it.
+func (t *Transport) observeAPIVersion(served string) {
+	if t.apiVersion == "" || served == "" || served == t.apiVersion {
+		return
+	}
+	if !t.apiVersionWarned.Swap(true) {
+		t.log("server applied a different API version", "requested", t.apiVersion, "served", served)
+		t.emit(APIVersionMismatchEvent{
+			Requested: t.apiVersion,
+			Served:    served,
+		})
+	}
+}
@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,11 +11,17 @@ import (
 	"time"
 )
 
+// RefreshAhead is how long before an access token expires it is refreshed.
+const RefreshAhead = time.Minute
+
 // TokenRefresher handles automatic token refresh.
 type TokenRefresher struct {
 	mu         sync.Mutex
 	refreshing bool
 	done       chan struct{}
+	// lastErr is the result of the last refresh, returned to the goroutines
+	// that waited for it.
+	lastErr error
 
 	baseURL      string
 	apiKey       string
@@ -22,8 +29,10 @@ type TokenRefresher struct {
 	accessToken  string
 	expiresAt    time.Time
 
-	client *http.Client
-	logger Logger
+	client    *http.Client
+	logger    Logger
+	onRefresh func(method string, expiresIn int, err error)
+	onTokens  []func(accessToken, refreshToken string)
 }
 
 // NewTokenRefresher creates a new token refresher.
@@ -33,20 +42,80 @@ func NewTokenRefresher(baseURL, apiKey, refreshToken, accessToken string, logger
 		apiKey:       apiKey,
 		refreshToken: refreshToken,
 		accessToken:  accessToken,
+		expiresAt:    refreshAt(accessToken, 0),
 		client:       &http.Client{Timeout: 30 * time.Second},
 		logger:       logger,
 	}
 }
 
-// SetAccessToken updates the access token.
+// OnRefresh registers a callback invoked after every refresh attempt.
+// method is "refresh_token" or "api_key".
+func (tr *TokenRefresher) OnRefresh(fn func(method string, expiresIn int, err error)) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.onRefresh = fn
+}
+
+// OnTokens registers a callback invoked with the new tokens after every
+// successful refresh, e.g. to persist rotated tokens. Callbacks run on the
+// goroutine that performed the refresh and should return quickly.
+func (tr *TokenRefresher) OnTokens(fn func(accessToken, refreshToken string)) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.onTokens = append(tr.onTokens, fn)
+}
+
+// SetAccessToken updates the access token. If expiresIn is not positive, the
+// expiry is read from the token's exp claim when it is a JWT.
 func (tr *TokenRefresher) SetAccessToken(token string, expiresIn int) {
 	tr.mu.Lock()
 	defer tr.mu.Unlock()
 	tr.accessToken = token
+	tr.expiresAt = refreshAt(token, expiresIn)
+}
+
+// refreshAt returns when token should be refreshed: RefreshAhead before it
+// expires, or the zero time if its expiry is unknown.
+func refreshAt(token string, expiresIn int) time.Time {
 	if expiresIn > 0 {
-		// Set expiry with some buffer (refresh 1 minute before actual expiry)
-		tr.expiresAt = time.Now().Add(time.Duration(expiresIn-60) * time.Second)
+		return time.Now().Add(time.Duration(expiresIn)*time.Second - RefreshAhead)
+	}
+	if exp := jwtExpiry(token); !exp.IsZero() {
+		return exp.Add(-RefreshAhead)
+	}
+	return time.Time{}
+}
+
+// jwtExpiry returns the exp claim of a JWT, or the zero time if token is not
+// a JWT or has no exp claim. The signature is not verified; the expiry is
+// only used to schedule refreshes.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp json.Number `json:"exp"`
 	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil || exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(exp), 0)
+}
+
+// SetAPIKey updates the API key used to log in again.
+func (tr *TokenRefresher) SetAPIKey(key string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.apiKey = key
 }
 
 // SetRefreshToken updates the refresh token.
@@ -76,6 +145,14 @@ func (tr *TokenRefresher) NeedsRefresh() bool {
 	return time.Now().After(tr.expiresAt)
 }
 
+// RefreshAt returns when the access token will next be refreshed, or the zero
+// time if its expiry is unknown.
+func (tr *TokenRefresher) RefreshAt() time.Time {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.expiresAt
+}
+
 // RefreshIfNeeded refreshes the token if it's expired or about to expire.
 // Uses single-flight to prevent multiple concurrent refreshes.
 func (tr *TokenRefresher) RefreshIfNeeded(ctx context.Context) error {
@@ -88,34 +165,47 @@ func (tr *TokenRefresher) RefreshIfNeeded(ctx context.Context) error {
 // Refresh performs a token refresh with single-flight coordination.
 // Only one goroutine will actually perform the refresh; others will wait for it.
 func (tr *TokenRefresher) Refresh(ctx context.Context) error {
-	return tr.refreshInternal(ctx, false)
+	return tr.refreshInternal(ctx, false, "")
 }
 
 // ForceRefresh performs a token refresh regardless of expiry status.
-// Used when receiving a 401 response.
 func (tr *TokenRefresher) ForceRefresh(ctx context.Context) error {
-	return tr.refreshInternal(ctx, true)
+	return tr.refreshInternal(ctx, true, "")
 }
 
-// refreshInternal performs the actual refresh with optional force flag.
-func (tr *TokenRefresher) refreshInternal(ctx context.Context, force bool) error {
+// RefreshRejected refreshes after the API rejected token with a 401. If the
+// access token has already been replaced since token was sent, it returns
+// without refreshing, so a burst of requests failing with the same expired
+// token triggers a single refresh.
+func (tr *TokenRefresher) RefreshRejected(ctx context.Context, token string) error {
+	return tr.refreshInternal(ctx, true, token)
+}
+
+// refreshInternal performs the actual refresh with optional force flag. When
+// rejected is set, the refresh is skipped if the access token has changed.
+func (tr *TokenRefresher) refreshInternal(ctx context.Context, force bool, rejected string) error {
 	tr.mu.Lock()
 
-	// If another goroutine is already refreshing, wait for it
+	// If another goroutine is already refreshing, wait for it and share its result
 	if tr.refreshing {
 		done := tr.done
 		tr.mu.Unlock()
 
-		// Wait for refresh to complete
 		select {
 		case <-done:
-			return nil
+			tr.mu.Lock()
+			defer tr.mu.Unlock()
+			return tr.lastErr
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 
-	// Check if token was already refreshed by another goroutine (unless forced)
+	// Check if token was already refreshed by another goroutine
+	if rejected != "" && tr.accessToken != rejected {
+		tr.mu.Unlock()
+		return nil
+	}
 	if !force && !tr.needsRefreshLocked() && tr.accessToken != "" {
 		tr.mu.Unlock()
 		return nil
@@ -133,6 +223,7 @@ func (tr *TokenRefresher) refreshInternal(ctx context.Context, force bool) error
 	defer func() {
 		tr.mu.Lock()
 		tr.refreshing = false
+		tr.lastErr = err
 		close(tr.done)
 		tr.mu.Unlock()
 	}()
@@ -142,7 +233,10 @@ func (tr *TokenRefresher) refreshInternal(ctx context.Context, force bool) error
 	} else if apiKey != "" {
 		err = tr.refreshWithAPIKey(ctx, apiKey)
 	} else {
-		return fmt.Errorf("no refresh token or API key available")
+		err = fmt.Errorf("no refresh token or API key available")
+	}
+	if err == nil {
+		tr.notifyTokens()
 	}
 
 	return err
@@ -160,9 +254,17 @@ func (tr *TokenRefresher) needsRefreshLocked() bool {
 }
 
 // refreshWithToken refreshes using a refresh token.
-func (tr *TokenRefresher) refreshWithToken(ctx context.Context, refreshToken string) error {
+func (tr *TokenRefresher) refreshWithToken(ctx context.Context, refreshToken string) (err error) {
 	tr.log("refreshing token using refresh_token")
 
+	var expiresIn int
+	fallback := false
+	defer func() {
+		if !fallback {
+			tr.notifyRefresh("refresh_token", expiresIn, err)
+		}
+	}()
+
 	body := fmt.Sprintf(`{"refresh_token":"%s"}`, refreshToken)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
 		tr.baseURL+"/api/v1/auth/refresh",
@@ -182,29 +284,41 @@ func (tr *TokenRefresher) refreshWithToken(ctx context.Context, refreshToken str
 	if resp.StatusCode != http.StatusOK {
 		// If refresh token is invalid, try API key if available
 		if resp.StatusCode == http.StatusUnauthorized && tr.apiKey != "" {
+			fallback = true
 			return tr.refreshWithAPIKey(ctx, tr.apiKey)
 		}
 		return fmt.Errorf("refresh failed with status %d", resp.StatusCode)
 	}
 
 	var result struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return fmt.Errorf("failed to decode refresh response: %w", err)
 	}
 
 	tr.SetAccessToken(result.AccessToken, result.ExpiresIn)
+	// The server may rotate the refresh token on use
+	if result.RefreshToken != "" {
+		tr.SetRefreshToken(result.RefreshToken)
+	}
+	expiresIn = result.ExpiresIn
 	tr.log("token refreshed successfully", "expires_in", result.ExpiresIn)
 
 	return nil
 }
 
 // refreshWithAPIKey performs a fresh login using the API key.
-func (tr *TokenRefresher) refreshWithAPIKey(ctx context.Context, apiKey string) error {
+func (tr *TokenRefresher) refreshWithAPIKey(ctx context.Context, apiKey string) (err error) {
 	tr.log("refreshing token using api_key (re-login)")
 
+	var expiresIn int
+	defer func() {
+		tr.notifyRefresh("api_key", expiresIn, err)
+	}()
+
 	body := fmt.Sprintf(`{"api_key":"%s"}`, apiKey)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
 		tr.baseURL+"/api/v1/auth/login",
@@ -236,11 +350,33 @@ func (tr *TokenRefresher) refreshWithAPIKey(ctx context.Context, apiKey string)
 
 	tr.SetAccessToken(result.AccessToken, result.ExpiresIn)
 	tr.SetRefreshToken(result.RefreshToken)
+	expiresIn = result.ExpiresIn
 	tr.log("re-login successful", "expires_in", result.ExpiresIn)
 
 	return nil
 }
 
+// notifyRefresh invokes the refresh callback, if any.
+func (tr *TokenRefresher) notifyRefresh(method string, expiresIn int, err error) {
+	tr.mu.Lock()
+	fn := tr.onRefresh
+	tr.mu.Unlock()
+	if fn != nil {
+		fn(method, expiresIn, err)
+	}
+}
+
+// notifyTokens invokes the token callbacks with the current tokens.
+func (tr *TokenRefresher) notifyTokens() {
+	tr.mu.Lock()
+	fns := tr.onTokens
+	access, refresh := tr.accessToken, tr.refreshToken
+	tr.mu.Unlock()
+	for _, fn := range fns {
+		fn(access, refresh)
+	}
+}
+
 // log logs a debug message.
 func (tr *TokenRefresher) log(msg string, keysAndValues ...any) {
 	if tr.logger != nil {
@@ -2,7 +2,9 @@ package httpx
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -125,6 +127,59 @@ func TestTokenRefresher_SingleFlight(t *testing.T) {
 	}
 }
 
+func TestTokenRefresher_SingleFlightSharesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tr := NewTokenRefresher(server.URL, "", "refresh-token", "old-access-token", nil)
+	var wg sync.WaitGroup
+	var failures int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tr.ForceRefresh(context.Background()); err != nil {
+				atomic.AddInt32(&failures, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Goroutines that waited for the failed refresh must see its error
+	if failures != 5 {
+		t.Errorf("Expected 5 failed refreshes, got %d", failures)
+	}
+}
+
+func TestTokenRefresher_JWTExpiry(t *testing.T) {
+	jwt := func(exp time.Time) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","exp":%d}`, exp.Unix())))
+		return "eyJhbGciOiJIUzI1NiJ9." + payload + ".signature"
+	}
+
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	if got := jwtExpiry(jwt(exp)); !got.Equal(exp) {
+		t.Errorf("jwtExpiry = %v, want %v", got, exp)
+	}
+	if got := jwtExpiry("opaque-token"); !got.IsZero() {
+		t.Errorf("jwtExpiry of opaque token = %v, want zero", got)
+	}
+
+	tr := NewTokenRefresher("http://example.com", "", "refresh-token", jwt(exp), nil)
+	if tr.NeedsRefresh() {
+		t.Error("Shouldn't need refresh an hour before expiry")
+	}
+
+	// Tokens are refreshed RefreshAhead before they expire
+	tr.SetAccessToken(jwt(time.Now().Add(RefreshAhead/2)), 0)
+	if !tr.NeedsRefresh() {
+		t.Error("Should need refresh within RefreshAhead of expiry")
+	}
+}
+
 func TestTokenRefresher_NeedsRefresh(t *testing.T) {
 	tr := NewTokenRefresher("http://example.com", "", "", "access-token", nil)
 
@@ -255,3 +310,67 @@ func TestTransport_AutoRefresh401(t *testing.T) {
 		t.Errorf("Expected 1 refresh, got %d", refreshCount)
 	}
 }
+
+func TestTransport_Concurrent401SingleRefresh(t *testing.T) {
+	refreshCount := int32(0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			atomic.AddInt32(&refreshCount, 1)
+			time.Sleep(50 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "new-access-token",
+				"refresh_token": "rotated-refresh-token",
+				"expires_in":    3600,
+			})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer new-access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "token expired"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL:          server.URL,
+		AccessToken:      "expired-token",
+		RefreshToken:     "valid-refresh-token",
+		AutoRefreshToken: true,
+		Retry: RetryConfig{
+			MaxRetries: 0,
+			BaseDelay:  1 * time.Millisecond,
+		},
+	})
+
+	var mu sync.Mutex
+	var rotated [][2]string
+	transport.OnTokenRefreshed(func(accessToken, refreshToken string) {
+		mu.Lock()
+		defer mu.Unlock()
+		rotated = append(rotated, [2]string{accessToken, refreshToken})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/api/v1/test"}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count := atomic.LoadInt32(&refreshCount); count != 1 {
+		t.Errorf("Expected 1 refresh for concurrent 401s, got %d", count)
+	}
+	if len(rotated) != 1 || rotated[0] != [2]string{"new-access-token", "rotated-refresh-token"} {
+		t.Errorf("Expected one OnTokenRefreshed call with the rotated tokens, got %v", rotated)
+	}
+}
@@ -41,6 +41,7 @@ type CircuitBreaker struct {
 	successThreshold int
 	timeout          time.Duration
 	lastStateChange  time.Time
+	onStateChange    func(from, to CircuitState)
 }
 
 // NewCircuitBreaker creates a new circuit breaker.
@@ -61,36 +62,38 @@ func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
 		successThreshold: cfg.SuccessThreshold,
 		timeout:          cfg.Timeout,
 		lastStateChange:  time.Now(),
+		onStateChange:    cfg.OnStateChange,
 	}
 }
 
 // Allow checks if a request is allowed through the circuit breaker.
 func (cb *CircuitBreaker) Allow() bool {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.state
+	allowed := true
 
 	switch cb.state {
-	case CircuitClosed:
-		return true
 	case CircuitOpen:
 		// Check if timeout has passed
 		if time.Since(cb.lastStateChange) >= cb.timeout {
 			cb.transitionTo(CircuitHalfOpen)
-			return true
+		} else {
+			allowed = false
 		}
-		return false
 	case CircuitHalfOpen:
 		// Allow one test request
-		return true
-	default:
-		return true
 	}
+
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
+	return allowed
 }
 
 // RecordSuccess records a successful request.
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.state
 
 	switch cb.state {
 	case CircuitClosed:
@@ -102,12 +105,16 @@ func (cb *CircuitBreaker) RecordSuccess() {
 			cb.transitionTo(CircuitClosed)
 		}
 	}
+
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
 }
 
 // RecordFailure records a failed request.
 func (cb *CircuitBreaker) RecordFailure() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.state
 
 	switch cb.state {
 	case CircuitClosed:
@@ -119,6 +126,10 @@ func (cb *CircuitBreaker) RecordFailure() {
 		// Any failure in half-open state reopens the circuit
 		cb.transitionTo(CircuitOpen)
 	}
+
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
 }
 
 // State returns the current state of the circuit breaker.
@@ -136,11 +147,20 @@ func (cb *CircuitBreaker) transitionTo(state CircuitState) {
 	cb.lastStateChange = time.Now()
 }
 
+// notify invokes the state change callback (must be called without lock held).
+func (cb *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.onStateChange != nil {
+		cb.onStateChange(from, to)
+	}
+}
+
 // Reset resets the circuit breaker to its initial state.
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.state
 	cb.transitionTo(CircuitClosed)
+	cb.mu.Unlock()
+	cb.notify(from, CircuitClosed)
 }
 
 // Metrics returns current circuit breaker metrics.
@@ -221,3 +221,30 @@ func TestCircuitState_String(t *testing.T) {
 		}
 	}
 }
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	var transitions []string
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          30 * time.Second,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	cb.RecordFailure()
+	cb.RecordFailure() // already open, no transition
+	cb.Reset()
+	cb.Reset() // already closed, no transition
+
+	want := []string{"closed->open", "open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transitions[%d] = %q, want %q", i, transitions[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package httpx
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultClockSkewThreshold is the skew above which a warning is logged and emitted.
+const DefaultClockSkewThreshold = 5 * time.Second
+
+// observeClock updates the estimated server clock offset from a response Date
+// header. The header has one-second resolution, so offsets under a second are
+// treated as no skew.
+func (t *Transport) observeClock(date string, sent, received time.Time) {
+	if date == "" {
+		return
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// Compare the middle of the server's second to the middle of the round trip
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := serverTime.Add(500 * time.Millisecond).Sub(local)
+	if skew > -time.Second && skew < time.Second {
+		skew = 0
+	}
+	t.clockSkew.Store(int64(skew))
+
+	over := skew >= t.skewThreshold || skew <= -t.skewThreshold
+	if over && !t.skewWarned.Swap(true) {
+		t.log("client clock skew detected", "skew", skew, "threshold", t.skewThreshold)
+		t.emit(ClockSkewEvent{
+			Skew:      skew,
+			Threshold: t.skewThreshold,
+		})
+	} else if !over {
+		t.skewWarned.Store(false)
+	}
+}
+
+// ClockSkew returns the estimated offset of the server clock from the local
+// clock (server minus local), based on the most recent response.
+func (t *Transport) ClockSkew() time.Duration {
+	return time.Duration(t.clockSkew.Load())
+}
+
+// Now returns the current time adjusted to the server's clock.
+func (t *Transport) Now() time.Time {
+	return time.Now().Add(t.ClockSkew())
+}
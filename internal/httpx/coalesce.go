@@ -0,0 +1,85 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent identical requests.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+// do runs fn once per key among concurrent callers. The shared call is not
+// cancelled by any single caller; each caller stops waiting when its own ctx
+// is done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (*Response, error)) (*Response, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go func() {
+			call.resp, call.err = fn(context.WithoutCancel(ctx))
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.resp, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// coalesceKey identifies a GET request for deduplication, or returns "" if
+// the request must not be coalesced.
+func coalesceKey(req *Request) string {
+	if req.Method != http.MethodGet || req.Stream {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(req.Method)
+	b.WriteByte(' ')
+	b.WriteString(req.Path)
+	if req.UseAdminKey {
+		b.WriteString(" admin")
+	}
+	writeSorted(&b, "?", req.Query)
+	writeSorted(&b, "#", req.Headers)
+	return b.String()
+}
+
+func writeSorted(b *strings.Builder, sep string, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(sep)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(values[k])
+		sep = "&"
+	}
+}
@@ -0,0 +1,38 @@
+package httpx
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
+// Codec encodes request bodies and decodes response bodies for one media type.
+type Codec interface {
+	// ContentType is the media type sent in Content-Type and Accept (e.g. "application/msgpack").
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONContentType is the media type of the default codec.
+const JSONContentType = "application/json"
+
+// JSONCodec is the default codec.
+type JSONCodec struct{}
+
+// ContentType returns "application/json".
+func (JSONCodec) ContentType() string { return JSONContentType }
+
+// Marshal encodes v as JSON.
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON data into v.
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// mediaType returns the media type of a Content-Type header without parameters.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return strings.TrimSpace(strings.ToLower(contentType))
+}
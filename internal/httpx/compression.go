@@ -0,0 +1,63 @@
+package httpx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GzipEncoding is the Content-Encoding of compressed request bodies.
+const GzipEncoding = "gzip"
+
+// NewPayloadTooLargeError returns the error for a payload of size bytes that
+// exceeds limit.
+func NewPayloadTooLargeError(size, limit int) *PayloadTooLargeError {
+	return &PayloadTooLargeError{
+		APIError: &APIError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Code:       "payload_too_large",
+			Message:    fmt.Sprintf("payload of %d bytes exceeds the %d byte limit", size, limit),
+		},
+		Size:  size,
+		Limit: limit,
+	}
+}
+
+// CheckPayloadSize returns a *PayloadTooLargeError if payload is larger than
+// limit bytes when encoded as JSON. A limit of 0 or less disables the check.
+func CheckPayloadSize(payload any, limit int) error {
+	if limit <= 0 || payload == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	if len(data) > limit {
+		return NewPayloadTooLargeError(len(data), limit)
+	}
+	return nil
+}
+
+// compressBody gzips a request body of Compress requests at least as large as
+// the compression threshold. It reports false, leaving the body as is, when
+// compression is disabled, the server has rejected it, or it would not help.
+func (t *Transport) compressBody(req *Request, body []byte) ([]byte, bool) {
+	if !req.Compress || t.compressionThreshold <= 0 || len(body) < t.compressionThreshold || t.compressionRejected.Load() {
+		return body, false
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return body, false
+	}
+	if err := zw.Close(); err != nil {
+		return body, false
+	}
+	if buf.Len() >= len(body) {
+		return body, false
+	}
+	return buf.Bytes(), true
+}
@@ -0,0 +1,165 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationWarning describes a deprecated or removed endpoint, built from
+// the Deprecation (RFC 9745), Sunset (RFC 8594), and Link response headers.
+type DeprecationWarning struct {
+	Method string
+	Path   string
+	// DeprecatedAt is when the endpoint was deprecated, if the server said.
+	DeprecatedAt *time.Time
+	// Sunset is when the endpoint will be, or was, removed.
+	Sunset *time.Time
+	// Link points to migration documentation or the successor endpoint.
+	Link string
+	// Removed is true when the endpoint answered 410 Gone.
+	Removed bool
+}
+
+// String returns a human-readable warning with migration hints.
+func (w DeprecationWarning) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s ", w.Method, w.Path)
+	if w.Removed {
+		b.WriteString("has been removed")
+	} else {
+		b.WriteString("is deprecated")
+	}
+	if w.Sunset != nil && !w.Removed {
+		fmt.Fprintf(&b, " and will be removed after %s", w.Sunset.Format(time.RFC3339))
+	}
+	if w.Link != "" {
+		fmt.Fprintf(&b, "; see %s", w.Link)
+	}
+	return b.String()
+}
+
+// DeprecationError is returned in strict mode when a response carries
+// deprecation headers.
+type DeprecationError struct {
+	Warning DeprecationWarning
+}
+
+func (e *DeprecationError) Error() string {
+	return "deprecated endpoint: " + e.Warning.String()
+}
+
+// parseDeprecation returns the deprecation warning carried by headers, or nil.
+func parseDeprecation(method, path string, statusCode int, headers http.Header) *DeprecationWarning {
+	deprecation := headers.Get("Deprecation")
+	sunset := headers.Get("Sunset")
+	if deprecation == "" && sunset == "" && statusCode != http.StatusGone {
+		return nil
+	}
+
+	w := &DeprecationWarning{
+		Method:  method,
+		Path:    path,
+		Link:    deprecationLink(headers.Values("Link")),
+		Removed: statusCode == http.StatusGone,
+	}
+	if at, ok := parseDeprecationDate(deprecation); ok {
+		w.DeprecatedAt = &at
+	}
+	if at, err := http.ParseTime(sunset); err == nil {
+		w.Sunset = &at
+	}
+	return w
+}
+
+// parseDeprecationDate parses a Deprecation header value. RFC 9745 uses a
+// structured-field date ("@1688169599"); earlier drafts used an HTTP date or
+// "true".
+func parseDeprecationDate(v string) (time.Time, bool) {
+	if unix, found := strings.CutPrefix(v, "@"); found {
+		secs, err := strconv.ParseInt(unix, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(secs, 0).UTC(), true
+	}
+	at, err := http.ParseTime(v)
+	return at, err == nil
+}
+
+// deprecationLink picks the most useful target from Link headers: a successor
+// version, then deprecation or sunset documentation.
+func deprecationLink(values []string) string {
+	links := map[string]string{}
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok {
+				continue
+			}
+			target = strings.Trim(strings.TrimSpace(target), "<>")
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(key, "rel") {
+					for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+						links[strings.ToLower(rel)] = target
+					}
+				}
+			}
+		}
+	}
+	for _, rel := range []string{"successor-version", "deprecation", "sunset"} {
+		if target := links[rel]; target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
+// observeDeprecation records a deprecation warning for the request, logging
+// and emitting it the first time it is seen. In strict mode it returns a
+// *DeprecationError.
+func (t *Transport) observeDeprecation(req *Request, statusCode int, headers http.Header) error {
+	w := parseDeprecation(req.Method, req.Path, statusCode, headers)
+	if w == nil {
+		return nil
+	}
+
+	key := w.Method + " " + w.Path
+	t.deprecationsMu.Lock()
+	_, seen := t.deprecations[key]
+	t.deprecations[key] = *w
+	t.deprecationsMu.Unlock()
+
+	if !seen {
+		t.log("deprecated endpoint", "warning", w.String())
+		t.emit(*w)
+	}
+
+	if t.strictDeprecations && !w.Removed {
+		return &DeprecationError{Warning: *w}
+	}
+	return nil
+}
+
+// DeprecationWarnings returns the deprecated endpoints seen so far, one per
+// method and path, with the most recent headers.
+func (t *Transport) DeprecationWarnings() []DeprecationWarning {
+	t.deprecationsMu.Lock()
+	defer t.deprecationsMu.Unlock()
+
+	warnings := make([]DeprecationWarning, 0, len(t.deprecations))
+	for _, w := range t.deprecations {
+		warnings = append(warnings, w)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path < warnings[j].Path
+		}
+		return warnings[i].Method < warnings[j].Method
+	})
+	return warnings
+}
@@ -94,12 +94,84 @@ func (e *RateLimitError) GetRetryAfter() int {
 	return int(e.RetryAfter.Seconds())
 }
 
-// PayloadTooLargeError represents a 413 error.
-type PayloadTooLargeError struct{ *APIError }
+// PayloadTooLargeError represents a 413 error, or a payload rejected by
+// CheckPayloadSize before it was sent.
+type PayloadTooLargeError struct {
+	*APIError
+	// Size is the encoded payload size in bytes, if known.
+	Size int
+	// Limit is the size limit in bytes, if known.
+	Limit int
+}
 
 // Unwrap returns the underlying API error.
 func (e *PayloadTooLargeError) Unwrap() error { return e.APIError }
 
+// GoneError represents a 410 error: the endpoint has been removed.
+type GoneError struct {
+	*APIError
+	// Sunset is when the endpoint was removed, if the server said.
+	Sunset *time.Time
+	// Link points to migration documentation or the successor endpoint.
+	Link string
+}
+
+// Unwrap returns the underlying API error.
+func (e *GoneError) Unwrap() error { return e.APIError }
+
+// Error includes the migration link when the server provided one.
+func (e *GoneError) Error() string {
+	if e.Link != "" {
+		return fmt.Sprintf("%s (see %s)", e.APIError.Error(), e.Link)
+	}
+	return e.APIError.Error()
+}
+
+// PlanLimitError is returned when the organization's plan does not include
+// an operation: a 402, or a 403 the server attributes to the plan rather than
+// to the caller's permissions.
+type PlanLimitError struct {
+	*APIError
+	// Feature is the plan-gated feature or limit, such as "workflows".
+	Feature string
+	// CurrentTier is the organization's plan tier.
+	CurrentTier string
+	// RequiredTier is the lowest plan tier that allows the operation, if the
+	// server said.
+	RequiredTier string
+}
+
+// Unwrap returns the underlying API error.
+func (e *PlanLimitError) Unwrap() error { return e.APIError }
+
+// Error includes the required tier when the server provided one.
+func (e *PlanLimitError) Error() string {
+	if e.RequiredTier != "" {
+		return fmt.Sprintf("%s (requires %s plan)", e.APIError.Error(), e.RequiredTier)
+	}
+	return e.APIError.Error()
+}
+
+// planLimitCodes are the error codes the API uses for plan-gated 403s.
+var planLimitCodes = map[string]bool{
+	"plan_limit_exceeded":   true,
+	"plan_upgrade_required": true,
+	"feature_not_available": true,
+}
+
+// ParsePlanLimitError returns baseErr as a PlanLimitError if it is a 402 or
+// carries a plan limit code or required tier.
+func ParsePlanLimitError(baseErr *APIError) (*PlanLimitError, bool) {
+	requiredTier, _ := baseErr.Details["required_tier"].(string)
+	if baseErr.StatusCode != http.StatusPaymentRequired && !planLimitCodes[baseErr.Code] && requiredTier == "" {
+		return nil, false
+	}
+	err := &PlanLimitError{APIError: baseErr, RequiredTier: requiredTier}
+	err.Feature, _ = baseErr.Details["feature"].(string)
+	err.CurrentTier, _ = baseErr.Details["current_tier"].(string)
+	return err, true
+}
+
 // ServerError represents a 5xx error.
 type ServerError struct{ *APIError }
 
@@ -169,7 +241,13 @@ func ParseErrorFromResponse(statusCode int, body []byte, headers http.Header) er
 	case http.StatusUnauthorized:
 		return &AuthenticationError{APIError: baseErr}
 	case http.StatusForbidden:
+		if planErr, ok := ParsePlanLimitError(baseErr); ok {
+			return planErr
+		}
 		return &AuthorizationError{APIError: baseErr}
+	case http.StatusPaymentRequired:
+		planErr, _ := ParsePlanLimitError(baseErr)
+		return planErr
 	case http.StatusNotFound:
 		return &NotFoundError{APIError: baseErr}
 	case http.StatusConflict:
@@ -180,6 +258,13 @@ func ParseErrorFromResponse(statusCode int, body []byte, headers http.Header) er
 		return parseRateLimitError(baseErr, headers)
 	case http.StatusRequestEntityTooLarge:
 		return &PayloadTooLargeError{APIError: baseErr}
+	case http.StatusGone:
+		goneErr := &GoneError{APIError: baseErr}
+		if w := parseDeprecation("", "", statusCode, headers); w != nil {
+			goneErr.Sunset = w.Sunset
+			goneErr.Link = w.Link
+		}
+		return goneErr
 	default:
 		if statusCode >= 500 {
 			return &ServerError{APIError: baseErr}
@@ -202,14 +287,30 @@ func parseRateLimitError(baseErr *APIError, headers http.Header) *RateLimitError
 	// Try both canonical and non-canonical header names
 	if limit := headers.Get("X-Ratelimit-Limit"); limit != "" {
 		err.Limit, _ = strconv.Atoi(limit)
+	} else if limit := headers.Get("RateLimit-Limit"); limit != "" {
+		err.Limit, _ = strconv.Atoi(limit)
 	}
 	if remaining := headers.Get("X-Ratelimit-Remaining"); remaining != "" {
 		err.Remaining, _ = strconv.Atoi(remaining)
+	} else if remaining := headers.Get("RateLimit-Remaining"); remaining != "" {
+		err.Remaining, _ = strconv.Atoi(remaining)
 	}
 	if reset := headers.Get("X-Ratelimit-Reset"); reset != "" {
 		if ts, parseErr := strconv.ParseInt(reset, 10, 64); parseErr == nil {
 			err.Reset = time.Unix(ts, 0)
 		}
+	} else if reset := headers.Get("RateLimit-Reset"); reset != "" {
+		// The IETF header counts seconds until the reset
+		if secs, parseErr := strconv.Atoi(reset); parseErr == nil {
+			err.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+		}
+	}
+
+	// Without Retry-After, wait for the window to reset
+	if err.RetryAfter == 0 && !err.Reset.IsZero() {
+		if until := time.Until(err.Reset); until > 0 {
+			err.RetryAfter = until
+		}
 	}
 
 	return err
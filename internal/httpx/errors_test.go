@@ -184,6 +184,33 @@ func TestParseErrorFromResponse_RateLimit(t *testing.T) {
 	}
 }
 
+func TestParseErrorFromResponse_PlanLimit(t *testing.T) {
+	err := ParseErrorFromResponse(403, []byte(`{"code":"plan_upgrade_required","message":"Workflows are not available on the free plan","details":{"feature":"workflows","current_tier":"free","required_tier":"starter"}}`), http.Header{})
+
+	var planErr *PlanLimitError
+	if !errors.As(err, &planErr) {
+		t.Fatalf("expected *PlanLimitError, got %T: %v", err, err)
+	}
+	if planErr.Feature != "workflows" || planErr.CurrentTier != "free" || planErr.RequiredTier != "starter" {
+		t.Errorf("PlanLimitError = %+v", planErr)
+	}
+	if want := "[403] plan_upgrade_required: Workflows are not available on the free plan (requires starter plan)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	// A 402 is always plan-related, even without details.
+	err = ParseErrorFromResponse(402, []byte(`{"code":"payment_required","message":"Upgrade required"}`), http.Header{})
+	if !errors.As(err, &planErr) || planErr.RequiredTier != "" {
+		t.Errorf("402: got %T: %v, want *PlanLimitError", err, err)
+	}
+
+	// Permission 403s stay authorization errors.
+	err = ParseErrorFromResponse(403, []byte(`{"code":"forbidden","message":"Access denied"}`), http.Header{})
+	if errors.As(err, &planErr) {
+		t.Errorf("forbidden: got *PlanLimitError, want *AuthorizationError")
+	}
+}
+
 func TestIsRetryable(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,59 @@
+package httpx
+
+import "time"
+
+// EventEmitter receives the transport's events: one of RetryEvent,
+// CircuitStateChangeEvent, TokenRefreshEvent, EndpointFailoverEvent,
+// ClockSkewEvent, DeprecationWarning, or APIVersionMismatchEvent. The spooled
+// package adapts them to its public event bus.
+type EventEmitter func(event any)
+
+// RetryEvent is emitted before a request is retried.
+type RetryEvent struct {
+	Method  string
+	Path    string
+	Attempt int
+	Delay   time.Duration
+	Error   error
+}
+
+// CircuitStateChangeEvent is emitted when the circuit breaker changes state.
+type CircuitStateChangeEvent struct {
+	From CircuitState
+	To   CircuitState
+}
+
+// TokenRefreshEvent is emitted after a token refresh attempt.
+type TokenRefreshEvent struct {
+	// Method is "refresh_token" or "api_key".
+	Method    string
+	ExpiresIn int
+	Error     error
+}
+
+// EndpointFailoverEvent is emitted when requests move to a different base URL.
+type EndpointFailoverEvent struct {
+	From string
+	To   string
+}
+
+// ClockSkewEvent is emitted when the local clock drifts from the server clock
+// by more than the configured threshold.
+type ClockSkewEvent struct {
+	Skew      time.Duration
+	Threshold time.Duration
+}
+
+// APIVersionMismatchEvent is emitted the first time the server applies a
+// different API version than the pinned one.
+type APIVersionMismatchEvent struct {
+	Requested string
+	Served    string
+}
+
+// emit sends event to the configured emitter, if any.
+func (t *Transport) emit(event any) {
+	if t.events != nil {
+		t.events(event)
+	}
+}
@@ -0,0 +1,184 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailoverConfig configures failover between multiple base URLs.
+type FailoverConfig struct {
+	// Cooldown is how long a failed endpoint is skipped before it is tried
+	// again (default: 30s).
+	Cooldown time.Duration
+	// LatencyBased prefers the healthy endpoint with the lowest observed
+	// latency instead of the first healthy one in list order.
+	LatencyBased bool
+	// ProbeInterval, if set, probes every endpoint's /health/live endpoint in
+	// the background to detect recovery and measure latency.
+	ProbeInterval time.Duration
+}
+
+// DefaultFailoverCooldown is the default time a failed endpoint is skipped.
+const DefaultFailoverCooldown = 30 * time.Second
+
+// endpointPool tracks the health and latency of a list of base URLs.
+type endpointPool struct {
+	urls []string
+	cfg  FailoverConfig
+
+	mu        sync.Mutex
+	downUntil []time.Time
+	latency   []time.Duration
+	current   int
+	onSwitch  func(from, to string)
+	stopProbe context.CancelFunc
+}
+
+func newEndpointPool(urls []string, cfg FailoverConfig) *endpointPool {
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = DefaultFailoverCooldown
+	}
+	for i, u := range urls {
+		urls[i] = strings.TrimSuffix(u, "/")
+	}
+	return &endpointPool{
+		urls:      urls,
+		cfg:       cfg,
+		downUntil: make([]time.Time, len(urls)),
+		latency:   make([]time.Duration, len(urls)),
+	}
+}
+
+// pick returns the index and URL of the endpoint to use for the next request.
+// If every endpoint is down, the one that recovers soonest is returned.
+func (p *endpointPool) pick() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	best := -1
+	for i := range p.urls {
+		if now.Before(p.downUntil[i]) {
+			continue
+		}
+		if best == -1 {
+			best = i
+			if !p.cfg.LatencyBased {
+				break
+			}
+			continue
+		}
+		// Unmeasured endpoints rank after measured ones
+		if p.latency[i] > 0 && (p.latency[best] == 0 || p.latency[i] < p.latency[best]) {
+			best = i
+		}
+	}
+	if best == -1 {
+		best = 0
+		for i := range p.urls {
+			if p.downUntil[i].Before(p.downUntil[best]) {
+				best = i
+			}
+		}
+	}
+
+	if best != p.current && p.onSwitch != nil {
+		from, to := p.urls[p.current], p.urls[best]
+		go p.onSwitch(from, to)
+	}
+	p.current = best
+	return best, p.urls[best]
+}
+
+// fail marks an endpoint as down for the cooldown period.
+func (p *endpointPool) fail(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[i] = time.Now().Add(p.cfg.Cooldown)
+}
+
+// succeed marks an endpoint as healthy and records its latency.
+func (p *endpointPool) succeed(i int, rtt time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[i] = time.Time{}
+	if p.latency[i] == 0 {
+		p.latency[i] = rtt
+	} else {
+		// Exponentially weighted moving average
+		p.latency[i] = (p.latency[i]*4 + rtt) / 5
+	}
+}
+
+// startProbing probes all endpoints every ProbeInterval until stop is called.
+func (p *endpointPool) startProbing(client *http.Client) {
+	if p.cfg.ProbeInterval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.stopProbe = cancel
+
+	go func() {
+		ticker := time.NewTicker(p.cfg.ProbeInterval)
+		defer ticker.Stop()
+		for {
+			p.probeAll(ctx, client)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *endpointPool) probeAll(ctx context.Context, client *http.Client) {
+	for i, u := range p.urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u+"/health/live", nil)
+		if err != nil {
+			continue
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			p.fail(i)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			p.fail(i)
+		} else {
+			p.succeed(i, time.Since(start))
+		}
+	}
+}
+
+func (p *endpointPool) stop() {
+	if p.stopProbe != nil {
+		p.stopProbe()
+	}
+}
+
+// isEndpointFailure reports whether err indicates the endpoint itself is
+// unavailable, as opposed to a problem with the request.
+func isEndpointFailure(err error) bool {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package httpx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// LegacyFieldNames reports whether Decode accepts camelCase field names, for
+// servers that predate snake_case responses.
+func (r *Response) LegacyFieldNames() bool {
+	return r.legacyFieldNames && r.codec == nil
+}
+
+// NormalizeFieldNames renames camelCase keys in decoded JSON data (as produced
+// by decoding into any) to the snake_case names of the fields of dst's type,
+// so the data then decodes into dst. Keys that already match a field, and the
+// contents of untyped fields such as payloads, are left alone. It returns
+// data, which is modified in place.
+func NormalizeFieldNames(data any, dst any) any {
+	normalizeValue(data, reflect.TypeOf(dst))
+	return data
+}
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+func normalizeValue(data any, t reflect.Type) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		if t.Implements(unmarshalerType) {
+			return
+		}
+		t = t.Elem()
+	}
+	if t == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		// The type decodes itself, and knows which names it accepts
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := data.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := structFieldNames(t)
+		renames := map[string]string{}
+		for key := range obj {
+			if _, known := fields.byName[key]; known {
+				continue
+			}
+			if name, ok := fields.byFolded[foldFieldName(key)]; ok {
+				if _, taken := obj[name]; !taken {
+					renames[key] = name
+				}
+			}
+		}
+		for from, to := range renames {
+			obj[to] = obj[from]
+			delete(obj, from)
+		}
+		for key, value := range obj {
+			if field, ok := fields.byName[key]; ok {
+				normalizeValue(value, field)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := data.([]any); ok {
+			for _, item := range items {
+				normalizeValue(item, t.Elem())
+			}
+		}
+	case reflect.Map:
+		if obj, ok := data.(map[string]any); ok {
+			for _, value := range obj {
+				normalizeValue(value, t.Elem())
+			}
+		}
+	}
+}
+
+// fieldNames are the JSON names of a struct's fields.
+type fieldNames struct {
+	// byName maps each name to its field's type.
+	byName map[string]reflect.Type
+	// byFolded maps names without underscores or case to the name.
+	byFolded map[string]string
+}
+
+var fieldNamesCache sync.Map // reflect.Type -> *fieldNames
+
+func structFieldNames(t reflect.Type) *fieldNames {
+	if cached, ok := fieldNamesCache.Load(t); ok {
+		return cached.(*fieldNames)
+	}
+	names := &fieldNames{byName: map[string]reflect.Type{}, byFolded: map[string]string{}}
+	collectFieldNames(t, names)
+	cached, _ := fieldNamesCache.LoadOrStore(t, names)
+	return cached.(*fieldNames)
+}
+
+func collectFieldNames(t reflect.Type, names *fieldNames) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		names.byName[name] = f.Type
+		names.byFolded[foldFieldName(name)] = name
+	}
+
+	// Embedded struct fields are promoted into the parent object, unless the
+	// parent has a field of the same name
+	for _, et := range embedded {
+		promoted := &fieldNames{byName: map[string]reflect.Type{}, byFolded: map[string]string{}}
+		collectFieldNames(et, promoted)
+		for name, ft := range promoted.byName {
+			if _, shadowed := names.byName[name]; !shadowed {
+				names.byName[name] = ft
+				names.byFolded[foldFieldName(name)] = name
+			}
+		}
+	}
+}
+
+// foldFieldName maps "queue_name", "queueName", and "QueueName" alike to
+// "queuename".
+func foldFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
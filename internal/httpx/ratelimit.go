@@ -0,0 +1,123 @@
+package httpx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a Limiter allowing rate requests per second, with bursts of
+// up to burst, within one process.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full token bucket. A rate of 0 or less disables it.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait implements Limiter. Callers reserve tokens in arrival order, so a
+// burst of requests is spread out rather than retried in lockstep.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Give the reservation back to later callers
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ChainLimiters returns a Limiter that waits on each non-nil limiter in turn,
+// or nil if there are none.
+func ChainLimiters(limiters ...Limiter) Limiter {
+	var chain limiterChain
+	for _, l := range limiters {
+		if l != nil {
+			chain = append(chain, l)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	}
+	return chain
+}
+
+type limiterChain []Limiter
+
+func (c limiterChain) Wait(ctx context.Context) error {
+	for _, l := range c {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitRateLimited blocks until a Retry-After received on an earlier 429 has
+// passed, so concurrent requests back off together instead of each being
+// rejected.
+func (t *Transport) waitRateLimited(ctx context.Context) error {
+	until := t.rateLimitedUntil.Load()
+	if until == 0 {
+		return nil
+	}
+	wait := time.Until(time.Unix(0, until))
+	if wait <= 0 {
+		return nil
+	}
+	t.log("rate limited, waiting", "delay", wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// noteRateLimit records how long the server asked clients to back off.
+func (t *Transport) noteRateLimit(err *RateLimitError) {
+	if err.RetryAfter <= 0 {
+		return
+	}
+	until := time.Now().Add(err.RetryAfter).UnixNano()
+	for {
+		current := t.rateLimitedUntil.Load()
+		if current >= until || t.rateLimitedUntil.CompareAndSwap(current, until) {
+			return
+		}
+	}
+}
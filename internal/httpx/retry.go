@@ -3,6 +3,7 @@ package httpx
 import (
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -13,7 +14,9 @@ type RetryPolicy struct {
 	MaxDelay   time.Duration
 	Factor     float64
 	Jitter     bool
-	rng        *rand.Rand
+	// rngMu guards rng, which is shared by concurrent requests
+	rngMu sync.Mutex
+	rng   *rand.Rand
 }
 
 // NewRetryPolicy creates a new retry policy.
@@ -53,7 +56,9 @@ func (p *RetryPolicy) Delay(attempt int) time.Duration {
 
 	// Add jitter if enabled (±25% of delay)
 	if p.Jitter {
+		p.rngMu.Lock()
 		jitterFactor := 0.5 + p.rng.Float64() // 0.5 to 1.5
+		p.rngMu.Unlock()
 		delay = delay * jitterFactor
 	}
 
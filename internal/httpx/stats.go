@@ -0,0 +1,107 @@
+package httpx
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestStats counts a resource's requests since the transport was created.
+type RequestStats struct {
+	Requests int64 `json:"requests"`
+	// Retries counts retried attempts, not requests that needed a retry.
+	Retries     int64     `json:"retries"`
+	Failures    int64     `json:"failures"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+}
+
+// requestStats keeps RequestStats per resource.
+type requestStats struct {
+	mu        sync.Mutex
+	resources map[string]*RequestStats
+}
+
+// resourceOf names the API resource a path belongs to: "jobs" for
+// /api/v1/jobs/123/complete.
+func resourceOf(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	path = strings.TrimPrefix(path, "/")
+	if rest, ok := strings.CutPrefix(path, "api/"); ok {
+		if _, after, ok := strings.Cut(rest, "/"); ok {
+			path = after
+		}
+	}
+	resource, _, _ := strings.Cut(path, "/")
+	if resource == "" {
+		return "/"
+	}
+	return resource
+}
+
+func (s *requestStats) get(path string) *RequestStats {
+	resource := resourceOf(path)
+	if s.resources == nil {
+		s.resources = make(map[string]*RequestStats)
+	}
+	stats, ok := s.resources[resource]
+	if !ok {
+		stats = &RequestStats{}
+		s.resources[resource] = stats
+	}
+	return stats
+}
+
+func (s *requestStats) request(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(path).Requests++
+}
+
+func (s *requestStats) retry(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(path).Retries++
+}
+
+func (s *requestStats) failure(path string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.get(path)
+	stats.Failures++
+	stats.LastError = err.Error()
+	stats.LastErrorAt = time.Now()
+}
+
+// RequestStats returns request, retry, and failure counts per API resource.
+func (t *Transport) RequestStats() map[string]RequestStats {
+	t.stats.mu.Lock()
+	defer t.stats.mu.Unlock()
+	out := make(map[string]RequestStats, len(t.stats.resources))
+	for resource, stats := range t.stats.resources {
+		out[resource] = *stats
+	}
+	return out
+}
+
+// CircuitBreaker returns the circuit breaker's metrics, or false when it is
+// disabled. One breaker guards requests to all resources.
+func (t *Transport) CircuitBreaker() (CircuitBreakerMetrics, bool) {
+	if t.circuitBreaker == nil {
+		return CircuitBreakerMetrics{}, false
+	}
+	return t.circuitBreaker.Metrics(), true
+}
+
+// TokenExpiry returns when the access token expires and when it will be
+// refreshed, or zero times when they are unknown or refresh is disabled.
+func (t *Transport) TokenExpiry() (expiresAt, refreshAt time.Time) {
+	if t.tokenRefresher == nil {
+		return time.Time{}, time.Time{}
+	}
+	refreshAt = t.tokenRefresher.RefreshAt()
+	if refreshAt.IsZero() {
+		return time.Time{}, time.Time{}
+	}
+	return refreshAt.Add(RefreshAhead), refreshAt
+}
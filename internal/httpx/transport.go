@@ -5,11 +5,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/version"
@@ -29,6 +32,42 @@ type Transport struct {
 	logger           Logger
 	tokenRefresher   *TokenRefresher
 	autoRefreshToken bool
+	events           EventEmitter
+	queuePrefix      string
+	codec            Codec
+	// codecRejected is set once the server answers 415 to a codec-encoded body;
+	// request bodies are sent as JSON from then on.
+	codecRejected atomic.Bool
+	// compressionThreshold is Config.CompressionThreshold.
+	compressionThreshold int
+	// compressionRejected is set once the server answers 415 to a gzipped
+	// body; request bodies are sent uncompressed from then on.
+	compressionRejected atomic.Bool
+	// clockSkew is the estimated server clock offset in nanoseconds.
+	clockSkew     atomic.Int64
+	skewThreshold time.Duration
+	skewWarned    atomic.Bool
+	// endpoints is set when fallback base URLs are configured.
+	endpoints *endpointPool
+	// flights is set when GET coalescing is enabled.
+	flights *flightGroup
+	// deprecations holds deprecation warnings keyed by method and path.
+	deprecationsMu     sync.Mutex
+	deprecations       map[string]DeprecationWarning
+	strictDeprecations bool
+	useNumber          bool
+	legacyFieldNames   bool
+	// apiKeyMu guards apiKey and accessToken, which SetAPIKey and token
+	// refreshes can swap while requests are in flight.
+	apiKeyMu sync.RWMutex
+	limiter  Limiter
+	// rateLimitedUntil is when the last 429's Retry-After ends (unix
+	// nanoseconds); requests wait for it before being sent.
+	rateLimitedUntil atomic.Int64
+	// apiVersion is sent in APIVersionHeader when set.
+	apiVersion       string
+	apiVersionWarned atomic.Bool
+	stats            requestStats
 }
 
 // Logger is an interface for debug logging.
@@ -50,6 +89,49 @@ type Config struct {
 	CircuitBreaker   CircuitBreakerConfig
 	Logger           Logger
 	AutoRefreshToken bool
+	// Events receives the transport's events (optional); see EventEmitter.
+	Events EventEmitter
+	// QueuePrefix is prepended to queue names by resources (optional).
+	QueuePrefix string
+	// Codec is an alternate body codec negotiated via Content-Type and Accept,
+	// with JSON as the fallback (optional).
+	Codec Codec
+	// ClockSkewThreshold is the client/server clock skew that triggers a warning
+	// (default: DefaultClockSkewThreshold).
+	ClockSkewThreshold time.Duration
+	// FallbackBaseURLs are tried, in order, when BaseURL is unavailable (optional).
+	FallbackBaseURLs []string
+	// Failover configures health tracking across BaseURL and FallbackBaseURLs.
+	Failover FailoverConfig
+	// CoalesceGETs shares one upstream request between concurrent identical
+	// GET requests.
+	CoalesceGETs bool
+	// StrictDeprecations fails requests whose responses carry deprecation
+	// headers with a *DeprecationError, after the request has been executed.
+	StrictDeprecations bool
+	// UseNumber decodes JSON numbers in untyped values as json.Number instead
+	// of float64, so large integers survive decoding.
+	UseNumber bool
+	// Limiter is waited on before every attempt, including retries (optional).
+	Limiter Limiter
+	// APIVersion pins the API version sent in APIVersionHeader (optional).
+	APIVersion string
+	// LegacyFieldNames accepts camelCase field names in responses, as sent
+	// by older servers, alongside snake_case ones.
+	LegacyFieldNames bool
+	// CompressionThreshold is the body size in bytes from which Compress
+	// requests are gzipped (0 disables compression).
+	CompressionThreshold int
+	// HTTPClient sends requests instead of a client built from Timeout
+	// (optional). Timeout still applies if the client has none.
+	HTTPClient *http.Client
+}
+
+// Limiter throttles requests, e.g. across every client sharing an API key.
+type Limiter interface {
+	// Wait blocks until one more request may be sent, or returns an error if
+	// the request must not be sent.
+	Wait(ctx context.Context) error
 }
 
 // RetryConfig configures retry behavior.
@@ -67,6 +149,8 @@ type CircuitBreakerConfig struct {
 	FailureThreshold int
 	SuccessThreshold int
 	Timeout          time.Duration
+	// OnStateChange is called after every state transition (optional).
+	OnStateChange func(from, to CircuitState)
 }
 
 // DefaultRetryConfig returns the default retry configuration.
@@ -98,10 +182,20 @@ func NewTransport(cfg Config) *Transport {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 30 * time.Second
 	}
+	if cfg.ClockSkewThreshold == 0 {
+		cfg.ClockSkewThreshold = DefaultClockSkewThreshold
+	}
 
 	httpClient := &http.Client{
 		Timeout: cfg.Timeout,
 	}
+	if cfg.HTTPClient != nil {
+		custom := *cfg.HTTPClient
+		if custom.Timeout == 0 {
+			custom.Timeout = cfg.Timeout
+		}
+		httpClient = &custom
+	}
 
 	t := &Transport{
 		client:           httpClient,
@@ -113,6 +207,35 @@ func NewTransport(cfg Config) *Transport {
 		headers:          cfg.Headers,
 		logger:           cfg.Logger,
 		autoRefreshToken: cfg.AutoRefreshToken,
+		events:           cfg.Events,
+		queuePrefix:      cfg.QueuePrefix,
+		codec:            cfg.Codec,
+		skewThreshold:    cfg.ClockSkewThreshold,
+
+		compressionThreshold: cfg.CompressionThreshold,
+	}
+	if _, isJSON := t.codec.(JSONCodec); isJSON {
+		t.codec = nil
+	}
+	if cfg.CoalesceGETs {
+		t.flights = &flightGroup{}
+	}
+	t.deprecations = make(map[string]DeprecationWarning)
+	t.strictDeprecations = cfg.StrictDeprecations
+	t.useNumber = cfg.UseNumber
+	t.legacyFieldNames = cfg.LegacyFieldNames
+	t.limiter = cfg.Limiter
+	t.apiVersion = cfg.APIVersion
+
+	// Initialize endpoint failover
+	if len(cfg.FallbackBaseURLs) > 0 {
+		urls := append([]string{cfg.BaseURL}, cfg.FallbackBaseURLs...)
+		t.endpoints = newEndpointPool(urls, cfg.Failover)
+		t.endpoints.onSwitch = func(from, to string) {
+			t.log("switching endpoint", "from", from, "to", to)
+			t.emit(EndpointFailoverEvent{From: from, To: to})
+		}
+		t.endpoints.startProbing(&http.Client{Timeout: 5 * time.Second})
 	}
 
 	// Initialize retry policy - use defaults if not specified
@@ -127,7 +250,17 @@ func NewTransport(cfg Config) *Transport {
 
 	// Initialize circuit breaker
 	if cfg.CircuitBreaker.Enabled {
-		t.circuitBreaker = NewCircuitBreaker(cfg.CircuitBreaker)
+		cbConfig := cfg.CircuitBreaker
+		if cfg.Events != nil {
+			userHook := cbConfig.OnStateChange
+			cbConfig.OnStateChange = func(from, to CircuitState) {
+				cfg.Events(CircuitStateChangeEvent{From: from, To: to})
+				if userHook != nil {
+					userHook(from, to)
+				}
+			}
+		}
+		t.circuitBreaker = NewCircuitBreaker(cbConfig)
 	}
 
 	// Initialize token refresher if auto-refresh is enabled and we have an access token
@@ -139,6 +272,16 @@ func NewTransport(cfg Config) *Transport {
 			cfg.AccessToken,
 			cfg.Logger,
 		)
+		t.tokenRefresher.OnTokens(func(accessToken, _ string) {
+			t.apiKeyMu.Lock()
+			t.accessToken = accessToken
+			t.apiKeyMu.Unlock()
+		})
+		if cfg.Events != nil {
+			t.tokenRefresher.OnRefresh(func(method string, expiresIn int, err error) {
+				cfg.Events(TokenRefreshEvent{Method: method, ExpiresIn: expiresIn, Error: err})
+			})
+		}
 	}
 
 	return t
@@ -146,12 +289,64 @@ func NewTransport(cfg Config) *Transport {
 
 // SetAccessToken updates the access token (used for token refresh).
 func (t *Transport) SetAccessToken(token string) {
+	t.apiKeyMu.Lock()
 	t.accessToken = token
+	t.apiKeyMu.Unlock()
 	if t.tokenRefresher != nil {
 		t.tokenRefresher.SetAccessToken(token, 0)
 	}
 }
 
+// AccessToken returns the access token requests are authenticated with.
+func (t *Transport) AccessToken() string {
+	t.apiKeyMu.RLock()
+	defer t.apiKeyMu.RUnlock()
+	return t.accessToken
+}
+
+// OnTokenRefreshed registers a callback invoked with the new access and
+// refresh tokens after every successful automatic refresh. It is a no-op when
+// automatic refresh is disabled.
+func (t *Transport) OnTokenRefreshed(fn func(accessToken, refreshToken string)) {
+	if t.tokenRefresher != nil {
+		t.tokenRefresher.OnTokens(fn)
+	}
+}
+
+// APIKey returns the API key requests are authenticated with.
+func (t *Transport) APIKey() string {
+	t.apiKeyMu.RLock()
+	defer t.apiKeyMu.RUnlock()
+	return t.apiKey
+}
+
+// SetAPIKey replaces the API key for subsequent requests and token refreshes.
+// The access and refresh tokens were issued for the old key, so they are
+// dropped and requests authenticate with the new key.
+func (t *Transport) SetAPIKey(key string) {
+	t.apiKeyMu.Lock()
+	t.apiKey = key
+	t.accessToken = ""
+	t.apiKeyMu.Unlock()
+	if t.tokenRefresher != nil {
+		t.tokenRefresher.SetAPIKey(key)
+		t.tokenRefresher.SetAccessToken("", 0)
+		t.tokenRefresher.SetRefreshToken("")
+	}
+}
+
+// QueuePrefix returns the configured queue name prefix.
+func (t *Transport) QueuePrefix() string {
+	return t.queuePrefix
+}
+
+// Close stops background endpoint probing.
+func (t *Transport) Close() {
+	if t.endpoints != nil {
+		t.endpoints.stop()
+	}
+}
+
 // SetRefreshToken updates the refresh token.
 func (t *Transport) SetRefreshToken(token string) {
 	if t.tokenRefresher != nil {
@@ -159,6 +354,9 @@ func (t *Transport) SetRefreshToken(token string) {
 	}
 }
 
+// IdempotencyKeyHeader carries a request's idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 // Request represents an HTTP request to be made.
 type Request struct {
 	Method string
@@ -171,6 +369,17 @@ type Request struct {
 	Headers     map[string]string
 	UseAdminKey bool
 	Idempotent  bool // If true, can be retried for POST
+	// IdempotencyKey is sent in IdempotencyKeyHeader. The server applies a
+	// request with a key at most once, so it is retried like an idempotent one.
+	IdempotencyKey string
+	// Stream leaves a successful response body unread in Response.Stream,
+	// which the caller must close. Stream requests are JSON-only and bounded
+	// by the request context rather than the client timeout, which would
+	// otherwise cut off long downloads.
+	Stream bool
+	// Compress gzips the body when it reaches the transport's
+	// CompressionThreshold, for endpoints that carry large job payloads.
+	Compress bool
 }
 
 // Response represents an HTTP response.
@@ -179,10 +388,82 @@ type Response struct {
 	Body       []byte
 	Headers    http.Header
 	RequestID  string
+	// Stream is the unread response body of a Stream request; Body is nil.
+	Stream io.ReadCloser
+
+	// codec decodes Body when the server answered with the configured codec.
+	codec Codec
+	// useNumber decodes JSON numbers in untyped values as json.Number.
+	useNumber bool
+	// legacyFieldNames accepts camelCase field names; see NormalizeFieldNames.
+	legacyFieldNames bool
+}
+
+// Decode decodes the response body into v using the codec the server
+// responded with, or JSON.
+func (r *Response) Decode(v any) error {
+	if r.codec != nil {
+		return r.codec.Unmarshal(r.Body, v)
+	}
+	if r.legacyFieldNames {
+		var data any
+		if err := UnmarshalUseNumber(r.Body, &data); err != nil {
+			return err
+		}
+		body, err := json.Marshal(NormalizeFieldNames(data, v))
+		if err != nil {
+			return err
+		}
+		if r.useNumber {
+			return UnmarshalUseNumber(body, v)
+		}
+		return json.Unmarshal(body, v)
+	}
+	if r.useNumber {
+		return UnmarshalUseNumber(r.Body, v)
+	}
+	return json.Unmarshal(r.Body, v)
+}
+
+// UsesNumber reports whether Decode yields json.Number for untyped numbers.
+func (r *Response) UsesNumber() bool {
+	return r.useNumber && r.codec == nil
+}
+
+// UnmarshalUseNumber is json.Unmarshal with numbers in untyped values decoded
+// as json.Number.
+func UnmarshalUseNumber(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
 }
 
 // Do executes an HTTP request with retry and circuit breaker logic.
+//
+// When GET coalescing is enabled, concurrent identical GET requests share a
+// single upstream request and receive the same *Response, which must be
+// treated as read-only.
 func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
+	if t.flights != nil {
+		if key := coalesceKey(req); key != "" {
+			return t.flights.do(ctx, key, func(ctx context.Context) (*Response, error) {
+				return t.do(ctx, req)
+			})
+		}
+	}
+	return t.do(ctx, req)
+}
+
+func (t *Transport) do(ctx context.Context, req *Request) (*Response, error) {
+	t.stats.request(req.Path)
+	resp, err := t.doWithRetry(ctx, req)
+	if err != nil {
+		t.stats.failure(req.Path, err)
+	}
+	return resp, err
+}
+
+func (t *Transport) doWithRetry(ctx context.Context, req *Request) (*Response, error) {
 	// Check circuit breaker
 	if t.circuitBreaker != nil && !t.circuitBreaker.Allow() {
 		return nil, NewCircuitBreakerOpenError()
@@ -192,8 +473,6 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 	if t.tokenRefresher != nil && t.autoRefreshToken {
 		if err := t.tokenRefresher.RefreshIfNeeded(ctx); err != nil {
 			t.log("proactive token refresh failed", "error", err)
-		} else if token := t.tokenRefresher.GetAccessToken(); token != "" {
-			t.accessToken = token
 		}
 	}
 
@@ -203,9 +482,21 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		if attempt > 0 {
-			// Wait before retry
+			// Wait before retry, at least as long as a 429 asked
 			delay := t.retry.Delay(attempt - 1)
+			var rateErr *RateLimitError
+			if errors.As(lastErr, &rateErr) && rateErr.RetryAfter > delay {
+				delay = rateErr.RetryAfter
+			}
 			t.log("retrying request", "attempt", attempt, "delay", delay, "path", req.Path)
+			t.stats.retry(req.Path)
+			t.emit(RetryEvent{
+				Method:  req.Method,
+				Path:    req.Path,
+				Attempt: attempt,
+				Delay:   delay,
+				Error:   lastErr,
+			})
 
 			select {
 			case <-ctx.Done():
@@ -214,6 +505,16 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 			}
 		}
 
+		if err := t.waitRateLimited(ctx); err != nil {
+			return nil, err
+		}
+		if t.limiter != nil {
+			if err := t.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		token := t.AccessToken()
 		resp, err := t.doOnce(ctx, req)
 		if err == nil {
 			// Success - record for circuit breaker
@@ -224,15 +525,18 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 		}
 
 		lastErr = err
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) {
+			t.noteRateLimit(rateErr)
+		}
 
 		// Check for 401 and try to refresh token (only once)
 		if IsAuthenticationError(err) && t.tokenRefresher != nil && t.autoRefreshToken && !tokenRefreshAttempted {
 			tokenRefreshAttempted = true
 			t.log("received 401, attempting token refresh")
-			// Use ForceRefresh since we got a 401 (token is definitely invalid)
-			if refreshErr := t.tokenRefresher.ForceRefresh(ctx); refreshErr == nil {
-				if token := t.tokenRefresher.GetAccessToken(); token != "" {
-					t.accessToken = token
+			// Concurrent requests rejected with the same token share one refresh
+			if refreshErr := t.tokenRefresher.RefreshRejected(ctx, token); refreshErr == nil {
+				if t.AccessToken() != "" {
 					// Retry immediately without counting as a retry attempt
 					attempt--
 					continue
@@ -242,8 +546,9 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 			}
 		}
 
-		// Record failure for circuit breaker
-		if t.circuitBreaker != nil {
+		// Record failure for circuit breaker. A 429 shows the server is up and
+		// shedding load, so retrying it must not open the circuit.
+		if t.circuitBreaker != nil && rateErr == nil {
 			t.circuitBreaker.RecordFailure()
 		}
 
@@ -259,7 +564,11 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 // doOnce executes a single HTTP request.
 func (t *Transport) doOnce(ctx context.Context, req *Request) (*Response, error) {
 	// Build URL
-	fullURL := t.baseURL + req.Path
+	baseURL, endpoint := t.baseURL, -1
+	if t.endpoints != nil {
+		endpoint, baseURL = t.endpoints.pick()
+	}
+	fullURL := baseURL + req.Path
 	if len(req.Query) > 0 {
 		// Properly URL-encode query parameters (important for commas, unicode, spaces, etc.)
 		q := url.Values{}
@@ -274,13 +583,25 @@ func (t *Transport) doOnce(ctx context.Context, req *Request) (*Response, error)
 
 	// Prepare body
 	var bodyReader io.Reader
+	contentType := JSONContentType
+	useCodec := false
+	compressed := false
 	if req.RawBody != nil {
 		bodyReader = bytes.NewReader(req.RawBody)
 	} else if req.Body != nil {
-		bodyBytes, err := json.Marshal(req.Body)
+		var bodyBytes []byte
+		var err error
+		if t.codec != nil && !t.codecRejected.Load() {
+			bodyBytes, err = t.codec.Marshal(req.Body)
+			contentType = t.codec.ContentType()
+			useCodec = true
+		} else {
+			bodyBytes, err = json.Marshal(req.Body)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+		bodyBytes, compressed = t.compressBody(req, bodyBytes)
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
@@ -292,19 +613,29 @@ func (t *Transport) doOnce(ctx context.Context, req *Request) (*Response, error)
 
 	// Set headers
 	httpReq.Header.Set("User-Agent", t.userAgent)
-	httpReq.Header.Set("Accept", "application/json")
+	if t.apiVersion != "" {
+		httpReq.Header.Set(APIVersionHeader, t.apiVersion)
+	}
+	if t.codec != nil && !req.Stream {
+		httpReq.Header.Set("Accept", t.codec.ContentType()+", "+JSONContentType+";q=0.9")
+	} else {
+		httpReq.Header.Set("Accept", JSONContentType)
+	}
 	if req.Body != nil || req.RawBody != nil {
-		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", GzipEncoding)
 	}
 
 	// Set auth header
 	if req.UseAdminKey && t.adminKey != "" {
 		httpReq.Header.Set("X-Admin-Key", t.adminKey)
-	} else if t.accessToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+t.accessToken)
-	} else if t.apiKey != "" {
+	} else if accessToken := t.AccessToken(); accessToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+	} else if apiKey := t.APIKey(); apiKey != "" {
 		// API keys are sent via Bearer token, not X-API-Key header
-		httpReq.Header.Set("Authorization", "Bearer "+t.apiKey)
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
 	}
 
 	// Add custom headers from transport config
@@ -316,17 +647,52 @@ func (t *Transport) doOnce(ctx context.Context, req *Request) (*Response, error)
 	for k, v := range req.Headers {
 		httpReq.Header.Set(k, v)
 	}
+	if req.IdempotencyKey != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, req.IdempotencyKey)
+	}
 
 	// Execute request
 	t.log("executing request", "method", req.Method, "url", fullURL)
-	httpResp, err := t.client.Do(httpReq)
+	client := t.client
+	if req.Stream {
+		streamClient := *t.client
+		streamClient.Timeout = 0
+		client = &streamClient
+	}
+	sentAt := time.Now()
+	httpResp, err := client.Do(httpReq)
 	if err != nil {
 		// Check for timeout
 		if ctx.Err() != nil {
 			return nil, NewTimeoutError(t.client.Timeout, ctx.Err())
 		}
+		if endpoint >= 0 {
+			t.endpoints.fail(endpoint)
+		}
 		return nil, NewNetworkError(err)
 	}
+	t.observeClock(httpResp.Header.Get("Date"), sentAt, time.Now())
+	t.observeAPIVersion(httpResp.Header.Get(APIVersionHeader))
+
+	if req.Stream && httpResp.StatusCode < 400 {
+		t.log("streaming response", "status", httpResp.StatusCode, "request_id", httpResp.Header.Get("X-Request-ID"))
+		if endpoint >= 0 {
+			t.endpoints.succeed(endpoint, time.Since(sentAt))
+		}
+		if err := t.observeDeprecation(req, httpResp.StatusCode, httpResp.Header); err != nil {
+			httpResp.Body.Close()
+			return nil, err
+		}
+		return &Response{
+			StatusCode: httpResp.StatusCode,
+			Headers:    httpResp.Header,
+			RequestID:  httpResp.Header.Get("X-Request-ID"),
+			Stream:     httpResp.Body,
+			useNumber:  t.useNumber,
+
+			legacyFieldNames: t.legacyFieldNames,
+		}, nil
+	}
 	defer httpResp.Body.Close()
 
 	// Read body
@@ -340,13 +706,45 @@ func (t *Transport) doOnce(ctx context.Context, req *Request) (*Response, error)
 		Body:       body,
 		Headers:    httpResp.Header,
 		RequestID:  httpResp.Header.Get("X-Request-ID"),
+		useNumber:  t.useNumber,
+
+		legacyFieldNames: t.legacyFieldNames,
+	}
+	if t.codec != nil && mediaType(httpResp.Header.Get("Content-Type")) == mediaType(t.codec.ContentType()) {
+		resp.codec = t.codec
 	}
 
 	t.log("received response", "status", resp.StatusCode, "request_id", resp.RequestID)
 
+	// The server does not accept gzipped bodies; send them uncompressed
+	if httpResp.StatusCode == http.StatusUnsupportedMediaType && compressed {
+		t.compressionRejected.Store(true)
+		t.log("compressed body rejected by server, sending uncompressed", "content_encoding", GzipEncoding)
+		return t.doOnce(ctx, req)
+	}
+
+	// The server does not accept the codec; fall back to JSON bodies
+	if httpResp.StatusCode == http.StatusUnsupportedMediaType && useCodec {
+		t.codecRejected.Store(true)
+		t.log("codec rejected by server, falling back to JSON", "content_type", contentType)
+		return t.doOnce(ctx, req)
+	}
+
+	deprecationErr := t.observeDeprecation(req, httpResp.StatusCode, httpResp.Header)
+
 	// Check for errors
 	if httpResp.StatusCode >= 400 {
-		return nil, ParseErrorFromResponse(httpResp.StatusCode, body, httpResp.Header)
+		respErr := ParseErrorFromResponse(httpResp.StatusCode, body, httpResp.Header)
+		if endpoint >= 0 && isEndpointFailure(respErr) {
+			t.endpoints.fail(endpoint)
+		}
+		return nil, respErr
+	}
+	if endpoint >= 0 {
+		t.endpoints.succeed(endpoint, time.Since(sentAt))
+	}
+	if deprecationErr != nil {
+		return nil, deprecationErr
 	}
 
 	return resp, nil
@@ -358,8 +756,14 @@ func (t *Transport) shouldRetry(req *Request, err error, attempt int) bool {
 		return false
 	}
 
+	// A 429 means the request was not processed, so it is safe to repeat
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return true
+	}
+
 	// Don't retry non-idempotent requests unless explicitly marked
-	if req.Method == http.MethodPost && !req.Idempotent {
+	if req.Method == http.MethodPost && !req.Idempotent && req.IdempotencyKey == "" {
 		return false
 	}
 
@@ -380,7 +784,7 @@ func JSON[T any](resp *Response) (*T, error) {
 		return nil, nil
 	}
 	var result T
-	if err := json.Unmarshal(resp.Body, &result); err != nil {
+	if err := resp.Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	return &result, nil
@@ -392,7 +796,7 @@ func JSONArray[T any](resp *Response) ([]T, error) {
 		return nil, nil
 	}
 	var result []T
-	if err := json.Unmarshal(resp.Body, &result); err != nil {
+	if err := resp.Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	return result, nil
@@ -1,12 +1,18 @@
 package httpx
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -428,6 +434,49 @@ func TestTransport_Do_CircuitBreaker(t *testing.T) {
 	_ = cbErr
 }
 
+func TestTransport_Do_RateLimitKeepsCircuitClosed(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Retry: RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  1 * time.Millisecond,
+			Jitter:     false,
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			Enabled:          true,
+			FailureThreshold: 2,
+			SuccessThreshold: 1,
+			Timeout:          time.Hour,
+		},
+	})
+
+	// Every attempt is rate limited, which is not a server failure
+	_, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if requestCount != 4 {
+		t.Errorf("Expected 4 requests (initial + 3 retries), got %d", requestCount)
+	}
+	if metrics, _ := transport.CircuitBreaker(); metrics.State != CircuitClosed || metrics.FailureCount != 0 {
+		t.Errorf("Expected circuit to stay closed with no failures, got %s with %d", metrics.State, metrics.FailureCount)
+	}
+
+	_, _ = transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"})
+	if requestCount != 8 {
+		t.Errorf("Expected the next request to reach the server, got %d requests", requestCount)
+	}
+}
+
 func TestTransport_Do_ContextCancellation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(1 * time.Second)
@@ -525,3 +574,673 @@ func TestJSONArray(t *testing.T) {
 		t.Errorf("len(result) = %d, want 2", len(result))
 	}
 }
+
+func TestTransport_Do_EmitsEvents(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requestCount, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var events []any
+	emit := func(event any) {
+		events = append(events, event)
+	}
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Retry: RetryConfig{
+			MaxRetries: 2,
+			BaseDelay:  1 * time.Millisecond,
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			Enabled:          true,
+			FailureThreshold: 1,
+			SuccessThreshold: 1,
+			Timeout:          time.Minute,
+		},
+		Events: emit,
+	})
+
+	if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("events = %+v, want a circuit state change and a retry", events)
+	}
+	change, ok := events[0].(CircuitStateChangeEvent)
+	if !ok || change.From != CircuitClosed || change.To != CircuitOpen {
+		t.Errorf("events[0] = %+v, want closed -> open", events[0])
+	}
+	retry, ok := events[1].(RetryEvent)
+	if !ok || retry.Attempt != 1 || retry.Path != "/test" || retry.Error == nil {
+		t.Errorf("events[1] = %+v, want the first retry of /test", events[1])
+	}
+}
+
+// prefixCodec is a test codec that wraps JSON in a marker prefix.
+type prefixCodec struct{}
+
+func (prefixCodec) ContentType() string { return "application/x-prefixed" }
+
+func (prefixCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return append([]byte("P:"), data...), err
+}
+
+func (prefixCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(bytes.TrimPrefix(data, []byte("P:")), v)
+}
+
+func TestTransport_Do_Codec(t *testing.T) {
+	var contentTypes []string
+	var accepts bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		accepts = strings.Contains(r.Header.Get("Accept"), "application/x-prefixed")
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/rejects" && r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		if bytes.HasPrefix(body, []byte("P:")) {
+			w.Header().Set("Content-Type", "application/x-prefixed")
+			w.Write([]byte(`P:{"codec":true}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"codec":false}`))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Codec:   prefixCodec{},
+	})
+
+	resp, err := transport.Do(context.Background(), &Request{
+		Method: http.MethodPut,
+		Path:   "/accepts",
+		Body:   map[string]string{"a": "b"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := JSON[map[string]bool](resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !(*result)["codec"] {
+		t.Errorf("codec response not decoded: %v", *result)
+	}
+	if !accepts {
+		t.Error("Accept header does not list the codec")
+	}
+
+	// A 415 switches request bodies back to JSON for good
+	resp, err = transport.Do(context.Background(), &Request{
+		Method: http.MethodPut,
+		Path:   "/rejects",
+		Body:   map[string]string{"a": "b"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err = JSON[map[string]bool](resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if (*result)["codec"] {
+		t.Errorf("expected JSON fallback, got %v", *result)
+	}
+	want := []string{"application/x-prefixed", "application/x-prefixed", "application/json"}
+	if strings.Join(contentTypes, ",") != strings.Join(want, ",") {
+		t.Errorf("Content-Types = %v, want %v", contentTypes, want)
+	}
+}
+
+func TestTransport_ClockSkew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(2*time.Minute).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var skewEvents int
+	emit := func(event any) {
+		if _, ok := event.(ClockSkewEvent); ok {
+			skewEvents++
+		}
+	}
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Events:  emit,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if skew := transport.ClockSkew(); skew < 118*time.Second || skew > 122*time.Second {
+		t.Errorf("ClockSkew() = %v, want ~2m", skew)
+	}
+	if skewEvents != 1 {
+		t.Errorf("skew events = %d, want 1", skewEvents)
+	}
+	if diff := transport.Now().Sub(time.Now()); diff < 118*time.Second {
+		t.Errorf("Now() is %v ahead, want ~2m", diff)
+	}
+}
+
+func TestTransport_Do_Failover(t *testing.T) {
+	var primaryCount, secondaryCount int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCount, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryCount, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	transport := NewTransport(Config{
+		BaseURL:          primary.URL,
+		FallbackBaseURLs: []string{secondary.URL},
+		APIKey:           "sp_test_123456789012345678901234567890",
+		Retry: RetryConfig{
+			MaxRetries: 1,
+			BaseDelay:  1 * time.Millisecond,
+			Jitter:     false,
+		},
+	})
+	defer transport.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The primary is skipped for the cooldown after its first failure
+	if primaryCount != 1 {
+		t.Errorf("primary requests = %d, want 1", primaryCount)
+	}
+	if secondaryCount != 3 {
+		t.Errorf("secondary requests = %d, want 3", secondaryCount)
+	}
+}
+
+func TestTransport_Do_CoalesceGETs(t *testing.T) {
+	var requestCount int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL:      server.URL,
+		APIKey:       "sp_test_123456789012345678901234567890",
+		CoalesceGETs: true,
+	})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test", Query: map[string]string{"a": "1"}})
+			if err == nil && string(resp.Body) != `{"status":"ok"}` {
+				err = fmt.Errorf("unexpected body %q", resp.Body)
+			}
+			errs <- err
+		}()
+	}
+
+	// Let every caller join the in-flight request before answering it
+	for atomic.LoadInt32(&requestCount) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if requestCount != 1 {
+		t.Errorf("requests = %d, want 1", requestCount)
+	}
+
+	// Requests that differ in query are not coalesced, and POSTs never are
+	transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test", Query: map[string]string{"a": "2"}})
+	transport.Do(context.Background(), &Request{Method: http.MethodPost, Path: "/test"})
+	if requestCount != 3 {
+		t.Errorf("requests = %d, want 3", requestCount)
+	}
+}
+
+func TestTransport_Do_Deprecation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			w.Header().Set("Deprecation", "@1767225600")
+			w.Header().Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+			w.Header().Add("Link", `<https://docs.example.com/migrate>; rel="deprecation", <https://api.example.com/new>; rel="successor-version"`)
+			w.WriteHeader(http.StatusOK)
+		case "/gone":
+			w.Header().Set("Link", `<https://docs.example.com/migrate>; rel="deprecation"`)
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"code":"gone","message":"endpoint removed"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	var events int
+	emit := func(event any) {
+		if _, ok := event.(DeprecationWarning); ok {
+			events++
+		}
+	}
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Events:  emit,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/old"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/current"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/gone"})
+	var goneErr *GoneError
+	if !errors.As(err, &goneErr) {
+		t.Fatalf("expected *GoneError, got %T: %v", err, err)
+	}
+	if goneErr.Link != "https://docs.example.com/migrate" {
+		t.Errorf("GoneError.Link = %q", goneErr.Link)
+	}
+
+	warnings := transport.DeprecationWarnings()
+	if len(warnings) != 2 {
+		t.Fatalf("len(DeprecationWarnings()) = %d, want 2", len(warnings))
+	}
+	old := warnings[1]
+	if old.Path != "/old" || old.Link != "https://api.example.com/new" || old.Removed {
+		t.Errorf("warning = %+v", old)
+	}
+	if old.Sunset == nil || old.Sunset.Year() != 2026 || old.DeprecatedAt == nil {
+		t.Errorf("warning dates = %v, %v", old.DeprecatedAt, old.Sunset)
+	}
+	if !warnings[0].Removed {
+		t.Errorf("warning for /gone should be marked removed")
+	}
+	if events != 2 {
+		t.Errorf("deprecation events = %d, want 2", events)
+	}
+
+	strict := NewTransport(Config{
+		BaseURL:            server.URL,
+		APIKey:             "sp_test_123456789012345678901234567890",
+		StrictDeprecations: true,
+	})
+	_, err = strict.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/old"})
+	var deprecationErr *DeprecationError
+	if !errors.As(err, &deprecationErr) {
+		t.Errorf("expected *DeprecationError in strict mode, got %v", err)
+	}
+}
+
+type countingLimiter struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.calls.Add(1)
+	return l.err
+}
+
+func TestTransport_Do_Limiter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := &countingLimiter{}
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Retry:   RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1},
+		Limiter: limiter,
+	})
+
+	if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Retries are throttled too
+	if got := limiter.calls.Load(); got != 2 {
+		t.Errorf("limiter calls = %d, want 2", got)
+	}
+
+	limiter.err = errors.New("rate limited")
+	if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); !errors.Is(err, limiter.err) {
+		t.Errorf("err = %v, want limiter error", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestTransport_Do_RateLimited(t *testing.T) {
+	var requests atomic.Int32
+	var retriedAt atomic.Int64
+	start := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("RateLimit-Limit", "100")
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":"rate_limit_exceeded","message":"Too many requests"}`))
+			return
+		}
+		retriedAt.Store(int64(time.Since(start)))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Retry:   RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1},
+	})
+
+	// A rejected POST was never applied, so it is retried, after Retry-After
+	if _, err := transport.Do(context.Background(), &Request{Method: http.MethodPost, Path: "/jobs/bulk"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := time.Duration(retriedAt.Load()); got < time.Second {
+		t.Errorf("retried after %v, want at least Retry-After (1s)", got)
+	}
+
+	err := ParseErrorFromResponse(429, nil, http.Header{"Ratelimit-Reset": []string{"30"}, "Ratelimit-Limit": []string{"100"}})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.Limit != 100 {
+		t.Fatalf("err = %v, want a RateLimitError with Limit 100", err)
+	}
+	if rateErr.RetryAfter < 29*time.Second || rateErr.RetryAfter > 30*time.Second {
+		t.Errorf("RetryAfter = %v, want the time until RateLimit-Reset", rateErr.RetryAfter)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(100, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := bucket.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	// Two requests fit in the burst; the other two wait 10ms each
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("4 requests took %v, want the last two throttled", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewTokenBucket(0.001, 1)
+	_ = slow.Wait(ctx)
+	if err := slow.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+
+	if ChainLimiters(nil, nil) != nil {
+		t.Error("ChainLimiters of nils should be nil")
+	}
+}
+
+func TestTransport_Do_APIVersion(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(APIVersionHeader))
+		w.Header().Set(APIVersionHeader, "2025-06")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var mismatches []APIVersionMismatchEvent
+	emit := func(event any) {
+		if e, ok := event.(APIVersionMismatchEvent); ok {
+			mismatches = append(mismatches, e)
+		}
+	}
+
+	transport := NewTransport(Config{
+		BaseURL:    server.URL,
+		APIKey:     "sp_test_123456789012345678901234567890",
+		APIVersion: "2024-11",
+		Events:     emit,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/test"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(sent) != 2 || sent[0] != "2024-11" || sent[1] != "2024-11" {
+		t.Errorf("sent versions = %v, want 2024-11 on every request", sent)
+	}
+	if len(mismatches) != 1 || mismatches[0].Requested != "2024-11" || mismatches[0].Served != "2025-06" {
+		t.Errorf("mismatch events = %+v, want one 2024-11 -> 2025-06", mismatches)
+	}
+}
+
+func TestTransport_Do_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not_found","message":"missing"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[1,2,3]`))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL: server.URL,
+		APIKey:  "sp_test_123456789012345678901234567890",
+		Timeout: time.Nanosecond, // would fail any buffered request
+	})
+
+	resp, err := transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/items", Stream: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Stream.Close()
+	if resp.Body != nil {
+		t.Errorf("Body = %q, want nil for a stream", resp.Body)
+	}
+	body, err := io.ReadAll(resp.Stream)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(body) != "[1,2,3]" {
+		t.Errorf("Stream = %q, want [1,2,3]", body)
+	}
+
+	_, err = transport.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/missing", Stream: true})
+	if !IsNotFoundError(err) {
+		t.Errorf("error = %v, want NotFoundError", err)
+	}
+}
+
+func TestTransport_Do_Compress(t *testing.T) {
+	var rejectGzip atomic.Bool
+	var encodings []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		mu.Lock()
+		encodings = append(encodings, encoding)
+		mu.Unlock()
+		if encoding == GzipEncoding && rejectGzip.Load() {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		var body io.Reader = r.Body
+		if encoding == GzipEncoding {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader() error = %v", err)
+				return
+			}
+			body = zr
+		}
+		var got map[string]string
+		if err := json.NewDecoder(body).Decode(&got); err != nil || got["data"] == "" || strings.Trim(got["data"], "a") != "" {
+			t.Errorf("decoded body = %q, %v; want the original body", got, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(Config{
+		BaseURL:              server.URL,
+		APIKey:               "sp_test_123456789012345678901234567890",
+		CompressionThreshold: 1024,
+	})
+	large := map[string]string{"data": strings.Repeat("a", 2048)}
+	small := map[string]string{"data": "a"}
+	ctx := context.Background()
+
+	// Only Compress requests at or over the threshold are gzipped
+	transport.Do(ctx, &Request{Method: http.MethodPost, Path: "/jobs", Body: large, Compress: true})
+	transport.Do(ctx, &Request{Method: http.MethodPost, Path: "/jobs", Body: small, Compress: true})
+	transport.Do(ctx, &Request{Method: http.MethodPost, Path: "/other", Body: large})
+	if want := []string{GzipEncoding, "", ""}; !slices.Equal(encodings, want) {
+		t.Errorf("Content-Encoding = %q, want %q", encodings, want)
+	}
+
+	// A 415 to a gzipped body is resent uncompressed, and so are later bodies
+	rejectGzip.Store(true)
+	encodings = nil
+	if _, err := transport.Do(ctx, &Request{Method: http.MethodPost, Path: "/jobs", Body: large, Compress: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	transport.Do(ctx, &Request{Method: http.MethodPost, Path: "/jobs", Body: large, Compress: true})
+	if want := []string{GzipEncoding, "", ""}; !slices.Equal(encodings, want) {
+		t.Errorf("Content-Encoding after 415 = %q, want %q", encodings, want)
+	}
+}
+
+func TestCheckPayloadSize(t *testing.T) {
+	payload := map[string]any{"data": strings.Repeat("a", 100)}
+	if err := CheckPayloadSize(payload, 0); err != nil {
+		t.Errorf("CheckPayloadSize() with no limit = %v", err)
+	}
+	if err := CheckPayloadSize(payload, 1000); err != nil {
+		t.Errorf("CheckPayloadSize() under the limit = %v", err)
+	}
+	err := CheckPayloadSize(payload, 50)
+	var tooLarge *PayloadTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Size != 111 || tooLarge.Limit != 50 || tooLarge.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("err = %#v, want a PayloadTooLargeError with Size 111 and Limit 50", err)
+	}
+}
+
+func TestNormalizeFieldNames(t *testing.T) {
+	type inner struct {
+		WorkerID string `json:"worker_id"`
+	}
+	type base struct {
+		CreatedAt time.Time `json:"created_at"`
+	}
+	type outer struct {
+		base
+		QueueName string           `json:"queue_name"`
+		Workers   []inner          `json:"workers"`
+		ByQueue   map[string]inner `json:"by_queue"`
+		Payload   map[string]any   `json:"payload"`
+	}
+
+	var data any
+	if err := json.Unmarshal([]byte(`{
+		"createdAt": "2024-01-01T00:00:00Z",
+		"queueName": "camel", "queue_name": "snake",
+		"workers": [{"workerId": "w1"}],
+		"byQueue": {"emails": {"workerID": "w2"}},
+		"payload": {"userId": 1}
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	var got outer
+	body, _ := json.Marshal(NormalizeFieldNames(data, &got))
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got.CreatedAt.IsZero() {
+		t.Error("embedded field was not renamed")
+	}
+	if got.QueueName != "snake" {
+		t.Errorf("QueueName = %q, want the snake_case value to take precedence", got.QueueName)
+	}
+	if len(got.Workers) != 1 || got.Workers[0].WorkerID != "w1" || got.ByQueue["emails"].WorkerID != "w2" {
+		t.Errorf("nested fields = %+v, %+v; want them renamed", got.Workers, got.ByQueue)
+	}
+	if _, ok := got.Payload["userId"]; !ok {
+		t.Errorf("Payload = %v, want untyped values left alone", got.Payload)
+	}
+}
+
+func TestResourceOf(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/jobs/123/complete":  "jobs",
+		"/api/v1/queues?limit=10":    "queues",
+		"/api/v1/auth/refresh":       "auth",
+		"/health":                    "health",
+		"/":                          "/",
+		"api/v2/workflows/abc/nodes": "workflows",
+	}
+	for path, want := range tests {
+		if got := resourceOf(path); got != want {
+			t.Errorf("resourceOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Package jsonschema validates decoded JSON values against a subset of JSON
+// Schema (draft 2020-12) sufficient for job payload contracts: type, enum,
+// const, properties, required, additionalProperties, items, numeric and
+// length bounds, and pattern. Unknown keywords are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	types                []string
+	enum                 []any
+	constValue           any
+	hasConst             bool
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	minItems             *int
+	maxItems             *int
+	pattern              *regexp.Regexp
+}
+
+// raw is the JSON form of a schema.
+type raw struct {
+	Type                 json.RawMessage            `json:"type"`
+	Enum                 []any                      `json:"enum"`
+	Const                json.RawMessage            `json:"const"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	ExclusiveMinimum     *float64                   `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64                   `json:"exclusiveMaximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+	Pattern              *string                    `json:"pattern"`
+}
+
+// Compile parses a JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	// true and false are valid schemas
+	switch strings.TrimSpace(string(data)) {
+	case "true":
+		return &Schema{}, nil
+	case "false":
+		return &Schema{types: []string{}}, nil
+	}
+
+	var r raw
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	s := &Schema{
+		enum:             r.Enum,
+		required:         r.Required,
+		minimum:          r.Minimum,
+		maximum:          r.Maximum,
+		exclusiveMinimum: r.ExclusiveMinimum,
+		exclusiveMaximum: r.ExclusiveMaximum,
+		minLength:        r.MinLength,
+		maxLength:        r.MaxLength,
+		minItems:         r.MinItems,
+		maxItems:         r.MaxItems,
+	}
+	if len(r.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(r.Type, &single); err == nil {
+			s.types = []string{single}
+		} else if err := json.Unmarshal(r.Type, &s.types); err != nil {
+			return nil, fmt.Errorf("invalid schema type: %s", r.Type)
+		}
+	}
+	if len(r.Const) > 0 {
+		if err := json.Unmarshal(r.Const, &s.constValue); err != nil {
+			return nil, fmt.Errorf("invalid schema const: %w", err)
+		}
+		s.hasConst = true
+	}
+	if len(r.Properties) > 0 {
+		s.properties = make(map[string]*Schema, len(r.Properties))
+		for name, prop := range r.Properties {
+			compiled, err := Compile(prop)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", name, err)
+			}
+			s.properties[name] = compiled
+		}
+	}
+	if len(r.AdditionalProperties) > 0 {
+		if strings.TrimSpace(string(r.AdditionalProperties)) == "false" {
+			s.noAdditional = true
+		} else {
+			compiled, err := Compile(r.AdditionalProperties)
+			if err != nil {
+				return nil, fmt.Errorf("additionalProperties: %w", err)
+			}
+			s.additionalProperties = compiled
+		}
+	}
+	if len(r.Items) > 0 {
+		compiled, err := Compile(r.Items)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		s.items = compiled
+	}
+	if r.Pattern != nil {
+		re, err := regexp.Compile(*r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	return s, nil
+}
+
+// Validate checks v, a value decoded from JSON (json.Number is accepted), and
+// returns one message per violation, prefixed with its JSON pointer.
+func (s *Schema) Validate(v any) []string {
+	var errs []string
+	s.validate("", v, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, errs *[]string) {
+	fail := func(format string, args ...any) {
+		at := path
+		if at == "" {
+			at = "/"
+		}
+		*errs = append(*errs, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if s.types != nil && !matchesType(s.types, v) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(v))
+		return
+	}
+	if s.hasConst && !equal(s.constValue, v) {
+		fail("must equal %v", s.constValue)
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", s.enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := path + "/" + name
+			if prop, ok := s.properties[name]; ok {
+				prop.validate(child, v[name], errs)
+			} else if s.noAdditional {
+				fail("unexpected property %q", name)
+			} else if s.additionalProperties != nil {
+				s.additionalProperties.validate(child, v[name], errs)
+			}
+		}
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(fmt.Sprintf("%s/%d", path, i), item, errs)
+			}
+		}
+	case string:
+		n := len([]rune(v))
+		if s.minLength != nil && n < *s.minLength {
+			fail("must be at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("must be at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match %q", s.pattern.String())
+		}
+	default:
+		f, ok := number(v)
+		if !ok {
+			return
+		}
+		if s.minimum != nil && f < *s.minimum {
+			fail("must be >= %v", *s.minimum)
+		}
+		if s.maximum != nil && f > *s.maximum {
+			fail("must be <= %v", *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+			fail("must be > %v", *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+			fail("must be < %v", *s.exclusiveMaximum)
+		}
+	}
+}
+
+// number converts a decoded JSON number to float64.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		if f, ok := number(v); ok {
+			if f == math.Trunc(f) {
+				return "integer"
+			}
+			return "number"
+		}
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func matchesType(types []string, v any) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares decoded JSON values, treating numbers by value.
+func equal(a, b any) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	return err == nil && string(aj) == string(bj)
+}
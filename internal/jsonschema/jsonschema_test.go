@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"type": "object",
+		"required": ["to", "attempt"],
+		"properties": {
+			"to": {"type": "string", "pattern": "@"},
+			"attempt": {"type": "integer", "minimum": 1},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+		},
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{"valid", `{"to":"a@example.com","attempt":1,"tags":["x"]}`, nil},
+		{"missing required", `{"to":"a@example.com"}`, []string{`/: missing required property "attempt"`}},
+		{"wrong type", `{"to":"a@example.com","attempt":1.5}`, []string{"/attempt: expected integer, got number"}},
+		{"bounds", `{"to":"nobody","attempt":0}`, []string{"/attempt: must be >= 1", `/to: must match "@"`}},
+		{"items", `{"to":"a@example.com","attempt":1,"tags":["x",2,"z"]}`, []string{"/tags: must have at most 2 items", "/tags/1: expected string, got integer"}},
+		{"additional", `{"to":"a@example.com","attempt":1,"cc":"b"}`, []string{`/: unexpected property "cc"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := json.NewDecoder(strings.NewReader(tt.payload))
+			dec.UseNumber()
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got := schema.Validate(v)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, doc := range []string{`{"type": 1}`, `{"pattern": "("}`, `not json`} {
+		if _, err := Compile([]byte(doc)); err == nil {
+			t.Errorf("Compile(%s) should fail", doc)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package resume tracks the position of a stream that can be re-subscribed
+// after a dropped connection: the resume token to send when re-subscribing,
+// and the recently delivered items, so that items the server replays after
+// resuming are not delivered twice.
+package resume
+
+import "sync"
+
+// DefaultWindow is how many delivered items a Cursor remembers.
+const DefaultWindow = 1024
+
+// Cursor is the position of a resumable stream. It is safe for concurrent
+// use.
+type Cursor struct {
+	mu     sync.Mutex
+	token  string
+	window int
+	seen   map[string]struct{}
+	order  []string
+}
+
+// NewCursor returns a cursor starting at token, which may be empty to start
+// from the live position, remembering the last window delivered items
+// (DefaultWindow if window is 0 or less).
+func NewCursor(token string, window int) *Cursor {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Cursor{token: token, window: window, seen: make(map[string]struct{}, window)}
+}
+
+// Token returns the token to resume from, or "" if nothing has been
+// delivered.
+func (c *Cursor) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// SetToken moves the cursor to a token provided by the server, without
+// marking an item delivered.
+func (c *Cursor) SetToken(token string) {
+	if token == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// Advance records the delivery of the item identified by id and moves the
+// cursor to token. It reports false, leaving the cursor as is, if the item
+// was delivered before and should be dropped. An empty id is always
+// delivered.
+func (c *Cursor) Advance(id, token string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id != "" {
+		if _, dup := c.seen[id]; dup {
+			return false
+		}
+		if len(c.order) == c.window {
+			delete(c.seen, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.seen[id] = struct{}{}
+		c.order = append(c.order, id)
+	}
+	if token != "" {
+		c.token = token
+	}
+	return true
+}
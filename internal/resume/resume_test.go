@@ -0,0 +1,37 @@
+package resume
+
+import "testing"
+
+func TestCursor(t *testing.T) {
+	c := NewCursor("start", 2)
+	if got := c.Token(); got != "start" {
+		t.Fatalf("Token() = %q, want start", got)
+	}
+
+	if !c.Advance("a", "1") || !c.Advance("b", "2") {
+		t.Fatal("first deliveries were dropped")
+	}
+	if c.Advance("a", "9") {
+		t.Error("replayed item a was delivered twice")
+	}
+	if got := c.Token(); got != "2" {
+		t.Errorf("Token() = %q after a duplicate, want 2", got)
+	}
+
+	// The window forgets the oldest item
+	if !c.Advance("c", "3") {
+		t.Fatal("item c was dropped")
+	}
+	if !c.Advance("a", "4") {
+		t.Error("item a is outside the window and should be delivered")
+	}
+
+	if !c.Advance("", "") || !c.Advance("", "") {
+		t.Error("items without an ID should always be delivered")
+	}
+	c.SetToken("")
+	c.SetToken("server")
+	if got := c.Token(); got != "server" {
+		t.Errorf("Token() = %q, want server", got)
+	}
+}
@@ -0,0 +1,235 @@
+package spooled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// BootstrapSpec describes everything a new tenant needs.
+type BootstrapSpec struct {
+	// Organization is created if no organization with the same slug exists.
+	// Leave empty to provision into the organization the client is authenticated for.
+	Organization *resources.CreateOrganizationRequest
+	// OrganizationAPIKey authenticates as Organization when it already exists,
+	// e.g. the OrganizationKey of the run that created it. It is required to
+	// re-run Bootstrap, so that nothing is provisioned into the caller's own
+	// organization.
+	OrganizationAPIKey string
+	// APIKeys are created unless a key with the same name already exists.
+	APIKeys []resources.CreateAPIKeyRequest
+	// Queues are created or updated to match the given configuration.
+	Queues []BootstrapQueue
+	// Schedules are created unless a schedule with the same name exists on the queue.
+	Schedules []resources.CreateScheduleRequest
+	// Webhooks are created unless a webhook with the same name already exists.
+	Webhooks []resources.CreateOutgoingWebhookRequest
+}
+
+// ErrOrganizationKeyRequired is returned by Bootstrap when the spec's
+// organization already exists and BootstrapSpec.OrganizationAPIKey is not
+// set, or does not belong to it.
+var ErrOrganizationKeyRequired = errors.New("bootstrap: the organization exists; an API key for it is required")
+
+// BootstrapQueue is a queue to provision during bootstrap.
+type BootstrapQueue struct {
+	Name   string
+	Config resources.UpdateQueueConfigRequest
+}
+
+// BootstrapResult reports what Bootstrap created or found.
+type BootstrapResult struct {
+	Organization *resources.Organization
+	// OrganizationCreated is true if the organization was created by this run.
+	OrganizationCreated bool
+	// OrganizationKey is the initial API key of a newly created organization.
+	OrganizationKey *resources.CreateAPIKeyResponse
+	// APIKeys contains newly created keys. Raw keys are only available here.
+	APIKeys   []resources.CreateAPIKeyResponse
+	Queues    []resources.QueueConfig
+	Schedules []resources.Schedule
+	Webhooks  []resources.OutgoingWebhook
+	// Skipped lists resources that already existed, e.g. "api_key:ci".
+	Skipped []string
+}
+
+// Bootstrap provisions an organization with API keys, queues, schedules, and
+// webhooks in that order. It is safe to re-run: existing resources are matched
+// by name and left untouched (queues are updated to the given configuration).
+//
+// With an Organization, everything else is provisioned with an API key for
+// that organization: the key created with it, or OrganizationAPIKey when it
+// already exists. Without one, Bootstrap returns ErrOrganizationKeyRequired
+// rather than provision into the caller's organization.
+//
+// Failures after the organization step do not stop the run; all errors are
+// collected and returned together with the partial result.
+//
+// Example:
+//
+//	result, err := spooled.Bootstrap(ctx, client, spooled.BootstrapSpec{
+//		Organization: &resources.CreateOrganizationRequest{Name: "Acme", Slug: "acme"},
+//		Queues:       []spooled.BootstrapQueue{{Name: "emails"}},
+//	})
+func Bootstrap(ctx context.Context, client *Client, spec BootstrapSpec) (*BootstrapResult, error) {
+	result := &BootstrapResult{}
+	target := client
+
+	if spec.Organization != nil {
+		org, created, err := bootstrapOrganization(ctx, client, spec.Organization)
+		if err != nil {
+			return result, fmt.Errorf("bootstrap organization %q: %w", spec.Organization.Slug, err)
+		}
+		result.Organization = &org.Organization
+		result.OrganizationCreated = created
+		// Provision the rest with a key for the organization, never the
+		// caller's
+		key := spec.OrganizationAPIKey
+		if created {
+			result.OrganizationKey = &org.APIKey
+			key = org.APIKey.Key
+		}
+		if key == "" {
+			return result, fmt.Errorf("bootstrap organization %q: %w", spec.Organization.Slug, ErrOrganizationKeyRequired)
+		}
+		orgClient, err := client.withAPIKey(key)
+		if err != nil {
+			return result, fmt.Errorf("bootstrap organization %q: %w", spec.Organization.Slug, err)
+		}
+		defer orgClient.Close()
+		if !created {
+			if err := checkOrganizationKey(ctx, orgClient, org.Organization.ID); err != nil {
+				return result, fmt.Errorf("bootstrap organization %q: %w", spec.Organization.Slug, err)
+			}
+		}
+		target = orgClient
+	}
+
+	var errs []error
+
+	if len(spec.APIKeys) > 0 {
+		existing, err := target.APIKeys().List(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list api keys: %w", err))
+		} else {
+			names := make(map[string]bool, len(existing))
+			for _, k := range existing {
+				names[k.Name] = true
+			}
+			for i := range spec.APIKeys {
+				req := spec.APIKeys[i]
+				if names[req.Name] {
+					result.Skipped = append(result.Skipped, "api_key:"+req.Name)
+					continue
+				}
+				key, err := target.APIKeys().Create(ctx, &req)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("create api key %q: %w", req.Name, err))
+					continue
+				}
+				result.APIKeys = append(result.APIKeys, *key)
+			}
+		}
+	}
+
+	for i := range spec.Queues {
+		q := spec.Queues[i]
+		queue, err := target.Queues().UpdateConfig(ctx, q.Name, &q.Config)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provision queue %q: %w", q.Name, err))
+			continue
+		}
+		result.Queues = append(result.Queues, *queue)
+	}
+
+	schedulesByQueue := make(map[string]map[string]bool)
+	for i := range spec.Schedules {
+		req := spec.Schedules[i]
+		names, ok := schedulesByQueue[req.QueueName]
+		if !ok {
+			queueName := req.QueueName
+			existing, err := target.Schedules().List(ctx, &resources.ListSchedulesParams{QueueName: &queueName})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("list schedules for queue %q: %w", req.QueueName, err))
+				continue
+			}
+			names = make(map[string]bool, len(existing))
+			for _, s := range existing {
+				names[s.Name] = true
+			}
+			schedulesByQueue[req.QueueName] = names
+		}
+		if names[req.Name] {
+			result.Skipped = append(result.Skipped, "schedule:"+req.Name)
+			continue
+		}
+		schedule, err := target.Schedules().Create(ctx, &req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("create schedule %q: %w", req.Name, err))
+			continue
+		}
+		names[req.Name] = true
+		result.Schedules = append(result.Schedules, *schedule)
+	}
+
+	if len(spec.Webhooks) > 0 {
+		existing, err := target.Webhooks().List(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list webhooks: %w", err))
+		} else {
+			names := make(map[string]bool, len(existing))
+			for _, w := range existing {
+				names[w.Name] = true
+			}
+			for i := range spec.Webhooks {
+				req := spec.Webhooks[i]
+				if names[req.Name] {
+					result.Skipped = append(result.Skipped, "webhook:"+req.Name)
+					continue
+				}
+				webhook, err := target.Webhooks().Create(ctx, &req)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("create webhook %q: %w", req.Name, err))
+					continue
+				}
+				result.Webhooks = append(result.Webhooks, *webhook)
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// bootstrapOrganization finds an organization by slug or creates it.
+func bootstrapOrganization(ctx context.Context, client *Client, req *resources.CreateOrganizationRequest) (*resources.CreateOrganizationResponse, bool, error) {
+	orgs, err := client.Organizations().List(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, org := range orgs {
+		if org.Slug == req.Slug {
+			return &resources.CreateOrganizationResponse{Organization: org}, false, nil
+		}
+	}
+
+	created, err := client.Organizations().Create(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	return created, true, nil
+}
+
+// checkOrganizationKey verifies that client's key belongs to the organization
+// with the given ID.
+func checkOrganizationKey(ctx context.Context, client *Client, orgID string) error {
+	me, err := client.Auth().Me(ctx)
+	if err != nil {
+		return err
+	}
+	if me.OrganizationID != orgID {
+		return ErrOrganizationKeyRequired
+	}
+	return nil
+}
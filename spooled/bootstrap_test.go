@@ -0,0 +1,92 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestBootstrap_RerunProvisionsIntoOrganization(t *testing.T) {
+	const callerKey = testAPIKey
+	const tenantKey = "sp_test_tenant00000000000000000000000000"
+	var mu sync.Mutex
+	var orgCreated bool
+	queueKeys := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/organizations" && r.Method == http.MethodGet:
+			orgs := []map[string]any{}
+			if key == callerKey {
+				orgs = append(orgs, map[string]any{"id": "org-caller", "slug": "caller"})
+			}
+			if orgCreated {
+				orgs = append(orgs, map[string]any{"id": "org-acme", "slug": "acme"})
+			}
+			_ = json.NewEncoder(w).Encode(orgs)
+		case r.URL.Path == "/api/v1/auth/me":
+			org := "org-caller"
+			if key == tenantKey {
+				org = "org-acme"
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"organization_id": org})
+		case r.URL.Path == "/api/v1/organizations" && r.Method == http.MethodPost:
+			orgCreated = true
+			_, _ = w.Write([]byte(`{"organization":{"id":"org-acme","slug":"acme"},"api_key":{"id":"key-1","key":"` + tenantKey + `"}}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/queues/") && r.Method == http.MethodPut:
+			queueKeys[key] = append(queueKeys[key], strings.TrimPrefix(r.URL.Path, "/api/v1/queues/"))
+			_, _ = w.Write([]byte(`{"queue_name":"emails"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey(callerKey), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	spec := BootstrapSpec{
+		Organization: &resources.CreateOrganizationRequest{Name: "Acme", Slug: "acme"},
+		Queues:       []BootstrapQueue{{Name: "emails"}},
+	}
+
+	first, err := Bootstrap(ctx, client, spec)
+	if err != nil || !first.OrganizationCreated {
+		t.Fatalf("first Bootstrap = %+v, %v", first, err)
+	}
+
+	// Re-running without a key for the existing organization must not fall
+	// back to the caller's
+	if _, err := Bootstrap(ctx, client, spec); !errors.Is(err, ErrOrganizationKeyRequired) {
+		t.Fatalf("re-run without a key: err = %v, want ErrOrganizationKeyRequired", err)
+	}
+	spec.OrganizationAPIKey = callerKey
+	if _, err := Bootstrap(ctx, client, spec); !errors.Is(err, ErrOrganizationKeyRequired) {
+		t.Errorf("re-run with a key for another organization: err = %v, want ErrOrganizationKeyRequired", err)
+	}
+
+	spec.OrganizationAPIKey = first.OrganizationKey.Key
+	second, err := Bootstrap(ctx, client, spec)
+	if err != nil || second.OrganizationCreated || second.Organization.ID != "org-acme" {
+		t.Fatalf("second Bootstrap = %+v, %v", second, err)
+	}
+	if got := queueKeys[tenantKey]; len(got) != 2 {
+		t.Errorf("queues provisioned with the tenant key = %v, want one per run", got)
+	}
+	if got := queueKeys[callerKey]; len(got) != 0 {
+		t.Errorf("queues provisioned with the caller's key = %v, want none", got)
+	}
+}
@@ -2,13 +2,17 @@ package spooled
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
 	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
 	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
 	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
 )
 
@@ -20,26 +24,75 @@ type SpooledWorkerOptions struct {
 	Concurrency int
 	// PollInterval is how often to poll for new jobs (default: 1s).
 	PollInterval time.Duration
+	// Streaming receives jobs over a gRPC stream instead of polling. It needs
+	// the gRPC transport (WithPreferredTransport); the worker polls otherwise.
+	// See worker.Options.
+	Streaming bool
 	// LeaseDuration is the job lease duration in seconds (default: 30).
 	LeaseDuration int
 	// Hostname is the worker hostname (default: auto-detected).
 	Hostname string
+	// WorkerID is the identity to register as. Required with keys from
+	// APIKeys().CreateWorkerKey, which can only act as their own worker.
+	WorkerID string
 	// WorkerType identifies the type of worker (default: "go").
 	WorkerType string
 	// Version is the worker version (default: SDK version).
 	Version string
 	// Metadata contains additional worker metadata.
 	Metadata map[string]string
+	// ResultValidator checks job results before completion; an error fails the job.
+	ResultValidator func(result map[string]any) error
+	// EventTriggeredPolling claims jobs as soon as job.created events arrive instead
+	// of waiting for PollInterval. Uses Realtime, or the parent client's shared
+	// realtime connection if Realtime is nil.
+	EventTriggeredPolling bool
+	// Realtime is a realtime client used to wake the worker on new jobs (optional).
+	// Setting it enables event-triggered polling.
+	Realtime realtime.RealtimeClient
+	// ReportProcessMetrics adds memory, CPU, goroutine, and active job counts to
+	// worker heartbeats so the dashboard shows resource pressure.
+	ReportProcessMetrics bool
+	// AckMode controls when jobs are completed: worker.AckOnSuccess (default,
+	// at-least-once) or worker.AckEarly (at-most-once).
+	AckMode worker.AckMode
+	// ExitWhenIdle stops the worker once the queue has stayed empty this long
+	// (default: run until Stop). See RunUntilDrained.
+	ExitWhenIdle time.Duration
+	// SlowJobThreshold and SlowJobPercentile emit worker.job_slow events for
+	// long-running jobs. See worker.Options.
+	SlowJobThreshold  time.Duration
+	SlowJobPercentile float64
+	// StatsWindow is the rolling window Stats reports on (default:
+	// worker.DefaultStatsWindow).
+	StatsWindow time.Duration
+	// ValidateOnStart makes Start run Validate first and return its
+	// *WorkerConfigError instead of starting a misconfigured worker.
+	ValidateOnStart bool
+	// ExpectedJobDuration is how long jobs typically take to process
+	// (optional). Validate reports a LeaseDuration shorter than it.
+	ExpectedJobDuration time.Duration
+	// CanaryFraction makes the worker claim only this fraction (0-1) of the
+	// queue's jobs, to roll out a new handler version. See worker.Options.
+	CanaryFraction float64
+	// OnStart and OnStop run before the worker starts claiming jobs and after
+	// it has drained on shutdown. See worker.Options.
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
 	// handler is the job handler function (internal)
 	handler func(context.Context, *resources.Job) (any, error)
 }
 
 // SpooledWorker is a high-level worker for processing jobs.
 type SpooledWorker struct {
+	client  *Client
 	jobs    *resources.JobsResource
 	workers *resources.WorkersResource
 	opts    SpooledWorkerOptions
 	worker  *worker.Worker
+	events  *sdkevents.Bus
+	// handlers counts Process calls, so Validate can report duplicates.
+	handlers int
 }
 
 // Start starts the worker.
@@ -47,6 +100,11 @@ func (w *SpooledWorker) Start() error {
 	if w.worker != nil {
 		return nil // Already started
 	}
+	if w.opts.ValidateOnStart {
+		if err := w.Validate(context.Background()); err != nil {
+			return err
+		}
+	}
 
 	// Set defaults
 	opts := w.opts
@@ -76,19 +134,51 @@ func (w *SpooledWorker) Start() error {
 		opts.Metadata = make(map[string]string)
 	}
 
+	if config, ok := w.client.cfg.QueueConfigs[opts.QueueName]; ok {
+		if _, err := w.client.Queues().Ensure(context.Background(), opts.QueueName, &config); err != nil {
+			return fmt.Errorf("failed to ensure queue %q: %w", opts.QueueName, err)
+		}
+	}
+
 	// Create low-level worker
 	workerOpts := worker.Options{
-		QueueName:     opts.QueueName,
-		Concurrency:   opts.Concurrency,
-		PollInterval:  opts.PollInterval,
-		LeaseDuration: opts.LeaseDuration,
-		Hostname:      opts.Hostname,
-		WorkerType:    opts.WorkerType,
-		Version:       opts.Version,
-		Metadata:      opts.Metadata,
+		QueueName:            w.client.queueName(opts.QueueName),
+		Concurrency:          opts.Concurrency,
+		PollInterval:         opts.PollInterval,
+		Streaming:            opts.Streaming,
+		LeaseDuration:        opts.LeaseDuration,
+		Hostname:             opts.Hostname,
+		WorkerID:             opts.WorkerID,
+		WorkerType:           opts.WorkerType,
+		Version:              opts.Version,
+		Metadata:             opts.Metadata,
+		Events:               w.events,
+		ResultValidator:      opts.ResultValidator,
+		TracePropagator:      w.client.tracePropagator(),
+		ReportProcessMetrics: opts.ReportProcessMetrics,
+		AckMode:              opts.AckMode,
+		ExitWhenIdle:         opts.ExitWhenIdle,
+		SchemaRegistry:       w.client.schemaRegistry,
+		SlowJobThreshold:     opts.SlowJobThreshold,
+		SlowJobPercentile:    opts.SlowJobPercentile,
+		StatsWindow:          opts.StatsWindow,
+		CanaryFraction:       opts.CanaryFraction,
+		OnStart:              opts.OnStart,
+		OnStop:               opts.OnStop,
+	}
+	if opts.Realtime != nil || opts.EventTriggeredPolling {
+		workerOpts.EventTriggeredPolling = true
+		workerOpts.Realtime = opts.Realtime
+		if workerOpts.Realtime == nil {
+			workerOpts.Realtime = w.client.Realtime()
+		}
 	}
 
-	w.worker = worker.NewWorker(w.jobs, w.workers, workerOpts)
+	backend, err := w.client.Transport()
+	if err != nil {
+		return fmt.Errorf("failed to create transport: %w", err)
+	}
+	w.worker = worker.NewWorkerWithBackend(resolvedQueueBackend(backend), workerOpts)
 
 	// Set handler if provided
 	if w.opts.handler != nil {
@@ -110,7 +200,13 @@ func (w *SpooledWorker) Start() error {
 		})
 	}
 
-	return w.worker.Start(context.Background())
+	if err := w.worker.Start(context.Background()); err != nil {
+		// Allow Start to be retried
+		w.worker = nil
+		return err
+	}
+	w.client.trackWorker(w)
+	return nil
 }
 
 // Stop stops the worker.
@@ -118,9 +214,52 @@ func (w *SpooledWorker) Stop() error {
 	if w.worker == nil {
 		return nil
 	}
+	w.client.untrackWorker(w)
 	return w.worker.Stop()
 }
 
+// RunUntilDrained starts the worker, processes jobs until the queue has stayed
+// empty for ExitWhenIdle (worker.DefaultExitWhenIdle if unset), then stops and
+// returns the run summary. Cancelling ctx stops the worker early. It suits
+// cron-triggered or serverless batch workers that should not run forever.
+func (w *SpooledWorker) RunUntilDrained(ctx context.Context) (worker.RunSummary, error) {
+	if w.opts.ExitWhenIdle <= 0 {
+		w.opts.ExitWhenIdle = worker.DefaultExitWhenIdle
+	}
+	if err := w.Start(); err != nil {
+		return worker.RunSummary{}, err
+	}
+
+	select {
+	case <-w.worker.Done():
+		w.client.untrackWorker(w)
+	case <-ctx.Done():
+		if err := w.Stop(); err != nil {
+			return w.Summary(), err
+		}
+	}
+	return w.Summary(), w.worker.Err()
+}
+
+// Summary returns the worker's run report: jobs processed and failed, average
+// duration, claims, poll errors, and uptime. Call it after Stop for an
+// end-of-run report; it is also emitted as a worker.summary event.
+func (w *SpooledWorker) Summary() worker.RunSummary {
+	if w.worker == nil {
+		return worker.RunSummary{}
+	}
+	return w.worker.Summary()
+}
+
+// Stats returns the worker's rolling throughput, latency percentiles, and
+// error rate, overall and per queue.
+func (w *SpooledWorker) Stats() worker.Stats {
+	if w.worker == nil {
+		return worker.Stats{}
+	}
+	return w.worker.Stats()
+}
+
 // Process registers a job handler function.
 func (w *SpooledWorker) Process(handler func(context.Context, *resources.Job) (any, error)) {
 	if w.worker != nil {
@@ -128,12 +267,14 @@ func (w *SpooledWorker) Process(handler func(context.Context, *resources.Job) (a
 	}
 	// Store handler for when worker is created
 	w.opts.handler = handler
+	w.handlers++
 }
 
 // Client is the main Spooled SDK client.
 type Client struct {
 	cfg       *Config
 	transport *httpx.Transport
+	events    *sdkevents.Bus
 	mu        sync.RWMutex
 	closed    bool
 
@@ -153,15 +294,39 @@ type Client struct {
 	auth          *resources.AuthResource
 	admin         *resources.AdminResource
 	ingest        *resources.IngestResource
+	reports       *resources.ReportsResource
+
+	enqueueGuard   *resources.EnqueueGuard
+	schemaRegistry *resources.SchemaRegistry
+	policies       *resources.PolicyRegistry
 
 	// Lazy-loaded clients
-	grpcClient *grpc.Client
+	grpcClient     *grpc.Client
+	realtimeClient *realtime.WebSocketClient
+
+	// deadletterWatchers counts the OnDeadletter watchers of each queue,
+	// which share one filter on the realtime connection.
+	deadletterWatchers map[string]int
+
+	// runningWorkers are the started workers reported by DebugState.
+	runningWorkers map[*SpooledWorker]struct{}
+
+	// stopCredentials stops watching the credential provider.
+	stopCredentials context.CancelFunc
+	// stopSpool stops replaying the offline spool.
+	stopSpool context.CancelFunc
 }
 
 // NewClient creates a new Spooled client with the given options.
 func NewClient(opts ...Option) (*Client, error) {
 	cfg := resolveConfig(opts...)
 
+	if cfg.CredentialProvider != nil && cfg.APIKey == "" {
+		if err := loadCredentials(cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate configuration
 	if cfg.APIKey == "" && cfg.AccessToken == "" {
 		return nil, ErrNoAuth
@@ -171,6 +336,14 @@ func NewClient(opts ...Option) (*Client, error) {
 			return nil, err
 		}
 	}
+	if cfg.APIVersion != "" && !apiVersionPattern.MatchString(cfg.APIVersion) {
+		return nil, fmt.Errorf("invalid API version %q: expected YYYY-MM", cfg.APIVersion)
+	}
+
+	events := sdkevents.NewBus()
+	if cfg.Logger != nil {
+		events.SetLogger(cfg.Logger.Debug)
+	}
 
 	// Create transport
 	transport := httpx.NewTransport(httpx.Config{
@@ -196,20 +369,117 @@ func NewClient(opts ...Option) (*Client, error) {
 			SuccessThreshold: cfg.CircuitBreaker.SuccessThreshold,
 			Timeout:          cfg.CircuitBreaker.Timeout,
 		},
-		Logger: wrapLogger(cfg.Logger),
+		Logger:             wrapLogger(cfg.Logger),
+		Events:             transportEvents(events),
+		QueuePrefix:        cfg.QueuePrefix,
+		Codec:              cfg.Codec,
+		ClockSkewThreshold: cfg.ClockSkewThreshold,
+		FallbackBaseURLs:   cfg.FallbackBaseURLs,
+		Failover:           cfg.Failover,
+		CoalesceGETs:       cfg.CoalesceGETs,
+		StrictDeprecations: cfg.StrictDeprecations,
+		UseNumber:          cfg.UseNumber,
+		Limiter:            rateLimiter(cfg),
+		APIVersion:         cfg.APIVersion,
+
+		CompressionThreshold: cfg.PayloadCompressionThreshold,
+		HTTPClient:           cfg.HTTPClient,
+		LegacyFieldNames:     cfg.LegacyFieldNames,
 	})
 
 	c := &Client{
 		cfg:       cfg,
 		transport: transport,
+		events:    events,
 	}
 
 	// Initialize resources
 	c.initResources()
 
+	if cfg.OfflineSpoolDir != "" {
+		spool, err := resources.NewOfflineSpool(cfg.OfflineSpoolDir)
+		if err != nil {
+			return nil, err
+		}
+		c.jobs.SetOfflineSpool(spool)
+		c.replaySpool()
+	}
+	if cfg.CredentialProvider != nil {
+		c.watchCredentials()
+	}
+
 	return c, nil
 }
 
+// withAPIKey returns a new client with the same configuration but
+// authenticated with the given API key.
+func (c *Client) withAPIKey(key string) (*Client, error) {
+	c.mu.RLock()
+	cfg := *c.cfg
+	cfg.Headers = make(map[string]string, len(c.cfg.Headers))
+	for k, v := range c.cfg.Headers {
+		cfg.Headers[k] = v
+	}
+	c.mu.RUnlock()
+	cfg.APIKey = key
+	cfg.AccessToken = ""
+	cfg.RefreshToken = ""
+	cfg.CredentialProvider = nil
+	cfg.OfflineSpoolDir = ""
+
+	return NewClient(func(c *Config) { *c = cfg })
+}
+
+// queueName applies the configured queue prefix to name.
+func (c *Client) queueName(name string) string {
+	prefix := c.cfg.QueuePrefix
+	if prefix == "" || name == "" {
+		return name
+	}
+	return prefix + name
+}
+
+// contextQueueName is queueName for a call made with ctx, which bypasses the
+// prefix if it came from WithoutQueuePrefix.
+func (c *Client) contextQueueName(ctx context.Context, name string) string {
+	if resources.RawQueueNames(ctx) {
+		return name
+	}
+	return c.queueName(name)
+}
+
+// WithoutQueuePrefix returns a context that bypasses the client's queue prefix
+// for calls made with it, e.g. to reach a queue shared by all environments.
+func WithoutQueuePrefix(ctx context.Context) context.Context {
+	return resources.WithRawQueueNames(ctx)
+}
+
+// WithIdempotencyKey returns a context that sends key as the Idempotency-Key
+// of mutating calls made with it, so the server applies the call at most once
+// and the client can safely retry it. Use each key for a single call:
+//
+//	ctx = spooled.WithIdempotencyKey(ctx, "create-webhook:"+orderID)
+//	webhook, err := client.Webhooks().Create(ctx, req)
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return resources.WithIdempotencyKey(ctx, key)
+}
+
+// debug logs a debug message if a logger is configured.
+func (c *Client) debug(msg string, keysAndValues ...any) {
+	if c.cfg.Logger != nil {
+		c.cfg.Logger.Debug(msg, keysAndValues...)
+	}
+}
+
+// rateLimiter combines the local rate limit, if any, with the shared limiter.
+func rateLimiter(cfg *Config) httpx.Limiter {
+	var local httpx.Limiter
+	if cfg.RateLimit > 0 {
+		local = httpx.NewTokenBucket(cfg.RateLimit, cfg.RateLimitBurst)
+	}
+	return httpx.ChainLimiters(local, cfg.SharedLimiter)
+}
+
 // wrapLogger wraps a spooled.Logger to an httpx.Logger.
 func wrapLogger(l Logger) httpx.Logger {
 	if l == nil {
@@ -243,6 +513,45 @@ func (c *Client) initResources() {
 	c.auth = resources.NewAuthResource(c.transport)
 	c.admin = resources.NewAdminResource(c.transport)
 	c.ingest = resources.NewIngestResource(c.transport)
+	c.reports = resources.NewReportsResource(c.jobs, c.queues)
+
+	protection := resources.NewProtection(c.cfg.Protection, c.events)
+	c.jobs.SetProtection(protection)
+	c.queues.SetProtection(protection)
+	c.policies = resources.NewPolicyRegistry()
+	c.jobs.SetPolicyRegistry(c.policies)
+	c.jobs.SetMaxPayloadBytes(c.cfg.MaxPayloadBytes)
+	watcher := &realtimeJobWatcher{client: c}
+	c.jobs.SetJobWatcher(watcher)
+	c.workflows.SetJobWatcher(watcher)
+	if p := c.tracePropagator(); p != nil {
+		c.jobs.SetTracePropagator(p)
+	}
+	if c.cfg.PayloadRedactor != nil {
+		c.queues.SetPayloadRedactor(c.cfg.PayloadRedactor)
+	}
+	c.jobs.SetPayloadTransforms(c.cfg.PayloadTransforms)
+	c.workflows.SetPayloadTransforms(c.cfg.PayloadTransforms)
+
+	if c.cfg.EnqueueGuard != nil {
+		c.enqueueGuard = resources.NewEnqueueGuard(c.queues, *c.cfg.EnqueueGuard)
+		c.jobs.SetEnqueueGuard(c.enqueueGuard)
+	}
+	if c.cfg.SchemaRegistryURL != "" {
+		c.schemaRegistry = resources.NewSchemaRegistry(c.cfg.SchemaRegistryURL, nil)
+		c.jobs.SetSchemaRegistry(c.schemaRegistry)
+	}
+}
+
+// tracePropagator returns the propagator to use, or nil if propagation is off.
+func (c *Client) tracePropagator() propagation.Propagator {
+	if !c.cfg.TracePropagation {
+		return nil
+	}
+	if c.cfg.TracePropagator != nil {
+		return c.cfg.TracePropagator
+	}
+	return propagation.W3C{}
 }
 
 // Close closes the client and releases any resources.
@@ -250,6 +559,20 @@ func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.closed = true
+	c.transport.Close()
+	if c.stopCredentials != nil {
+		c.stopCredentials()
+	}
+	if c.stopSpool != nil {
+		c.stopSpool()
+	}
+	if c.grpcClient != nil {
+		_ = c.grpcClient.Close()
+		c.grpcClient = nil
+	}
+	if c.realtimeClient != nil {
+		return c.realtimeClient.Disconnect()
+	}
 	return nil
 }
 
@@ -260,11 +583,101 @@ func (c *Client) GetConfig() Config {
 	return *c.cfg
 }
 
+// OnEvent registers a handler for SDK-level events (retries, circuit breaker
+// transitions, token refreshes, gRPC reconnects, and worker lifecycle).
+//
+// Handlers are called synchronously from the goroutine that produced the event
+// and should return quickly.
+//
+// Example:
+//
+//	client.OnEvent(func(e sdkevents.Event) {
+//		if e.Type == sdkevents.TypeRequestRetry {
+//			data := e.Data.(sdkevents.RequestRetryData)
+//			log.Printf("retrying %s %s (attempt %d)", data.Method, data.Path, data.Attempt)
+//		}
+//	})
+func (c *Client) OnEvent(handler sdkevents.Handler) {
+	c.events.Subscribe(handler)
+}
+
+// transportEvents adapts the REST transport's events to the client's bus.
+func transportEvents(bus *sdkevents.Bus) httpx.EventEmitter {
+	return func(event any) {
+		switch e := event.(type) {
+		case httpx.RetryEvent:
+			bus.Emit(sdkevents.TypeRequestRetry, sdkevents.RequestRetryData{
+				Method:  e.Method,
+				Path:    e.Path,
+				Attempt: e.Attempt,
+				Delay:   e.Delay,
+				Error:   e.Error,
+			})
+		case httpx.CircuitStateChangeEvent:
+			bus.Emit(sdkevents.TypeCircuitStateChange, sdkevents.CircuitStateChangeData{
+				From: e.From.String(),
+				To:   e.To.String(),
+			})
+		case httpx.TokenRefreshEvent:
+			eventType := sdkevents.TypeTokenRefreshed
+			if e.Error != nil {
+				eventType = sdkevents.TypeTokenRefreshFailed
+			}
+			bus.Emit(eventType, sdkevents.TokenRefreshData{
+				Method:    e.Method,
+				ExpiresIn: e.ExpiresIn,
+				Error:     e.Error,
+			})
+		case httpx.EndpointFailoverEvent:
+			bus.Emit(sdkevents.TypeEndpointFailover, sdkevents.EndpointFailoverData{From: e.From, To: e.To})
+		case httpx.ClockSkewEvent:
+			bus.Emit(sdkevents.TypeClockSkewDetected, sdkevents.ClockSkewData{Skew: e.Skew, Threshold: e.Threshold})
+		case httpx.DeprecationWarning:
+			data := sdkevents.DeprecationData{
+				Method:  e.Method,
+				Path:    e.Path,
+				Link:    e.Link,
+				Removed: e.Removed,
+			}
+			if e.Sunset != nil {
+				data.Sunset = *e.Sunset
+			}
+			bus.Emit(sdkevents.TypeDeprecationWarning, data)
+		case httpx.APIVersionMismatchEvent:
+			bus.Emit(sdkevents.TypeAPIVersionMismatch, sdkevents.APIVersionMismatchData{
+				Requested: e.Requested,
+				Served:    e.Served,
+			})
+		}
+	}
+}
+
+// OnTokenRefreshed registers a callback invoked with the new access and
+// refresh tokens after every successful automatic token refresh, so rotated
+// tokens can be persisted. Concurrent requests share a single refresh, so fn
+// is called once per refresh. It is a no-op unless the client was created
+// with an access or refresh token and automatic refresh enabled.
+//
+// Example:
+//
+//	client.OnTokenRefreshed(func(access, refresh string) {
+//		store.Save(access, refresh)
+//	})
+func (c *Client) OnTokenRefreshed(fn func(accessToken, refreshToken string)) {
+	c.transport.OnTokenRefreshed(fn)
+}
+
 // Jobs returns the Jobs resource.
 func (c *Client) Jobs() *resources.JobsResource {
 	return c.jobs
 }
 
+// Policies returns the registry of retry and timeout policies applied to
+// jobs by their payload's "type" field.
+func (c *Client) Policies() *resources.PolicyRegistry {
+	return c.policies
+}
+
 // Queues returns the Queues resource.
 func (c *Client) Queues() *resources.QueuesResource {
 	return c.queues
@@ -335,6 +748,11 @@ func (c *Client) Ingest() *resources.IngestResource {
 	return c.ingest
 }
 
+// Reports returns the Reports resource.
+func (c *Client) Reports() *resources.ReportsResource {
+	return c.reports
+}
+
 // GRPC returns the gRPC client for high-performance operations.
 //
 // Note: This method dials the gRPC server the first time it is called.
@@ -348,9 +766,20 @@ func (c *Client) GRPC() (*grpc.Client, error) {
 	}
 
 	grpcClient, err := grpc.NewClient(grpc.ClientOptions{
-		Address: c.cfg.GRPCAddress,
-		APIKey:  c.cfg.APIKey,
-		Timeout: 5 * time.Second,
+		Address:           c.cfg.GRPCAddress,
+		APIKey:            c.cfg.APIKey,
+		Timeout:           5 * time.Second,
+		Events:            c.events,
+		UserAgent:         c.cfg.UserAgent,
+		Metadata:          c.grpcMetadata(),
+		FallbackAddresses: c.cfg.FallbackGRPCAddresses,
+		UseNumber:         c.cfg.UseNumber,
+		MaxRecvMsgSize:    c.cfg.GRPCMaxRecvMsgSize,
+		MaxSendMsgSize:    c.cfg.GRPCMaxSendMsgSize,
+		IdleTimeout:       c.cfg.GRPCIdleTimeout,
+		MaxPayloadBytes:   c.cfg.MaxPayloadBytes,
+
+		CompressionThreshold: c.cfg.PayloadCompressionThreshold,
 	})
 	if err != nil {
 		return nil, err
@@ -360,14 +789,93 @@ func (c *Client) GRPC() (*grpc.Client, error) {
 	return c.grpcClient, nil
 }
 
-// Realtime returns a realtime client for WebSocket/SSE event streaming.
-// TODO: Implement realtime client
-// func (c *Client) Realtime(opts ...realtime.Option) *realtime.Client {
-// 	return realtime.NewClient(realtime.Config{
-// 		BaseURL: c.cfg.BaseURL,
-// 		APIKey:  c.cfg.APIKey,
-// 	}, opts...)
-// }
+// CloseGRPC closes the gRPC connection, if one is open. The next call to GRPC
+// or Transport dials a new one; gRPC clients and transports obtained earlier
+// must not be used afterwards.
+//
+// Idle connections are already torn down after WithGRPCIdleTimeout, so this is
+// only needed to release the connection immediately.
+func (c *Client) CloseGRPC() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.grpcClient == nil {
+		return nil
+	}
+	err := c.grpcClient.Close()
+	c.grpcClient = nil
+	return err
+}
+
+// grpcMetadata returns the per-call gRPC metadata derived from the config.
+func (c *Client) grpcMetadata() map[string]string {
+	var md map[string]string
+	if app := c.cfg.appInfo(); app != "" {
+		md = map[string]string{ClientAppHeader: app}
+	}
+	if c.cfg.APIVersion != "" {
+		if md == nil {
+			md = make(map[string]string)
+		}
+		md[APIVersionHeader] = c.cfg.APIVersion
+	}
+	return md
+}
+
+// ClockSkew returns the estimated offset of the server clock from the local
+// clock (server minus local), measured from REST response Date headers.
+func (c *Client) ClockSkew() time.Duration {
+	return c.transport.ClockSkew()
+}
+
+// ServerTime returns the current time adjusted for clock skew.
+// Use it instead of time.Now when computing ScheduledAt values.
+func (c *Client) ServerTime() time.Time {
+	return c.transport.Now()
+}
+
+// DeprecationWarning describes an endpoint the API marked as deprecated or removed.
+type DeprecationWarning = httpx.DeprecationWarning
+
+// DeprecationError is returned under WithStrictDeprecations when a response
+// marks the endpoint as deprecated.
+type DeprecationError = httpx.DeprecationError
+
+// GoneError is returned when an endpoint has been removed (HTTP 410).
+type GoneError = httpx.GoneError
+
+// PlanLimitError is returned when the organization's plan does not include an
+// operation. RequiredTier names the plan to upgrade to, when known.
+type PlanLimitError = httpx.PlanLimitError
+
+// DeprecationWarnings returns the deprecated or removed endpoints this client
+// has called, as reported by Deprecation, Sunset, and Link response headers.
+// Each warning is also logged and emitted as an api.deprecation_warning event
+// the first time it is seen.
+func (c *Client) DeprecationWarnings() []DeprecationWarning {
+	return c.transport.DeprecationWarnings()
+}
+
+// Realtime returns the client's shared WebSocket realtime client.
+// It is created on first use and disconnected when the client is closed,
+// so workers and subscribers can share one connection.
+func (c *Client) Realtime() realtime.RealtimeClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.realtimeClient == nil {
+		c.realtimeClient = realtime.NewWebSocketClient(realtime.ConnectionOptions{
+			WSURL:         c.cfg.WSURL + DefaultAPIBasePath + "/ws",
+			Token:         c.transport.AccessToken(),
+			APIKey:        c.cfg.APIKey,
+			AutoReconnect: true,
+			Logger: func(msg string, args ...any) {
+				c.debug(fmt.Sprintf(msg, args...))
+			},
+		})
+	}
+	return c.realtimeClient
+}
 
 // NewSpooledWorker creates a new Spooled worker for processing jobs.
 //
@@ -389,8 +897,22 @@ func (c *Client) GRPC() (*grpc.Client, error) {
 //	}
 func NewSpooledWorker(c *Client, opts SpooledWorkerOptions) *SpooledWorker {
 	return &SpooledWorker{
+		client:  c,
 		jobs:    c.Jobs(),
 		workers: c.Workers(),
 		opts:    opts,
+		events:  c.events,
 	}
 }
+
+// NewQueueResumeWorker creates a worker that resumes queues paused with
+// Queues().PauseUntil or PauseFor on servers without auto-resume support.
+// opts.QueueName is set to resources.QueueResumeQueue. Call Start to run it.
+func NewQueueResumeWorker(c *Client, opts SpooledWorkerOptions) *SpooledWorker {
+	opts.QueueName = resources.QueueResumeQueue
+	w := NewSpooledWorker(c, opts)
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) {
+		return c.Queues().HandleResumeJob(ctx, job)
+	})
+	return w
+}
@@ -1,12 +1,32 @@
 package spooled
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
 )
 
 func TestNewClient_WithAPIKey(t *testing.T) {
 	client, err := NewClient(
-		WithAPIKey("sp_test_123456789012345678901234567890"),
+		WithAPIKey(testAPIKey),
 	)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -14,7 +34,7 @@ func TestNewClient_WithAPIKey(t *testing.T) {
 	defer client.Close()
 
 	cfg := client.GetConfig()
-	if cfg.APIKey != "sp_test_123456789012345678901234567890" {
+	if cfg.APIKey != testAPIKey {
 		t.Errorf("Expected API key to be set")
 	}
 }
@@ -95,7 +115,7 @@ func TestNewClient_AllOptions(t *testing.T) {
 
 func TestNewClient_WSURLDerived(t *testing.T) {
 	client, err := NewClient(
-		WithAPIKey("sp_test_123456789012345678901234567890"),
+		WithAPIKey(testAPIKey),
 		WithBaseURL("https://custom.example.com"),
 	)
 	if err != nil {
@@ -109,9 +129,50 @@ func TestNewClient_WSURLDerived(t *testing.T) {
 	}
 }
 
+func TestNewClient_WithAppInfo(t *testing.T) {
+	client, err := NewClient(
+		WithAPIKey(testAPIKey),
+		WithUserAgent("custom-agent/1.0"),
+		WithAppInfo("billing-service", "2.3.1"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	cfg := client.GetConfig()
+	if cfg.UserAgent != "custom-agent/1.0 billing-service/2.3.1" {
+		t.Errorf("UserAgent = %q, want %q", cfg.UserAgent, "custom-agent/1.0 billing-service/2.3.1")
+	}
+	if got := cfg.Headers[ClientAppHeader]; got != "billing-service/2.3.1" {
+		t.Errorf("Headers[%s] = %q, want %q", ClientAppHeader, got, "billing-service/2.3.1")
+	}
+}
+
+func TestNewClient_WithAPIVersion(t *testing.T) {
+	client, err := NewClient(
+		WithAPIKey(testAPIKey),
+		WithAPIVersion("2024-11"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if got := client.grpcMetadata()[APIVersionHeader]; got != "2024-11" {
+		t.Errorf("gRPC metadata[%s] = %q, want %q", APIVersionHeader, got, "2024-11")
+	}
+
+	for _, version := range []string{"v2", "2024-13", "2024-11-01"} {
+		if _, err := NewClient(WithAPIKey(testAPIKey), WithAPIVersion(version)); err == nil {
+			t.Errorf("Expected error for API version %q", version)
+		}
+	}
+}
+
 func TestNewClient_ResourcesInitialized(t *testing.T) {
 	client, err := NewClient(
-		WithAPIKey("sp_test_123456789012345678901234567890"),
+		WithAPIKey(testAPIKey),
 	)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -164,6 +225,9 @@ func TestNewClient_ResourcesInitialized(t *testing.T) {
 	if client.Ingest() == nil {
 		t.Error("Ingest resource not initialized")
 	}
+	if client.Reports() == nil {
+		t.Error("Reports resource not initialized")
+	}
 }
 
 func TestValidateAPIKey(t *testing.T) {
@@ -173,7 +237,7 @@ func TestValidateAPIKey(t *testing.T) {
 	}{
 		// New prefix keys (sp_)
 		{"sp_live_123456789012345678901234567890", false},
-		{"sp_test_123456789012345678901234567890", false},
+		{testAPIKey, false},
 		// Legacy prefix keys (sk_) - use clearly fake values to avoid GitHub detection
 		{"sk_live_FAKE_TEST_KEY_12345678901234", false},
 		{"sk_test_FAKE_TEST_KEY_12345678901234", false},
@@ -193,3 +257,1227 @@ func TestValidateAPIKey(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClient_WithQueuePrefix(t *testing.T) {
+	var gotQueue, gotPath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotQueue, _ = body["queue_name"].(string)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+	},
+		WithQueuePrefix("staging-"),
+	)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		queue string
+		want  string
+	}{
+		{"prefixed", context.Background(), "emails", "staging-emails"},
+		{"prefix-like name", context.Background(), "staging-emails", "staging-staging-emails"},
+		{"bypassed", WithoutQueuePrefix(context.Background()), "shared", "shared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &resources.CreateJobRequest{QueueName: tt.queue, Payload: map[string]any{}}
+			if _, err := client.Jobs().Create(tt.ctx, req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if gotQueue != tt.want {
+				t.Errorf("queue_name = %q, want %q", gotQueue, tt.want)
+			}
+			if req.QueueName != tt.queue {
+				t.Errorf("request was modified: QueueName = %q", req.QueueName)
+			}
+		})
+	}
+
+	if _, err := client.Queues().Get(context.Background(), "emails"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotPath != "/api/v1/queues/staging-emails" {
+		t.Errorf("path = %q, want %q", gotPath, "/api/v1/queues/staging-emails")
+	}
+}
+
+func TestClient_Transport(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want TransportKind
+	}{
+		{"default", nil, TransportREST},
+		{"rest", []Option{WithPreferredTransport(TransportREST)}, TransportREST},
+		{"grpc", []Option{WithPreferredTransport(TransportGRPC)}, TransportGRPC},
+		{"auto falls back", []Option{WithPreferredTransport(TransportAuto), WithGRPCAddress("127.0.0.1:1")}, TransportREST},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(append([]Option{WithAPIKey(testAPIKey)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer client.Close()
+
+			transport, err := client.Transport()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if transport.Kind() != tt.want {
+				t.Errorf("Kind() = %v, want %v", transport.Kind(), tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CloseGRPC(t *testing.T) {
+	client, err := NewClient(
+		WithAPIKey(testAPIKey),
+		WithGRPCAddress("127.0.0.1:1"),
+		WithGRPCIdleTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.CloseGRPC(); err != nil {
+		t.Errorf("CloseGRPC() without a connection = %v, want nil", err)
+	}
+
+	first, err := client.GRPC()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.CloseGRPC(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := client.GRPC()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first == second {
+		t.Error("GRPC() after CloseGRPC returned the closed client")
+	}
+}
+
+func TestNewClient_WithEnqueueGuard(t *testing.T) {
+	var created int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/queues/busy/stats":
+			_, _ = w.Write([]byte(`{"queue_name":"busy","pending_jobs":10}`))
+		case "/api/v1/queues/idle/stats":
+			_, _ = w.Write([]byte(`{"queue_name":"idle","pending_jobs":1}`))
+		case "/api/v1/jobs":
+			created++
+			_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var spilled []string
+	tests := []struct {
+		name        string
+		action      GuardAction
+		queue       string
+		wantErr     bool
+		wantSpilled bool
+	}{
+		{"under limit", GuardReject, "idle", false, false},
+		{"reject", GuardReject, "busy", true, false},
+		{"spill", GuardSpill, "busy", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, spilled = 0, nil
+			client, err := NewClient(
+				WithAPIKey(testAPIKey),
+				WithBaseURL(server.URL),
+				WithEnqueueGuard(GuardConfig{
+					MaxPendingPerQueue: 5,
+					Action:             tt.action,
+					Spill: func(ctx context.Context, req *resources.CreateJobRequest) error {
+						spilled = append(spilled, req.QueueName)
+						return nil
+					},
+				}),
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer client.Close()
+
+			_, err = client.Jobs().Create(context.Background(), &resources.CreateJobRequest{QueueName: tt.queue, Payload: map[string]any{}})
+			var overloaded *resources.QueueOverloadedError
+			if got := errors.As(err, &overloaded); got != tt.wantErr {
+				t.Fatalf("QueueOverloadedError = %v, want %v (err: %v)", got, tt.wantErr, err)
+			}
+			if tt.wantErr && overloaded.Spilled != tt.wantSpilled {
+				t.Errorf("Spilled = %v, want %v", overloaded.Spilled, tt.wantSpilled)
+			}
+			if (len(spilled) > 0) != tt.wantSpilled {
+				t.Errorf("spilled = %v, want spilled %v", spilled, tt.wantSpilled)
+			}
+			if wantCreated := !tt.wantErr; (created == 1) != wantCreated {
+				t.Errorf("created = %d, want created %v", created, wantCreated)
+			}
+		})
+	}
+}
+
+func TestNewClient_WithTracePropagation(t *testing.T) {
+	var gotTags map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotTags, _ = body["tags"].(map[string]any)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+	},
+		WithTracePropagation(true),
+	)
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := propagation.ContextWithHeaders(context.Background(), map[string]string{"Traceparent": traceparent})
+	req := &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{}, Tags: map[string]any{"env": "test"}}
+	if _, err := client.Jobs().Create(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotTags["env"] != "test" {
+		t.Errorf("tags[env] = %v, want test", gotTags["env"])
+	}
+	if _, ok := req.Tags[propagation.TagKey]; ok {
+		t.Error("request tags were modified")
+	}
+
+	restored := propagation.ExtractTags(context.Background(), propagation.W3C{}, gotTags)
+	if got := propagation.HeadersFromContext(restored)[propagation.TraceparentKey]; got != traceparent {
+		t.Errorf("traceparent = %q, want %q", got, traceparent)
+	}
+}
+
+func TestSpooledWorker_RunUntilDrained(t *testing.T) {
+	var claims, deregistered atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/workers/register":
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"batch"}`))
+		case r.URL.Path == "/api/v1/jobs/claim":
+			claims.Add(1)
+			_, _ = w.Write([]byte(`{"jobs":[]}`))
+		case r.Method == http.MethodDelete:
+			deregistered.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:    "batch",
+		PollInterval: 10 * time.Millisecond,
+		ExitWhenIdle: 50 * time.Millisecond,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	summary, err := w.RunUntilDrained(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("worker did not exit when idle")
+	}
+	if claims.Load() < 2 {
+		t.Errorf("claims = %d, want at least 2", claims.Load())
+	}
+	if deregistered.Load() != 1 {
+		t.Errorf("deregistered = %d, want 1", deregistered.Load())
+	}
+	if summary.WorkerID != "worker-1" || summary.StoppedAt.IsZero() {
+		t.Errorf("summary = %+v, want a stopped run of worker-1", summary)
+	}
+}
+
+func TestSpooledWorker_CanaryStopsWhenServerIgnoresFraction(t *testing.T) {
+	// A server without canary support hands out every job
+	var jobs []map[string]any
+	outOfSample := 0
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("job-%d", i)
+		if !worker.InCanary(id, 0.1) {
+			outOfSample++
+		}
+		jobs = append(jobs, map[string]any{"id": id, "queue_name": "resize", "payload": map[string]any{}})
+	}
+	if outOfSample == 0 {
+		t.Fatal("test batch has no jobs outside the sample")
+	}
+	var claims, completed atomic.Int32
+	var gotFraction atomic.Value
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/workers/register":
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"resize"}`))
+		case r.URL.Path == "/api/v1/jobs/claim":
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			gotFraction.Store(req["canary_fraction"])
+			batch := []map[string]any{}
+			if claims.Add(1) == 1 {
+				batch = jobs
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"jobs": batch})
+		case strings.HasSuffix(r.URL.Path, "/complete"):
+			completed.Add(1)
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	var processed atomic.Int32
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:      "resize",
+		PollInterval:   10 * time.Millisecond,
+		ExitWhenIdle:   time.Second,
+		CanaryFraction: 0.1,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) {
+		processed.Add(1)
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := w.RunUntilDrained(ctx)
+	if !errors.Is(err, worker.ErrCanaryUnsupported) {
+		t.Fatalf("RunUntilDrained() error = %v, want ErrCanaryUnsupported", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("worker did not stop on an out-of-sample claim")
+	}
+	if got := gotFraction.Load(); got != 0.1 {
+		t.Errorf("canary_fraction = %v, want 0.1", got)
+	}
+	if processed.Load() != 0 || completed.Load() != 0 {
+		t.Errorf("processed %d and completed %d jobs, want none from a batch outside the sample", processed.Load(), completed.Load())
+	}
+}
+
+func TestSpooledWorker_LifecycleHooks(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/workers/register":
+			record("register")
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"batch"}`))
+		case r.URL.Path == "/api/v1/jobs/claim":
+			_, _ = w.Write([]byte(`{"jobs":[]}`))
+		case r.Method == http.MethodDelete:
+			record("deregister")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	// A failed warm-up aborts Start before the worker registers.
+	warmErr := errors.New("cache unavailable")
+	failing := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName: "batch",
+		OnStart:   func(ctx context.Context) error { return warmErr },
+	})
+	failing.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+	if err := failing.Start(); !errors.Is(err, warmErr) {
+		t.Fatalf("Start error = %v, want OnStart error", err)
+	}
+	mu.Lock()
+	if len(calls) != 0 {
+		t.Errorf("calls after failed OnStart = %v, want none", calls)
+	}
+	mu.Unlock()
+
+	flushErr := errors.New("flush failed")
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:    "batch",
+		PollInterval: 10 * time.Millisecond,
+		ExitWhenIdle: 30 * time.Millisecond,
+		OnStart: func(ctx context.Context) error {
+			record("start")
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			record("stop")
+			return flushErr
+		},
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := w.RunUntilDrained(ctx); !errors.Is(err, flushErr) {
+		t.Errorf("RunUntilDrained error = %v, want OnStop error", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"start", "register", "stop", "deregister"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSpooledWorker_Canary(t *testing.T) {
+	var mu sync.Mutex
+	var registered, claimed map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/workers/register":
+			registered = body
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"resize"}`))
+		case "/api/v1/jobs/claim":
+			claimed = body
+			_, _ = w.Write([]byte(`{"jobs":[]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:      "resize",
+		PollInterval:   5 * time.Millisecond,
+		ExitWhenIdle:   20 * time.Millisecond,
+		CanaryFraction: 0.1,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	summary, err := w.RunUntilDrained(ctx)
+	if err != nil {
+		t.Fatalf("RunUntilDrained: %v", err)
+	}
+	mu.Lock()
+	metadata, _ := registered["metadata"].(map[string]any)
+	if metadata["canary"] != "true" || metadata["canary_fraction"] != "0.1" {
+		t.Errorf("registration metadata = %v, want canary markers", metadata)
+	}
+	if claimed["canary_fraction"] != 0.1 {
+		t.Errorf("claim request = %v, want canary_fraction 0.1", claimed)
+	}
+	mu.Unlock()
+	if !summary.Canary || !strings.HasSuffix(summary.String(), "canary=true") {
+		t.Errorf("summary = %s, want it marked canary", summary)
+	}
+
+	// Larger samples contain smaller ones, so widening a rollout keeps jobs in it.
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("job-%d", i)
+		if worker.InCanary(id, 0.1) && !worker.InCanary(id, 0.5) {
+			t.Fatalf("%s is in the 10%% sample but not the 50%% one", id)
+		}
+	}
+
+	bad := NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "resize", CanaryFraction: 1.5})
+	bad.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+	if err := bad.Validate(context.Background()); err == nil || !strings.Contains(err.Error(), "CanaryFraction") {
+		t.Errorf("Validate error = %v, want a CanaryFraction problem", err)
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/jobs/job-1" {
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":"rate_limit_exceeded","message":"Too many requests"}`))
+			return
+		}
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"id":"job-2"}`))
+	},
+		WithRetry(RetryConfig{MaxRetries: 0, BaseDelay: time.Millisecond}),
+		WithRateLimit(50, 1),
+	)
+
+	ctx := context.Background()
+
+	_, err := client.Jobs().Get(ctx, "job-1")
+	rl, ok := AsRateLimitError(err)
+	if !ok || !IsRateLimitError(err) {
+		t.Fatalf("err = %T %v, want a rate limit error", err, err)
+	}
+	if rl.Limit != 100 || rl.Remaining != 0 || rl.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("RateLimitError = %+v", rl)
+	}
+
+	// 50 requests/s with no burst headroom spaces requests 20ms apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Jobs().Get(ctx, "job-2"); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("4 requests took %v, want them throttled", elapsed)
+	}
+}
+
+func TestClient_PayloadTransform(t *testing.T) {
+	var mu sync.Mutex
+	var sent []map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		stored := `{"id":"job-1","queue_name":"emails","payload":{"to":"a@example.com","_trace":"x"}}`
+		switch r.URL.Path {
+		case "/api/v1/jobs":
+			var body resources.CreateJobRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			sent = append(sent, body.Payload)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+		case "/api/v1/jobs/bulk":
+			var body resources.BulkEnqueueRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			for _, job := range body.Jobs {
+				sent = append(sent, job.Payload)
+			}
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"total":1,"success_count":1}`))
+		case "/api/v1/jobs/job-1":
+			_, _ = w.Write([]byte(stored))
+		case "/api/v1/jobs/claim":
+			_, _ = w.Write([]byte(`{"jobs":[` + stored + `]}`))
+		case "/api/v1/jobs/dlq":
+			_, _ = w.Write([]byte(`[` + stored + `]`))
+		}
+	},
+		WithPayloadTransform(
+			func(p map[string]any) map[string]any {
+				p["tenant_id"] = "acme"
+				return p
+			},
+			func(p map[string]any) map[string]any {
+				delete(p, "_trace")
+				return p
+			},
+		),
+	)
+
+	ctx := context.Background()
+
+	payload := map[string]any{"to": "a@example.com"}
+	if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: payload}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{
+		QueueName: "emails",
+		Jobs:      []resources.BulkJobItem{{Payload: payload}},
+	}); err != nil {
+		t.Fatalf("BulkEnqueue() error = %v", err)
+	}
+	if len(sent) != 2 || sent[0]["tenant_id"] != "acme" || sent[1]["tenant_id"] != "acme" {
+		t.Errorf("sent payloads %v, want the tenant ID injected", sent)
+	}
+	if _, ok := payload["tenant_id"]; ok {
+		t.Error("outbound transform modified the caller's payload")
+	}
+
+	job, err := client.Jobs().Get(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	claimed, err := client.Jobs().Claim(ctx, &resources.ClaimJobsRequest{QueueName: "emails", WorkerID: "w-1"})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	dead, err := client.Jobs().DLQ().List(ctx, nil)
+	if err != nil {
+		t.Fatalf("DLQ().List() error = %v", err)
+	}
+	for name, p := range map[string]map[string]any{"Get": job.Payload, "Claim": claimed.Jobs[0].Payload, "DLQ": dead[0].Payload} {
+		if _, ok := p["_trace"]; ok || p["to"] != "a@example.com" {
+			t.Errorf("%s payload = %v, want internal fields stripped", name, p)
+		}
+	}
+}
+
+func TestJobs_PayloadCompressionAndLimit(t *testing.T) {
+	var requests atomic.Int32
+	var encodings []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader() error = %v", err)
+				return
+			}
+			body = zr
+		}
+		var got map[string]any
+		if err := json.NewDecoder(body).Decode(&got); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true,"total":1}`))
+	},
+		WithPayloadCompression(512),
+		WithMaxPayloadBytes(4096),
+	)
+
+	ctx := context.Background()
+
+	large := map[string]any{"data": strings.Repeat("a", 2000)}
+	if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "q", Payload: large}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "q", Payload: map[string]any{"n": 1}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{QueueName: "q", Jobs: []resources.BulkJobItem{{Payload: large}}}); err != nil {
+		t.Fatalf("BulkEnqueue() error = %v", err)
+	}
+	if want := []string{"gzip", "", "gzip"}; !slices.Equal(encodings, want) {
+		t.Errorf("Content-Encoding = %q, want %q", encodings, want)
+	}
+
+	// Oversized payloads fail before anything is sent
+	requests.Store(0)
+	tooLarge := map[string]any{"data": strings.Repeat("a", 5000)}
+	_, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "q", Payload: tooLarge})
+	tooLargeErr, ok := AsPayloadTooLargeError(err)
+	if !ok || tooLargeErr.Limit != 4096 || tooLargeErr.Size <= 4096 {
+		t.Fatalf("Create() err = %v, want a PayloadTooLargeError over the 4096 byte limit", err)
+	}
+	_, err = client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{
+		QueueName: "q",
+		Jobs:      []resources.BulkJobItem{{Payload: large}, {Payload: tooLarge}},
+	})
+	if _, ok := AsPayloadTooLargeError(err); !ok || !strings.HasPrefix(err.Error(), "job 1:") {
+		t.Fatalf("BulkEnqueue() err = %v, want a PayloadTooLargeError for job 1", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("%d requests sent for oversized payloads, want 0", n)
+	}
+}
+
+func TestClient_LegacyFieldNames(t *testing.T) {
+	const job = `{"id":"job-1","queueName":"emails","status":"pending","maxRetries":3,"lastError":"boom",` +
+		`"createdAt":"2024-01-01T00:00:00Z","payload":{"userId":1,"user_id":2}}`
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/jobs" {
+			_, _ = w.Write([]byte(`[` + job + `]`))
+			return
+		}
+		_, _ = w.Write([]byte(job))
+	},
+		WithLegacyFieldNames(true),
+	)
+
+	ctx := context.Background()
+
+	check := func(name string, got *resources.Job) {
+		t.Helper()
+		if got.QueueName != "emails" || got.MaxRetries != 3 || got.LastError == nil || *got.LastError != "boom" || got.CreatedAt.IsZero() {
+			t.Errorf("%s: job = %+v, want camelCase fields decoded", name, got)
+		}
+		// Payloads are returned as sent
+		if len(got.Payload) != 2 || got.Payload["userId"] != float64(1) || got.Payload["user_id"] != float64(2) {
+			t.Errorf("%s: payload = %v, want it unchanged", name, got.Payload)
+		}
+	}
+
+	got, err := client.Jobs().Get(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	check("Get", got)
+
+	jobs, err := client.Jobs().List(ctx, nil)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("List() = %v, %v; want one job", jobs, err)
+	}
+	check("List", &jobs[0])
+
+	items, errs := client.Jobs().ListStream(ctx, nil)
+	for item := range items {
+		check("ListStream", &item)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("ListStream() error = %v", err)
+	}
+}
+
+func TestJobs_Create_SchemaRegistry(t *testing.T) {
+	var fetches int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if r.URL.Path != "/schemas/ids/7" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema":"{\"type\":\"object\",\"required\":[\"to\"],\"properties\":{\"to\":{\"type\":\"string\"}}}"}`))
+	}))
+	defer registry.Close()
+
+	var enqueued int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&enqueued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+	},
+		WithSchemaRegistry(registry.URL),
+	)
+
+	_, err := client.Jobs().Create(context.Background(), &resources.CreateJobRequest{
+		QueueName: "emails",
+		Payload:   map[string]any{resources.SchemaIDKey: 7, "to": 42},
+	})
+	var verr *resources.SchemaValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Create() error = %v, want *SchemaValidationError", err)
+	}
+	if verr.SchemaID != "7" {
+		t.Errorf("SchemaID = %q, want %q", verr.SchemaID, "7")
+	}
+	if n := atomic.LoadInt32(&enqueued); n != 0 {
+		t.Errorf("enqueue requests = %d, want 0", n)
+	}
+
+	if _, err := client.Jobs().Create(context.Background(), &resources.CreateJobRequest{
+		QueueName: "emails",
+		Payload:   map[string]any{resources.SchemaIDKey: 7, "to": "a@example.com"},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&enqueued); n != 1 {
+		t.Errorf("enqueue requests = %d, want 1", n)
+	}
+	// Schemas are cached per ID
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("registry fetches = %d, want 1", n)
+	}
+}
+
+func TestResource_WithHeaders(t *testing.T) {
+	var gotHeaders []http.Header
+	var gotQueries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = append(gotHeaders, r.Header.Clone())
+		gotQueries = append(gotQueries, r.URL.Query().Get("source"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","queue_name":"emails","status":"pending","payload":{}}`))
+	})
+
+	jobs := client.Jobs().
+		WithHeaders(map[string]string{"x-source-service": "billing"}).
+		WithQuery(url.Values{"source": {"billing"}})
+	if _, err := jobs.Get(context.Background(), "job-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The original accessor is not decorated
+	if _, err := client.Jobs().Get(context.Background(), "job-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := gotHeaders[0].Get("X-Source-Service"); got != "billing" {
+		t.Errorf("X-Source-Service = %q, want %q", got, "billing")
+	}
+	if gotQueries[0] != "billing" {
+		t.Errorf("source = %q, want %q", gotQueries[0], "billing")
+	}
+	if got := gotHeaders[1].Get("X-Source-Service"); got != "" {
+		t.Errorf("undecorated X-Source-Service = %q, want empty", got)
+	}
+	if gotQueries[1] != "" {
+		t.Errorf("undecorated source = %q, want empty", gotQueries[1])
+	}
+}
+
+func TestJobs_BulkEnqueue_Idempotent(t *testing.T) {
+	var keys [][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body resources.BulkEnqueueRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var batch []string
+		for _, job := range body.Jobs {
+			key := ""
+			if job.IdempotencyKey != nil {
+				key = *job.IdempotencyKey
+			}
+			batch = append(batch, key)
+		}
+		keys = append(keys, batch)
+		if len(keys) == 1 {
+			// Lost response after the batch was accepted
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"succeeded":[{"index":0,"job_id":"a"},{"index":1,"job_id":"b"}],"failed":[],"total":2,"success_count":2,"failure_count":0}`))
+	},
+		WithRetry(RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}),
+	)
+
+	own := "own-key"
+	resp, err := client.Jobs().BulkEnqueue(context.Background(), &resources.BulkEnqueueRequest{
+		QueueName:  "emails",
+		Jobs:       []resources.BulkJobItem{{Payload: map[string]any{"n": 0}}, {Payload: map[string]any{"n": 1}, IdempotencyKey: &own}},
+		Idempotent: true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("requests = %d, want 2", len(keys))
+	}
+	want := "bulk:" + resp.BatchID + ":0"
+	if resp.BatchID == "" || keys[0][0] != want {
+		t.Errorf("key[0] = %q, want %q", keys[0][0], want)
+	}
+	if keys[0][1] != own {
+		t.Errorf("key[1] = %q, want %q", keys[0][1], own)
+	}
+	// The retry carries the same keys, so the server dedupes it
+	if strings.Join(keys[0], ",") != strings.Join(keys[1], ",") {
+		t.Errorf("retry keys = %v, want %v", keys[1], keys[0])
+	}
+}
+
+func TestAPIKeys_CreateWorkerKey(t *testing.T) {
+	var keyReq resources.CreateAPIKeyRequest
+	var registerReq resources.RegisterWorkerRequest
+	var claimQueue atomic.Value
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/api-keys":
+			_ = json.NewDecoder(r.Body).Decode(&keyReq)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"key-1","key":"sk_live_worker","name":"` + keyReq.Name + `","worker_id":"` + *keyReq.WorkerID + `"}`))
+		case "/api/v1/workers/register":
+			_ = json.NewDecoder(r.Body).Decode(&registerReq)
+			_, _ = w.Write([]byte(`{"id":"` + *registerReq.WorkerID + `","queue_name":"staging-thumbnails"}`))
+		case "/api/v1/jobs/claim":
+			var claimReq resources.ClaimJobsRequest
+			_ = json.NewDecoder(r.Body).Decode(&claimReq)
+			claimQueue.Store(claimReq.QueueName)
+			_, _ = w.Write([]byte(`{"jobs":[]}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	},
+		WithQueuePrefix("staging-"),
+	)
+
+	key, err := client.APIKeys().CreateWorkerKey(context.Background(), "thumbnails")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keyReq.Queues) != 1 || keyReq.Queues[0] != "staging-thumbnails" {
+		t.Errorf("Queues = %v, want [staging-thumbnails]", keyReq.Queues)
+	}
+	if len(keyReq.Scopes) != len(resources.WorkerScopes) {
+		t.Errorf("Scopes = %v, want %v", keyReq.Scopes, resources.WorkerScopes)
+	}
+	if key.WorkerID == nil || !strings.HasPrefix(*key.WorkerID, "thumbnails-") {
+		t.Fatalf("WorkerID = %v, want a thumbnails- identity", key.WorkerID)
+	}
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:    "thumbnails",
+		WorkerID:     *key.WorkerID,
+		PollInterval: 10 * time.Millisecond,
+		ExitWhenIdle: 20 * time.Millisecond,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	summary, err := w.RunUntilDrained(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if registerReq.WorkerID == nil || *registerReq.WorkerID != *key.WorkerID || summary.WorkerID != *key.WorkerID {
+		t.Errorf("worker registered as %v (summary %q), want %q", registerReq.WorkerID, summary.WorkerID, *key.WorkerID)
+	}
+	// The worker's queue is prefixed once
+	if claimed, _ := claimQueue.Load().(string); registerReq.QueueName != "staging-thumbnails" || claimed != "staging-thumbnails" {
+		t.Errorf("worker registered on %q and claimed from %q, want staging-thumbnails", registerReq.QueueName, claimed)
+	}
+}
+
+func TestQueues_Ensure(t *testing.T) {
+	three, five, seven, enabled := 3, 5, 7, true
+	var puts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/queues/missing" && r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not_found","message":"queue not found"}`))
+		case r.Method == http.MethodPut:
+			puts.Add(1)
+			_, _ = w.Write([]byte(`{"queue_name":"missing","max_retries":5,"default_timeout":300,"enabled":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"queue_name":"emails","max_retries":3,"default_timeout":300,"enabled":true,` +
+				`"settings":{"dead_letter":{"enabled":true,"retention_days":7}}}`))
+		}
+	},
+		WithQueueConfig("emails", resources.UpdateQueueConfigRequest{MaxRetries: &five}),
+	)
+
+	ctx := context.Background()
+
+	if _, err := client.Queues().Ensure(ctx, "missing", &resources.UpdateQueueConfigRequest{MaxRetries: &five}); err != nil {
+		t.Fatalf("Ensure(missing) error = %v", err)
+	}
+	if puts.Load() != 1 {
+		t.Errorf("Ensure(missing) sent %d PUTs, want 1", puts.Load())
+	}
+
+	matching := &resources.UpdateQueueConfigRequest{
+		MaxRetries: &three,
+		Settings:   map[string]any{"dead_letter": resources.QueueDLQPolicy{Enabled: true, RetentionDays: &seven}},
+	}
+	if _, err := client.Queues().Ensure(ctx, "emails", matching); err != nil {
+		t.Fatalf("Ensure(matching) error = %v", err)
+	}
+	if puts.Load() != 1 {
+		t.Errorf("Ensure(matching) updated the queue")
+	}
+
+	_, err := client.Queues().Ensure(ctx, "emails", &resources.UpdateQueueConfigRequest{MaxRetries: &five, Enabled: &enabled})
+	var conflict *resources.QueueConfigConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Ensure(conflicting) error = %v, want *QueueConfigConflictError", err)
+	}
+	if len(conflict.Differences) != 1 || conflict.Differences[0] != "max_retries: have 3, want 5" {
+		t.Errorf("Differences = %v", conflict.Differences)
+	}
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "emails"})
+	if err := w.Start(); !errors.As(err, &conflict) {
+		t.Errorf("Start() error = %v, want *QueueConfigConflictError", err)
+	}
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	var keys []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"wh-1","name":"orders"}`))
+	},
+		WithRetry(RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond}),
+	)
+
+	// POSTs with a key are retried, and every attempt carries the key
+	ctx := WithIdempotencyKey(context.Background(), "create-webhook:order-1")
+	webhook, err := client.Webhooks().Create(ctx, &resources.CreateOutgoingWebhookRequest{Name: "orders", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if webhook.ID != "wh-1" {
+		t.Errorf("ID = %q, want wh-1", webhook.ID)
+	}
+	if len(keys) != 2 || keys[0] != "create-webhook:order-1" || keys[1] != "create-webhook:order-1" {
+		t.Errorf("Idempotency-Key headers = %v, want the key on both attempts", keys)
+	}
+
+	// Without a key the POST is not retried
+	keys = nil
+	if _, err := client.Webhooks().Create(context.Background(), &resources.CreateOutgoingWebhookRequest{Name: "orders"}); err == nil {
+		t.Error("expected error without retry")
+	}
+	if len(keys) != 1 || keys[0] != "" {
+		t.Errorf("Idempotency-Key headers = %v, want one attempt without a key", keys)
+	}
+}
+
+func TestSpooledWorker_StatsAndSlowJobs(t *testing.T) {
+	var claimed atomic.Bool
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/workers/register":
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"batch"}`))
+		case r.URL.Path == "/api/v1/jobs/claim":
+			if claimed.Swap(true) {
+				_, _ = w.Write([]byte(`{"jobs":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"jobs":[
+				{"id":"fast","queue_name":"batch","payload":{}},
+				{"id":"slow","queue_name":"batch","payload":{}},
+				{"id":"broken","queue_name":"batch","payload":{}}
+			]}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	var mu sync.Mutex
+	var slow []worker.JobSlowData
+	client.OnEvent(func(e sdkevents.Event) {
+		if e.Type == sdkevents.TypeWorkerJobSlow {
+			mu.Lock()
+			slow = append(slow, e.Data.(worker.JobSlowData))
+			mu.Unlock()
+		}
+	})
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:        "batch",
+		PollInterval:     10 * time.Millisecond,
+		ExitWhenIdle:     300 * time.Millisecond,
+		SlowJobThreshold: 50 * time.Millisecond,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) {
+		switch job.ID {
+		case "slow":
+			time.Sleep(150 * time.Millisecond)
+		case "broken":
+			return nil, errors.New("boom")
+		}
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := w.RunUntilDrained(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(slow) != 1 || slow[0].JobID != "slow" || slow[0].Threshold != 50*time.Millisecond {
+		t.Errorf("slow job events = %+v, want one for job slow", slow)
+	}
+	stats := w.Stats()
+	if stats.Jobs != 3 || stats.Failed != 1 || stats.Throughput <= 0 {
+		t.Errorf("stats = %+v, want 3 jobs with 1 failure", stats)
+	}
+	if stats.Max < 150*time.Millisecond || stats.P50 >= 50*time.Millisecond {
+		t.Errorf("latency p50=%v max=%v, want a fast median and a slow max", stats.P50, stats.Max)
+	}
+	if q := stats.Queues["batch"]; q.Jobs != 3 || q.ErrorRate < 0.33 || q.ErrorRate > 0.34 {
+		t.Errorf("queue stats = %+v", q)
+	}
+}
+
+func TestPolicies_AppliedByPayloadType(t *testing.T) {
+	var bodies []map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/jobs/bulk" {
+			_, _ = w.Write([]byte(`{"succeeded":[],"failed":[],"total":2,"success_count":2,"failure_count":0}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+	})
+
+	ctx := context.Background()
+
+	client.Policies().Register("send-email", resources.Policy{
+		MaxRetries: 5,
+		Timeout:    30 * time.Second,
+		Backoff:    &resources.Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 3},
+	})
+
+	req := &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{"type": "send-email"}}
+	if _, err := client.Jobs().Create(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.MaxRetries != nil {
+		t.Error("Create modified the caller's request")
+	}
+	// Explicit values win over the policy
+	explicit := 1
+	if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{"type": "send-email"}, MaxRetries: &explicit}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{"type": "other"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{QueueName: "emails", Jobs: []resources.BulkJobItem{
+		{Payload: map[string]any{"type": "send-email"}},
+		{Payload: map[string]any{"type": "send-email"}},
+	}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	backoff := map[string]any{"initial_delay_seconds": float64(1), "max_delay_seconds": float64(60), "multiplier": float64(3)}
+	if b := bodies[0]; b["max_retries"] != float64(5) || b["timeout_seconds"] != float64(30) || !reflect.DeepEqual(b["retry_backoff"], backoff) {
+		t.Errorf("policy job body = %v", b)
+	}
+	if b := bodies[1]; b["max_retries"] != float64(1) || b["timeout_seconds"] != float64(30) {
+		t.Errorf("explicit job body = %v", b)
+	}
+	if b := bodies[2]; b["max_retries"] != nil || b["retry_backoff"] != nil {
+		t.Errorf("unregistered type body = %v", b)
+	}
+	if b := bodies[3]; b["default_max_retries"] != float64(5) || b["default_timeout_seconds"] != float64(30) {
+		t.Errorf("bulk body = %v", b)
+	}
+}
+
+func TestWithProtection(t *testing.T) {
+	var purges, deletes atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/jobs/dlq":
+			_, _ = w.Write([]byte(`[{"id":"job-1"},{"id":"job-2"}]`))
+		case r.URL.Path == "/api/v1/jobs/dlq/purge":
+			purges.Add(1)
+			_, _ = w.Write([]byte(`{"purged_count":2}`))
+		case r.URL.Path == "/api/v1/queues/emails" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"queue_name":"emails"}`))
+		case r.URL.Path == "/api/v1/queues/emails" && r.Method == http.MethodDelete:
+			deletes.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, WithProtection(true))
+
+	var mu sync.Mutex
+	var audit []sdkevents.DestructiveOperationData
+	client.OnEvent(func(e sdkevents.Event) {
+		if e.Type == sdkevents.TypeDestructiveOperation {
+			mu.Lock()
+			audit = append(audit, e.Data.(sdkevents.DestructiveOperationData))
+			mu.Unlock()
+		}
+	})
+	ctx := context.Background()
+	queue := "emails"
+
+	if _, err := client.Jobs().DLQ().Purge(ctx, &resources.PurgeDLQRequest{QueueName: &queue}); !errors.Is(err, resources.ErrConfirmationRequired) {
+		t.Fatalf("unconfirmed purge error = %v, want ErrConfirmationRequired", err)
+	}
+	resp, err := client.Jobs().DLQ().Purge(ctx, &resources.PurgeDLQRequest{QueueName: &queue, DryRun: true})
+	if err != nil || !resp.DryRun || resp.PurgedCount != 2 || purges.Load() != 0 {
+		t.Fatalf("dry run = %+v, %v (purges %d)", resp, err, purges.Load())
+	}
+	// The dry run authorizes one purge of the same queue
+	if _, err := client.Jobs().DLQ().Purge(ctx, &resources.PurgeDLQRequest{}); !errors.Is(err, resources.ErrConfirmationRequired) {
+		t.Errorf("purge of all queues after a dry run of one: error = %v", err)
+	}
+	if _, err := client.Jobs().DLQ().Purge(ctx, &resources.PurgeDLQRequest{QueueName: &queue}); err != nil {
+		t.Errorf("purge after dry run: %v", err)
+	}
+	if _, err := client.Jobs().DLQ().Purge(ctx, &resources.PurgeDLQRequest{QueueName: &queue}); !errors.Is(err, resources.ErrConfirmationRequired) {
+		t.Errorf("second purge reused the dry run: error = %v", err)
+	}
+
+	if err := client.Queues().Delete(ctx, "emails"); !errors.Is(err, resources.ErrConfirmationRequired) {
+		t.Errorf("unconfirmed delete error = %v", err)
+	}
+	if err := client.Queues().DeleteWithOptions(ctx, "emails", &resources.DeleteQueueOptions{Confirm: true}); err != nil {
+		t.Errorf("confirmed delete: %v", err)
+	}
+	if purges.Load() != 1 || deletes.Load() != 1 {
+		t.Errorf("purges = %d, deletes = %d, want 1 each", purges.Load(), deletes.Load())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(audit) != 7 {
+		t.Fatalf("audit events = %d, want 7", len(audit))
+	}
+	if a := audit[1]; !a.DryRun || a.Affected != 2 || a.Target != "emails" || !a.Protected {
+		t.Errorf("dry run audit = %+v", a)
+	}
+	if a := audit[6]; a.Operation != resources.OperationDeleteQueue || !a.Confirmed || a.Error != nil {
+		t.Errorf("delete audit = %+v", a)
+	}
+}
+
+func TestNewClient_WithUseNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","retry_count":2,"payload":{"user_id":9007199254740993,"ratio":0.5}}`))
+	}))
+	defer server.Close()
+
+	for _, useNumber := range []bool{false, true} {
+		client, err := NewClient(
+			WithAPIKey(testAPIKey),
+			WithBaseURL(server.URL),
+			WithUseNumber(useNumber),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		job, err := client.Jobs().Get(context.Background(), "job-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if job.RetryCount != 2 {
+			t.Errorf("RetryCount = %d, want 2", job.RetryCount)
+		}
+
+		userID, err := payload.Map(job.Payload).Int64("user_id")
+		if useNumber {
+			if err != nil || userID != 9007199254740993 {
+				t.Errorf("Int64(user_id) = %d, %v, want 9007199254740993", userID, err)
+			}
+		} else if !errors.Is(err, payload.ErrPrecisionLoss) {
+			t.Errorf("Int64(user_id) error = %v, want ErrPrecisionLoss", err)
+		}
+		if ratio, err := payload.Map(job.Payload).Float64("ratio"); err != nil || ratio != 0.5 {
+			t.Errorf("Float64(ratio) = %v, %v, want 0.5", ratio, err)
+		}
+	}
+}
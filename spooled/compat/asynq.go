@@ -0,0 +1,134 @@
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// AsynqDefaultQueue is the queue Asynq uses when none is given.
+const AsynqDefaultQueue = "default"
+
+// AsynqTask is an Asynq task: asynq.NewTask(typename, payload, opts...).
+type AsynqTask struct {
+	Type string
+	// Payload is the task payload. JSON objects become the job payload as-is;
+	// any other bytes are stored under the "data" key.
+	Payload []byte
+	Opts    AsynqOptions
+}
+
+// AsynqOptions are the Asynq task options that can be translated.
+type AsynqOptions struct {
+	// Queue is the queue name (default: AsynqDefaultQueue).
+	Queue string
+	// MaxRetry is the number of retries; nil keeps the queue default.
+	MaxRetry  *int
+	Timeout   time.Duration
+	Deadline  time.Time
+	ProcessAt time.Time
+	ProcessIn time.Duration
+	TaskID    string
+	// Unique, Retention, and Group have no Spooled equivalent.
+	Unique    time.Duration
+	Retention time.Duration
+	Group     string
+}
+
+// AsynqMapping describes how Asynq task options map to CreateJobRequest.
+var AsynqMapping = []FieldMapping{
+	{Source: "typename", Target: "Tags[job_name]", Note: "Spooled jobs are typed by queue; the type is kept as a tag"},
+	{Source: "payload", Target: "Payload", Note: "non-object payloads are stored under \"data\""},
+	{Source: "asynq.Queue", Target: "QueueName"},
+	{Source: "asynq.MaxRetry", Target: "MaxRetries"},
+	{Source: "asynq.Timeout", Target: "TimeoutSeconds", Note: "rounded up to whole seconds"},
+	{Source: "asynq.Deadline", Target: "TimeoutSeconds", Note: "converted to a timeout from now when shorter than Timeout"},
+	{Source: "asynq.ProcessAt", Target: "ScheduledAt"},
+	{Source: "asynq.ProcessIn", Target: "Delay"},
+	{Source: "asynq.TaskID", Target: "IdempotencyKey"},
+	{Source: "asynq.Unique", Note: "set TaskID to a key derived from the payload instead"},
+	{Source: "asynq.Retention", Note: "use queue retention settings"},
+	{Source: "asynq.Group", Note: "task aggregation is not supported; use workflows or batch handlers"},
+}
+
+// FromAsynq translates an Asynq task into a CreateJobRequest.
+func FromAsynq(task AsynqTask) (*Translation, error) {
+	opts := task.Opts
+	if opts.MaxRetry != nil && *opts.MaxRetry < 0 {
+		return nil, fmt.Errorf("asynq MaxRetry must not be negative")
+	}
+	if !opts.Deadline.IsZero() && !opts.Deadline.After(time.Now()) {
+		return nil, fmt.Errorf("asynq Deadline has already passed")
+	}
+	if !opts.ProcessAt.IsZero() && opts.ProcessIn > 0 {
+		return nil, fmt.Errorf("asynq ProcessAt and ProcessIn are mutually exclusive")
+	}
+
+	payload, err := asynqPayload(task.Payload)
+	if err != nil {
+		return nil, err
+	}
+	queue := opts.Queue
+	if queue == "" {
+		queue = AsynqDefaultQueue
+	}
+	req := &resources.CreateJobRequest{
+		QueueName:  queue,
+		Payload:    payload,
+		MaxRetries: opts.MaxRetry,
+		Delay:      opts.ProcessIn,
+	}
+	if task.Type != "" {
+		req.Tags = map[string]any{NameTag: task.Type}
+	}
+	if !opts.ProcessAt.IsZero() {
+		at := opts.ProcessAt
+		req.ScheduledAt = &at
+	}
+	if opts.TaskID != "" {
+		req.IdempotencyKey = stringPtr(opts.TaskID)
+	}
+
+	// Asynq enforces the earlier of Timeout and Deadline
+	timeout := opts.Timeout
+	if !opts.Deadline.IsZero() {
+		if untilDeadline := time.Until(opts.Deadline); timeout == 0 || untilDeadline < timeout {
+			timeout = untilDeadline
+		}
+	}
+	if timeout > 0 {
+		req.TimeoutSeconds = intPtr(int((timeout + time.Second - 1) / time.Second))
+	}
+
+	t := &Translation{Request: req}
+	if opts.Unique > 0 {
+		t.Dropped = append(t.Dropped, "asynq.Unique")
+	}
+	if opts.Retention > 0 {
+		t.Dropped = append(t.Dropped, "asynq.Retention")
+	}
+	if opts.Group != "" {
+		t.Dropped = append(t.Dropped, "asynq.Group")
+	}
+	return t, nil
+}
+
+// asynqPayload decodes a JSON object payload, or wraps other bytes.
+func asynqPayload(data []byte) (map[string]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return map[string]any{}, nil
+	}
+	if trimmed[0] == '{' {
+		var payload map[string]any
+		if err := json.Unmarshal(trimmed, &payload); err != nil {
+			return nil, fmt.Errorf("invalid asynq JSON payload: %w", err)
+		}
+		return payload, nil
+	}
+	// Encoded as base64 when the job is sent
+	return map[string]any{"data": data}, nil
+}
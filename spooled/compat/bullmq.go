@@ -0,0 +1,115 @@
+package compat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// BullMQJob is a BullMQ job definition: queue.add(name, data, opts).
+type BullMQJob struct {
+	Name string
+	Data map[string]any
+	Opts BullMQOptions
+}
+
+// BullMQOptions are the BullMQ JobsOptions that can be translated.
+type BullMQOptions struct {
+	// JobID deduplicates jobs, like BullMQ's custom job IDs.
+	JobID string
+	// Priority is 1 (highest) to 2,097,152; 0 means no priority.
+	Priority int
+	Delay    time.Duration
+	// Attempts is the total number of attempts, including the first.
+	Attempts int
+	Backoff  *BullMQBackoff
+	// Timeout is the per-attempt processing timeout (Bull's "timeout" option).
+	Timeout time.Duration
+	// Lifo, RemoveOnComplete, and RemoveOnFail have no Spooled equivalent.
+	Lifo             bool
+	RemoveOnComplete bool
+	RemoveOnFail     bool
+}
+
+// BullMQBackoff is a BullMQ retry backoff strategy.
+type BullMQBackoff struct {
+	// Type is "fixed" or "exponential". Custom strategies run in the BullMQ
+	// worker and cannot be translated.
+	Type  string
+	Delay time.Duration
+}
+
+// BullMQMapping describes how BullMQ options map to CreateJobRequest.
+var BullMQMapping = []FieldMapping{
+	{Source: "name", Target: "Tags[job_name]", Note: "Spooled jobs are typed by queue; the name is kept as a tag"},
+	{Source: "data", Target: "Payload"},
+	{Source: "opts.jobId", Target: "IdempotencyKey", Note: "duplicates return the existing job instead of being ignored"},
+	{Source: "opts.priority", Target: "Priority", Note: "BullMQ 1 (highest) becomes 99; 200 and above become -100"},
+	{Source: "opts.delay", Target: "Delay"},
+	{Source: "opts.attempts", Target: "MaxRetries", Note: "attempts - 1: Spooled counts retries, not attempts"},
+	{Source: "opts.backoff", Target: "RetryBackoff", Note: "fixed keeps the delay (Multiplier 1); exponential doubles it (Multiplier 2)"},
+	{Source: "opts.timeout", Target: "TimeoutSeconds", Note: "rounded up to whole seconds"},
+	{Source: "opts.lifo", Note: "jobs are ordered by priority, then FIFO"},
+	{Source: "opts.removeOnComplete", Note: "use queue retention settings"},
+	{Source: "opts.removeOnFail", Note: "use queue retention settings"},
+}
+
+// FromBullMQ translates a BullMQ job added to queue into a CreateJobRequest.
+func FromBullMQ(queue string, job BullMQJob) (*Translation, error) {
+	if queue == "" {
+		return nil, fmt.Errorf("queue name is required")
+	}
+	opts := job.Opts
+	if opts.Attempts < 0 || opts.Priority < 0 || opts.Delay < 0 || opts.Timeout < 0 ||
+		(opts.Backoff != nil && opts.Backoff.Delay < 0) {
+		return nil, fmt.Errorf("BullMQ options must not be negative")
+	}
+
+	payload := job.Data
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	req := &resources.CreateJobRequest{
+		QueueName: queue,
+		Payload:   payload,
+		Delay:     opts.Delay,
+	}
+	if job.Name != "" {
+		req.Tags = map[string]any{NameTag: job.Name}
+	}
+	if opts.JobID != "" {
+		req.IdempotencyKey = stringPtr(opts.JobID)
+	}
+	if opts.Priority > 0 {
+		req.Priority = intPtr(max(100-opts.Priority, -100))
+	}
+	if opts.Attempts > 0 {
+		req.MaxRetries = intPtr(opts.Attempts - 1)
+	}
+	if opts.Timeout > 0 {
+		req.TimeoutSeconds = intPtr(int((opts.Timeout + time.Second - 1) / time.Second))
+	}
+	if b := opts.Backoff; b != nil {
+		switch b.Type {
+		case "fixed":
+			req.RetryBackoff = &resources.Backoff{Initial: b.Delay, Multiplier: 1}
+		case "exponential":
+			req.RetryBackoff = &resources.Backoff{Initial: b.Delay, Multiplier: 2}
+		default:
+			return nil, fmt.Errorf("BullMQ backoff type %q cannot be translated; use fixed or exponential", b.Type)
+		}
+	}
+
+	t := &Translation{Request: req}
+	if opts.Lifo {
+		t.Dropped = append(t.Dropped, "opts.lifo")
+	}
+	if opts.RemoveOnComplete {
+		t.Dropped = append(t.Dropped, "opts.removeOnComplete")
+	}
+	if opts.RemoveOnFail {
+		t.Dropped = append(t.Dropped, "opts.removeOnFail")
+	}
+	return t, nil
+}
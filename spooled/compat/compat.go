@@ -0,0 +1,44 @@
+// Package compat translates BullMQ- and Asynq-style job definitions into
+// Spooled jobs, to reduce the diff when porting existing queue code:
+//
+//	t, err := compat.FromBullMQ("emails", compat.BullMQJob{
+//		Name: "welcome",
+//		Data: map[string]any{"to": "a@example.com"},
+//		Opts: compat.BullMQOptions{Attempts: 3, Delay: 5 * time.Second},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := client.Jobs().Create(ctx, t.Request)
+//
+// Options with no Spooled equivalent are listed in Translation.Dropped rather
+// than silently ignored. BullMQMapping and AsynqMapping describe every option
+// for migration guides and code review.
+package compat
+
+import (
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// NameTag is the job tag that holds the BullMQ job name or Asynq task type.
+const NameTag = "job_name"
+
+// Translation is the result of translating a foreign job definition.
+type Translation struct {
+	Request *resources.CreateJobRequest
+	// Dropped lists the options that were set but have no Spooled equivalent.
+	Dropped []string
+}
+
+// FieldMapping describes how a foreign job option maps to CreateJobRequest.
+type FieldMapping struct {
+	// Source is the option in the original library.
+	Source string
+	// Target is the CreateJobRequest field, or "" if the option is dropped.
+	Target string
+	Note   string
+}
+
+func intPtr(v int) *int { return &v }
+
+func stringPtr(v string) *string { return &v }
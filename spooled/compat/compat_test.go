@@ -0,0 +1,268 @@
+package compat
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/spooledtest"
+)
+
+// enqueue creates the translated job and returns it as the server stored it.
+func enqueue(t *testing.T, srv *spooledtest.Server, client *spooled.Client, tr *Translation) (*resources.CreateJobResponse, resources.Job) {
+	t.Helper()
+	resp, err := client.Jobs().Create(context.Background(), tr.Request)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	job, ok := srv.Job(resp.ID)
+	if !ok {
+		t.Fatalf("job %s was not stored", resp.ID)
+	}
+	return resp, job
+}
+
+// failOnce claims and fails job id, then moves the clock to its retry and
+// returns the backoff the server applied.
+func failOnce(t *testing.T, srv *spooledtest.Server, client *spooled.Client, id string) time.Duration {
+	t.Helper()
+	ctx := context.Background()
+	job, _ := srv.Job(id)
+	claimed, err := client.Jobs().Claim(ctx, &resources.ClaimJobsRequest{QueueName: job.QueueName, WorkerID: "worker-1"})
+	if err != nil || len(claimed.Jobs) != 1 || claimed.Jobs[0].ID != id {
+		t.Fatalf("Claim() = %+v, %v; want job %s", claimed, err, id)
+	}
+	if err := client.Jobs().Fail(ctx, id, &resources.FailJobRequest{WorkerID: "worker-1", Error: "boom"}); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	job, _ = srv.Job(id)
+	if job.ScheduledAt == nil {
+		t.Fatalf("job %s is %s, want a scheduled retry", id, job.Status)
+	}
+	delay := job.ScheduledAt.Sub(srv.Now())
+	srv.AdvanceTime(delay)
+	return delay
+}
+
+func TestFromBullMQ_RoundTrip(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	bull := BullMQJob{
+		Name: "welcome",
+		Data: map[string]any{"to": "a@example.com"},
+		Opts: BullMQOptions{
+			JobID:    "welcome-a",
+			Priority: 1,
+			Delay:    5 * time.Second,
+			Attempts: 3,
+			Timeout:  1500 * time.Millisecond,
+			Backoff:  &BullMQBackoff{Type: "exponential", Delay: time.Second},
+			Lifo:     true,
+		},
+	}
+	tr, err := FromBullMQ("emails", bull)
+	if err != nil {
+		t.Fatalf("FromBullMQ: %v", err)
+	}
+	if want := []string{"opts.lifo"}; !slices.Equal(tr.Dropped, want) {
+		t.Errorf("Dropped = %v, want %v", tr.Dropped, want)
+	}
+
+	resp, job := enqueue(t, srv, client, tr)
+	if job.QueueName != "emails" || !reflect.DeepEqual(job.Payload, bull.Data) {
+		t.Errorf("job = %s %v, want emails %v", job.QueueName, job.Payload, bull.Data)
+	}
+	if job.Tags[NameTag] != "welcome" {
+		t.Errorf("tags = %v, want %s=welcome", job.Tags, NameTag)
+	}
+	if job.Priority != 99 || job.MaxRetries != 2 || job.TimeoutSeconds != 2 {
+		t.Errorf("priority, max retries, timeout = %d, %d, %d, want 99, 2, 2", job.Priority, job.MaxRetries, job.TimeoutSeconds)
+	}
+	if job.Status != resources.JobStatusScheduled || job.ScheduledAt == nil || job.ScheduledAt.Sub(srv.Now()) < 4*time.Second {
+		t.Errorf("job = %s at %v, want scheduled 5s out", job.Status, job.ScheduledAt)
+	}
+
+	// The BullMQ job ID deduplicates like a custom job ID
+	again, err := FromBullMQ("emails", bull)
+	if err != nil {
+		t.Fatalf("FromBullMQ: %v", err)
+	}
+	dup, _ := enqueue(t, srv, client, again)
+	if dup.ID != resp.ID || dup.Created {
+		t.Errorf("duplicate = %+v, want existing job %s", dup, resp.ID)
+	}
+
+	// Failed attempts are retried with the BullMQ backoff
+	for _, tt := range []struct {
+		backoff string
+		want    []time.Duration
+	}{
+		{"fixed", []time.Duration{2 * time.Second, 2 * time.Second}},
+		{"exponential", []time.Duration{2 * time.Second, 4 * time.Second}},
+	} {
+		tr, err := FromBullMQ(tt.backoff, BullMQJob{Opts: BullMQOptions{
+			Attempts: 3,
+			Backoff:  &BullMQBackoff{Type: tt.backoff, Delay: 2 * time.Second},
+		}})
+		if err != nil {
+			t.Fatalf("FromBullMQ: %v", err)
+		}
+		resp, _ := enqueue(t, srv, client, tr)
+		for i, want := range tt.want {
+			if delay := failOnce(t, srv, client, resp.ID); delay != want {
+				t.Errorf("%s backoff: retry %d after %v, want %v", tt.backoff, i+1, delay, want)
+			}
+		}
+	}
+
+	// Priorities beyond Spooled's range are clamped; unset options keep the defaults
+	tr, err = FromBullMQ("emails", BullMQJob{Opts: BullMQOptions{Priority: 500}})
+	if err != nil {
+		t.Fatalf("FromBullMQ: %v", err)
+	}
+	_, job = enqueue(t, srv, client, tr)
+	if job.Priority != -100 || job.MaxRetries != spooledtest.DefaultMaxRetries || job.Tags != nil || job.Status != resources.JobStatusPending {
+		t.Errorf("job = %+v, want priority -100 with defaults", job)
+	}
+}
+
+func TestFromAsynq_RoundTrip(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	maxRetry := 5
+	tr, err := FromAsynq(AsynqTask{
+		Type:    "email:deliver",
+		Payload: []byte(` {"user_id": "u1"} `),
+		Opts: AsynqOptions{
+			Queue:     "critical",
+			MaxRetry:  &maxRetry,
+			Timeout:   time.Minute,
+			Deadline:  time.Now().Add(10 * time.Second),
+			ProcessIn: time.Minute,
+			TaskID:    "deliver-u1",
+			Unique:    time.Hour,
+			Group:     "digest",
+		},
+	})
+	if err != nil {
+		t.Fatalf("FromAsynq: %v", err)
+	}
+	if want := []string{"asynq.Unique", "asynq.Group"}; !slices.Equal(tr.Dropped, want) {
+		t.Errorf("Dropped = %v, want %v", tr.Dropped, want)
+	}
+
+	_, job := enqueue(t, srv, client, tr)
+	if job.QueueName != "critical" || job.Payload["user_id"] != "u1" || job.Tags[NameTag] != "email:deliver" {
+		t.Errorf("job = %s %v %v", job.QueueName, job.Payload, job.Tags)
+	}
+	// The deadline is sooner than the timeout, so it wins
+	if job.MaxRetries != 5 || job.TimeoutSeconds != 10 {
+		t.Errorf("max retries, timeout = %d, %d, want 5, 10", job.MaxRetries, job.TimeoutSeconds)
+	}
+	if job.Status != resources.JobStatusScheduled || job.ScheduledAt == nil || job.ScheduledAt.Sub(srv.Now()) < 59*time.Second {
+		t.Errorf("job = %s at %v, want scheduled a minute out", job.Status, job.ScheduledAt)
+	}
+
+	// Non-object payloads survive the trip under "data"
+	raw := []byte("\x00binary task")
+	processAt := srv.Now().Add(time.Hour).Truncate(time.Second)
+	tr, err = FromAsynq(AsynqTask{Type: "blob", Payload: raw, Opts: AsynqOptions{ProcessAt: processAt}})
+	if err != nil {
+		t.Fatalf("FromAsynq: %v", err)
+	}
+	_, job = enqueue(t, srv, client, tr)
+	if job.QueueName != AsynqDefaultQueue {
+		t.Errorf("queue = %s, want %s", job.QueueName, AsynqDefaultQueue)
+	}
+	encoded, _ := job.Payload["data"].(string)
+	if decoded, err := base64.StdEncoding.DecodeString(encoded); err != nil || string(decoded) != string(raw) {
+		t.Errorf("payload data = %q, want %q encoded", encoded, raw)
+	}
+	if job.ScheduledAt == nil || !job.ScheduledAt.Equal(processAt) {
+		t.Errorf("scheduled at = %v, want %v", job.ScheduledAt, processAt)
+	}
+}
+
+func TestTranslation_Errors(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name string
+		fn   func() (*Translation, error)
+	}{
+		{"bullmq missing queue", func() (*Translation, error) { return FromBullMQ("", BullMQJob{}) }},
+		{"bullmq negative attempts", func() (*Translation, error) {
+			return FromBullMQ("q", BullMQJob{Opts: BullMQOptions{Attempts: -1}})
+		}},
+		{"bullmq custom backoff", func() (*Translation, error) {
+			return FromBullMQ("q", BullMQJob{Opts: BullMQOptions{Backoff: &BullMQBackoff{Type: "jitter", Delay: time.Second}}})
+		}},
+		{"asynq negative retries", func() (*Translation, error) {
+			return FromAsynq(AsynqTask{Opts: AsynqOptions{MaxRetry: &negative}})
+		}},
+		{"asynq past deadline", func() (*Translation, error) {
+			return FromAsynq(AsynqTask{Opts: AsynqOptions{Deadline: time.Now().Add(-time.Second)}})
+		}},
+		{"asynq process at and in", func() (*Translation, error) {
+			return FromAsynq(AsynqTask{Opts: AsynqOptions{ProcessAt: time.Now(), ProcessIn: time.Second}})
+		}},
+		{"asynq invalid json", func() (*Translation, error) { return FromAsynq(AsynqTask{Payload: []byte(`{"a":`)}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.fn(); err == nil {
+				t.Error("translation should fail")
+			}
+		})
+	}
+}
+
+// TestMappings checks that every option a translation can drop is documented
+// as having no target.
+func TestMappings(t *testing.T) {
+	bull, err := FromBullMQ("q", BullMQJob{Opts: BullMQOptions{
+		Backoff: &BullMQBackoff{Type: "fixed"}, Lifo: true, RemoveOnComplete: true, RemoveOnFail: true,
+	}})
+	if err != nil {
+		t.Fatalf("FromBullMQ: %v", err)
+	}
+	asynq, err := FromAsynq(AsynqTask{Opts: AsynqOptions{Unique: time.Hour, Retention: time.Hour, Group: "g"}})
+	if err != nil {
+		t.Fatalf("FromAsynq: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		mapping []FieldMapping
+		dropped []string
+	}{
+		{"bullmq", BullMQMapping, bull.Dropped},
+		{"asynq", AsynqMapping, asynq.Dropped},
+	} {
+		var untranslated []string
+		for _, m := range tt.mapping {
+			if m.Target == "" {
+				untranslated = append(untranslated, m.Source)
+			}
+		}
+		if !slices.Equal(untranslated, tt.dropped) {
+			t.Errorf("%s: mapping has no target for %v, translation dropped %v", tt.name, untranslated, tt.dropped)
+		}
+	}
+}
@@ -3,10 +3,15 @@ package spooled
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
 	"github.com/spooled-cloud/spooled-sdk-go/internal/version"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
 )
 
 // Default configuration values
@@ -19,6 +24,15 @@ const (
 	DefaultAPIBasePath = "/api/v1"
 )
 
+// ClientAppHeader is the header used to identify the calling application.
+const ClientAppHeader = "X-Client-App"
+
+// APIVersionHeader is the header that pins the API version (see WithAPIVersion).
+const APIVersionHeader = httpx.APIVersionHeader
+
+// apiVersionPattern matches dated API versions such as "2024-11".
+var apiVersionPattern = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
 // RetryConfig configures retry behavior for failed requests.
 type RetryConfig struct {
 	// MaxRetries is the maximum number of retry attempts.
@@ -89,6 +103,8 @@ type Config struct {
 	RefreshToken string
 	// AdminKey is the admin API key (for /api/v1/admin/* endpoints; uses X-Admin-Key header).
 	AdminKey string
+	// CredentialProvider supplies the API key and reloads it when it changes.
+	CredentialProvider CredentialProvider
 
 	// BaseURL is the base URL for the REST API.
 	BaseURL string
@@ -96,18 +112,89 @@ type Config struct {
 	WSURL string
 	// GRPCAddress is the gRPC server address.
 	GRPCAddress string
+	// FallbackBaseURLs are REST base URLs used when BaseURL is unavailable.
+	FallbackBaseURLs []string
+	// FallbackGRPCAddresses are gRPC addresses used when GRPCAddress is unreachable.
+	FallbackGRPCAddresses []string
+	// GRPCMaxRecvMsgSize is the largest gRPC message the client accepts (default: 4MB).
+	GRPCMaxRecvMsgSize int
+	// GRPCMaxSendMsgSize is the largest gRPC message the client sends (default: 4MB).
+	GRPCMaxSendMsgSize int
+	// GRPCIdleTimeout closes the gRPC connection after this long unused (default: 5m).
+	GRPCIdleTimeout time.Duration
+	// Failover configures health tracking across BaseURL and FallbackBaseURLs.
+	Failover FailoverConfig
+	// PreferredTransport selects REST or gRPC for core job and worker operations.
+	PreferredTransport TransportKind
 
 	// Timeout is the request timeout.
 	Timeout time.Duration
+	// HTTPClient sends REST requests, e.g. one with a custom RoundTripper
+	// (optional).
+	HTTPClient *http.Client
 	// Retry is the retry configuration.
 	Retry RetryConfig
 	// CircuitBreaker is the circuit breaker configuration.
 	CircuitBreaker CircuitBreakerConfig
+	// SharedLimiter throttles REST requests across every client sharing the API key.
+	SharedLimiter DistributedLimiter
+	// RateLimit and RateLimitBurst throttle this client's REST requests with a
+	// local token bucket (0: unlimited).
+	RateLimit      float64
+	RateLimitBurst int
 
 	// Headers are additional headers to include in all requests.
 	Headers map[string]string
 	// UserAgent is the custom user agent string.
 	UserAgent string
+	// AppName identifies the calling application (sent as X-Client-App and appended to the User-Agent).
+	AppName string
+	// AppVersion is the version of the calling application.
+	AppVersion string
+	// QueuePrefix is prepended to every queue name (e.g. "staging-").
+	QueuePrefix string
+	// EnqueueGuard, if set, blocks enqueues to queues with too many pending jobs.
+	EnqueueGuard *GuardConfig
+	// SchemaRegistryURL, if set, validates payloads that carry a schema ID.
+	SchemaRegistryURL string
+	// QueueConfigs are the queue configurations SpooledWorker ensures at
+	// startup, by queue name.
+	QueueConfigs map[string]resources.UpdateQueueConfigRequest
+	// OfflineSpoolDir, if set, stores enqueues made while the API is
+	// unreachable in this directory and replays them later.
+	OfflineSpoolDir string
+	// Codec is an alternate REST body codec (default: JSON).
+	Codec Codec
+	// PayloadCompressionThreshold is the request size in bytes from which job
+	// enqueues are gzipped (0 disables compression).
+	PayloadCompressionThreshold int
+	// MaxPayloadBytes rejects job payloads larger than this when encoded as
+	// JSON before they are sent (0: no limit).
+	MaxPayloadBytes int
+	// ClockSkewThreshold is the client/server clock skew that triggers a
+	// clock.skew_detected event and debug log (default: 5s).
+	ClockSkewThreshold time.Duration
+	// CoalesceGETs shares one upstream request between concurrent identical GETs.
+	CoalesceGETs bool
+	// PayloadRedactor rewrites payloads returned by Queues().SamplePayloads.
+	PayloadRedactor PayloadRedactor
+	// PayloadTransforms rewrite job payloads as they are enqueued and read.
+	PayloadTransforms *resources.PayloadTransforms
+	// StrictDeprecations fails calls to deprecated endpoints with a *DeprecationError.
+	StrictDeprecations bool
+	// Protection requires destructive operations to be confirmed or dry-run first.
+	Protection bool
+	// APIVersion pins the dated API version, e.g. "2024-11" (default: the
+	// organization's default version).
+	APIVersion string
+	// UseNumber decodes payload and result numbers as json.Number instead of float64.
+	UseNumber bool
+	// LegacyFieldNames accepts camelCase field names in REST responses.
+	LegacyFieldNames bool
+	// TracePropagation carries trace context from producers to workers via job tags.
+	TracePropagation bool
+	// TracePropagator injects and extracts trace context (default: propagation.W3C).
+	TracePropagator propagation.Propagator
 	// Logger is the debug logger.
 	Logger Logger
 	// AutoRefreshToken enables automatic token refresh.
@@ -124,6 +211,23 @@ func WithAPIKey(key string) Option {
 	}
 }
 
+// WithCredentialProvider loads the API key from p and swaps in new keys as p
+// reports them, without restarting the process. An explicit WithAPIKey takes
+// precedence for the initial key.
+func WithCredentialProvider(p CredentialProvider) Option {
+	return func(c *Config) {
+		c.CredentialProvider = p
+	}
+}
+
+// WithCredentialFile reads the API key from the file at path, e.g. a mounted
+// Kubernetes secret, and reloads it when the file changes. The file is polled
+// every DefaultCredentialPollInterval; use WithCredentialProvider with
+// FileCredentials to change the interval.
+func WithCredentialFile(path string) Option {
+	return WithCredentialProvider(FileCredentials(path))
+}
+
 // WithAccessToken sets the JWT access token.
 func WithAccessToken(token string) Option {
 	return func(c *Config) {
@@ -166,6 +270,89 @@ func WithGRPCAddress(addr string) Option {
 	}
 }
 
+// WithPreferredTransport selects the protocol used by Client.Transport and
+// workers: TransportREST (default), TransportGRPC, or TransportAuto.
+func WithPreferredTransport(kind TransportKind) Option {
+	return func(c *Config) {
+		c.PreferredTransport = kind
+	}
+}
+
+// WithBaseURLs sets the primary REST base URL and fallbacks for regional
+// failover. Requests go to the first healthy URL; a URL that returns network
+// errors or 502/503/504 is skipped for FailoverConfig.Cooldown.
+func WithBaseURLs(primary string, fallbacks ...string) Option {
+	return func(c *Config) {
+		c.BaseURL = primary
+		c.FallbackBaseURLs = fallbacks
+	}
+}
+
+// WithGRPCAddresses sets the primary gRPC address and fallbacks. The client
+// connects to the first reachable address, in order.
+func WithGRPCAddresses(primary string, fallbacks ...string) Option {
+	return func(c *Config) {
+		c.GRPCAddress = primary
+		c.FallbackGRPCAddresses = fallbacks
+	}
+}
+
+// WithGRPCMaxMessageSize sets the largest gRPC messages the client will
+// receive and send, in bytes; zero keeps the 4MB default. Workers on the gRPC
+// transport upload results larger than send in chunks over REST.
+func WithGRPCMaxMessageSize(recv, send int) Option {
+	return func(c *Config) {
+		c.GRPCMaxRecvMsgSize = recv
+		c.GRPCMaxSendMsgSize = send
+	}
+}
+
+// WithGRPCIdleTimeout closes the gRPC connection after d without calls; the
+// next call re-dials transparently. A negative d keeps the connection open
+// for the life of the client.
+func WithGRPCIdleTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.GRPCIdleTimeout = d
+	}
+}
+
+// FailoverConfig configures failover between REST base URLs.
+type FailoverConfig = httpx.FailoverConfig
+
+// WithFailover configures failover between the URLs given to WithBaseURLs,
+// e.g. to enable latency-based selection and background health probes.
+func WithFailover(cfg FailoverConfig) Option {
+	return func(c *Config) {
+		c.Failover = cfg
+	}
+}
+
+// DistributedLimiter throttles requests cooperatively across processes.
+// ratelimit.Redis is a reference implementation.
+type DistributedLimiter = httpx.Limiter
+
+// WithSharedLimiter makes every REST request, including retries, wait on l
+// first, so a fleet of clients sharing one API key stays within the
+// organization's rate limit instead of each retrying independently. gRPC calls
+// are not throttled.
+func WithSharedLimiter(l DistributedLimiter) Option {
+	return func(c *Config) {
+		c.SharedLimiter = l
+	}
+}
+
+// WithRateLimit throttles this client's REST requests, including retries, to
+// rps per second with bursts of up to burst, so bulk producers stay under the
+// organization's rate limit instead of being rejected with 429s. Requests wait
+// for a token rather than failing. Combine it with WithSharedLimiter to also
+// coordinate across processes.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Config) {
+		c.RateLimit = rps
+		c.RateLimitBurst = burst
+	}
+}
+
 // WithTimeout sets the request timeout.
 func WithTimeout(d time.Duration) Option {
 	return func(c *Config) {
@@ -173,6 +360,15 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithHTTPClient sets the HTTP client used for REST requests, e.g. one with
+// a custom RoundTripper or proxy settings. The request timeout still applies
+// if the client has none.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
 // WithRetry sets the retry configuration.
 func WithRetry(cfg RetryConfig) Option {
 	return func(c *Config) {
@@ -206,6 +402,245 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
+// WithAppInfo identifies the calling application. The name and version are
+// appended to the User-Agent and sent as an X-Client-App header on every
+// REST and gRPC call, so traffic can be attributed to a specific service.
+func WithAppInfo(name, version string) Option {
+	return func(c *Config) {
+		c.AppName = name
+		c.AppVersion = version
+	}
+}
+
+// Codec encodes and decodes REST request and response bodies.
+type Codec = httpx.Codec
+
+// WithCodec registers an alternate body codec such as msgpack or CBOR.
+// The codec's media type is sent in Content-Type and Accept; responses in any
+// other format are decoded as JSON, and if the server rejects the codec with
+// 415 Unsupported Media Type the client switches request bodies back to JSON.
+func WithCodec(codec Codec) Option {
+	return func(c *Config) {
+		c.Codec = codec
+	}
+}
+
+// WithPayloadCompression gzips job enqueues of at least thresholdBytes:
+// Jobs().Create and BulkEnqueue send them with Content-Encoding: gzip, and
+// gRPC Enqueue calls use the gzip compressor. If the server rejects a
+// compressed body with 415 Unsupported Media Type, the client resends it and
+// later bodies uncompressed. Zero disables compression.
+func WithPayloadCompression(thresholdBytes int) Option {
+	return func(c *Config) {
+		c.PayloadCompressionThreshold = thresholdBytes
+	}
+}
+
+// WithMaxPayloadBytes rejects job payloads larger than n bytes when encoded as
+// JSON before anything is sent, in Jobs().Create, BulkEnqueue, and gRPC
+// Enqueue; see AsPayloadTooLargeError. Zero removes the limit.
+func WithMaxPayloadBytes(n int) Option {
+	return func(c *Config) {
+		c.MaxPayloadBytes = n
+	}
+}
+
+// WithClockSkewThreshold sets how far the local clock may drift from the
+// server's before a clock.skew_detected event is emitted. The measured skew is
+// always applied when CreateJobRequest.Delay is converted to ScheduledAt.
+func WithClockSkewThreshold(d time.Duration) Option {
+	return func(c *Config) {
+		c.ClockSkewThreshold = d
+	}
+}
+
+// WithUseNumber decodes numbers in job payloads, results, and other untyped
+// fields as json.Number instead of float64, over both REST and gRPC, so 64-bit
+// IDs and other large integers are not corrupted. Read them with the helpers
+// in the payload package, e.g. payload.Map(job.Payload).Int64("user_id").
+func WithUseNumber(enabled bool) Option {
+	return func(c *Config) {
+		c.UseNumber = enabled
+	}
+}
+
+// WithLegacyFieldNames makes REST responses decode whether the server names
+// fields in snake_case or in camelCase, as some older self-hosted servers do.
+// Only the SDK's own fields are renamed; job payloads, results, tags, and other
+// untyped values are returned as the server sent them. Decoding is slower in
+// this mode, so enable it only for servers that need it.
+func WithLegacyFieldNames(enabled bool) Option {
+	return func(c *Config) {
+		c.LegacyFieldNames = enabled
+	}
+}
+
+// WithStrictDeprecations fails every call to an endpoint the API marks as
+// deprecated with a *DeprecationError. The request has already been executed
+// when the error is returned, so enable it in tests and CI to catch upcoming
+// removals, not in production.
+func WithStrictDeprecations(enabled bool) Option {
+	return func(c *Config) {
+		c.StrictDeprecations = enabled
+	}
+}
+
+// WithProtection makes destructive operations, Jobs().DLQ().Purge and
+// Queues().Delete, fail with resources.ErrConfirmationRequired unless they
+// set Confirm or follow a DryRun of the same operation, so a mistyped ops
+// script cannot wipe a queue. Every attempt emits an
+// audit.destructive_operation event whether or not protection is enabled.
+func WithProtection(enabled bool) Option {
+	return func(c *Config) {
+		c.Protection = enabled
+	}
+}
+
+// WithAPIVersion pins the dated API version ("YYYY-MM", e.g. "2024-11") sent
+// in the Spooled-Version header on every REST and gRPC call. The server then
+// keeps request validation, defaults, and response shapes as they were in
+// that version, so upgrading the SDK or a change to the organization's default
+// version does not silently change behavior. If the server applies a different
+// version, an api.version_mismatch event is emitted.
+//
+// This is independent of the /api/v1 path version (DefaultAPIVersion).
+func WithAPIVersion(version string) Option {
+	return func(c *Config) {
+		c.APIVersion = version
+	}
+}
+
+// PayloadRedactor rewrites sampled job payloads and results.
+type PayloadRedactor = resources.PayloadRedactor
+
+// WithPayloadRedactor sets the redactor applied to payloads and results
+// returned by Queues().SamplePayloads, e.g. resources.RedactKeys("email", "ssn").
+func WithPayloadRedactor(redactor PayloadRedactor) Option {
+	return func(c *Config) {
+		c.PayloadRedactor = redactor
+	}
+}
+
+// PayloadTransform rewrites a job payload.
+type PayloadTransform = resources.PayloadTransform
+
+// WithPayloadTransform applies outbound to the payload of every job enqueued,
+// over REST or gRPC, and inbound to the payload of every job read or claimed,
+// e.g. to inject a tenant ID on the way out and strip internal fields on the
+// way in. Either may be nil. outbound is passed a shallow copy of the caller's
+// payload.
+func WithPayloadTransform(outbound, inbound PayloadTransform) Option {
+	return func(c *Config) {
+		c.PayloadTransforms = &resources.PayloadTransforms{Outbound: outbound, Inbound: inbound}
+	}
+}
+
+// WithRequestCoalescing deduplicates concurrent identical GET requests (same
+// path, query, and headers) so they share a single upstream request. This
+// reduces load from dashboards and pollers that read the same resource from
+// many goroutines. A caller whose context is cancelled stops waiting without
+// cancelling the shared request for the others.
+func WithRequestCoalescing(enabled bool) Option {
+	return func(c *Config) {
+		c.CoalesceGETs = enabled
+	}
+}
+
+// WithTracePropagation injects the caller's trace context and baggage into job
+// tags on Jobs().Create and restores it into the handler's context in workers,
+// so distributed traces span producer, queue, and worker. gRPC jobs carry no
+// tags, so with TransportGRPC traced enqueues and worker claims use REST.
+func WithTracePropagation(enabled bool) Option {
+	return func(c *Config) {
+		c.TracePropagation = enabled
+	}
+}
+
+// WithTracePropagator sets the propagator used for trace propagation, e.g. an
+// OpenTelemetry adapter, and enables propagation.
+func WithTracePropagator(p propagation.Propagator) Option {
+	return func(c *Config) {
+		c.TracePropagator = p
+		c.TracePropagation = p != nil
+	}
+}
+
+// GuardConfig configures the producer-side enqueue guard.
+type GuardConfig = resources.GuardConfig
+
+// GuardAction is what the enqueue guard does with a job for an overloaded queue.
+type GuardAction = resources.GuardAction
+
+// Enqueue guard actions.
+const (
+	GuardReject = resources.GuardReject
+	GuardDelay  = resources.GuardDelay
+	GuardSpill  = resources.GuardSpill
+)
+
+// WithEnqueueGuard protects the platform from runaway producers: queue stats
+// are sampled periodically and enqueues to queues with MaxPendingPerQueue or
+// more pending jobs are rejected, delayed, or spilled according to cfg.Action.
+// Blocked enqueues return a *resources.QueueOverloadedError.
+func WithEnqueueGuard(cfg GuardConfig) Option {
+	return func(c *Config) {
+		c.EnqueueGuard = &cfg
+	}
+}
+
+// WithSchemaRegistry validates job payloads against JSON Schemas held by the
+// registry at url. Payloads name their schema with a resources.SchemaIDKey
+// entry and are checked when enqueued (REST and gRPC) and when claimed by a
+// SpooledWorker, so producer/consumer contract drift fails fast with a
+// *resources.SchemaValidationError. Payloads without a schema ID are not
+// checked.
+func WithSchemaRegistry(url string) Option {
+	return func(c *Config) {
+		c.SchemaRegistryURL = url
+	}
+}
+
+// WithQueueConfig declares the configuration of a queue in code. A
+// SpooledWorker for the queue calls Queues().Ensure with it on Start, creating
+// the queue if it is missing and failing to start if the queue exists with a
+// conflicting configuration (*resources.QueueConfigConflictError). Only the
+// fields set in config are checked. Use it once per queue.
+func WithQueueConfig(name string, config resources.UpdateQueueConfigRequest) Option {
+	return func(c *Config) {
+		if c.QueueConfigs == nil {
+			c.QueueConfigs = make(map[string]resources.UpdateQueueConfigRequest)
+		}
+		c.QueueConfigs[name] = config
+	}
+}
+
+// WithOfflineSpool enables store-and-forward enqueueing for hosts with flaky
+// connectivity. When the API is unreachable (network errors, timeouts, or an
+// open circuit breaker), Jobs().Create and Jobs().BulkEnqueue durably write
+// the request to dir and return a response with Spooled set instead of an
+// error. The client replays the spool on creation and every
+// OfflineSpoolInterval, or call Jobs().ReplaySpool to flush it sooner. Every spooled request carries idempotency keys, so a
+// request that did reach the API is not enqueued twice. Requests the API
+// rejects on replay are moved to dir/failed.
+//
+// Only REST enqueues are spooled. NewClient fails if dir cannot be created.
+func WithOfflineSpool(dir string) Option {
+	return func(c *Config) {
+		c.OfflineSpoolDir = dir
+	}
+}
+
+// WithQueuePrefix prepends prefix to every queue name used by Jobs, Queues,
+// Schedules, Workflows, Ingest, and workers, so one codebase can share
+// infrastructure across environments. The prefix is added even to names that
+// already start with it; queue names in API responses carry the prefix, so
+// pass them back with WithoutQueuePrefix on the call's context.
+func WithQueuePrefix(prefix string) Option {
+	return func(c *Config) {
+		c.QueuePrefix = prefix
+	}
+}
+
 // WithLogger sets the debug logger.
 func WithLogger(l Logger) Option {
 	return func(c *Config) {
@@ -241,15 +676,16 @@ func WithAutoRefreshToken(enabled bool) Option {
 // newDefaultConfig creates a new config with default values.
 func newDefaultConfig() *Config {
 	return &Config{
-		BaseURL:          DefaultBaseURL,
-		WSURL:            DefaultWSURL,
-		GRPCAddress:      DefaultGRPCAddress,
-		Timeout:          DefaultTimeout,
-		Retry:            DefaultRetryConfig(),
-		CircuitBreaker:   DefaultCircuitBreakerConfig(),
-		Headers:          make(map[string]string),
-		UserAgent:        version.UserAgent(),
-		AutoRefreshToken: true,
+		BaseURL:            DefaultBaseURL,
+		WSURL:              DefaultWSURL,
+		GRPCAddress:        DefaultGRPCAddress,
+		PreferredTransport: TransportREST,
+		Timeout:            DefaultTimeout,
+		Retry:              DefaultRetryConfig(),
+		CircuitBreaker:     DefaultCircuitBreakerConfig(),
+		Headers:            make(map[string]string),
+		UserAgent:          version.UserAgent(),
+		AutoRefreshToken:   true,
 	}
 }
 
@@ -260,6 +696,14 @@ func resolveConfig(opts ...Option) *Config {
 		opt(cfg)
 	}
 
+	// Tag traffic with the application identity
+	if app := cfg.appInfo(); app != "" {
+		if !strings.HasSuffix(cfg.UserAgent, app) {
+			cfg.UserAgent = strings.TrimSpace(cfg.UserAgent + " " + app)
+		}
+		cfg.Headers[ClientAppHeader] = app
+	}
+
 	// Derive WS URL from base URL if not explicitly set
 	if cfg.WSURL == DefaultWSURL && cfg.BaseURL != DefaultBaseURL {
 		cfg.WSURL = deriveWSURL(cfg.BaseURL)
@@ -268,6 +712,17 @@ func resolveConfig(opts ...Option) *Config {
 	return cfg
 }
 
+// appInfo returns the "name/version" application identifier, or "" if unset.
+func (c *Config) appInfo() string {
+	if c.AppName == "" {
+		return ""
+	}
+	if c.AppVersion == "" {
+		return c.AppName
+	}
+	return c.AppName + "/" + c.AppVersion
+}
+
 // deriveWSURL converts an HTTP URL to a WebSocket URL.
 func deriveWSURL(baseURL string) string {
 	if strings.HasPrefix(baseURL, "https://") {
@@ -0,0 +1,124 @@
+package contract
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// IncompatibleError is returned when a consumer cannot read every payload a
+// producer's contract allows.
+type IncompatibleError struct {
+	Violations []string
+}
+
+func (e *IncompatibleError) Error() string {
+	return "consumer does not match producer contract: " + strings.Join(e.Violations, "; ")
+}
+
+// Check reports whether a consumer decoding payloads into v, a struct value
+// or pointer, can read every payload producer allows. See Compatible.
+func Check(producer *Schema, v any) error {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return fmt.Errorf("contract consumer must be a struct, got %T", v)
+	}
+	consumer, err := SchemaOf(t)
+	if err != nil {
+		return err
+	}
+	return Compatible(producer, consumer)
+}
+
+// Compatible reports whether every payload valid under producer is valid
+// under consumer, returning an *IncompatibleError listing where they differ:
+// fields the consumer requires that the producer may omit or never sends,
+// values of a type, format, or nullability the consumer does not accept.
+// Fields the producer sends and the consumer ignores are compatible.
+func Compatible(producer, consumer *Schema) error {
+	var violations []string
+	compare(producer, consumer, "", &violations)
+	if len(violations) > 0 {
+		return &IncompatibleError{Violations: violations}
+	}
+	return nil
+}
+
+func compare(p, c *Schema, path string, violations *[]string) {
+	if len(c.Type) == 0 {
+		return
+	}
+	if len(p.Type) == 0 {
+		*violations = append(*violations, fmt.Sprintf("%s: producer sends any value, consumer expects %s", pathOrRoot(path), typeString(c.Type)))
+		return
+	}
+	for _, t := range p.Type {
+		if !c.has(t) && (t != "integer" || !c.has("number")) {
+			*violations = append(*violations, fmt.Sprintf("%s: producer sends %s, consumer expects %s", pathOrRoot(path), typeString(p.Type), typeString(c.Type)))
+			return
+		}
+	}
+	if c.Format != "" && c.Format != p.Format {
+		*violations = append(*violations, fmt.Sprintf("%s: consumer expects format %s", pathOrRoot(path), c.Format))
+	}
+	if c.ContentEncoding != "" && c.ContentEncoding != p.ContentEncoding {
+		*violations = append(*violations, fmt.Sprintf("%s: consumer expects %s content", pathOrRoot(path), c.ContentEncoding))
+	}
+
+	if p.has("array") && c.Items != nil {
+		items := p.Items
+		if items == nil {
+			items = &Schema{}
+		}
+		compare(items, c.Items, path+"[]", violations)
+	}
+	if p.has("object") {
+		compareObjects(p, c, path, violations)
+	}
+}
+
+func compareObjects(p, c *Schema, path string, violations *[]string) {
+	for _, name := range sortedKeys(c.Properties) {
+		fieldPath := join(path, name)
+		pp, ok := p.Properties[name]
+		if !ok {
+			pp = p.AdditionalProperties
+		}
+		switch {
+		case pp == nil && slices.Contains(c.Required, name):
+			*violations = append(*violations, fmt.Sprintf("%s: consumer requires a field the producer does not send", fieldPath))
+			continue
+		case pp == nil:
+			continue
+		case slices.Contains(c.Required, name) && !slices.Contains(p.Required, name):
+			*violations = append(*violations, fmt.Sprintf("%s: consumer requires a field the producer may omit", fieldPath))
+		}
+		compare(pp, c.Properties[name], fieldPath, violations)
+	}
+
+	if c.AdditionalProperties != nil {
+		if p.AdditionalProperties != nil {
+			compare(p.AdditionalProperties, c.AdditionalProperties, path+"{}", violations)
+		}
+		for _, name := range sortedKeys(p.Properties) {
+			if _, ok := c.Properties[name]; !ok {
+				compare(p.Properties[name], c.AdditionalProperties, join(path, name), violations)
+			}
+		}
+	}
+}
+
+func typeString(types TypeList) string {
+	return strings.Join(types, " or ")
+}
+
+func sortedKeys(properties map[string]*Schema) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+// Package contract derives payload contracts from Go structs, so producers
+// and consumers written against different SDKs agree on a queue's payload.
+//
+// A Contract holds the JSON Schema of a payload type, following
+// encoding/json's rules for field names, omitempty, and embedded structs.
+// Contracts can be written out as JSON Schema for registries and other
+// languages, rendered as TypeScript types for Node workers, and checked
+// against a consumer's struct:
+//
+//	c, err := contract.New("emails", EmailPayload{})
+//	schema, err := c.JSONSchema()
+//	err = contract.Check(c.Schema, ConsumerEmail{})
+//
+// The spooled-contract command generates these files from annotated types;
+// see cmd/spooled-contract.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SchemaDialect is the JSON Schema draft generated schemas declare.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Contract is the payload contract of a queue.
+type Contract struct {
+	// Name is the payload's Go type name, used as the schema title and the
+	// TypeScript type name.
+	Name   string  `json:"name"`
+	Queue  string  `json:"queue"`
+	Schema *Schema `json:"schema"`
+}
+
+// New derives the contract for payloads of type v on queue. v is a value or
+// pointer of a named struct type.
+func New(queue string, v any) (*Contract, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+		return nil, fmt.Errorf("contract payload must be a named struct, got %T", v)
+	}
+	schema, err := SchemaOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return &Contract{Name: t.Name(), Queue: queue, Schema: schema}, nil
+}
+
+// JSONSchema returns the contract as an indented JSON Schema document,
+// titled with Name and carrying the queue as x-spooled-queue.
+func (c *Contract) JSONSchema() ([]byte, error) {
+	doc := *c.Schema
+	doc.Dialect = SchemaDialect
+	doc.Title = c.Name
+	doc.Queue = c.Queue
+	data, err := json.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Load parses a JSON Schema document written by JSONSchema back into a
+// Contract.
+func Load(data []byte) (*Contract, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid contract schema: %w", err)
+	}
+	c := &Contract{Name: schema.Title, Queue: schema.Queue, Schema: &schema}
+	schema.Dialect, schema.Title, schema.Queue = "", "", ""
+	return c, nil
+}
@@ -0,0 +1,130 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+type contractAddress struct {
+	City string `json:"city"`
+}
+
+type contractEmail struct {
+	To      string            `json:"to"`
+	CC      []string          `json:"cc,omitempty"`
+	SendAt  *time.Time        `json:"send_at,omitempty"`
+	Retries int               `json:"retries"`
+	Address contractAddress   `json:"address"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func TestContract_SchemaTypeScriptAndCheck(t *testing.T) {
+	c, err := New("emails", &contractEmail{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if c.Name != "contractEmail" || c.Queue != "emails" {
+		t.Fatalf("contract = %s on %s", c.Name, c.Queue)
+	}
+	if want := []string{"to", "retries", "address"}; !reflect.DeepEqual(c.Schema.Required, want) {
+		t.Errorf("Required = %v, want %v", c.Schema.Required, want)
+	}
+	if got := c.Schema.Properties["send_at"]; got.Format != "date-time" || !reflect.DeepEqual(got.Type, TypeList{"string", "null"}) {
+		t.Errorf("send_at = %+v", got)
+	}
+
+	doc, err := c.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	loaded, err := Load(doc)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Name != c.Name || loaded.Queue != c.Queue || !reflect.DeepEqual(loaded.Schema, c.Schema) {
+		t.Errorf("Load round trip = %+v", loaded)
+	}
+
+	// The schema validates payloads through a schema registry.
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(doc)
+	}))
+	defer registry.Close()
+	schemas := resources.NewSchemaRegistry(registry.URL, nil)
+	valid := map[string]any{resources.SchemaIDKey: "1", "to": "a@example.com", "retries": 3, "address": map[string]any{"city": "Oslo"}}
+	if err := schemas.Validate(context.Background(), valid); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+	invalid := map[string]any{resources.SchemaIDKey: "1", "to": "a@example.com", "retries": 1.5, "address": map[string]any{}}
+	var validationErr *resources.SchemaValidationError
+	if err := schemas.Validate(context.Background(), invalid); !errors.As(err, &validationErr) || len(validationErr.Violations) != 2 {
+		t.Errorf("Validate(invalid) = %v, want 2 violations", err)
+	}
+
+	ts := string(TypeScript(c))
+	for _, want := range []string{
+		"export interface contractEmail {\n",
+		"  address: {\n    city: string;\n  };\n",
+		"  cc?: string[] | null;\n",
+		"  labels?: Record<string, string> | null;\n",
+		"  retries: number;\n",
+		"  send_at?: string | null;\n",
+		"export interface QueuePayloads {\n  emails: contractEmail;\n}\n",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("TypeScript missing %q:\n%s", want, ts)
+		}
+	}
+
+	// A consumer reading a subset of the fields, with wider number types,
+	// matches.
+	type compatibleConsumer struct {
+		To      string  `json:"to"`
+		Retries float64 `json:"retries"`
+	}
+	if err := Check(c.Schema, compatibleConsumer{}); err != nil {
+		t.Errorf("Check(compatible) = %v", err)
+	}
+
+	type staleConsumer struct {
+		To      int        `json:"to"`
+		CC      []string   `json:"cc"`
+		Subject string     `json:"subject"`
+		SendAt  *time.Time `json:"send_at,omitempty"`
+		Address struct {
+			Zip string `json:"zip"`
+		} `json:"address"`
+	}
+	err = Check(c.Schema, staleConsumer{})
+	var incompatible *IncompatibleError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("Check(stale) = %v, want IncompatibleError", err)
+	}
+	want := []string{
+		"address.zip: consumer requires a field the producer does not send",
+		"cc: consumer requires a field the producer may omit",
+		"subject: consumer requires a field the producer does not send",
+		"to: producer sends string, consumer expects integer",
+	}
+	if !reflect.DeepEqual(incompatible.Violations, want) {
+		t.Errorf("Violations = %q, want %q", incompatible.Violations, want)
+	}
+
+	if _, err := New("emails", map[string]any{}); err == nil {
+		t.Error("New(map) succeeded, want error")
+	}
+	type badPayload struct {
+		Done chan bool `json:"done"`
+	}
+	if _, err := New("emails", badPayload{}); err == nil || !strings.Contains(err.Error(), "done") {
+		t.Errorf("New(chan field) = %v, want error naming the field", err)
+	}
+}
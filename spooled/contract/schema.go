@@ -0,0 +1,331 @@
+package contract
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema a payload contract uses. An empty
+// Schema accepts any value.
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 TypeList           `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	// Queue is the queue the payload is sent to, in schema documents.
+	Queue string `json:"x-spooled-queue,omitempty"`
+}
+
+// TypeList is a schema's "type": a single JSON type, or several when a
+// value may also be null.
+type TypeList []string
+
+// MarshalJSON writes a single type as a string.
+func (l TypeList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return json.Marshal(l[0])
+	}
+	return json.Marshal([]string(l))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (l *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("schema type must be a string or array of strings: %w", err)
+	}
+	*l = list
+	return nil
+}
+
+// has reports whether the schema allows JSON type t.
+func (s *Schema) has(t string) bool {
+	return slices.Contains(s.Type, t)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	numberType        = reflect.TypeOf(json.Number(""))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaOf derives the schema of values of type t as encoding/json writes
+// them. Types with their own MarshalJSON accept any value, and recursive
+// struct types are cut off with an empty schema where they recur.
+func SchemaOf(t reflect.Type) (*Schema, error) {
+	g := &generator{visiting: make(map[reflect.Type]bool)}
+	return g.schema(t, "")
+}
+
+type generator struct {
+	visiting map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type, path string) (*Schema, error) {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		elem, err := g.schema(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case t == timeType:
+		return &Schema{Type: TypeList{"string"}, Format: "date-time"}, nil
+	case t == numberType:
+		return &Schema{Type: TypeList{"number"}}, nil
+	case implements(t, jsonMarshalerType):
+		return &Schema{}, nil
+	case implements(t, textMarshalerType):
+		return &Schema{Type: TypeList{"string"}}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeList{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: TypeList{"integer"}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeList{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: TypeList{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !implements(t.Elem(), jsonMarshalerType) && !implements(t.Elem(), textMarshalerType) {
+			return &Schema{Type: TypeList{"string", "null"}, ContentEncoding: "base64"}, nil
+		}
+		items, err := g.schema(t.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeList{"array", "null"}, Items: items}, nil
+	case reflect.Array:
+		items, err := g.schema(t.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeList{"array"}, Items: items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !implements(t.Key(), textMarshalerType) {
+				return nil, fmt.Errorf("%s: unsupported map key type %s", pathOrRoot(path), t.Key())
+			}
+		}
+		values, err := g.schema(t.Elem(), path+"{}")
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeList{"object", "null"}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.object(t, path)
+	default:
+		return nil, fmt.Errorf("%s: unsupported type %s", pathOrRoot(path), t)
+	}
+}
+
+// field is a struct field as encoding/json sees it.
+type field struct {
+	name     string
+	index    []int
+	typ      reflect.Type
+	tagged   bool
+	optional bool
+	quoted   bool
+}
+
+func (g *generator) object(t reflect.Type, path string) (*Schema, error) {
+	if g.visiting[t] {
+		return &Schema{}, nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{Type: TypeList{"object"}, Properties: make(map[string]*Schema)}
+	for _, f := range fields(t) {
+		prop, err := g.schema(f.typ, join(path, f.name))
+		if err != nil {
+			return nil, err
+		}
+		if f.quoted {
+			prop = quoted(prop)
+		}
+		s.Properties[f.name] = prop
+		if !f.optional {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s, nil
+}
+
+// fields lists the JSON fields of struct type t, promoting the fields of
+// embedded structs. As in encoding/json, a shallower field hides deeper ones
+// of the same name, and ambiguous fields at the same depth are dropped unless
+// exactly one is tagged.
+func fields(t reflect.Type) []field {
+	var all []field
+	collect(t, nil, false, make(map[reflect.Type]bool), &all)
+
+	byName := make(map[string][]field)
+	var order []string
+	for _, f := range all {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var out []field
+	for _, name := range order {
+		if f, ok := dominant(byName[name]); ok {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func collect(t reflect.Type, index []int, optional bool, seen map[reflect.Type]bool, out *[]field) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := sf.Type
+		if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous {
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+			if name == "" && ft.Kind() == reflect.Struct {
+				collect(ft, append(slices.Clip(index), i), optional || sf.Type.Kind() == reflect.Pointer, seen, out)
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		f := field{
+			name:     name,
+			index:    append(slices.Clip(index), i),
+			typ:      sf.Type,
+			tagged:   name != "",
+			optional: optional || hasOption(opts, "omitempty"),
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		if hasOption(opts, "string") {
+			switch ft.Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64, reflect.String:
+				f.quoted = true
+			}
+		}
+		*out = append(*out, f)
+	}
+}
+
+// dominant picks the field encoding/json uses among fields sharing a name.
+func dominant(fs []field) (field, bool) {
+	depth := len(fs[0].index)
+	for _, f := range fs[1:] {
+		depth = min(depth, len(f.index))
+	}
+	var candidates []field
+	for _, f := range fs {
+		if len(f.index) == depth {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	var tagged []field
+	for _, f := range candidates {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return field{}, false
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || (t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(iface))
+}
+
+// nullable returns s also allowing null.
+func nullable(s *Schema) *Schema {
+	if len(s.Type) == 0 || s.has("null") {
+		return s
+	}
+	n := *s
+	n.Type = append(slices.Clip(s.Type), "null")
+	return &n
+}
+
+// quoted is the schema of a value written with the ",string" option.
+func quoted(s *Schema) *Schema {
+	q := &Schema{Type: TypeList{"string"}}
+	if s.has("null") {
+		q.Type = append(q.Type, "null")
+	}
+	return q
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "payload"
+	}
+	return path
+}
@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GeneratedHeader starts every file the spooled-contract command writes.
+const GeneratedHeader = "Code generated by spooled-contract. DO NOT EDIT."
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript renders contracts as a TypeScript module for Node workers: one
+// exported type per contract, named after it, and a QueuePayloads interface
+// mapping each queue to its payload type.
+func TypeScript(contracts ...*Contract) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n", GeneratedHeader)
+	for _, c := range contracts {
+		fmt.Fprintf(&buf, "\n/** Payload of jobs on the %q queue. */\n", c.Queue)
+		if c.Schema.has("object") && len(c.Schema.Type) == 1 && c.Schema.AdditionalProperties == nil {
+			fmt.Fprintf(&buf, "export interface %s %s\n", c.Name, tsObject(c.Schema, ""))
+		} else {
+			fmt.Fprintf(&buf, "export type %s = %s;\n", c.Name, tsType(c.Schema, ""))
+		}
+	}
+
+	buf.WriteString("\n/** Payload types by queue name. */\nexport interface QueuePayloads {\n")
+	for _, c := range contracts {
+		fmt.Fprintf(&buf, "  %s: %s;\n", tsKey(c.Queue), c.Name)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// tsType renders a schema as a TypeScript type; indent is the indentation of
+// the line the type starts on.
+func tsType(s *Schema, indent string) string {
+	if len(s.Type) == 0 {
+		return "unknown"
+	}
+	var parts []string
+	add := func(part string) {
+		if !slices.Contains(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	for _, t := range s.Type {
+		switch t {
+		case "string":
+			add("string")
+		case "integer", "number":
+			add("number")
+		case "boolean":
+			add("boolean")
+		case "null":
+			add("null")
+		case "array":
+			items := "unknown"
+			if s.Items != nil {
+				items = tsType(s.Items, indent)
+			}
+			if strings.Contains(items, " | ") {
+				items = "(" + items + ")"
+			}
+			add(items + "[]")
+		case "object":
+			switch {
+			case s.AdditionalProperties != nil && len(s.Properties) == 0:
+				add("Record<string, " + tsType(s.AdditionalProperties, indent) + ">")
+			case len(s.Properties) == 0:
+				add("Record<string, unknown>")
+			default:
+				add(tsObject(s, indent))
+			}
+		default:
+			add("unknown")
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// tsObject renders an object schema's properties as a type literal.
+func tsObject(s *Schema, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	inner := indent + "  "
+	for _, name := range sortedKeys(s.Properties) {
+		optional := ""
+		if !slices.Contains(s.Required, name) {
+			optional = "?"
+		}
+		fmt.Fprintf(&b, "%s%s%s: %s;\n", inner, tsKey(name), optional, tsType(s.Properties[name], inner))
+	}
+	if s.AdditionalProperties != nil {
+		fmt.Fprintf(&b, "%s[key: string]: %s;\n", inner, tsType(s.AdditionalProperties, inner))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsKey(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
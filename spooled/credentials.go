@@ -0,0 +1,106 @@
+package spooled
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultCredentialPollInterval is how often PollingCredentials re-fetches
+// the API key when no interval is set.
+const DefaultCredentialPollInterval = 10 * time.Second
+
+// CredentialProvider supplies the client's API key and reports changes, so
+// credentials can be rotated without restarting the process.
+type CredentialProvider interface {
+	// APIKey returns the current API key.
+	APIKey(ctx context.Context) (string, error)
+	// Watch calls onChange with the new API key whenever it changes, until
+	// ctx is cancelled. current is the key the client is using, so a change
+	// made after it was loaded is reported too.
+	Watch(ctx context.Context, current string, onChange func(apiKey string))
+}
+
+// PollingCredentials is a CredentialProvider that re-fetches the API key
+// every Interval, for secret managers without change notifications.
+type PollingCredentials struct {
+	// Fetch returns the current API key.
+	Fetch func(ctx context.Context) (string, error)
+	// Interval is the poll interval (default: DefaultCredentialPollInterval).
+	Interval time.Duration
+}
+
+// APIKey implements CredentialProvider.
+func (p *PollingCredentials) APIKey(ctx context.Context) (string, error) {
+	return p.Fetch(ctx)
+}
+
+// Watch implements CredentialProvider. Fetch errors are ignored and the
+// previous key stays in use until a fetch succeeds.
+func (p *PollingCredentials) Watch(ctx context.Context, current string, onChange func(apiKey string)) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultCredentialPollInterval
+	}
+	last := current
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			key, err := p.Fetch(ctx)
+			if err != nil || key == "" || key == last {
+				continue
+			}
+			last = key
+			onChange(key)
+		}
+	}
+}
+
+// FileCredentials returns a provider that reads the API key from path, such
+// as a mounted Kubernetes secret, and picks up changes to the file.
+// Surrounding whitespace is ignored.
+func FileCredentials(path string) *PollingCredentials {
+	return &PollingCredentials{
+		Fetch: func(ctx context.Context) (string, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", fmt.Errorf("read credential file: %w", err)
+			}
+			return strings.TrimSpace(string(data)), nil
+		},
+	}
+}
+
+// loadCredentials resolves the initial API key from the credential provider.
+func loadCredentials(cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	key, err := cfg.CredentialProvider.APIKey(ctx)
+	if err != nil {
+		return fmt.Errorf("load credentials: %w", err)
+	}
+	cfg.APIKey = key
+	return nil
+}
+
+// watchCredentials swaps in API keys reported by the credential provider
+// until the client is closed.
+func (c *Client) watchCredentials() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopCredentials = cancel
+	go c.cfg.CredentialProvider.Watch(ctx, c.transport.APIKey(), func(key string) {
+		if err := ValidateAPIKey(key); err != nil {
+			c.debug("ignoring reloaded API key", "error", err)
+			return
+		}
+		c.setAPIKey(key)
+		c.debug("API key reloaded")
+	})
+}
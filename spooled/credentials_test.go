@@ -0,0 +1,100 @@
+package spooled
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollingCredentials_Watch(t *testing.T) {
+	var key atomic.Value
+	key.Store("sp_test_new")
+	provider := &PollingCredentials{
+		Fetch:    func(context.Context) (string, error) { return key.Load().(string), nil },
+		Interval: 5 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan string, 10)
+	go provider.Watch(ctx, "sp_test_old", func(apiKey string) { changes <- apiKey })
+
+	// The key changed after the client loaded it, before Watch started
+	select {
+	case got := <-changes:
+		if got != "sp_test_new" {
+			t.Errorf("onChange(%q), want sp_test_new", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("change before Watch not reported")
+	}
+
+	// An unchanged key is not reported again
+	time.Sleep(50 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Errorf("onChange(%q) for an unchanged key", <-changes)
+	}
+
+	key.Store("sp_test_newer")
+	select {
+	case got := <-changes:
+		if got != "sp_test_newer" {
+			t.Errorf("onChange(%q), want sp_test_newer", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second change not reported")
+	}
+}
+
+func TestClient_CredentialFileReload(t *testing.T) {
+	const oldKey = testAPIKey
+	const newKey = "sp_test_abcdefghijklmnopqrstuvwxyzabcd"
+	var lastAuth atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastAuth.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "api-key")
+	if err := os.WriteFile(path, []byte(oldKey+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := FileCredentials(path)
+	provider.Interval = 10 * time.Millisecond
+
+	client, err := NewClient(WithCredentialProvider(provider), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Health().Get(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := lastAuth.Load(); got != "Bearer "+oldKey {
+		t.Errorf("Authorization = %v, want old key", got)
+	}
+
+	if err := os.WriteFile(path, []byte(newKey), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for client.GetConfig().APIKey != newKey {
+		if time.Now().After(deadline) {
+			t.Fatal("API key was not reloaded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := client.Health().Get(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := lastAuth.Load(); got != "Bearer "+newKey {
+		t.Errorf("Authorization = %v, want new key", got)
+	}
+}
@@ -0,0 +1,273 @@
+package spooled
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// DefaultDeadletterPollInterval is how often the DLQ is polled while realtime is unavailable.
+const DefaultDeadletterPollInterval = 10 * time.Second
+
+// deadletterPageSize is the page size the DLQ is listed with.
+const deadletterPageSize = 100
+
+// OnDeadletter invokes handler whenever a job in queue lands in the dead-letter queue.
+//
+// Deadletters are picked up from events on the client's shared realtime
+// connection (see Client.Realtime); while it is down the whole DLQ is polled
+// instead. Jobs already in the DLQ when
+// OnDeadletter is called are not reported. The watcher runs in the background
+// until ctx is cancelled.
+//
+// Example:
+//
+//	err := client.OnDeadletter(ctx, "emails", func(job *resources.Job) {
+//		log.Printf("job %s deadlettered", job.ID)
+//	})
+func (c *Client) OnDeadletter(ctx context.Context, queue string, handler func(job *resources.Job)) error {
+	w := &deadletterWatcher{
+		client:   c,
+		rt:       c.Realtime(),
+		queue:    queue,
+		handler:  handler,
+		interval: DefaultDeadletterPollInterval,
+	}
+	return w.start(ctx)
+}
+
+// ForwardDeadletters posts a message to webhookURL whenever a job in queue
+// lands in the dead-letter queue. The body is compatible with Slack incoming
+// webhooks and also carries the job details for generic receivers.
+func (c *Client) ForwardDeadletters(ctx context.Context, queue, webhookURL string) error {
+	return c.OnDeadletter(ctx, queue, c.deadletterForwarder(ctx, webhookURL))
+}
+
+// deadletterForwarder returns a deadletter handler that posts each job to
+// webhookURL.
+func (c *Client) deadletterForwarder(ctx context.Context, webhookURL string) func(job *resources.Job) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	return func(job *resources.Job) {
+		lastError := ""
+		if job.LastError != nil {
+			lastError = *job.LastError
+		}
+		text := fmt.Sprintf("Job %s in queue %s moved to the dead-letter queue after %d retries: %s", job.ID, job.QueueName, job.RetryCount, lastError)
+		message := map[string]any{
+			"job_id":     job.ID,
+			"queue_name": job.QueueName,
+			"error":      lastError,
+		}
+		if job.OwnerTeam != nil {
+			text += "\nOwner: " + *job.OwnerTeam
+			message["owner_team"] = *job.OwnerTeam
+		}
+		if job.RunbookURL != nil {
+			text += "\nRunbook: " + *job.RunbookURL
+			message["runbook_url"] = *job.RunbookURL
+		}
+		message["text"] = text
+		body, err := json.Marshal(message)
+		if err != nil {
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+		if err != nil {
+			c.debug("deadletter forward failed", "job_id", job.ID, "error", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			c.debug("deadletter forward failed", "job_id", job.ID, "error", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			c.debug("deadletter forward failed", "job_id", job.ID, "status", resp.StatusCode)
+		}
+	}
+}
+
+type deadletterWatcher struct {
+	client   *Client
+	rt       realtime.RealtimeClient
+	queue    string
+	handler  func(job *resources.Job)
+	interval time.Duration
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// start snapshots the current DLQ, so only new deadletters are reported,
+// and runs the watcher in the background until ctx is cancelled.
+func (w *deadletterWatcher) start(ctx context.Context) error {
+	jobs, err := w.list(ctx)
+	if err != nil {
+		return fmt.Errorf("list dead-letter queue: %w", err)
+	}
+	w.seen = make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		w.seen[job.ID] = true
+	}
+
+	go w.run(ctx)
+	return nil
+}
+
+func (w *deadletterWatcher) run(ctx context.Context) {
+	queueName := w.client.queueName(w.queue)
+	rt := w.rt
+	// Handlers are removed once ctx is done; until then they check it too
+	stopDeadletter := rt.OnJobEvent(realtime.EventJobDeadletter, func(e *realtime.JobEvent) {
+		if ctx.Err() == nil && (e.QueueName == "" || e.QueueName == queueName) {
+			w.report(ctx, e.JobID)
+		}
+	})
+	defer stopDeadletter()
+	stopFailed := rt.OnJobEvent(realtime.EventJobFailed, func(e *realtime.JobEvent) {
+		if ctx.Err() == nil && (e.QueueName == "" || e.QueueName == queueName) && e.Status == string(resources.JobStatusDeadletter) {
+			w.report(ctx, e.JobID)
+		}
+	})
+	defer stopFailed()
+	filter := realtime.SubscriptionFilter{
+		QueueName: queueName,
+		Events:    []string{string(realtime.EventJobDeadletter), string(realtime.EventJobFailed)},
+	}
+	if rt.State() != realtime.StateConnected {
+		if err := rt.Connect(); err != nil {
+			w.client.debug("deadletter realtime unavailable, polling", "queue", w.queue, "error", err)
+		}
+	}
+	if err := rt.Subscribe(filter); err != nil {
+		w.client.debug("deadletter realtime subscribe failed", "queue", w.queue, "error", err)
+	}
+	w.client.retainDeadletterFilter(queueName)
+	defer func() {
+		// Other watchers of the queue share the filter
+		if w.client.releaseDeadletterFilter(queueName) && rt.State() == realtime.StateConnected {
+			_ = rt.Unsubscribe(filter)
+		}
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if rt.State() != realtime.StateConnected {
+				w.poll(ctx)
+			}
+		}
+	}
+}
+
+// retainDeadletterFilter counts a watcher of queue's deadletter filter on
+// the shared realtime connection.
+func (c *Client) retainDeadletterFilter(queue string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deadletterWatchers == nil {
+		c.deadletterWatchers = make(map[string]int)
+	}
+	c.deadletterWatchers[queue]++
+}
+
+// releaseDeadletterFilter reports whether the last watcher of queue's
+// deadletter filter has stopped.
+func (c *Client) releaseDeadletterFilter(queue string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadletterWatchers[queue]--
+	if c.deadletterWatchers[queue] > 0 {
+		return false
+	}
+	delete(c.deadletterWatchers, queue)
+	return true
+}
+
+// list returns every job in the queue's DLQ, a page at a time.
+func (w *deadletterWatcher) list(ctx context.Context) ([]resources.Job, error) {
+	var jobs []resources.Job
+	limit := deadletterPageSize
+	for offset := 0; ; offset += limit {
+		page, err := w.client.Jobs().DLQ().List(ctx, &resources.ListDLQParams{
+			QueueName: &w.queue,
+			Limit:     &limit,
+			Offset:    &offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+		if len(page) < limit {
+			return jobs, nil
+		}
+	}
+}
+
+func (w *deadletterWatcher) poll(ctx context.Context) {
+	jobs, err := w.list(ctx)
+	if err != nil {
+		w.client.debug("deadletter poll failed", "queue", w.queue, "error", err)
+		return
+	}
+
+	var fresh []resources.Job
+	w.mu.Lock()
+	current := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		current[job.ID] = true
+		if !w.seen[job.ID] {
+			fresh = append(fresh, job)
+		}
+	}
+	// Forget jobs that left the DLQ (retried or purged)
+	w.seen = current
+	w.mu.Unlock()
+
+	for i := range fresh {
+		w.deliver(&fresh[i])
+	}
+}
+
+func (w *deadletterWatcher) report(ctx context.Context, jobID string) {
+	w.mu.Lock()
+	if w.seen[jobID] {
+		w.mu.Unlock()
+		return
+	}
+	w.seen[jobID] = true
+	w.mu.Unlock()
+
+	job, err := w.client.Jobs().Get(ctx, jobID)
+	if err != nil {
+		w.client.debug("deadletter job lookup failed", "job_id", jobID, "error", err)
+		job = &resources.Job{ID: jobID, QueueName: w.queue, Status: resources.JobStatusDeadletter}
+	}
+	w.deliver(job)
+}
+
+func (w *deadletterWatcher) deliver(job *resources.Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.client.debug("deadletter handler panic", "job_id", job.ID, "panic", r)
+		}
+	}()
+	w.handler(job)
+}
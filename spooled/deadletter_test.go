@@ -0,0 +1,286 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// fakeRealtime is a RealtimeClient that records job event handlers and
+// subscriptions. It is connected unless down is set, in which case Connect
+// fails.
+type fakeRealtime struct {
+	mu         sync.Mutex
+	handlers   map[int]realtime.JobEventHandler
+	nextID     int
+	subscribed map[string]int
+	down       bool
+}
+
+func (r *fakeRealtime) Connect() error {
+	if r.down {
+		return errors.New("realtime unavailable")
+	}
+	return nil
+}
+
+func (r *fakeRealtime) Disconnect() error { return nil }
+
+func (r *fakeRealtime) State() realtime.ConnectionState {
+	if r.down {
+		return realtime.StateDisconnected
+	}
+	return realtime.StateConnected
+}
+
+func (r *fakeRealtime) Subscribe(filter realtime.SubscriptionFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subscribed == nil {
+		r.subscribed = make(map[string]int)
+	}
+	r.subscribed[filter.QueueName]++
+	return nil
+}
+
+func (r *fakeRealtime) Unsubscribe(filter realtime.SubscriptionFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscribed, filter.QueueName)
+	return nil
+}
+
+func (r *fakeRealtime) OnEvent(realtime.EventHandler) func() { return func() {} }
+
+func (r *fakeRealtime) OnJobEvent(_ realtime.EventType, handler realtime.JobEventHandler) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handlers == nil {
+		r.handlers = make(map[int]realtime.JobEventHandler)
+	}
+	r.nextID++
+	id := r.nextID
+	r.handlers[id] = handler
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.handlers, id)
+	}
+}
+
+func (r *fakeRealtime) OnQueueEvent(realtime.EventType, realtime.QueueEventHandler) func() {
+	return func() {}
+}
+
+func (r *fakeRealtime) OnWorkerEvent(realtime.EventType, realtime.WorkerEventHandler) func() {
+	return func() {}
+}
+
+func (r *fakeRealtime) OnStateChange(realtime.StateChangeHandler) func() { return func() {} }
+
+// state returns the number of registered handlers and whether queue is subscribed.
+func (r *fakeRealtime) state(queue string) (handlers int, subscribed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.handlers), r.subscribed[queue] > 0
+}
+
+func TestDeadletterWatcher_SharedRealtime(t *testing.T) {
+	client, err := NewClient(WithAPIKey(testAPIKey))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	rt := &fakeRealtime{}
+
+	run := func(ctx context.Context) chan struct{} {
+		done := make(chan struct{})
+		w := &deadletterWatcher{client: client, rt: rt, queue: "emails", handler: func(*resources.Job) {}, interval: time.Hour}
+		go func() {
+			defer close(done)
+			w.run(ctx)
+		}()
+		return done
+	}
+	waitHandlers := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for handlers, _ := rt.state("emails"); handlers != want; handlers, _ = rt.state("emails") {
+			if time.Now().After(deadline) {
+				t.Fatalf("realtime handlers = %d, want %d", handlers, want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	done1, done2 := run(ctx1), run(ctx2)
+	waitHandlers(4)
+
+	// Stopping one watcher keeps the filter the other still needs
+	cancel1()
+	<-done1
+	if handlers, subscribed := rt.state("emails"); handlers != 2 || !subscribed {
+		t.Errorf("after one watcher stopped: %d handlers, subscribed %v; want 2, true", handlers, subscribed)
+	}
+
+	cancel2()
+	<-done2
+	if handlers, subscribed := rt.state("emails"); handlers != 0 || subscribed {
+		t.Errorf("after both watchers stopped: %d handlers, subscribed %v; want 0, false", handlers, subscribed)
+	}
+}
+
+// dlqServer is a REST API that lists jobs as the dead-letter queue.
+type dlqServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	jobs []resources.Job
+}
+
+func newDLQServer(t *testing.T, jobs ...resources.Job) *dlqServer {
+	t.Helper()
+	srv := &dlqServer{jobs: jobs}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/jobs/dlq" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(srv.jobs)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *dlqServer) add(job resources.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+func TestForwardDeadletters_Polling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dlq := newDLQServer(t, resources.Job{ID: "old", QueueName: "emails", Status: resources.JobStatusDeadletter})
+	bodies := make(chan map[string]any, 10)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies <- body
+	}))
+	defer hook.Close()
+
+	client, err := NewClient(WithAPIKey(testAPIKey), WithBaseURL(dlq.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	w := &deadletterWatcher{
+		client:   client,
+		rt:       &fakeRealtime{down: true},
+		queue:    "emails",
+		handler:  client.deadletterForwarder(ctx, hook.URL),
+		interval: 10 * time.Millisecond,
+	}
+	if err := w.start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	lastError, team, runbook := "smtp timeout", "growth", "https://runbooks.example.com/emails"
+	dlq.add(resources.Job{
+		ID: "new", QueueName: "emails", Status: resources.JobStatusDeadletter, RetryCount: 3,
+		LastError: &lastError, OwnerTeam: &team, RunbookURL: &runbook,
+	})
+	want := map[string]any{
+		"job_id":      "new",
+		"queue_name":  "emails",
+		"error":       lastError,
+		"owner_team":  team,
+		"runbook_url": runbook,
+		"text": "Job new in queue emails moved to the dead-letter queue after 3 retries: smtp timeout" +
+			"\nOwner: growth\nRunbook: https://runbooks.example.com/emails",
+	}
+	select {
+	case body := <-bodies:
+		if len(body) != len(want) {
+			t.Errorf("webhook body = %v, want %v", body, want)
+		}
+		for key, value := range want {
+			if body[key] != value {
+				t.Errorf("webhook body[%q] = %v, want %v", key, body[key], value)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("new deadletter not forwarded")
+	}
+
+	// Later polls see the same DLQ, so nothing is forwarded again
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case body := <-bodies:
+		t.Errorf("forwarded again: %v", body)
+	default:
+	}
+}
+
+func TestDeadletterWatcher_PollsEveryPage(t *testing.T) {
+	var mu sync.Mutex
+	var dlq []map[string]any
+	addJob := func(id string) {
+		mu.Lock()
+		defer mu.Unlock()
+		dlq = append(dlq, map[string]any{"id": id, "queue_name": "emails", "status": "deadletter"})
+	}
+	for i := 0; i < 250; i++ {
+		addJob(fmt.Sprintf("job-%03d", i))
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/api/v1/jobs/dlq" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		page := dlq[min(offset, len(dlq)):min(offset+limit, len(dlq))]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	})
+
+	var reported []string
+	w := &deadletterWatcher{client: client, queue: "emails", handler: func(job *resources.Job) {
+		reported = append(reported, job.ID)
+	}}
+	ctx := context.Background()
+	jobs, err := w.list(ctx)
+	if err != nil || len(jobs) != 250 {
+		t.Fatalf("list() = %d jobs, %v; want all 250", len(jobs), err)
+	}
+	w.seen = map[string]bool{}
+	for _, job := range jobs {
+		w.seen[job.ID] = true
+	}
+
+	// A deadletter on the third page is new; the second poll reports nothing
+	addJob("job-new")
+	w.poll(ctx)
+	w.poll(ctx)
+	if len(reported) != 1 || reported[0] != "job-new" {
+		t.Errorf("reported %v, want [job-new] once", reported)
+	}
+}
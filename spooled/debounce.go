@@ -0,0 +1,67 @@
+package spooled
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// DefaultDebounceWindow is the window used when NewDebouncer is given none.
+const DefaultDebounceWindow = 5 * time.Second
+
+// Debouncer collapses bursts of enqueue triggers for the same key into a
+// single job per window, for "recompute after the user stops editing" style
+// workloads.
+//
+// Time is divided into fixed windows on the server's clock. The first Trigger
+// for a key in a window creates a job scheduled at the end of that window;
+// later triggers in the same window reuse it through an idempotency key with
+// a time-bucket suffix, so collapsing works across processes without any
+// shared state. The job keeps the payload of the first trigger, so handlers
+// should read the latest state rather than rely on the payload being current.
+//
+// Example:
+//
+//	d := spooled.NewDebouncer(client, "reindex", 10*time.Second)
+//	_, err := d.Trigger(ctx, "doc-"+docID, map[string]any{"doc_id": docID})
+type Debouncer struct {
+	client *Client
+	queue  string
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewDebouncer creates a Debouncer enqueuing to queue. A window of zero or
+// less uses DefaultDebounceWindow.
+func NewDebouncer(client *Client, queue string, window time.Duration) *Debouncer {
+	if window <= 0 {
+		window = DefaultDebounceWindow
+	}
+	return &Debouncer{
+		client: client,
+		queue:  queue,
+		window: window,
+		now:    client.ServerTime,
+	}
+}
+
+// Trigger records a trigger for key. The response's Created is false when the
+// trigger was collapsed into a job already pending for this window.
+func (d *Debouncer) Trigger(ctx context.Context, key string, payload map[string]any) (*resources.CreateJobResponse, error) {
+	if key == "" {
+		return nil, fmt.Errorf("debounce key is required")
+	}
+
+	bucket := d.now().UnixNano() / int64(d.window)
+	runAt := time.Unix(0, (bucket+1)*int64(d.window)).UTC()
+	idempotencyKey := fmt.Sprintf("debounce:%s:%s:%d", d.queue, key, bucket)
+
+	return d.client.Jobs().Create(ctx, &resources.CreateJobRequest{
+		QueueName:      d.queue,
+		Payload:        payload,
+		ScheduledAt:    &runAt,
+		IdempotencyKey: &idempotencyKey,
+	})
+}
@@ -0,0 +1,53 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestDebouncer_Trigger(t *testing.T) {
+	var bodies []resources.CreateJobRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body resources.CreateJobRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+	})
+
+	start := time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC)
+	now := start
+	d := NewDebouncer(client, "reindex", 10*time.Second)
+	d.now = func() time.Time { return now }
+
+	for _, offset := range []time.Duration{0, 8 * time.Second, 9 * time.Second} {
+		now = start.Add(offset)
+		if _, err := d.Trigger(context.Background(), "doc-1", map[string]any{"doc_id": "1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("requests = %d, want 3", len(bodies))
+	}
+	// The first two triggers share a window; the third starts the next one
+	if *bodies[0].IdempotencyKey != *bodies[1].IdempotencyKey {
+		t.Errorf("keys %q and %q should match", *bodies[0].IdempotencyKey, *bodies[1].IdempotencyKey)
+	}
+	if *bodies[1].IdempotencyKey == *bodies[2].IdempotencyKey {
+		t.Errorf("key %q should differ across windows", *bodies[2].IdempotencyKey)
+	}
+	wantRunAt := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+	if !bodies[0].ScheduledAt.Equal(wantRunAt) {
+		t.Errorf("ScheduledAt = %v, want %v", bodies[0].ScheduledAt, wantRunAt)
+	}
+
+	if _, err := d.Trigger(context.Background(), "", nil); err == nil {
+		t.Error("expected error for empty key")
+	}
+}
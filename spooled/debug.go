@@ -0,0 +1,168 @@
+package spooled
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/internal/version"
+)
+
+// RequestStats counts an API resource's requests, retries, and failures.
+type RequestStats = httpx.RequestStats
+
+// DebugState is a snapshot of a client's internal state. It holds no
+// credentials.
+type DebugState struct {
+	Time       time.Time `json:"time"`
+	SDKVersion string    `json:"sdk_version"`
+	BaseURL    string    `json:"base_url"`
+	ClockSkew  string    `json:"clock_skew"`
+	// CircuitBreaker is nil when the breaker is disabled. One breaker guards
+	// requests to all resources.
+	CircuitBreaker *DebugCircuitBreaker `json:"circuit_breaker,omitempty"`
+	// Requests is keyed by API resource, such as "jobs" or "queues".
+	Requests map[string]RequestStats `json:"requests"`
+	Token    DebugToken              `json:"token"`
+	// GRPC is nil until the gRPC connection is first used.
+	GRPC *DebugGRPC `json:"grpc,omitempty"`
+	// Realtime is the shared realtime connection's state, if one was created.
+	Realtime string `json:"realtime,omitempty"`
+	// Workers are the client's started workers, sorted by ID.
+	Workers []DebugWorker `json:"workers"`
+}
+
+// DebugCircuitBreaker is the REST circuit breaker's state.
+type DebugCircuitBreaker struct {
+	State           string    `json:"state"`
+	Failures        int       `json:"failures"`
+	Successes       int       `json:"successes"`
+	LastStateChange time.Time `json:"last_state_change"`
+}
+
+// DebugToken describes how requests are authenticated.
+type DebugToken struct {
+	// Auth is "api_key" or "access_token".
+	Auth        string `json:"auth"`
+	AutoRefresh bool   `json:"auto_refresh"`
+	// ExpiresAt and RefreshAt are set when the access token's expiry is known.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RefreshAt *time.Time `json:"refresh_at,omitempty"`
+}
+
+// DebugGRPC is the gRPC connection's state.
+type DebugGRPC struct {
+	Address string `json:"address"`
+	State   string `json:"state"`
+}
+
+// DebugWorker is a worker's state and recent performance.
+type DebugWorker struct {
+	ID        string  `json:"id"`
+	Queue     string  `json:"queue"`
+	State     string  `json:"state"`
+	Active    int     `json:"active_jobs"`
+	Jobs      int     `json:"recent_jobs"`
+	Failed    int     `json:"recent_failed"`
+	ErrorRate float64 `json:"error_rate"`
+	P95       string  `json:"p95"`
+}
+
+// DebugState returns a snapshot of the client's circuit breaker, request and
+// retry counts, token expiry, gRPC and realtime connections, and workers.
+func (c *Client) DebugState() DebugState {
+	state := DebugState{
+		Time:       time.Now(),
+		SDKVersion: version.Version,
+		BaseURL:    c.cfg.BaseURL,
+		ClockSkew:  c.transport.ClockSkew().String(),
+		Requests:   c.transport.RequestStats(),
+		Token:      DebugToken{Auth: "api_key", AutoRefresh: c.cfg.AutoRefreshToken},
+		Workers:    []DebugWorker{},
+	}
+
+	if cb, ok := c.transport.CircuitBreaker(); ok {
+		state.CircuitBreaker = &DebugCircuitBreaker{
+			State:           cb.State.String(),
+			Failures:        cb.FailureCount,
+			Successes:       cb.SuccessCount,
+			LastStateChange: cb.LastStateChange,
+		}
+	}
+
+	if c.transport.AccessToken() != "" {
+		state.Token.Auth = "access_token"
+	}
+	if expiresAt, refreshAt := c.transport.TokenExpiry(); !expiresAt.IsZero() {
+		state.Token.ExpiresAt = &expiresAt
+		state.Token.RefreshAt = &refreshAt
+	}
+
+	c.mu.RLock()
+	if c.grpcClient != nil {
+		state.GRPC = &DebugGRPC{Address: c.cfg.GRPCAddress, State: c.grpcClient.State()}
+	}
+	if c.realtimeClient != nil {
+		state.Realtime = string(c.realtimeClient.State())
+	}
+	workers := make([]*SpooledWorker, 0, len(c.runningWorkers))
+	for w := range c.runningWorkers {
+		workers = append(workers, w)
+	}
+	c.mu.RUnlock()
+
+	for _, w := range workers {
+		stats := w.worker.Stats()
+		state.Workers = append(state.Workers, DebugWorker{
+			ID:        w.worker.WorkerID(),
+			Queue:     w.opts.QueueName,
+			State:     string(w.worker.State()),
+			Active:    stats.Active,
+			Jobs:      stats.Jobs,
+			Failed:    stats.Failed,
+			ErrorRate: stats.ErrorRate,
+			P95:       stats.P95.String(),
+		})
+	}
+	sort.Slice(state.Workers, func(i, j int) bool { return state.Workers[i].ID < state.Workers[j].ID })
+	return state
+}
+
+// DebugHandler returns an http.Handler serving DebugState as JSON, so a
+// misbehaving process can be inspected live. Mount it on an internal-only
+// listener, as it reveals the client's configuration:
+//
+//	mux.Handle("/debug/spooled", client.DebugHandler())
+func (c *Client) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(c.DebugState())
+	})
+}
+
+// trackWorker adds a started worker to DebugState.
+func (c *Client) trackWorker(w *SpooledWorker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runningWorkers == nil {
+		c.runningWorkers = make(map[*SpooledWorker]struct{})
+	}
+	c.runningWorkers[w] = struct{}{}
+}
+
+// untrackWorker removes a stopped worker from DebugState.
+func (c *Client) untrackWorker(w *SpooledWorker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.runningWorkers, w)
+}
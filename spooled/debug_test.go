@@ -0,0 +1,93 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestClient_DebugHandler(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/queues/flaky":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"unavailable","message":"try later"}`))
+		case r.URL.Path == "/api/v1/workers/register":
+			_, _ = w.Write([]byte(`{"id":"worker-1","queue_name":"debug"}`))
+		case r.URL.Path == "/api/v1/jobs/claim":
+			_, _ = w.Write([]byte(`{"jobs":[]}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	},
+		WithRetry(RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}),
+		WithCircuitBreaker(CircuitBreakerConfig{Enabled: true, FailureThreshold: 10, SuccessThreshold: 1, Timeout: time.Minute}),
+	)
+
+	if _, err := client.Queues().Get(context.Background(), "flaky"); err == nil {
+		t.Fatal("expected error from flaky queue")
+	}
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "debug", PollInterval: 10 * time.Millisecond})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) { return nil, nil })
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	get := func() DebugState {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		client.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/spooled", nil))
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("status = %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if strings.Contains(rec.Body.String(), "sp_test_") {
+			t.Errorf("debug state leaks the API key:\n%s", rec.Body.String())
+		}
+		var state DebugState
+		if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return state
+	}
+
+	state := get()
+	queues := state.Requests["queues"]
+	if queues.Requests != 1 || queues.Retries != 2 || queues.Failures != 1 || !strings.Contains(queues.LastError, "try later") {
+		t.Errorf("queues stats = %+v, want 1 request, 2 retries, 1 failure", queues)
+	}
+	if cb := state.CircuitBreaker; cb == nil || cb.State != "closed" {
+		t.Errorf("circuit breaker = %+v, want closed", cb)
+	}
+	if state.Token.Auth != "api_key" || state.Token.ExpiresAt != nil {
+		t.Errorf("token = %+v", state.Token)
+	}
+	if state.GRPC != nil || state.SDKVersion == "" {
+		t.Errorf("state = %+v", state)
+	}
+	if len(state.Workers) != 1 || state.Workers[0].ID != "worker-1" || state.Workers[0].Queue != "debug" || state.Workers[0].State != "running" {
+		t.Errorf("workers = %+v, want worker-1 running on debug", state.Workers)
+	}
+
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if state := get(); len(state.Workers) != 0 {
+		t.Errorf("workers after Stop = %+v", state.Workers)
+	}
+
+	rec := httptest.NewRecorder()
+	client.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/spooled", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,201 @@
+// Package devscheduler runs Spooled schedules in-process, for local
+// development against servers where the Spooled scheduler is not running.
+//
+// It takes the same CreateScheduleRequest definitions that are deployed with
+// Schedules().Create or spooled.Bootstrap and enqueues a job through the
+// client each time a cron expression fires:
+//
+//	s, err := devscheduler.New(client, schedules, devscheduler.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	go s.Run(ctx)
+//
+// Runs missed while the process is not running are skipped, not caught up.
+package devscheduler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/cron"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// Options configures a Scheduler.
+type Options struct {
+	// OnRun is called after each enqueue attempt with the job, or the error
+	// (optional). Errors are otherwise only logged.
+	OnRun func(schedule string, job *resources.CreateJobResponse, err error)
+	// Logger is a custom logger function (optional).
+	Logger func(msg string, args ...any)
+}
+
+// Scheduler enqueues jobs for locally defined schedules.
+type Scheduler struct {
+	jobs    *resources.JobsResource
+	entries []*entry
+	opts    Options
+}
+
+type entry struct {
+	def  resources.CreateScheduleRequest
+	cron *cron.Schedule
+	loc  *time.Location
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// New validates the schedule definitions and creates a Scheduler that
+// enqueues through client. Schedule names must be unique.
+func New(client *spooled.Client, schedules []resources.CreateScheduleRequest, opts Options) (*Scheduler, error) {
+	s := &Scheduler{jobs: client.Jobs(), opts: opts}
+	seen := make(map[string]bool, len(schedules))
+	for _, def := range schedules {
+		if def.Name == "" || def.QueueName == "" {
+			return nil, fmt.Errorf("schedule name and queue name are required")
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("duplicate schedule %q", def.Name)
+		}
+		seen[def.Name] = true
+
+		parsed, err := cron.Parse(def.CronExpression)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", def.Name, err)
+		}
+		loc := time.UTC
+		if def.Timezone != nil && *def.Timezone != "" {
+			if loc, err = time.LoadLocation(*def.Timezone); err != nil {
+				return nil, fmt.Errorf("schedule %q: %w", def.Name, err)
+			}
+		}
+		s.entries = append(s.entries, &entry{def: def, cron: parsed, loc: loc})
+	}
+	return s, nil
+}
+
+// Run enqueues jobs as schedules fire until ctx is cancelled, then returns
+// ctx.Err().
+func (s *Scheduler) Run(ctx context.Context) error {
+	now := time.Now()
+	for _, e := range s.entries {
+		e.mu.Lock()
+		e.next = e.cron.Next(now.In(e.loc))
+		e.mu.Unlock()
+		s.log("Scheduled %s on %s, next run at %s", e.def.Name, e.def.QueueName, e.next)
+	}
+
+	for {
+		wake := s.nextWake()
+		if wake.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for _, e := range s.entries {
+			e.mu.Lock()
+			due := !e.next.IsZero() && !e.next.After(now)
+			runAt := e.next
+			if due {
+				e.next = e.cron.Next(now.In(e.loc))
+			}
+			e.mu.Unlock()
+			if due {
+				s.enqueue(ctx, e, runAt)
+			}
+		}
+	}
+}
+
+// Next returns the next run time of the named schedule, or the zero time if
+// the schedule is unknown or Run has not started.
+func (s *Scheduler) Next(name string) time.Time {
+	for _, e := range s.entries {
+		if e.def.Name == name {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			return e.next
+		}
+	}
+	return time.Time{}
+}
+
+// Trigger enqueues a job for the named schedule immediately, like
+// Schedules().Trigger.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*resources.CreateJobResponse, error) {
+	for _, e := range s.entries {
+		if e.def.Name == name {
+			return s.jobs.Create(ctx, jobRequest(e.def, nil))
+		}
+	}
+	return nil, fmt.Errorf("unknown schedule %q", name)
+}
+
+// nextWake returns the earliest next run across schedules.
+func (s *Scheduler) nextWake() time.Time {
+	var wake time.Time
+	for _, e := range s.entries {
+		e.mu.Lock()
+		next := e.next
+		e.mu.Unlock()
+		if !next.IsZero() && (wake.IsZero() || next.Before(wake)) {
+			wake = next
+		}
+	}
+	return wake
+}
+
+func (s *Scheduler) enqueue(ctx context.Context, e *entry, runAt time.Time) {
+	job, err := s.jobs.Create(ctx, jobRequest(e.def, &runAt))
+	if err != nil {
+		s.log("Schedule %s failed to enqueue: %v", e.def.Name, err)
+	} else {
+		s.log("Schedule %s enqueued job %s", e.def.Name, job.ID)
+	}
+	if s.opts.OnRun != nil {
+		s.opts.OnRun(e.def.Name, job, err)
+	}
+}
+
+// jobRequest builds the job for a run of def. Scheduled runs get an
+// idempotency key, so several schedulers running the same definitions
+// enqueue each run once.
+func jobRequest(def resources.CreateScheduleRequest, runAt *time.Time) *resources.CreateJobRequest {
+	req := &resources.CreateJobRequest{
+		QueueName:      def.QueueName,
+		Payload:        maps.Clone(def.PayloadTemplate),
+		Priority:       def.Priority,
+		MaxRetries:     def.MaxRetries,
+		TimeoutSeconds: def.TimeoutSeconds,
+		Tags:           maps.Clone(def.Tags),
+	}
+	if req.Payload == nil {
+		req.Payload = map[string]any{}
+	}
+	if runAt != nil {
+		key := fmt.Sprintf("devscheduler:%s:%d", def.Name, runAt.Unix())
+		req.IdempotencyKey = &key
+	}
+	return req
+}
+
+func (s *Scheduler) log(msg string, args ...any) {
+	if s.opts.Logger != nil {
+		s.opts.Logger(msg, args...)
+	}
+}
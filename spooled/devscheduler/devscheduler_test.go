@@ -0,0 +1,126 @@
+package devscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/spooledtest"
+)
+
+func TestScheduler(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	type run struct {
+		schedule string
+		job      *resources.CreateJobResponse
+		err      error
+	}
+	runs := make(chan run, 10)
+	s, err := New(client, []resources.CreateScheduleRequest{
+		{Name: "tick", CronExpression: "* * * * * *", QueueName: "ticks", PayloadTemplate: map[string]any{"kind": "tick"}},
+		{Name: "yearly", CronExpression: "0 0 0 1 1 *", QueueName: "reports"},
+	}, Options{OnRun: func(schedule string, job *resources.CreateJobResponse, err error) {
+		runs <- run{schedule, job, err}
+	}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if next := s.Next("tick"); !next.IsZero() {
+		t.Errorf("Next() = %v before Run, want zero", next)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	// The every-second schedule fires twice, the yearly one not at all
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-runs:
+			if r.schedule != "tick" || r.err != nil || r.job == nil || r.job.ID == "" {
+				t.Fatalf("run %d = %+v, want a tick job", i, r)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("schedule fired %d times, want 2", i)
+		}
+	}
+	if next := s.Next("yearly"); next.Before(time.Now()) {
+		t.Errorf("Next(yearly) = %v, want a future run", next)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	select {
+	case r := <-runs:
+		// A run that was already due when ctx was cancelled may still land
+		if r.schedule != "tick" {
+			t.Errorf("unexpected run after shutdown: %+v", r)
+		}
+	default:
+	}
+
+	queue := "ticks"
+	jobs, err := client.Jobs().List(context.Background(), &resources.ListJobsParams{QueueName: &queue})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(jobs) < 2 || jobs[0].Payload["kind"] != "tick" {
+		t.Errorf("enqueued %d jobs (%+v), want at least 2 ticks", len(jobs), jobs)
+	}
+
+	// Trigger enqueues immediately, without waiting for the schedule
+	job, err := s.Trigger(context.Background(), "yearly")
+	if err != nil || job.ID == "" {
+		t.Fatalf("Trigger() = %+v, %v", job, err)
+	}
+	if _, err := s.Trigger(context.Background(), "missing"); err == nil {
+		t.Error("Trigger() of an unknown schedule should fail")
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	bad := "Mars/Olympus_Mons"
+	tests := []struct {
+		name      string
+		schedules []resources.CreateScheduleRequest
+	}{
+		{"missing queue", []resources.CreateScheduleRequest{{Name: "a", CronExpression: "* * * * *"}}},
+		{"duplicate name", []resources.CreateScheduleRequest{
+			{Name: "a", CronExpression: "* * * * *", QueueName: "q"},
+			{Name: "a", CronExpression: "* * * * *", QueueName: "q"},
+		}},
+		{"bad cron", []resources.CreateScheduleRequest{{Name: "a", CronExpression: "every day", QueueName: "q"}}},
+		{"bad timezone", []resources.CreateScheduleRequest{{Name: "a", CronExpression: "* * * * *", QueueName: "q", Timezone: &bad}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(client, tt.schedules, Options{}); err == nil {
+				t.Error("New() should fail")
+			}
+		})
+	}
+}
@@ -55,11 +55,8 @@
 //	if err != nil {
 //		if spooled.IsNotFoundError(err) {
 //			fmt.Println("Job not found")
-//		} else if spooled.IsRateLimitError(err) {
-//			var rateLimitErr *spooled.RateLimitError
-//			if errors.As(err, &rateLimitErr) {
-//				fmt.Printf("Rate limited, retry after %d seconds\n", rateLimitErr.GetRetryAfter())
-//			}
+//		} else if rateLimitErr, ok := spooled.AsRateLimitError(err); ok {
+//			fmt.Printf("Rate limited, retry after %d seconds\n", rateLimitErr.GetRetryAfter())
 //		} else {
 //			log.Fatal(err)
 //		}
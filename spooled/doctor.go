@@ -0,0 +1,286 @@
+package spooled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// CheckStatus is the outcome of a Doctor check.
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	// CheckSkip means the check could not run because an earlier one failed.
+	CheckSkip CheckStatus = "skip"
+)
+
+// Doctor check names.
+const (
+	CheckREST        = "rest"
+	CheckAuth        = "auth"
+	CheckClockSkew   = "clock_skew"
+	CheckPermissions = "permissions"
+	CheckLimits      = "limits"
+	CheckGRPC        = "grpc"
+	CheckRealtime    = "realtime"
+)
+
+// maxClockSkew is the clock skew above which Doctor warns.
+const maxClockSkew = 5 * time.Second
+
+// doctorCheckTimeout bounds each gRPC and realtime connection attempt.
+var doctorCheckTimeout = 5 * time.Second
+
+// DoctorCheck is the result of one Doctor check.
+type DoctorCheck struct {
+	Name    string
+	Status  CheckStatus
+	Message string
+	// Hint suggests how to fix a failed or warning check.
+	Hint     string
+	Duration time.Duration
+}
+
+// DoctorReport is the result of Doctor.
+type DoctorReport struct {
+	Checks []DoctorCheck
+}
+
+// OK reports whether no check failed. Warnings do not count as failures.
+func (r *DoctorReport) OK() bool {
+	for _, check := range r.Checks {
+		if check.Status == CheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns the check with the given name, or nil.
+func (r *DoctorReport) Check(name string) *DoctorCheck {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// String formats the report for a terminal or a support ticket.
+func (r *DoctorReport) String() string {
+	var b strings.Builder
+	for _, check := range r.Checks {
+		fmt.Fprintf(&b, "[%s] %s: %s (%s)\n", check.Status, check.Name, check.Message, check.Duration.Round(time.Millisecond))
+		if check.Hint != "" {
+			fmt.Fprintf(&b, "       hint: %s\n", check.Hint)
+		}
+	}
+	return b.String()
+}
+
+// Doctor runs connectivity checks against the client's configuration, the
+// same ones as `npx spooled doctor`: REST reachability, authentication, clock
+// skew, queue permissions, plan limits, gRPC, and the realtime WebSocket. It
+// never returns early; checks that depend on a failed one are skipped.
+//
+// Example:
+//
+//	report := spooled.Doctor(ctx, client)
+//	fmt.Print(report)
+//	if !report.OK() {
+//		os.Exit(1)
+//	}
+func Doctor(ctx context.Context, c *Client) *DoctorReport {
+	report := &DoctorReport{}
+	run := func(name string, check func() (CheckStatus, string, string)) CheckStatus {
+		start := time.Now()
+		status, message, hint := check()
+		report.Checks = append(report.Checks, DoctorCheck{
+			Name:     name,
+			Status:   status,
+			Message:  message,
+			Hint:     hint,
+			Duration: time.Since(start),
+		})
+		return status
+	}
+	skip := func(name, reason string) {
+		report.Checks = append(report.Checks, DoctorCheck{Name: name, Status: CheckSkip, Message: reason})
+	}
+
+	restStatus := run(CheckREST, func() (CheckStatus, string, string) {
+		health, err := c.Health().Get(ctx)
+		if err != nil {
+			return CheckFail, err.Error(), "check that " + c.cfg.BaseURL + " is reachable from this host (proxy, firewall, DNS) and that WithBaseURL is correct"
+		}
+		if health.Status != "" && health.Status != "healthy" && health.Status != "ok" {
+			return CheckWarn, "API reports status " + health.Status, "see https://status.spooled.cloud"
+		}
+		return CheckPass, "reached " + c.cfg.BaseURL, ""
+	})
+
+	var me *resources.MeResponse
+	if restStatus == CheckFail {
+		skip(CheckAuth, "API is unreachable")
+	} else {
+		run(CheckAuth, func() (CheckStatus, string, string) {
+			var err error
+			me, err = c.Auth().Me(ctx)
+			if err != nil {
+				return CheckFail, err.Error(), authHint(err)
+			}
+			return CheckPass, "authenticated for organization " + me.OrganizationID, ""
+		})
+	}
+
+	if restStatus == CheckFail {
+		skip(CheckClockSkew, "API is unreachable")
+	} else {
+		run(CheckClockSkew, func() (CheckStatus, string, string) {
+			skew := c.ClockSkew()
+			message := fmt.Sprintf("local clock is %s off the server clock", skew.Abs().Round(time.Millisecond))
+			if skew.Abs() > maxClockSkew {
+				return CheckWarn, message, "sync the system clock (NTP); scheduled jobs and token expiry are computed from it"
+			}
+			return CheckPass, message, ""
+		})
+	}
+
+	if me == nil {
+		skip(CheckPermissions, "not authenticated")
+		skip(CheckLimits, "not authenticated")
+	} else {
+		run(CheckPermissions, func() (CheckStatus, string, string) {
+			if _, err := c.Queues().List(ctx); err != nil {
+				return CheckFail, "cannot list queues: " + err.Error(), authHint(err)
+			}
+			if len(me.Queues) > 0 {
+				return CheckPass, "key is restricted to queues " + strings.Join(me.Queues, ", "), ""
+			}
+			return CheckPass, "key can access all queues", ""
+		})
+		run(CheckLimits, func() (CheckStatus, string, string) {
+			usage, err := c.Organizations().Usage(ctx, me.OrganizationID)
+			if err != nil {
+				return CheckWarn, "cannot read plan usage: " + err.Error(), "plan limits are only visible to keys with organization access"
+			}
+			return limitsStatus(usage)
+		})
+	}
+
+	run(CheckGRPC, func() (CheckStatus, string, string) {
+		hint := "check that " + c.cfg.GRPCAddress + " is reachable (egress on its port, HTTP/2 allowed by proxies), or use TransportREST"
+		client, err := c.GRPC()
+		if err != nil {
+			return CheckFail, err.Error(), hint
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		defer cancel()
+		if err := client.WaitForReady(dialCtx); err != nil {
+			return CheckFail, err.Error(), hint
+		}
+		return CheckPass, "connected to " + c.cfg.GRPCAddress, ""
+	})
+
+	run(CheckRealtime, func() (CheckStatus, string, string) {
+		wsURL := c.cfg.WSURL + DefaultAPIBasePath + "/ws"
+		hint := "check that " + wsURL + " is reachable and that proxies allow WebSocket upgrades, or use a polling or SSE realtime client"
+		// A dedicated connection leaves the shared Realtime client untouched
+		ws := realtime.NewWebSocketClient(realtime.ConnectionOptions{
+			WSURL:  wsURL,
+			Token:  c.transport.AccessToken(),
+			APIKey: c.transport.APIKey(),
+		})
+		done := make(chan error, 1)
+		go func() {
+			err := ws.Connect()
+			done <- err
+			if err == nil {
+				_ = ws.Disconnect()
+			}
+		}()
+		timer := time.NewTimer(doctorCheckTimeout)
+		defer timer.Stop()
+		select {
+		case err := <-done:
+			if err != nil {
+				return CheckFail, err.Error(), hint
+			}
+			return CheckPass, "connected to " + wsURL, ""
+		case <-timer.C:
+			return CheckFail, "timed out connecting to " + wsURL, hint
+		case <-ctx.Done():
+			return CheckFail, ctx.Err().Error(), hint
+		}
+	})
+
+	return report
+}
+
+// authHint suggests a fix for an authentication or authorization error.
+func authHint(err error) string {
+	var apiErr *httpx.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		return "the API key or token is invalid, expired, or revoked; create a new key in the dashboard"
+	case http.StatusForbidden:
+		return "the key lacks permission for this operation; check its queue restrictions in the dashboard"
+	}
+	return ""
+}
+
+// limitsStatus reports plan resources that are at or near their limit.
+func limitsStatus(usage *resources.UsageInfo) (CheckStatus, string, string) {
+	items := []struct {
+		name string
+		item resources.UsageItem
+	}{
+		{"jobs_today", usage.Usage.JobsToday},
+		{"active_jobs", usage.Usage.ActiveJobs},
+		{"queues", usage.Usage.Queues},
+		{"workers", usage.Usage.Workers},
+		{"api_keys", usage.Usage.APIKeys},
+		{"schedules", usage.Usage.Schedules},
+		{"workflows", usage.Usage.Workflows},
+		{"webhooks", usage.Usage.Webhooks},
+	}
+
+	var exceeded, near []string
+	for _, u := range items {
+		if u.item.Limit == nil || u.item.IsDisabled {
+			continue
+		}
+		switch {
+		case u.item.Current >= *u.item.Limit:
+			exceeded = append(exceeded, fmt.Sprintf("%s %d/%d", u.name, u.item.Current, *u.item.Limit))
+		case float64(u.item.Current) >= 0.9*float64(*u.item.Limit):
+			near = append(near, fmt.Sprintf("%s %d/%d", u.name, u.item.Current, *u.item.Limit))
+		}
+	}
+
+	plan := usage.PlanDisplayName
+	if plan == "" {
+		plan = usage.Plan
+	}
+	hint := "upgrade the plan or remove unused resources"
+	if len(exceeded) > 0 {
+		return CheckFail, fmt.Sprintf("%s plan limit reached: %s", plan, strings.Join(exceeded, ", ")), hint
+	}
+	if len(near) > 0 {
+		return CheckWarn, fmt.Sprintf("%s plan limit almost reached: %s", plan, strings.Join(near, ", ")), hint
+	}
+	return CheckPass, "within " + plan + " plan limits", ""
+}
@@ -0,0 +1,101 @@
+package spooled
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoctor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"status":"healthy","database":true,"cache":true}`))
+		case "/api/v1/auth/me":
+			_, _ = w.Write([]byte(`{"organization_id":"org-1","api_key_id":"key-1","queues":["emails"]}`))
+		case "/api/v1/queues":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v1/organizations/org-1/usage":
+			_, _ = w.Write([]byte(`{"plan":"free","plan_display_name":"Free","usage":{"queues":{"current":9,"limit":10}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey(testAPIKey),
+		WithBaseURL(server.URL),
+		WithWSURL("ws"+strings.TrimPrefix(server.URL, "http")),
+		WithGRPCAddress("127.0.0.1:1"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	defer func(timeout time.Duration) { doctorCheckTimeout = timeout }(doctorCheckTimeout)
+	doctorCheckTimeout = 200 * time.Millisecond
+
+	report := Doctor(context.Background(), client)
+	want := map[string]CheckStatus{
+		CheckREST:        CheckPass,
+		CheckAuth:        CheckPass,
+		CheckClockSkew:   CheckPass,
+		CheckPermissions: CheckPass,
+		CheckLimits:      CheckWarn,
+		CheckGRPC:        CheckFail,
+		CheckRealtime:    CheckFail,
+	}
+	if len(report.Checks) != len(want) {
+		t.Fatalf("Expected %d checks, got %d:\n%s", len(want), len(report.Checks), report)
+	}
+	for name, status := range want {
+		check := report.Check(name)
+		if check == nil || check.Status != status {
+			t.Errorf("Expected %s to be %s:\n%s", name, status, report)
+		}
+	}
+	if report.OK() {
+		t.Error("Expected report with failures not to be OK")
+	}
+	if !strings.Contains(report.Check(CheckLimits).Message, "queues 9/10") {
+		t.Errorf("Unexpected limits message: %s", report.Check(CheckLimits).Message)
+	}
+	if report.Check(CheckGRPC).Hint == "" {
+		t.Error("Expected a remediation hint for the failed gRPC check")
+	}
+}
+
+func TestDoctor_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	defer func(timeout time.Duration) { doctorCheckTimeout = timeout }(doctorCheckTimeout)
+	doctorCheckTimeout = 200 * time.Millisecond
+
+	client, err := NewClient(
+		WithAPIKey(testAPIKey),
+		WithBaseURL(server.URL),
+		WithRetry(RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithGRPCAddress("127.0.0.1:1"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	report := Doctor(context.Background(), client)
+	if report.Check(CheckREST).Status != CheckFail {
+		t.Errorf("Expected rest check to fail:\n%s", report)
+	}
+	for _, name := range []string{CheckAuth, CheckClockSkew, CheckPermissions, CheckLimits} {
+		if report.Check(name).Status != CheckSkip {
+			t.Errorf("Expected %s to be skipped:\n%s", name, report)
+		}
+	}
+}
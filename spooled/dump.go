@@ -0,0 +1,314 @@
+package spooled
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// DefaultDumpRedactor redacts payload, result, tag, and metadata keys that
+// commonly hold secrets or personal data in Dump and DiffJobs output.
+var DefaultDumpRedactor = resources.RedactKeys(
+	"password", "secret", "token", "access_token", "refresh_token", "api_key",
+	"apikey", "authorization", "cookie", "credit_card", "card_number", "ssn",
+)
+
+// DumpOptions configures Dump and DiffJobs.
+type DumpOptions struct {
+	// Redactor rewrites payloads, results, tags, and metadata before they are
+	// printed (default: DefaultDumpRedactor). It receives a copy.
+	Redactor PayloadRedactor
+	// Now is the time computed fields such as age are relative to (default:
+	// time.Now()).
+	Now time.Time
+}
+
+// dumpField is one line of a dump.
+type dumpField struct {
+	name  string
+	value string
+	// computed fields depend on the current time and are left out of diffs.
+	computed bool
+}
+
+// Dump writes a readable representation of a *resources.Job or
+// *resources.Workflow to w, for CLI output, test failures, and incident
+// notes. Fields are printed in a fixed order with payloads flattened into
+// sorted dotted keys, so dumps of equal objects are identical apart from
+// computed fields such as age and retries remaining. Secrets are redacted
+// with DefaultDumpRedactor.
+func Dump(w io.Writer, v any) error {
+	return DumpWithOptions(w, v, nil)
+}
+
+// DumpWithOptions is Dump with a custom redactor or reference time.
+func DumpWithOptions(w io.Writer, v any, opts *DumpOptions) error {
+	var title string
+	var fields []dumpField
+	switch v := v.(type) {
+	case *resources.Job:
+		title, fields = "Job "+v.ID, jobFields(v, opts)
+	case resources.Job:
+		title, fields = "Job "+v.ID, jobFields(&v, opts)
+	case *resources.Workflow:
+		title, fields = "Workflow "+v.ID, workflowFields(v, opts)
+	case resources.Workflow:
+		title, fields = "Workflow "+v.ID, workflowFields(&v, opts)
+	default:
+		return fmt.Errorf("cannot dump %T", v)
+	}
+
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.name))
+	}
+	var b strings.Builder
+	b.WriteString(title + "\n")
+	for _, f := range fields {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width+1, f.name+":", f.value)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DiffJobs describes how job b differs from job a, one "field: a -> b" line
+// per changed field, or returns "" if they are equal. Computed fields are
+// ignored and payloads are compared key by key after redaction.
+func DiffJobs(a, b *resources.Job) string {
+	return DiffJobsWithOptions(a, b, nil)
+}
+
+// DiffJobsWithOptions is DiffJobs with a custom redactor.
+func DiffJobsWithOptions(a, b *resources.Job, opts *DumpOptions) string {
+	values := func(job *resources.Job) (map[string]string, []string) {
+		m := map[string]string{}
+		var names []string
+		if job == nil {
+			return m, nil
+		}
+		for _, f := range jobFields(job, opts) {
+			if !f.computed {
+				m[f.name] = f.value
+				names = append(names, f.name)
+			}
+		}
+		return m, names
+	}
+	av, anames := values(a)
+	bv, bnames := values(b)
+
+	// Merge the field orders, placing fields only in b after the field that
+	// precedes them in b
+	var names []string
+	i := 0
+	for _, name := range bnames {
+		if _, ok := av[name]; !ok {
+			names = append(names, name)
+			continue
+		}
+		for i < len(anames) {
+			names = append(names, anames[i])
+			i++
+			if anames[i-1] == name {
+				break
+			}
+		}
+	}
+	names = append(names, anames[i:]...)
+	var lines []string
+	for _, name := range names {
+		old, inA := av[name]
+		cur, inB := bv[name]
+		switch {
+		case !inA:
+			lines = append(lines, fmt.Sprintf("%s: (unset) -> %s", name, cur))
+		case !inB:
+			lines = append(lines, fmt.Sprintf("%s: %s -> (unset)", name, old))
+		case old != cur:
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s", name, old, cur))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// jobFields lists a job's fields in dump order.
+func jobFields(job *resources.Job, opts *DumpOptions) []dumpField {
+	now := dumpNow(opts)
+	var fields []dumpField
+	add := func(name, value string) {
+		fields = append(fields, dumpField{name: name, value: value})
+	}
+	computed := func(name, value string) {
+		fields = append(fields, dumpField{name: name, value: value, computed: true})
+	}
+
+	add("queue", job.QueueName)
+	add("status", string(job.Status))
+	add("priority", fmt.Sprint(job.Priority))
+	add("retries", fmt.Sprintf("%d/%d", job.RetryCount, job.MaxRetries))
+	computed("retries_remaining", fmt.Sprint(max(job.MaxRetries-job.RetryCount, 0)))
+	add("timeout", (time.Duration(job.TimeoutSeconds) * time.Second).String())
+	addOptional(&fields, "last_error", job.LastError)
+	add("created_at", formatDumpTime(job.CreatedAt))
+	computed("age", formatDumpDuration(now.Sub(job.CreatedAt)))
+	addTime(&fields, "scheduled_at", job.ScheduledAt)
+	addTime(&fields, "started_at", job.StartedAt)
+	addTime(&fields, "completed_at", job.CompletedAt)
+	if job.StartedAt != nil {
+		end := now
+		if job.CompletedAt != nil {
+			end = *job.CompletedAt
+		}
+		computed("run_time", formatDumpDuration(end.Sub(*job.StartedAt)))
+	}
+	addTime(&fields, "expires_at", job.ExpiresAt)
+	addOptional(&fields, "worker", job.AssignedWorkerID)
+	addTime(&fields, "lease_expires_at", job.LeaseExpiresAt)
+	if job.LeaseExpiresAt != nil && job.Status == resources.JobStatusProcessing {
+		computed("lease_remaining", formatDumpDuration(job.LeaseExpiresAt.Sub(now)))
+	}
+	addOptional(&fields, "idempotency_key", job.IdempotencyKey)
+	addOptional(&fields, "parent_job", job.ParentJobID)
+	addOptional(&fields, "workflow", job.WorkflowID)
+	addOptional(&fields, "dependency_mode", job.DependencyMode)
+	if job.DependenciesMet != nil {
+		add("dependencies_met", fmt.Sprint(*job.DependenciesMet))
+	}
+	addOptional(&fields, "completion_webhook", job.CompletionWebhook)
+	addOptional(&fields, "runbook_url", job.RunbookURL)
+	addOptional(&fields, "owner_team", job.OwnerTeam)
+	addOptional(&fields, "cancellation_reason", job.CancellationReason)
+	addOptional(&fields, "cancelled_by", job.CancelledBy)
+	addMap(&fields, "tags", job.Tags, opts)
+	addMap(&fields, "payload", job.Payload, opts)
+	addMap(&fields, "result", job.Result, opts)
+	return fields
+}
+
+// workflowFields lists a workflow's fields in dump order.
+func workflowFields(wf *resources.Workflow, opts *DumpOptions) []dumpField {
+	now := dumpNow(opts)
+	var fields []dumpField
+	add := func(name, value string) {
+		fields = append(fields, dumpField{name: name, value: value})
+	}
+	computed := func(name, value string) {
+		fields = append(fields, dumpField{name: name, value: value, computed: true})
+	}
+
+	add("name", wf.Name)
+	addOptional(&fields, "description", wf.Description)
+	add("status", string(wf.Status))
+	add("jobs", fmt.Sprintf("%d completed, %d failed, %d total", wf.CompletedJobs, wf.FailedJobs, wf.TotalJobs))
+	if wf.TotalJobs > 0 {
+		computed("progress", fmt.Sprintf("%d%%", (wf.CompletedJobs+wf.FailedJobs)*100/wf.TotalJobs))
+	}
+	add("created_at", formatDumpTime(wf.CreatedAt))
+	computed("age", formatDumpDuration(now.Sub(wf.CreatedAt)))
+	addTime(&fields, "started_at", wf.StartedAt)
+	addTime(&fields, "completed_at", wf.CompletedAt)
+	if wf.TimeoutSeconds != nil {
+		add("timeout", (time.Duration(*wf.TimeoutSeconds) * time.Second).String())
+	}
+	addTime(&fields, "deadline", wf.Deadline)
+	if wf.Deadline != nil && wf.CompletedAt == nil {
+		computed("deadline_remaining", formatDumpDuration(wf.Deadline.Sub(now)))
+	}
+	addMap(&fields, "metadata", wf.Metadata, opts)
+	return fields
+}
+
+func addOptional(fields *[]dumpField, name string, v *string) {
+	if v != nil {
+		*fields = append(*fields, dumpField{name: name, value: *v})
+	}
+}
+
+func addTime(fields *[]dumpField, name string, t *time.Time) {
+	if t != nil {
+		*fields = append(*fields, dumpField{name: name, value: formatDumpTime(*t)})
+	}
+}
+
+// addMap adds the redacted values of m under dotted keys in sorted order, so
+// nested payloads diff key by key.
+func addMap(fields *[]dumpField, name string, m map[string]any, opts *DumpOptions) {
+	if len(m) == 0 {
+		return
+	}
+	redactor := DefaultDumpRedactor
+	if opts != nil && opts.Redactor != nil {
+		redactor = opts.Redactor
+	}
+	// Redactors may modify their argument, so give them a copy
+	var copied map[string]any
+	data, err := json.Marshal(m)
+	if err == nil {
+		err = json.Unmarshal(data, &copied)
+	}
+	if err != nil {
+		*fields = append(*fields, dumpField{name: name, value: fmt.Sprintf("(unprintable: %v)", err)})
+		return
+	}
+
+	flat := map[string]string{}
+	flattenDump(name, redactor(copied), flat)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		*fields = append(*fields, dumpField{name: k, value: flat[k]})
+	}
+}
+
+// flattenDump stores the JSON encoding of every leaf of v in out, keyed by
+// its dotted path. Empty objects and arrays are kept as leaves.
+func flattenDump(path string, v any, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) > 0 {
+			for k, inner := range v {
+				flattenDump(path+"."+k, inner, out)
+			}
+			return
+		}
+	case []any:
+		if len(v) > 0 {
+			for i, inner := range v {
+				flattenDump(fmt.Sprintf("%s[%d]", path, i), inner, out)
+			}
+			return
+		}
+	}
+	data, _ := json.Marshal(v)
+	out[path] = string(data)
+}
+
+func dumpNow(opts *DumpOptions) time.Time {
+	if opts != nil && !opts.Now.IsZero() {
+		return opts.Now
+	}
+	return time.Now()
+}
+
+func formatDumpTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatDumpDuration rounds d to the second; negative durations have passed.
+func formatDumpDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + (-d).Round(time.Second).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,73 @@
+package spooled
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestDumpAndDiffJobs(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	started := created.Add(time.Minute)
+	lastErr := "smtp timeout"
+	a := &resources.Job{
+		ID:         "job-1",
+		QueueName:  "emails",
+		Status:     resources.JobStatusProcessing,
+		RetryCount: 1,
+		MaxRetries: 3,
+		CreatedAt:  created,
+		StartedAt:  &started,
+		Payload:    map[string]any{"to": "a@example.com", "smtp": map[string]any{"password": "hunter2", "port": 25}},
+	}
+	opts := &DumpOptions{Now: created.Add(5 * time.Minute)}
+
+	var buf bytes.Buffer
+	if err := DumpWithOptions(&buf, a, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	for _, want := range []string{
+		"Job job-1",
+		"retries_remaining: 2",
+		"age: 5m0s",
+		"run_time: 4m0s",
+		"payload.smtp.password: \"[REDACTED]\"",
+		"payload.smtp.port: 25",
+	} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("Dump output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("Dump leaked a secret:\n%s", out)
+	}
+	if a.Payload["smtp"].(map[string]any)["password"] != "hunter2" {
+		t.Error("Dump modified the job's payload")
+	}
+
+	b := *a
+	b.Status = resources.JobStatusFailed
+	b.LastError = &lastErr
+	b.Payload = map[string]any{"to": "a@example.com", "smtp": map[string]any{"password": "other", "port": 587}}
+	diff := DiffJobsWithOptions(a, &b, opts)
+	want := "status: processing -> failed\nlast_error: (unset) -> smtp timeout\npayload.smtp.port: 25 -> 587\n"
+	if diff != want {
+		t.Errorf("DiffJobs() =\n%s\nwant\n%s", diff, want)
+	}
+	if diff := DiffJobs(a, a); diff != "" {
+		t.Errorf("DiffJobs() of equal jobs = %q", diff)
+	}
+
+	if err := Dump(&buf, "not a job"); err == nil {
+		t.Error("Dump() of unsupported type: expected error")
+	}
+}
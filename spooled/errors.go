@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
 )
 
 // APIError is the base error type for all Spooled SDK errors.
@@ -100,9 +102,14 @@ func (e *RateLimitError) GetRetryAfter() int {
 	return int(e.RetryAfter.Seconds())
 }
 
-// PayloadTooLargeError represents a 413 error.
+// PayloadTooLargeError represents a 413 error, or a payload over
+// WithMaxPayloadBytes rejected before it was sent.
 type PayloadTooLargeError struct {
 	*APIError
+	// Size is the JSON-encoded payload size in bytes, if known.
+	Size int
+	// Limit is the size limit in bytes, if known.
+	Limit int
 }
 
 // ServerError represents a 5xx error.
@@ -195,8 +202,67 @@ func IsNotFoundError(err error) bool {
 
 // IsRateLimitError returns true if the error is a rate limit error.
 func IsRateLimitError(err error) bool {
+	_, ok := AsRateLimitError(err)
+	return ok
+}
+
+// AsRateLimitError returns the rate limit details of a 429 error: how long to
+// wait before retrying and the limit, remaining requests, and reset time the
+// server reported. Requests are retried after Retry-After automatically; the
+// error is returned once retries are exhausted.
+func AsRateLimitError(err error) (*RateLimitError, bool) {
 	var rateLimitErr *RateLimitError
-	return errors.As(err, &rateLimitErr)
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr, true
+	}
+	var httpErr *httpx.RateLimitError
+	if !errors.As(err, &httpErr) {
+		return nil, false
+	}
+	base := httpErr.APIError
+	return &RateLimitError{
+		APIError: &APIError{
+			StatusCode: base.StatusCode,
+			Code:       base.Code,
+			Message:    base.Message,
+			Details:    base.Details,
+			RequestID:  base.RequestID,
+			RawBody:    base.RawBody,
+			Err:        httpErr,
+		},
+		RetryAfter: httpErr.RetryAfter,
+		Limit:      httpErr.Limit,
+		Remaining:  httpErr.Remaining,
+		Reset:      httpErr.Reset,
+	}, true
+}
+
+// AsPayloadTooLargeError returns the details of a payload rejected by the
+// server with 413, or by WithMaxPayloadBytes before it was sent, in which case
+// Size and Limit are set.
+func AsPayloadTooLargeError(err error) (*PayloadTooLargeError, bool) {
+	var tooLargeErr *PayloadTooLargeError
+	if errors.As(err, &tooLargeErr) {
+		return tooLargeErr, true
+	}
+	var httpErr *httpx.PayloadTooLargeError
+	if !errors.As(err, &httpErr) {
+		return nil, false
+	}
+	base := httpErr.APIError
+	return &PayloadTooLargeError{
+		APIError: &APIError{
+			StatusCode: base.StatusCode,
+			Code:       base.Code,
+			Message:    base.Message,
+			Details:    base.Details,
+			RequestID:  base.RequestID,
+			RawBody:    base.RawBody,
+			Err:        httpErr,
+		},
+		Size:  httpErr.Size,
+		Limit: httpErr.Limit,
+	}, true
 }
 
 // IsValidationError returns true if the error is a validation error.
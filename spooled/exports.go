@@ -243,3 +243,19 @@ func GetDashboard(client *Client) (*resources.DashboardData, error) {
 func GetHealth(client *Client) (*resources.HealthResponse, error) {
 	return client.Health().Get(context.Background())
 }
+
+// NextMaintenanceWindow returns the next period of length window, starting
+// now, in which none of the given active schedules runs. Use
+// Schedules().FindQuietPeriod to consider a queue's schedules directly.
+//
+// Example:
+//
+//	schedules, _ := client.Schedules().List(ctx, nil)
+//	w, err := spooled.NextMaintenanceWindow(schedules, 30*time.Minute)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Run maintenance at %s\n", w.Start)
+func NextMaintenanceWindow(schedules []resources.Schedule, window time.Duration) (*resources.QuietPeriod, error) {
+	return resources.NextQuietPeriod(schedules, window, time.Now())
+}
@@ -0,0 +1,299 @@
+// Package githubci reports Spooled job outcomes to GitHub, so build and test
+// pipelines orchestrated through Spooled show up on the commits they ran for.
+//
+// Producers tag each job with its commit, a queue's completion webhook is
+// pointed at a Notifier, and the Notifier turns every delivery into a commit
+// status or a repository_dispatch event:
+//
+//	// Producer
+//	client.Jobs().Create(ctx, &resources.CreateJobRequest{
+//		QueueName: "ci-tests",
+//		Payload:   map[string]any{"suite": "integration"},
+//		Tags:      githubci.Tags("acme/api", sha),
+//	})
+//
+//	// Receiver
+//	githubci.Subscribe(ctx, client, "ci-tests", "https://ci.example.com/spooled", secret)
+//	http.Handle("/spooled", githubci.NewNotifier(githubci.Options{
+//		Token:    os.Getenv("GITHUB_TOKEN"),
+//		Verifier: webhook.NewVerifier(secret, webhook.Options{}),
+//	}))
+package githubci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/webhook"
+)
+
+// Job tags that tie a job to a commit. See Tags.
+const (
+	// TagRepository holds the repository as "owner/name".
+	TagRepository = "github_repository"
+	// TagSHA holds the commit SHA. Commit statuses need it; dispatch events
+	// include it when present.
+	TagSHA = "github_sha"
+)
+
+// DefaultAPIURL is the GitHub REST API used unless Options.APIURL is set.
+const DefaultAPIURL = "https://api.github.com"
+
+// DefaultDispatchEventType is the repository_dispatch event type used unless
+// Options.DispatchEventType is set.
+const DefaultDispatchEventType = "spooled_job"
+
+// SubscribedEvents are the job events Subscribe delivers to the Notifier.
+var SubscribedEvents = []resources.WebhookEvent{
+	resources.WebhookEventJobCompleted,
+	resources.WebhookEventJobFailed,
+	resources.WebhookEventJobCancelled,
+}
+
+var (
+	// ErrNoCommit is returned by Notify for jobs without a TagRepository tag,
+	// or without a TagSHA tag in ModeCommitStatus.
+	ErrNoCommit = errors.New("githubci: job is not tagged with a GitHub commit")
+	// ErrUnsupportedEvent is returned by Notify for events with no commit
+	// status equivalent.
+	ErrUnsupportedEvent = errors.New("githubci: unsupported event")
+)
+
+// Tags returns the job tags that tie a job to a commit, for
+// CreateJobRequest.Tags.
+func Tags(repository, sha string) map[string]any {
+	return map[string]any{TagRepository: repository, TagSHA: sha}
+}
+
+// Subscribe points a queue's completion webhook at a Notifier's URL, signing
+// deliveries with secret so the Notifier's Verifier can check them.
+func Subscribe(ctx context.Context, client *spooled.Client, queue, receiverURL, secret string) (*resources.QueueCompletionWebhook, error) {
+	return client.Queues().SetCompletionWebhookWithOptions(ctx, queue, receiverURL, &resources.CompletionWebhookOptions{
+		Events:  SubscribedEvents,
+		Signing: &resources.WebhookSigning{Secret: secret},
+	})
+}
+
+// Delivery is a completion webhook delivery: the job event and the job.
+type Delivery struct {
+	Event resources.WebhookEvent `json:"event"`
+	Job   resources.Job          `json:"payload"`
+}
+
+// Mode selects what a Notifier creates on GitHub.
+type Mode string
+
+const (
+	// ModeCommitStatus sets a commit status on the job's commit.
+	ModeCommitStatus Mode = "status"
+	// ModeDispatch sends a repository_dispatch event, to trigger a workflow
+	// with "on: repository_dispatch".
+	ModeDispatch Mode = "dispatch"
+)
+
+// Options configures a Notifier.
+type Options struct {
+	// Token is a GitHub token allowed to create commit statuses or dispatch
+	// events on the tagged repositories.
+	Token string
+	// Mode selects commit statuses or dispatch events (default:
+	// ModeCommitStatus).
+	Mode Mode
+	// StatusContext labels commit statuses (default: "spooled/<queue>").
+	StatusContext string
+	// TargetURL links a commit status to a page for the job, such as the
+	// dashboard (optional).
+	TargetURL func(job *resources.Job) string
+	// DispatchEventType is the repository_dispatch event type (default:
+	// DefaultDispatchEventType).
+	DispatchEventType string
+	// APIURL is the GitHub API base URL, for GitHub Enterprise Server
+	// (default: DefaultAPIURL).
+	APIURL string
+	// HTTPClient sends requests to GitHub (default: http.DefaultClient).
+	HTTPClient *http.Client
+	// Verifier checks deliveries in ServeHTTP (optional but recommended).
+	Verifier *webhook.Verifier
+}
+
+// Notifier reports job outcomes to GitHub.
+type Notifier struct {
+	opts Options
+}
+
+// NewNotifier creates a Notifier.
+func NewNotifier(opts Options) *Notifier {
+	if opts.Mode == "" {
+		opts.Mode = ModeCommitStatus
+	}
+	if opts.DispatchEventType == "" {
+		opts.DispatchEventType = DefaultDispatchEventType
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = DefaultAPIURL
+	}
+	opts.APIURL = strings.TrimSuffix(opts.APIURL, "/")
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Notifier{opts: opts}
+}
+
+// commitStates maps job events to commit status states.
+var commitStates = map[resources.WebhookEvent]string{
+	resources.WebhookEventJobCreated:   "pending",
+	resources.WebhookEventJobStarted:   "pending",
+	resources.WebhookEventJobCompleted: "success",
+	resources.WebhookEventJobFailed:    "failure",
+	resources.WebhookEventJobCancelled: "error",
+}
+
+// Notify reports one delivery to GitHub.
+func (n *Notifier) Notify(ctx context.Context, d *Delivery) error {
+	repository, _ := d.Job.Tags[TagRepository].(string)
+	sha, _ := d.Job.Tags[TagSHA].(string)
+	if repository == "" || (n.opts.Mode == ModeCommitStatus && sha == "") {
+		return ErrNoCommit
+	}
+	if n.opts.Mode == ModeDispatch {
+		return n.dispatch(ctx, repository, sha, d)
+	}
+	return n.setStatus(ctx, repository, sha, d)
+}
+
+func (n *Notifier) setStatus(ctx context.Context, repository, sha string, d *Delivery) error {
+	state, ok := commitStates[d.Event]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedEvent, d.Event)
+	}
+	statusContext := n.opts.StatusContext
+	if statusContext == "" {
+		statusContext = "spooled/" + d.Job.QueueName
+	}
+	body := map[string]any{
+		"state":       state,
+		"context":     statusContext,
+		"description": description(d),
+	}
+	if n.opts.TargetURL != nil {
+		if target := n.opts.TargetURL(&d.Job); target != "" {
+			body["target_url"] = target
+		}
+	}
+	return n.post(ctx, fmt.Sprintf("/repos/%s/statuses/%s", repository, sha), body)
+}
+
+func (n *Notifier) dispatch(ctx context.Context, repository, sha string, d *Delivery) error {
+	payload := map[string]any{
+		"event":      d.Event,
+		"job_id":     d.Job.ID,
+		"queue_name": d.Job.QueueName,
+		"status":     d.Job.Status,
+	}
+	if sha != "" {
+		payload["sha"] = sha
+	}
+	if d.Job.Result != nil {
+		payload["result"] = d.Job.Result
+	}
+	if d.Job.LastError != nil {
+		payload["error"] = *d.Job.LastError
+	}
+	return n.post(ctx, fmt.Sprintf("/repos/%s/dispatches", repository), map[string]any{
+		"event_type":     n.opts.DispatchEventType,
+		"client_payload": payload,
+	})
+}
+
+// description summarizes the outcome within GitHub's 140 character limit.
+func description(d *Delivery) string {
+	desc := fmt.Sprintf("Job %s %s", d.Job.ID, strings.TrimPrefix(string(d.Event), "job."))
+	if d.Event == resources.WebhookEventJobFailed && d.Job.LastError != nil {
+		desc += ": " + *d.Job.LastError
+	}
+	if len(desc) > 140 {
+		desc = desc[:137] + "..."
+	}
+	return desc
+}
+
+func (n *Notifier) post(ctx context.Context, path string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.opts.APIURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if n.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.opts.Token)
+	}
+
+	resp, err := n.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("githubci: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("githubci: POST %s: %s: %s", path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// ServeHTTP handles completion webhook deliveries. It responds 200 once the
+// outcome has been reported, 204 for jobs without a commit or events that do
+// not map to a commit status, 401 for deliveries the Verifier rejects, and 502
+// if GitHub rejected the update, so Spooled redelivers it.
+func (n *Notifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body []byte
+	var err error
+	if n.opts.Verifier != nil {
+		body, err = n.opts.Verifier.VerifyRequest(r)
+		if errors.Is(err, webhook.ErrReplayDetected) {
+			w.WriteHeader(http.StatusOK) // already handled
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	} else if body, err = io.ReadAll(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var d Delivery
+	if err := json.Unmarshal(body, &d); err != nil {
+		http.Error(w, "invalid delivery: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = n.Notify(r.Context(), &d)
+	switch {
+	case errors.Is(err, ErrNoCommit), errors.Is(err, ErrUnsupportedEvent):
+		w.WriteHeader(http.StatusNoContent)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,265 @@
+package githubci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/webhook"
+)
+
+// githubRequest is a request received by the fake GitHub API.
+type githubRequest struct {
+	Path   string
+	Header http.Header
+	Body   map[string]any
+}
+
+// fakeGitHub records requests and answers them with status.
+type fakeGitHub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []githubRequest
+	status   int
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+	gh := &fakeGitHub{status: http.StatusCreated}
+	gh.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gh.mu.Lock()
+		defer gh.mu.Unlock()
+		gh.requests = append(gh.requests, githubRequest{Path: r.URL.Path, Header: r.Header, Body: body})
+		w.WriteHeader(gh.status)
+		if gh.status >= 300 {
+			_, _ = w.Write([]byte(`{"message":"Validation Failed"}`))
+		}
+	}))
+	t.Cleanup(gh.Close)
+	return gh
+}
+
+func (gh *fakeGitHub) received() []githubRequest {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	return append([]githubRequest(nil), gh.requests...)
+}
+
+func (gh *fakeGitHub) respondWith(status int) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	gh.status = status
+}
+
+func delivery(event resources.WebhookEvent, tags map[string]any) *Delivery {
+	return &Delivery{Event: event, Job: resources.Job{
+		ID:        "job-1",
+		QueueName: "ci-tests",
+		Status:    resources.JobStatusCompleted,
+		Tags:      tags,
+	}}
+}
+
+func TestNotifier_CommitStatus(t *testing.T) {
+	gh := newFakeGitHub(t)
+	n := NewNotifier(Options{Token: "ghp_test", APIURL: gh.URL + "/"})
+
+	tests := []struct {
+		event resources.WebhookEvent
+		state string
+	}{
+		{resources.WebhookEventJobCreated, "pending"},
+		{resources.WebhookEventJobCompleted, "success"},
+		{resources.WebhookEventJobFailed, "failure"},
+		{resources.WebhookEventJobCancelled, "error"},
+	}
+	for _, tt := range tests {
+		if err := n.Notify(context.Background(), delivery(tt.event, Tags("acme/api", "abc123"))); err != nil {
+			t.Fatalf("Notify(%s): %v", tt.event, err)
+		}
+	}
+	requests := gh.received()
+	if len(requests) != len(tests) {
+		t.Fatalf("GitHub received %d requests, want %d", len(requests), len(tests))
+	}
+	for i, req := range requests {
+		if req.Path != "/repos/acme/api/statuses/abc123" {
+			t.Errorf("path = %s", req.Path)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer ghp_test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if req.Body["state"] != tests[i].state || req.Body["context"] != "spooled/ci-tests" {
+			t.Errorf("%s: body = %v, want state %s", tests[i].event, req.Body, tests[i].state)
+		}
+		if _, ok := req.Body["target_url"]; ok {
+			t.Errorf("target_url set without Options.TargetURL: %v", req.Body)
+		}
+	}
+}
+
+func TestNotifier_StatusOptions(t *testing.T) {
+	gh := newFakeGitHub(t)
+	n := NewNotifier(Options{
+		APIURL:        gh.URL,
+		StatusContext: "integration",
+		TargetURL:     func(job *resources.Job) string { return "https://dash.example.com/jobs/" + job.ID },
+	})
+
+	d := delivery(resources.WebhookEventJobFailed, Tags("acme/api", "abc123"))
+	lastError := strings.Repeat("x", 200)
+	d.Job.LastError = &lastError
+	if err := n.Notify(context.Background(), d); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	body := gh.received()[0].Body
+	if body["context"] != "integration" || body["target_url"] != "https://dash.example.com/jobs/job-1" {
+		t.Errorf("body = %v", body)
+	}
+	desc, _ := body["description"].(string)
+	if len(desc) != 140 || !strings.HasPrefix(desc, "Job job-1 failed: xxx") || !strings.HasSuffix(desc, "...") {
+		t.Errorf("description = %q, want the error truncated to 140 characters", desc)
+	}
+}
+
+func TestNotifier_Dispatch(t *testing.T) {
+	gh := newFakeGitHub(t)
+	n := NewNotifier(Options{APIURL: gh.URL, Mode: ModeDispatch})
+
+	d := delivery(resources.WebhookEventJobCompleted, Tags("acme/api", "abc123"))
+	d.Job.Result = map[string]any{"passed": true}
+	if err := n.Notify(context.Background(), d); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	// Dispatch events need only the repository
+	d = delivery(resources.WebhookEventJobStarted, map[string]any{TagRepository: "acme/api"})
+	if err := n.Notify(context.Background(), d); err != nil {
+		t.Fatalf("Notify without a SHA: %v", err)
+	}
+
+	requests := gh.received()
+	if len(requests) != 2 {
+		t.Fatalf("GitHub received %d requests, want 2", len(requests))
+	}
+	if requests[0].Path != "/repos/acme/api/dispatches" || requests[0].Body["event_type"] != DefaultDispatchEventType {
+		t.Errorf("request = %s %v", requests[0].Path, requests[0].Body)
+	}
+	payload, _ := requests[0].Body["client_payload"].(map[string]any)
+	want := map[string]any{
+		"event":      "job.completed",
+		"job_id":     "job-1",
+		"queue_name": "ci-tests",
+		"status":     "completed",
+		"sha":        "abc123",
+	}
+	for k, v := range want {
+		if payload[k] != v {
+			t.Errorf("client_payload[%s] = %v, want %v", k, payload[k], v)
+		}
+	}
+	if result, _ := payload["result"].(map[string]any); result["passed"] != true {
+		t.Errorf("client_payload result = %v", payload["result"])
+	}
+	payload, _ = requests[1].Body["client_payload"].(map[string]any)
+	if _, ok := payload["sha"]; ok {
+		t.Errorf("client_payload = %v, want no sha", payload)
+	}
+}
+
+func TestNotifier_Errors(t *testing.T) {
+	gh := newFakeGitHub(t)
+	n := NewNotifier(Options{APIURL: gh.URL})
+	ctx := context.Background()
+
+	for _, tags := range []map[string]any{nil, {TagRepository: "acme/api"}, {TagSHA: "abc123"}, {TagRepository: 42, TagSHA: "abc123"}} {
+		if err := n.Notify(ctx, delivery(resources.WebhookEventJobCompleted, tags)); !errors.Is(err, ErrNoCommit) {
+			t.Errorf("Notify(tags %v) = %v, want ErrNoCommit", tags, err)
+		}
+	}
+	if err := n.Notify(ctx, delivery(resources.WebhookEventQueuePaused, Tags("acme/api", "abc123"))); !errors.Is(err, ErrUnsupportedEvent) {
+		t.Errorf("Notify(queue.paused) = %v, want ErrUnsupportedEvent", err)
+	}
+	if len(gh.received()) != 0 {
+		t.Errorf("GitHub received %d requests, want none", len(gh.received()))
+	}
+
+	gh.respondWith(http.StatusUnprocessableEntity)
+	err := n.Notify(ctx, delivery(resources.WebhookEventJobCompleted, Tags("acme/api", "abc123")))
+	if err == nil || !strings.Contains(err.Error(), "Validation Failed") {
+		t.Errorf("Notify() = %v, want GitHub's error", err)
+	}
+}
+
+func TestNotifier_ServeHTTP(t *testing.T) {
+	gh := newFakeGitHub(t)
+	const secret = "whsec_test"
+	n := NewNotifier(Options{
+		APIURL:   gh.URL,
+		Verifier: webhook.NewVerifier(secret, webhook.Options{Store: webhook.NewMemoryStore()}),
+	})
+
+	var seq int
+	serve := func(method, body string, sign bool) int {
+		req := httptest.NewRequest(method, "/spooled", strings.NewReader(body))
+		if sign {
+			seq++
+			id, now := "delivery-"+strconv.Itoa(seq), time.Now()
+			req.Header.Set(webhook.DeliveryIDHeader, id)
+			req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+			req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, now, id, []byte(body)))
+		}
+		rec := httptest.NewRecorder()
+		n.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A delivery as Spooled sends it: the event and the job under "payload"
+	body := `{"event":"job.completed","payload":{"id":"job-7","queue_name":"builds","status":"completed",` +
+		`"tags":{"github_repository":"acme/web","github_sha":"def456"}}}`
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		sign   bool
+		want   int
+	}{
+		{"reported", http.MethodPost, body, true, http.StatusOK},
+		{"unsigned", http.MethodPost, body, false, http.StatusUnauthorized},
+		{"untagged job", http.MethodPost, `{"event":"job.completed","payload":{"id":"job-8"}}`, true, http.StatusNoContent},
+		{"unsupported event", http.MethodPost, strings.Replace(body, "job.completed", "schedule.triggered", 1), true, http.StatusNoContent},
+		{"invalid json", http.MethodPost, `{"event":`, true, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "", false, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.method, tt.body, tt.sign); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	requests := gh.received()
+	if len(requests) != 1 {
+		t.Fatalf("GitHub received %d requests, want 1", len(requests))
+	}
+	if requests[0].Path != "/repos/acme/web/statuses/def456" ||
+		requests[0].Body["state"] != "success" || requests[0].Body["context"] != "spooled/builds" {
+		t.Errorf("request = %s %v", requests[0].Path, requests[0].Body)
+	}
+
+	// GitHub rejecting the update asks Spooled to redeliver
+	gh.respondWith(http.StatusInternalServerError)
+	if got := serve(http.MethodPost, body, true); got != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", got)
+	}
+}
@@ -5,17 +5,25 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
 	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
 )
 
 // Client is the gRPC client for Spooled.
@@ -24,6 +32,14 @@ type Client struct {
 	queueClient  pb.QueueServiceClient
 	workerClient pb.WorkerServiceClient
 	apiKey       string
+	apiKeyMu     sync.RWMutex
+	metadata     []string
+	stopWatch    context.CancelFunc
+	useNumber    bool
+
+	maxSendMsgSize       int
+	maxPayloadBytes      int
+	compressionThreshold int
 }
 
 // ClientOptions configures the gRPC client.
@@ -40,19 +56,61 @@ type ClientOptions struct {
 	DialOptions []grpc.DialOption
 	// Timeout is the connection timeout
 	Timeout time.Duration
+	// Events receives reconnect events (optional)
+	Events *sdkevents.Bus
+	// UserAgent is prepended to the gRPC user agent (optional)
+	UserAgent string
+	// Metadata is added to every outgoing call (optional)
+	Metadata map[string]string
+	// FallbackAddresses are connected to, in order, when Address is unreachable (optional)
+	FallbackAddresses []string
+	// UseNumber returns payload numbers as json.Number, as REST does with UseNumber
+	UseNumber bool
+	// MaxRecvMsgSize is the largest message the client accepts (default: 4MB)
+	MaxRecvMsgSize int
+	// MaxSendMsgSize is the largest message the client sends (default: 4MB).
+	// Larger requests fail with *MessageTooLargeError before being sent.
+	MaxSendMsgSize int
+	// IdleTimeout closes the connection after this long without RPCs; the next
+	// call re-dials transparently (default: 5 minutes, negative disables)
+	IdleTimeout time.Duration
+	// MaxPayloadBytes makes Enqueue reject payloads larger than this when
+	// encoded as JSON with *httpx.PayloadTooLargeError (0: no limit)
+	MaxPayloadBytes int
+	// CompressionThreshold gzips Enqueue requests of at least this many
+	// bytes (0 disables compression)
+	CompressionThreshold int
 }
 
 // DefaultAddress is the default gRPC server address.
 const DefaultAddress = "grpc.spooled.cloud:443"
 
+// DefaultIdleTimeout is how long an unused connection is kept open.
+const DefaultIdleTimeout = 5 * time.Minute
+
 // NewClient creates a new gRPC client.
 func NewClient(opts ClientOptions) (*Client, error) {
+	if !supported {
+		return nil, ErrUnsupportedPlatform
+	}
 	if opts.Address == "" {
 		opts.Address = DefaultAddress
 	}
 	if opts.Timeout == 0 {
 		opts.Timeout = 30 * time.Second
 	}
+	if opts.MaxRecvMsgSize == 0 {
+		opts.MaxRecvMsgSize = DefaultMaxMsgSize
+	}
+	if opts.MaxSendMsgSize == 0 {
+		opts.MaxSendMsgSize = DefaultMaxMsgSize
+	}
+	if opts.IdleTimeout == 0 {
+		opts.IdleTimeout = DefaultIdleTimeout
+	} else if opts.IdleTimeout < 0 {
+		// grpc-go treats zero as "never idle"
+		opts.IdleTimeout = 0
+	}
 
 	// Determine TLS setting
 	useTLS := true
@@ -78,6 +136,35 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	if opts.UserAgent != "" {
+		dialOpts = append(dialOpts, grpc.WithUserAgent(opts.UserAgent))
+	}
+	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(
+		grpc.MaxCallRecvMsgSize(opts.MaxRecvMsgSize),
+		grpc.MaxCallSendMsgSize(opts.MaxSendMsgSize),
+	))
+	// An idle channel drops its connections and reconnects on the next RPC, so
+	// occasional callers don't hold stale connections through LB restarts
+	dialOpts = append(dialOpts, grpc.WithIdleTimeout(opts.IdleTimeout))
+
+	// Fail over across addresses with a static resolver; the default
+	// pick_first policy connects to the first reachable address in order
+	target := opts.Address
+	if len(opts.FallbackAddresses) > 0 {
+		r := manual.NewBuilderWithScheme("spooled-failover")
+		addrs := make([]resolver.Address, 0, len(opts.FallbackAddresses)+1)
+		for _, addr := range append([]string{opts.Address}, opts.FallbackAddresses...) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			addrs = append(addrs, resolver.Address{Addr: addr, ServerName: host})
+		}
+		r.InitialState(resolver.State{Addresses: addrs})
+		dialOpts = append(dialOpts, grpc.WithResolvers(r))
+		target = r.Scheme() + ":///" + opts.Address
+	}
+
 	// Add custom dial options
 	dialOpts = append(dialOpts, opts.DialOptions...)
 
@@ -85,35 +172,122 @@ func NewClient(opts ClientOptions) (*Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, opts.Address, dialOpts...)
+	conn, err := grpc.DialContext(ctx, target, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
 	}
 
-	return &Client{
+	c := &Client{
 		conn:         conn,
 		queueClient:  pb.NewQueueServiceClient(conn),
 		workerClient: pb.NewWorkerServiceClient(conn),
 		apiKey:       opts.APIKey,
-	}, nil
+		useNumber:    opts.UseNumber,
+
+		maxSendMsgSize:       opts.MaxSendMsgSize,
+		maxPayloadBytes:      opts.MaxPayloadBytes,
+		compressionThreshold: opts.CompressionThreshold,
+	}
+	for k, v := range opts.Metadata {
+		c.metadata = append(c.metadata, strings.ToLower(k), v)
+	}
+
+	if opts.Events != nil {
+		watchCtx, stop := context.WithCancel(context.Background())
+		c.stopWatch = stop
+		go c.watchConnectivity(watchCtx, opts.Address, opts.IdleTimeout, opts.Events)
+	}
+
+	return c, nil
+}
+
+// watchConnectivity emits a reconnect event whenever the connection becomes
+// ready again after having been ready before. Re-dialing after an idle
+// teardown is not a reconnect, but pick_first also moves a dropped connection
+// to IDLE, so IDLE only counts as a teardown when the channel went there
+// straight from READY after at least idleTimeout.
+func (c *Client) watchConnectivity(ctx context.Context, address string, idleTimeout time.Duration, events *sdkevents.Bus) {
+	state := c.conn.GetState()
+	wasReady := state == connectivity.Ready
+	readySince := time.Now()
+	sawFailure := false
+	previous := state
+	for c.conn.WaitForStateChange(ctx, state) {
+		state = c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			if wasReady {
+				events.Emit(sdkevents.TypeGRPCReconnect, sdkevents.GRPCReconnectData{Address: address, PreviousState: previous.String()})
+			}
+			wasReady = true
+			readySince = time.Now()
+			sawFailure = false
+		case connectivity.TransientFailure, connectivity.Connecting:
+			sawFailure = true
+		case connectivity.Idle:
+			if !sawFailure && idleTimeout > 0 && time.Since(readySince) >= idleTimeout {
+				wasReady = false
+			}
+		}
+		previous = state
+	}
+}
+
+// WaitForReady blocks until the connection is ready or ctx is done.
+func (c *Client) WaitForReady(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return fmt.Errorf("gRPC connection is shut down")
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("gRPC connection not ready (%s): %w", state, ctx.Err())
+		}
+	}
+}
+
+// State returns the connection state: "idle", "connecting", "ready",
+// "transient_failure", or "shutdown".
+func (c *Client) State() string {
+	return strings.ToLower(c.conn.GetState().String())
 }
 
 // Close closes the gRPC connection.
 func (c *Client) Close() error {
+	if c.stopWatch != nil {
+		c.stopWatch()
+	}
 	if c.conn != nil {
 		return c.conn.Close()
 	}
 	return nil
 }
 
-// withAuth adds authentication metadata to the context.
+// withAuth adds authentication and client metadata to the context.
 func (c *Client) withAuth(ctx context.Context) context.Context {
-	if c.apiKey != "" {
-		return metadata.AppendToOutgoingContext(ctx, "x-api-key", c.apiKey)
+	if len(c.metadata) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, c.metadata...)
+	}
+	c.apiKeyMu.RLock()
+	apiKey := c.apiKey
+	c.apiKeyMu.RUnlock()
+	if apiKey != "" {
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
 	}
 	return ctx
 }
 
+// SetAPIKey replaces the API key sent with subsequent calls.
+func (c *Client) SetAPIKey(key string) {
+	c.apiKeyMu.Lock()
+	defer c.apiKeyMu.Unlock()
+	c.apiKey = key
+}
+
 // Queue Service Methods
 
 // EnqueueRequest is the request for enqueueing a job.
@@ -135,6 +309,11 @@ type EnqueueResponse struct {
 
 // Enqueue enqueues a new job.
 func (c *Client) Enqueue(ctx context.Context, req *EnqueueRequest) (*EnqueueResponse, error) {
+	if req.Payload != nil {
+		if err := httpx.CheckPayloadSize(req.Payload, c.maxPayloadBytes); err != nil {
+			return nil, err
+		}
+	}
 	ctx = c.withAuth(ctx)
 
 	pbReq := &pb.EnqueueRequest{
@@ -147,7 +326,7 @@ func (c *Client) Enqueue(ctx context.Context, req *EnqueueRequest) (*EnqueueResp
 
 	// Convert payload to protobuf Struct
 	if req.Payload != nil {
-		s, err := structpb.NewStruct(req.Payload)
+		s, err := newStruct(req.Payload)
 		if err == nil {
 			pbReq.Payload = s
 		}
@@ -157,10 +336,18 @@ func (c *Client) Enqueue(ctx context.Context, req *EnqueueRequest) (*EnqueueResp
 	if req.ScheduledAt != nil {
 		pbReq.ScheduledAt = timestamppb.New(*req.ScheduledAt)
 	}
+	if err := c.checkSendSize(pbReq); err != nil {
+		return nil, err
+	}
+
+	var callOpts []grpc.CallOption
+	if c.compressionThreshold > 0 && proto.Size(pbReq) >= c.compressionThreshold {
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	}
 
-	resp, err := c.queueClient.Enqueue(ctx, pbReq)
+	resp, err := c.queueClient.Enqueue(ctx, pbReq, callOpts...)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return &EnqueueResponse{
@@ -169,6 +356,69 @@ func (c *Client) Enqueue(ctx context.Context, req *EnqueueRequest) (*EnqueueResp
 	}, nil
 }
 
+// DefaultBatchConcurrency is the number of in-flight RPCs used by EnqueueBatch.
+const DefaultBatchConcurrency = 16
+
+// EnqueueBatchResult is the per-item result of EnqueueBatch.
+type EnqueueBatchResult struct {
+	// Index is the position of the request in the batch.
+	Index   int
+	JobID   string
+	Created bool
+	// Err is set if this item failed to enqueue.
+	Err error
+}
+
+// EnqueueBatch enqueues multiple jobs and returns one result per request, in order.
+//
+// The server has no batch RPC, so this is a concurrent fan-out of Enqueue
+// calls: each request is its own unary RPC, with up to
+// DefaultBatchConcurrency in flight over the client's connection. It saves
+// round trips, not requests; every item counts against rate limits, and the
+// batch is not atomic. Items fail independently, so retry only those with
+// Err set, or set IdempotencyKey so a retried batch does not enqueue twice.
+// The returned error is only set if ctx ends before all items were sent;
+// unsent items then carry ctx.Err().
+func (c *Client) EnqueueBatch(ctx context.Context, reqs []EnqueueRequest) ([]EnqueueBatchResult, error) {
+	results := make([]EnqueueBatchResult, len(reqs))
+	sem := make(chan struct{}, DefaultBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range reqs {
+		results[i].Index = i
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		// select picks at random when both are ready, so check ctx itself
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(reqs); j++ {
+				results[j] = EnqueueBatchResult{Index: j, Err: err}
+			}
+			wg.Wait()
+			return results, err
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := c.Enqueue(ctx, &reqs[i])
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].JobID = resp.JobID
+			results[i].Created = resp.Created
+		}(i)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
 // DequeueRequest is the request for dequeuing jobs.
 type DequeueRequest struct {
 	QueueName        string
@@ -207,12 +457,12 @@ func (c *Client) Dequeue(ctx context.Context, req *DequeueRequest) (*DequeueResp
 
 	resp, err := c.queueClient.Dequeue(ctx, pbReq)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	jobs := make([]*Job, len(resp.Jobs))
 	for i, j := range resp.Jobs {
-		jobs[i] = pbJobToJob(j)
+		jobs[i] = pbJobToJob(j, c.useNumber)
 	}
 
 	return &DequeueResponse{Jobs: jobs}, nil
@@ -235,14 +485,17 @@ func (c *Client) Complete(ctx context.Context, req *CompleteRequest) error {
 	}
 
 	if req.Result != nil {
-		s, err := structpb.NewStruct(req.Result)
+		s, err := newStruct(req.Result)
 		if err == nil {
 			pbReq.Result = s
 		}
 	}
+	if err := c.checkSendSize(pbReq); err != nil {
+		return err
+	}
 
 	_, err := c.queueClient.Complete(ctx, pbReq)
-	return err
+	return convertError(err)
 }
 
 // FailRequest is the request to fail a job.
@@ -265,7 +518,7 @@ func (c *Client) Fail(ctx context.Context, req *FailRequest) error {
 	}
 
 	_, err := c.queueClient.Fail(ctx, pbReq)
-	return err
+	return convertError(err)
 }
 
 // RenewLeaseRequest is the request to renew a job lease.
@@ -293,7 +546,7 @@ func (c *Client) RenewLease(ctx context.Context, req *RenewLeaseRequest) (*Renew
 
 	resp, err := c.queueClient.RenewLease(ctx, pbReq)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	result := &RenewLeaseResponse{
@@ -313,10 +566,10 @@ func (c *Client) GetJob(ctx context.Context, jobID string) (*Job, error) {
 
 	resp, err := c.queueClient.GetJob(ctx, &pb.GetJobRequest{JobId: jobID})
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
-	return pbJobToJob(resp.Job), nil
+	return pbJobToJob(resp.Job, c.useNumber), nil
 }
 
 // QueueStats represents queue statistics.
@@ -338,7 +591,7 @@ func (c *Client) GetQueueStats(ctx context.Context, queueName string) (*QueueSta
 
 	resp, err := c.queueClient.GetQueueStats(ctx, &pb.GetQueueStatsRequest{QueueName: queueName})
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return &QueueStats{
@@ -402,7 +655,7 @@ func (c *Client) RegisterWorker(ctx context.Context, req *RegisterWorkerRequest)
 
 	resp, err := c.workerClient.Register(ctx, pbReq)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return &RegisterWorkerResponse{
@@ -428,7 +681,7 @@ func (c *Client) WorkerHeartbeat(ctx context.Context, req *WorkerHeartbeatReques
 		CurrentJobs: req.CurrentJobs,
 		Status:      req.Status,
 	})
-	return err
+	return convertError(err)
 }
 
 // DeregisterWorker deregisters a worker.
@@ -438,12 +691,12 @@ func (c *Client) DeregisterWorker(ctx context.Context, workerID string) error {
 	_, err := c.workerClient.Deregister(ctx, &pb.DeregisterRequest{
 		WorkerId: workerID,
 	})
-	return err
+	return convertError(err)
 }
 
 // Helper functions
 
-func pbJobToJob(j *pb.Job) *Job {
+func pbJobToJob(j *pb.Job, useNumber bool) *Job {
 	if j == nil {
 		return nil
 	}
@@ -458,7 +711,7 @@ func pbJobToJob(j *pb.Job) *Job {
 	}
 
 	if j.Payload != nil {
-		job.Payload = j.Payload.AsMap()
+		job.Payload = structToMap(j.Payload, useNumber)
 	}
 
 	if j.LeaseExpiresAt != nil {
@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+)
+
+// newTestClient serves svc on a local port and returns a client for it.
+func newTestClient(t *testing.T, svc pb.QueueServiceServer) *Client {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterQueueServiceServer(srv, svc)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	useTLS := false
+	client, err := NewClient(ClientOptions{
+		Address: lis.Addr().String(),
+		APIKey:  "sp_test_123456789012345678901234567890",
+		UseTLS:  &useTLS,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// batchServer answers each Enqueue after a short delay, rejecting jobs for
+// the "invalid" queue, and tracks how many calls are in flight.
+type batchServer struct {
+	pb.UnimplementedQueueServiceServer
+	mu          sync.Mutex
+	calls       int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *batchServer) Enqueue(_ context.Context, req *pb.EnqueueRequest) (*pb.EnqueueResponse, error) {
+	s.mu.Lock()
+	s.calls++
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if req.QueueName == "invalid" {
+		return nil, status.Error(codes.InvalidArgument, "invalid queue")
+	}
+	return &pb.EnqueueResponse{JobId: req.IdempotencyKey, Created: true}, nil
+}
+
+func TestClient_EnqueueBatch(t *testing.T) {
+	svc := &batchServer{}
+	client := newTestClient(t, svc)
+
+	reqs := make([]EnqueueRequest, 3*DefaultBatchConcurrency)
+	for i := range reqs {
+		reqs[i] = EnqueueRequest{QueueName: "emails", IdempotencyKey: fmt.Sprintf("job-%d", i)}
+	}
+	reqs[5].QueueName = "invalid"
+
+	results, err := client.EnqueueBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("EnqueueBatch() error = %v", err)
+	}
+	if len(results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(results), len(reqs))
+	}
+	for i, result := range results {
+		if i == 5 {
+			if result.Err == nil || result.JobID != "" {
+				t.Errorf("results[5] = %+v, want the server's error", result)
+			}
+			continue
+		}
+		if result.Index != i || result.JobID != reqs[i].IdempotencyKey || !result.Created || result.Err != nil {
+			t.Errorf("results[%d] = %+v, want job %s", i, result, reqs[i].IdempotencyKey)
+		}
+	}
+
+	// One unary call per item, fanned out up to the concurrency limit
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.calls != len(reqs) {
+		t.Errorf("Enqueue RPCs = %d, want %d", svc.calls, len(reqs))
+	}
+	if svc.maxInFlight < 2 || svc.maxInFlight > DefaultBatchConcurrency {
+		t.Errorf("max in-flight RPCs = %d, want 2..%d", svc.maxInFlight, DefaultBatchConcurrency)
+	}
+}
+
+func TestClient_EnqueueBatchCancelled(t *testing.T) {
+	client := newTestClient(t, &batchServer{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := client.EnqueueBatch(ctx, make([]EnqueueRequest, 3))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("EnqueueBatch() error = %v, want context.Canceled", err)
+	}
+	for i, result := range results {
+		if result.Err == nil {
+			t.Errorf("results[%d].Err = nil, want an error for the cancelled batch", i)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// ErrUnsupportedPlatform is returned by NewClient under GOOS=js, where only
+// the REST API is available.
+var ErrUnsupportedPlatform = errors.New("gRPC is not supported on this platform; use the REST API")
+
+// FieldViolation describes an invalid request field reported by the server.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// convertError maps a gRPC status error to the same typed errors returned by
+// the REST transport, so errors.As behaves identically for both.
+// The original status error stays reachable via errors.Unwrap / status.FromError.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if st.Code() == codes.Canceled {
+		return err
+	}
+
+	baseErr := &httpx.APIError{
+		StatusCode: httpStatusFromCode(st.Code()),
+		Code:       strings.ToLower(st.Code().String()),
+		Message:    st.Message(),
+		Err:        err,
+	}
+
+	var (
+		violations []FieldViolation
+		retryInfo  *errdetails.RetryInfo
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Reason != "" {
+				baseErr.Code = strings.ToLower(d.Reason)
+			}
+			if len(d.Metadata) > 0 {
+				details := ensureDetails(baseErr)
+				for k, v := range d.Metadata {
+					details[k] = v
+				}
+			}
+		case *errdetails.RequestInfo:
+			baseErr.RequestID = d.RequestId
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				violations = append(violations, FieldViolation{Field: v.Field, Description: v.Description})
+			}
+		case *errdetails.RetryInfo:
+			retryInfo = d
+		}
+	}
+	if len(violations) > 0 {
+		ensureDetails(baseErr)["field_violations"] = violations
+	}
+
+	switch st.Code() {
+	case codes.Unauthenticated:
+		return &httpx.AuthenticationError{APIError: baseErr}
+	case codes.PermissionDenied:
+		if planErr, ok := httpx.ParsePlanLimitError(baseErr); ok {
+			return planErr
+		}
+		return &httpx.AuthorizationError{APIError: baseErr}
+	case codes.NotFound:
+		return &httpx.NotFoundError{APIError: baseErr}
+	case codes.AlreadyExists, codes.Aborted:
+		return &httpx.ConflictError{APIError: baseErr}
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return &httpx.ValidationError{APIError: baseErr}
+	case codes.ResourceExhausted:
+		// Size limit violations share the code with rate limiting
+		if strings.Contains(st.Message(), "larger than max") {
+			return &MessageTooLargeError{APIError: baseErr}
+		}
+		rateErr := &httpx.RateLimitError{APIError: baseErr}
+		if retryInfo != nil && retryInfo.RetryDelay != nil {
+			rateErr.RetryAfter = retryInfo.RetryDelay.AsDuration()
+		}
+		return rateErr
+	case codes.DeadlineExceeded:
+		return &httpx.TimeoutError{APIError: baseErr}
+	case codes.Unavailable:
+		return &httpx.NetworkError{APIError: baseErr}
+	default:
+		return &httpx.ServerError{APIError: baseErr}
+	}
+}
+
+// FieldViolations returns the field violations attached to a validation error.
+func FieldViolations(err error) []FieldViolation {
+	var apiErr *httpx.APIError
+	if !errors.As(err, &apiErr) || apiErr.Details == nil {
+		return nil
+	}
+	violations, _ := apiErr.Details["field_violations"].([]FieldViolation)
+	return violations
+}
+
+func ensureDetails(e *httpx.APIError) map[string]any {
+	if e.Details == nil {
+		e.Details = make(map[string]any)
+	}
+	return e.Details
+}
+
+// httpStatusFromCode maps a gRPC code to the equivalent HTTP status.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// DefaultMaxMsgSize is gRPC's default limit on message size in each direction.
+const DefaultMaxMsgSize = 4 << 20
+
+// MessageTooLargeError is returned when a request or response exceeds a gRPC
+// message size limit. Raise ClientOptions.MaxSendMsgSize or MaxRecvMsgSize,
+// send the data over REST, or store it elsewhere and pass a reference.
+type MessageTooLargeError struct {
+	*httpx.APIError
+	// Size is the encoded message size in bytes, if known.
+	Size int
+	// Limit is the size limit in bytes, if known.
+	Limit int
+}
+
+// Unwrap returns the underlying API error.
+func (e *MessageTooLargeError) Unwrap() error { return e.APIError }
+
+func newMessageTooLargeError(size, limit int) *MessageTooLargeError {
+	return &MessageTooLargeError{
+		APIError: &httpx.APIError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Code:       "message_too_large",
+			Message:    fmt.Sprintf("gRPC message of %d bytes exceeds the %d byte limit", size, limit),
+		},
+		Size:  size,
+		Limit: limit,
+	}
+}
+
+// checkSendSize returns a *MessageTooLargeError if msg exceeds the send limit.
+func (c *Client) checkSendSize(msg proto.Message) error {
+	if size := proto.Size(msg); size > c.maxSendMsgSize {
+		return newMessageTooLargeError(size, c.maxSendMsgSize)
+	}
+	return nil
+}
+
+// MaxSendMsgSize returns the largest message this client will send.
+func (c *Client) MaxSendMsgSize() int {
+	return c.maxSendMsgSize
+}
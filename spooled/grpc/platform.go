@@ -0,0 +1,6 @@
+//go:build !js
+
+package grpc
+
+// supported reports whether gRPC connections can be made on this platform.
+const supported = true
@@ -0,0 +1,7 @@
+//go:build js
+
+package grpc
+
+// supported reports whether gRPC connections can be made on this platform.
+// Browsers cannot open the HTTP/2 connections gRPC needs.
+const supported = false
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+)
+
+// JobStream is a worker's ProcessJobs stream. The worker asks for jobs with
+// Request as it has capacity, and the server pushes them as they become
+// available, so an idle worker does not poll.
+//
+// Request and Recv may be called from different goroutines, but neither may
+// be called concurrently with itself.
+type JobStream struct {
+	stream           pb.QueueService_ProcessJobsClient
+	queueName        string
+	workerID         string
+	leaseDurationSec int32
+	useNumber        bool
+}
+
+// OpenJobStream opens a ProcessJobs stream for a registered worker. Cancel ctx
+// to close the stream.
+func (c *Client) OpenJobStream(ctx context.Context, queueName, workerID string, leaseDurationSec int32) (*JobStream, error) {
+	stream, err := c.ProcessJobs(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &JobStream{
+		stream:           stream,
+		queueName:        queueName,
+		workerID:         workerID,
+		leaseDurationSec: leaseDurationSec,
+		useNumber:        c.useNumber,
+	}, nil
+}
+
+// Request asks the server for up to n more jobs.
+func (s *JobStream) Request(n int) error {
+	err := s.stream.Send(&pb.ProcessRequest{
+		Request: &pb.ProcessRequest_Dequeue{Dequeue: &pb.DequeueRequest{
+			QueueName:         s.queueName,
+			WorkerId:          s.workerID,
+			BatchSize:         int32(n),
+			LeaseDurationSecs: s.leaseDurationSec,
+		}},
+	})
+	return convertError(err)
+}
+
+// Recv blocks until the server pushes a job. Acknowledgements for requests
+// sent on the stream are skipped; an error message from the server ends the
+// stream and is returned as an *httpx.APIError.
+func (s *JobStream) Recv() (*Job, error) {
+	for {
+		resp, err := s.stream.Recv()
+		if err != nil {
+			return nil, convertError(err)
+		}
+		switch r := resp.Response.(type) {
+		case *pb.ProcessResponse_Job:
+			return pbJobToJob(r.Job, s.useNumber), nil
+		case *pb.ProcessResponse_Error:
+			return nil, &httpx.APIError{Code: r.Error.Code, Message: r.Error.Message}
+		}
+	}
+}
+
+// Close tells the server no more requests will be sent.
+func (s *JobStream) Close() error {
+	return s.stream.CloseSend()
+}
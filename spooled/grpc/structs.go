@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// maxExactFloat is the largest integer a float64 represents exactly.
+const maxExactFloat = 1 << 53
+
+// newStruct converts a payload or result to a protobuf Struct. Struct numbers
+// are doubles, so json.Number values that a double cannot hold exactly are
+// sent as strings to keep their digits.
+func newStruct(m map[string]any) (*structpb.Struct, error) {
+	return structpb.NewStruct(normalizeNumbers(m).(map[string]any))
+}
+
+// normalizeNumbers returns a copy of v with json.Number values converted to
+// float64 or, when that would lose precision, to strings.
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, inner := range v {
+			out[k] = normalizeNumbers(inner)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, inner := range v {
+			out[i] = normalizeNumbers(inner)
+		}
+		return out
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			if n > maxExactFloat || n < -maxExactFloat {
+				return v.String()
+			}
+			return float64(n)
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// structToMap converts a protobuf Struct to a map. With useNumber, numbers are
+// returned as json.Number to match REST responses decoded with UseNumber.
+func structToMap(s *structpb.Struct, useNumber bool) map[string]any {
+	m := s.AsMap()
+	if useNumber {
+		m = floatsToNumbers(m).(map[string]any)
+	}
+	return m
+}
+
+func floatsToNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, inner := range v {
+			v[k] = floatsToNumbers(inner)
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = floatsToNumbers(inner)
+		}
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return v
+}
@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/resume"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+)
+
+// ResumeTokenMetadataKey carries a StreamJobs resume token. The client sends
+// it when re-subscribing, and the server may return an updated token in the
+// stream's trailer.
+const ResumeTokenMetadataKey = "x-resume-token"
+
+// JobSubscriptionOptions configures OpenJobSubscription.
+type JobSubscriptionOptions struct {
+	// LeaseDurationSec is the lease of each streamed job (default: the server's)
+	LeaseDurationSec int32
+	// ResumeToken starts the stream after the job with this token, e.g. one
+	// saved from JobSubscription.ResumeToken before a restart (optional)
+	ResumeToken string
+	// ReconnectDelay is the delay before the first attempt to reopen a
+	// dropped stream, doubling after each failure (default: 1s)
+	ReconnectDelay time.Duration
+	// MaxReconnectDelay caps the delay between attempts (default: 30s)
+	MaxReconnectDelay time.Duration
+	// MaxReconnectAttempts is how many times in a row the stream is reopened
+	// without receiving a job before Recv gives up (default: 10, negative for
+	// no limit)
+	MaxReconnectAttempts int
+}
+
+// JobSubscription is a StreamJobs stream that survives transient network
+// failures. When the stream drops, Recv reopens it with exponential backoff
+// and resumes after the last job it delivered, so jobs are neither missed nor
+// delivered twice.
+//
+// Recv and Close must not be called concurrently.
+type JobSubscription struct {
+	client *Client
+	ctx    context.Context
+	req    *pb.StreamJobsRequest
+	opts   JobSubscriptionOptions
+	cursor *resume.Cursor
+
+	stream   pb.QueueService_StreamJobsClient
+	cancel   context.CancelFunc
+	failures int
+	// lastErr is the error that dropped the stream.
+	lastErr error
+	closed  bool
+}
+
+// OpenJobSubscription opens a resumable StreamJobs stream. Cancel ctx or call
+// Close to end it.
+//
+//	sub, err := client.OpenJobSubscription(ctx, "emails", workerID, grpc.JobSubscriptionOptions{})
+//	for {
+//		job, err := sub.Recv()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+func (c *Client) OpenJobSubscription(ctx context.Context, queueName, workerID string, opts JobSubscriptionOptions) (*JobSubscription, error) {
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = time.Second
+	}
+	if opts.MaxReconnectDelay <= 0 {
+		opts.MaxReconnectDelay = 30 * time.Second
+	}
+	if opts.MaxReconnectAttempts == 0 {
+		opts.MaxReconnectAttempts = 10
+	}
+	s := &JobSubscription{
+		client: c,
+		ctx:    ctx,
+		req: &pb.StreamJobsRequest{
+			QueueName:         queueName,
+			WorkerId:          workerID,
+			LeaseDurationSecs: opts.LeaseDurationSec,
+		},
+		opts:   opts,
+		cursor: resume.NewCursor(opts.ResumeToken, 0),
+	}
+	if err := s.open(); err != nil {
+		return nil, convertError(err)
+	}
+	return s, nil
+}
+
+// Recv blocks until the server pushes a job. It returns io.EOF when the
+// server ends the stream, and an error when the stream fails for a reason
+// other than a transient network failure or cannot be reopened.
+func (s *JobSubscription) Recv() (*Job, error) {
+	for {
+		if s.closed {
+			return nil, io.EOF
+		}
+		if s.stream == nil {
+			if err := s.reopen(); err != nil {
+				return nil, err
+			}
+		}
+		msg, err := s.stream.Recv()
+		if err == nil {
+			s.failures = 0
+			// Jobs replayed by the server after resuming were delivered
+			// already
+			if !s.cursor.Advance(msg.Id, msg.Id) {
+				continue
+			}
+			return pbJobToJob(msg, s.client.useNumber), nil
+		}
+
+		if tokens := s.stream.Trailer().Get(ResumeTokenMetadataKey); len(tokens) > 0 {
+			s.cursor.SetToken(tokens[len(tokens)-1])
+		}
+		s.closeStream()
+		s.lastErr = err
+		if errors.Is(err, io.EOF) || !isTransient(err) || s.ctx.Err() != nil {
+			return nil, convertError(err)
+		}
+	}
+}
+
+// ResumeToken returns the token of the last job delivered, which the stream
+// resumes from after reconnecting.
+func (s *JobSubscription) ResumeToken() string {
+	return s.cursor.Token()
+}
+
+// Close ends the stream. Recv then returns io.EOF.
+func (s *JobSubscription) Close() error {
+	s.closed = true
+	s.closeStream()
+	return nil
+}
+
+// open opens the stream, resuming from the cursor.
+func (s *JobSubscription) open() error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	if token := s.cursor.Token(); token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, ResumeTokenMetadataKey, token)
+	}
+	stream, err := s.client.queueClient.StreamJobs(s.client.withAuth(ctx), s.req)
+	if err != nil {
+		cancel()
+		return err
+	}
+	s.stream, s.cancel = stream, cancel
+	return nil
+}
+
+// reopen reopens a dropped stream with exponential backoff.
+func (s *JobSubscription) reopen() error {
+	for {
+		s.failures++
+		if s.opts.MaxReconnectAttempts > 0 && s.failures > s.opts.MaxReconnectAttempts {
+			return convertError(s.lastErr)
+		}
+		delay := s.opts.ReconnectDelay << min(s.failures-1, 16)
+		if delay > s.opts.MaxReconnectDelay || delay <= 0 {
+			delay = s.opts.MaxReconnectDelay
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return s.ctx.Err()
+		case <-timer.C:
+		}
+
+		err := s.open()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) {
+			return convertError(err)
+		}
+		s.lastErr = err
+	}
+}
+
+func (s *JobSubscription) closeStream() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.stream, s.cancel = nil, nil
+}
+
+// isTransient reports whether a stream error is a network failure worth
+// reopening the stream for.
+func isTransient(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
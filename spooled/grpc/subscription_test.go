@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+)
+
+// resumingServer drops the first StreamJobs stream after two jobs, then
+// replays the last one on the resumed stream.
+type resumingServer struct {
+	pb.UnimplementedQueueServiceServer
+	calls       atomic.Int32
+	resumedFrom atomic.Value
+}
+
+func (s *resumingServer) StreamJobs(req *pb.StreamJobsRequest, stream grpc.ServerStreamingServer[pb.Job]) error {
+	send := func(ids ...string) error {
+		for _, id := range ids {
+			if err := stream.Send(&pb.Job{Id: id, QueueName: req.QueueName}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if s.calls.Add(1) == 1 {
+		if err := send("job-1", "job-2"); err != nil {
+			return err
+		}
+		return status.Error(codes.Unavailable, "server going away")
+	}
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	s.resumedFrom.Store(md.Get(ResumeTokenMetadataKey))
+	if err := send("job-2", "job-3"); err != nil {
+		return err
+	}
+	stream.SetTrailer(metadata.Pairs(ResumeTokenMetadataKey, "cursor-3"))
+	return nil
+}
+
+func TestJobSubscription_ResumesAfterTransientFailure(t *testing.T) {
+	backend := &resumingServer{}
+	client := newTestClient(t, backend)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sub, err := client.OpenJobSubscription(ctx, "emails", "worker-1", JobSubscriptionOptions{ReconnectDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("OpenJobSubscription() error = %v", err)
+	}
+	defer sub.Close()
+
+	var got []string
+	for {
+		job, err := sub.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		got = append(got, job.ID)
+	}
+	if len(got) != 3 || got[0] != "job-1" || got[1] != "job-2" || got[2] != "job-3" {
+		t.Errorf("received %v, want each job once", got)
+	}
+	if from, _ := backend.resumedFrom.Load().([]string); len(from) != 1 || from[0] != "job-2" {
+		t.Errorf("resumed with token %v, want job-2", from)
+	}
+	if token := sub.ResumeToken(); token != "cursor-3" {
+		t.Errorf("ResumeToken() = %q, want the server's cursor-3", token)
+	}
+}
@@ -0,0 +1,25 @@
+package spooled
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testAPIKey is a well-formed API key for test clients.
+const testAPIKey = "sp_test_123456789012345678901234567890"
+
+// newTestClient starts a server answering with handler and returns a client
+// pointed at it, configured with opts. Both are closed when the test ends.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(append([]Option{WithAPIKey(testAPIKey), WithBaseURL(server.URL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
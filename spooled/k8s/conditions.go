@@ -0,0 +1,82 @@
+package k8s
+
+import "time"
+
+// ConditionStatus is the status of a Condition.
+type ConditionStatus string
+
+// Condition statuses, as in Kubernetes.
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition types set by Reconciler.Reconcile.
+const (
+	// ConditionQueueReady is true when the spec's queue exists and is enabled.
+	ConditionQueueReady = "QueueReady"
+	// ConditionSchedulesReady is true when the queue's schedules match the
+	// spec.
+	ConditionSchedulesReady = "SchedulesReady"
+	// ConditionReady is true when every other condition is.
+	ConditionReady = "Ready"
+)
+
+// Condition reasons set by Reconciler.Reconcile.
+const (
+	ReasonInvalidSpec         = "InvalidSpec"
+	ReasonAPIError            = "APIError"
+	ReasonQueueFound          = "QueueFound"
+	ReasonQueueCreated        = "QueueCreated"
+	ReasonQueueNotFound       = "QueueNotFound"
+	ReasonQueueDisabled       = "QueueDisabled"
+	ReasonQueueNotReady       = "QueueNotReady"
+	ReasonSchedulesInSync     = "SchedulesInSync"
+	ReasonSchedulesCorrected  = "SchedulesCorrected"
+	ReasonUnexpectedSchedules = "UnexpectedSchedules"
+	ReasonReconciled          = "Reconciled"
+	ReasonNotReady            = "NotReady"
+)
+
+// Condition is an observation of one aspect of a resource's state, with the
+// same fields and JSON encoding as a Kubernetes metav1.Condition.
+type Condition struct {
+	Type   string          `json:"type"`
+	Status ConditionStatus `json:"status"`
+	// ObservedGeneration is the resource generation the condition was set
+	// for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is when Status last changed.
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+	// Reason is a CamelCase identifier for the condition's last transition.
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// FindCondition returns the condition of the given type, or nil.
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of c's type and returns the
+// updated slice. LastTransitionTime is kept when the status is unchanged and
+// set to now otherwise.
+func SetCondition(conditions []Condition, c Condition, now time.Time) []Condition {
+	existing := FindCondition(conditions, c.Type)
+	if existing == nil {
+		c.LastTransitionTime = now
+		return append(conditions, c)
+	}
+	c.LastTransitionTime = existing.LastTransitionTime
+	if existing.Status != c.Status {
+		c.LastTransitionTime = now
+	}
+	*existing = c
+	return conditions
+}
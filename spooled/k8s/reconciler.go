@@ -0,0 +1,280 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// WorkerDeploymentStatus is the observed state of a worker deployment, as
+// maintained by Reconciler.Reconcile.
+type WorkerDeploymentStatus struct {
+	// ObservedGeneration is the spec generation last reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DesiredReplicas is the replica count the deployment should be scaled
+	// to, when the spec has autoscaling.
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	PendingJobs     int   `json:"pendingJobs"`
+	ProcessingJobs  int   `json:"processingJobs"`
+	// ScheduleIDs maps the names of the spec's schedules to their IDs.
+	ScheduleIDs map[string]string `json:"scheduleIDs,omitempty"`
+	Conditions  []Condition       `json:"conditions,omitempty"`
+}
+
+// ReconcilerOptions configures a Reconciler.
+type ReconcilerOptions struct {
+	// CreateQueue creates the spec's queue with the organization defaults if
+	// it is missing, instead of reporting it as not found.
+	CreateQueue bool
+	// PruneSchedules deletes schedules on the queue that the spec does not
+	// list, instead of reporting them as drift.
+	PruneSchedules bool
+	// Now returns the time conditions transition at (default: time.Now).
+	Now func() time.Time
+}
+
+// Reconciler brings Spooled in line with a WorkerDeploymentSpec and reports
+// what it found in a WorkerDeploymentStatus. It holds no state between calls,
+// so one Reconciler can serve every worker deployment using the client.
+type Reconciler struct {
+	client *spooled.Client
+	opts   ReconcilerOptions
+}
+
+// NewReconciler creates a Reconciler that uses client.
+func NewReconciler(client *spooled.Client, opts ReconcilerOptions) *Reconciler {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Reconciler{client: client, opts: opts}
+}
+
+// Reconcile checks that the spec's queue exists, registers the spec's
+// schedules, creating missing ones and updating ones that drifted, and
+// computes DesiredReplicas from the queue's stats. It records the outcome in
+// status as the QueueReady, SchedulesReady, and Ready conditions, stamped
+// with generation.
+//
+// Drift that Reconcile cannot correct, such as a missing queue, is reported
+// through the conditions only. The returned error covers an invalid spec and
+// failed API calls, after which the controller should requeue the resource;
+// the affected conditions are then Unknown.
+func (r *Reconciler) Reconcile(ctx context.Context, generation int64, spec WorkerDeploymentSpec, status *WorkerDeploymentStatus) error {
+	now := r.opts.Now()
+	set := func(conditionType string, conditionStatus ConditionStatus, reason, message string) {
+		status.Conditions = SetCondition(status.Conditions, Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: generation,
+			Reason:             reason,
+			Message:            message,
+		}, now)
+	}
+	status.ObservedGeneration = generation
+
+	if err := spec.Validate(); err != nil {
+		set(ConditionReady, ConditionFalse, ReasonInvalidSpec, err.Error())
+		return fmt.Errorf("invalid worker deployment spec: %w", err)
+	}
+
+	var errs []error
+	queueStatus, queueReason, queueMessage, err := r.reconcileQueue(ctx, spec)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	set(ConditionQueueReady, queueStatus, queueReason, queueMessage)
+
+	// Schedules and stats need the queue
+	schedulesStatus, schedulesReason, schedulesMessage := ConditionUnknown, ReasonQueueNotReady, "the queue is not ready"
+	if queueStatus == ConditionTrue {
+		schedulesStatus, schedulesReason, schedulesMessage, err = r.reconcileSchedules(ctx, spec, status)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if err := r.observeStats(ctx, spec, status); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	set(ConditionSchedulesReady, schedulesStatus, schedulesReason, schedulesMessage)
+
+	if queueStatus == ConditionTrue && schedulesStatus == ConditionTrue {
+		set(ConditionReady, ConditionTrue, ReasonReconciled, "the queue and schedules match the spec")
+	} else {
+		readyStatus := ConditionUnknown
+		if queueStatus == ConditionFalse || schedulesStatus == ConditionFalse {
+			readyStatus = ConditionFalse
+		}
+		set(ConditionReady, readyStatus, ReasonNotReady, "see the QueueReady and SchedulesReady conditions")
+	}
+	return errors.Join(errs...)
+}
+
+// reconcileQueue returns the QueueReady condition for the spec's queue.
+func (r *Reconciler) reconcileQueue(ctx context.Context, spec WorkerDeploymentSpec) (ConditionStatus, string, string, error) {
+	queue, err := r.client.Queues().Get(ctx, spec.QueueName)
+	reason := ReasonQueueFound
+	if httpx.IsNotFoundError(err) {
+		if !r.opts.CreateQueue {
+			return ConditionFalse, ReasonQueueNotFound, fmt.Sprintf("queue %q does not exist", spec.QueueName), nil
+		}
+		queue, err = r.client.Queues().Ensure(ctx, spec.QueueName, nil)
+		reason = ReasonQueueCreated
+	}
+	if err != nil {
+		return ConditionUnknown, ReasonAPIError, err.Error(), fmt.Errorf("queue %q: %w", spec.QueueName, err)
+	}
+	if !queue.Enabled {
+		return ConditionFalse, ReasonQueueDisabled, fmt.Sprintf("queue %q is disabled", spec.QueueName), nil
+	}
+	return ConditionTrue, reason, fmt.Sprintf("queue %q is enabled", spec.QueueName), nil
+}
+
+// reconcileSchedules registers the spec's schedules on the queue and returns
+// the SchedulesReady condition.
+func (r *Reconciler) reconcileSchedules(ctx context.Context, spec WorkerDeploymentSpec, status *WorkerDeploymentStatus) (ConditionStatus, string, string, error) {
+	existing, err := r.client.Schedules().List(ctx, &resources.ListSchedulesParams{QueueName: &spec.QueueName})
+	if err != nil {
+		return ConditionUnknown, ReasonAPIError, err.Error(), fmt.Errorf("list schedules: %w", err)
+	}
+	byName := make(map[string]resources.Schedule, len(existing))
+	for _, schedule := range existing {
+		byName[schedule.Name] = schedule
+	}
+
+	var corrected, unexpected []string
+	var errs []error
+	ids := make(map[string]string, len(spec.Schedules))
+	for _, want := range spec.Schedules {
+		have, ok := byName[want.Name]
+		delete(byName, want.Name)
+		switch {
+		case !ok:
+			created, err := r.client.Schedules().Create(ctx, createScheduleRequest(spec.QueueName, want))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("create schedule %q: %w", want.Name, err))
+				continue
+			}
+			ids[want.Name] = created.ID
+			corrected = append(corrected, fmt.Sprintf("created %q", want.Name))
+		case scheduleDrifted(have, want):
+			if _, err := r.client.Schedules().Update(ctx, have.ID, updateScheduleRequest(want)); err != nil {
+				errs = append(errs, fmt.Errorf("update schedule %q: %w", want.Name, err))
+				continue
+			}
+			ids[want.Name] = have.ID
+			corrected = append(corrected, fmt.Sprintf("updated %q", want.Name))
+		default:
+			ids[want.Name] = have.ID
+		}
+	}
+	// Schedules left in byName are not in the spec
+	for _, schedule := range existing {
+		name := schedule.Name
+		if _, ok := byName[name]; !ok {
+			continue
+		}
+		if !r.opts.PruneSchedules {
+			unexpected = append(unexpected, fmt.Sprintf("%q", name))
+			continue
+		}
+		if err := r.client.Schedules().Delete(ctx, schedule.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete schedule %q: %w", name, err))
+			continue
+		}
+		corrected = append(corrected, fmt.Sprintf("deleted %q", name))
+	}
+	status.ScheduleIDs = ids
+
+	switch {
+	case len(errs) > 0:
+		err := errors.Join(errs...)
+		return ConditionUnknown, ReasonAPIError, err.Error(), err
+	case len(unexpected) > 0:
+		return ConditionFalse, ReasonUnexpectedSchedules, "schedules not in the spec: " + strings.Join(unexpected, ", "), nil
+	case len(corrected) > 0:
+		return ConditionTrue, ReasonSchedulesCorrected, "corrected drift: " + strings.Join(corrected, ", "), nil
+	}
+	return ConditionTrue, ReasonSchedulesInSync, fmt.Sprintf("%d schedules match the spec", len(spec.Schedules)), nil
+}
+
+// observeStats records the queue's backlog and the replicas it calls for.
+func (r *Reconciler) observeStats(ctx context.Context, spec WorkerDeploymentSpec, status *WorkerDeploymentStatus) error {
+	stats, err := r.client.Queues().GetStats(ctx, spec.QueueName)
+	if err != nil {
+		return fmt.Errorf("queue %q stats: %w", spec.QueueName, err)
+	}
+	status.PendingJobs = stats.PendingJobs
+	status.ProcessingJobs = stats.ProcessingJobs
+	status.DesiredReplicas = spec.DesiredReplicas(stats)
+	return nil
+}
+
+func createScheduleRequest(queueName string, want ScheduleSpec) *resources.CreateScheduleRequest {
+	timezone, priority := scheduleTimezone(want), want.Priority
+	return &resources.CreateScheduleRequest{
+		Name:            want.Name,
+		CronExpression:  want.Cron,
+		Timezone:        &timezone,
+		QueueName:       queueName,
+		PayloadTemplate: scheduleSpecPayload(want),
+		Priority:        &priority,
+	}
+}
+
+func updateScheduleRequest(want ScheduleSpec) *resources.UpdateScheduleRequest {
+	timezone, priority := scheduleTimezone(want), want.Priority
+	return &resources.UpdateScheduleRequest{
+		CronExpression:  &want.Cron,
+		Timezone:        &timezone,
+		PayloadTemplate: scheduleSpecPayload(want),
+		Priority:        &priority,
+	}
+}
+
+// scheduleDrifted reports whether have differs from want in a field the
+// spec sets.
+func scheduleDrifted(have resources.Schedule, want ScheduleSpec) bool {
+	return have.CronExpression != want.Cron ||
+		have.Timezone != scheduleTimezone(want) ||
+		have.Priority != want.Priority ||
+		!reflect.DeepEqual(normalizeJSON(have.PayloadTemplate), normalizeJSON(scheduleSpecPayload(want)))
+}
+
+func scheduleTimezone(want ScheduleSpec) string {
+	if want.Timezone == "" {
+		return "UTC"
+	}
+	return want.Timezone
+}
+
+func scheduleSpecPayload(want ScheduleSpec) map[string]any {
+	if want.Payload == nil {
+		return map[string]any{}
+	}
+	return want.Payload
+}
+
+// normalizeJSON round-trips v through JSON, so payloads decoded from the API
+// compare equal to ones written in Go, e.g. float64(1) and int(1).
+func normalizeJSON(v map[string]any) any {
+	if len(v) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
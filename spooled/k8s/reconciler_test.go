@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/spooledtest"
+)
+
+func conditionStatus(t *testing.T, status *WorkerDeploymentStatus, conditionType string) (ConditionStatus, string) {
+	t.Helper()
+	c := FindCondition(status.Conditions, conditionType)
+	if c == nil {
+		t.Fatalf("condition %s not set", conditionType)
+	}
+	return c.Status, c.Reason
+}
+
+func TestReconciler(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewReconciler(client, ReconcilerOptions{Now: func() time.Time { return now }})
+	spec := WorkerDeploymentSpec{
+		QueueName:   "reports",
+		Concurrency: 4,
+		Autoscaling: &AutoscalingSpec{MinReplicas: 1, MaxReplicas: 3},
+		Schedules:   []ScheduleSpec{{Name: "nightly", Cron: "0 0 2 * * *", Payload: map[string]any{"kind": "nightly"}}},
+	}
+	var status WorkerDeploymentStatus
+
+	// The queue does not exist yet
+	if err := r.Reconcile(ctx, 1, spec, &status); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got, reason := conditionStatus(t, &status, ConditionQueueReady); got != ConditionFalse || reason != ReasonQueueNotFound {
+		t.Errorf("QueueReady = %s/%s, want False/%s", got, reason, ReasonQueueNotFound)
+	}
+	if got, _ := conditionStatus(t, &status, ConditionReady); got != ConditionFalse {
+		t.Errorf("Ready = %s, want False", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "reports", Payload: map[string]any{"i": i}}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if err := r.Reconcile(ctx, 1, spec, &status); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got, reason := conditionStatus(t, &status, ConditionSchedulesReady); got != ConditionTrue || reason != ReasonSchedulesCorrected {
+		t.Errorf("SchedulesReady = %s/%s, want True/%s", got, reason, ReasonSchedulesCorrected)
+	}
+	ready := FindCondition(status.Conditions, ConditionReady)
+	if ready.Status != ConditionTrue || !ready.LastTransitionTime.Equal(now) {
+		t.Errorf("Ready = %+v, want True since %v", ready, now)
+	}
+	if status.PendingJobs != 10 || status.DesiredReplicas != 3 {
+		t.Errorf("status = %d pending, %d replicas; want 10 and 3 (capped)", status.PendingJobs, status.DesiredReplicas)
+	}
+	id := status.ScheduleIDs["nightly"]
+	schedule, err := client.Schedules().Get(ctx, id)
+	if err != nil || schedule.CronExpression != "0 0 2 * * *" {
+		t.Fatalf("registered schedule = %+v, %v", schedule, err)
+	}
+
+	// Drift: the schedule is edited by hand and an unmanaged one is added
+	cron := "0 0 3 * * *"
+	if _, err := client.Schedules().Update(ctx, id, &resources.UpdateScheduleRequest{CronExpression: &cron}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := client.Schedules().Create(ctx, &resources.CreateScheduleRequest{Name: "adhoc", CronExpression: "0 * * * *", QueueName: "reports"}); err != nil {
+		t.Fatalf("Create schedule: %v", err)
+	}
+	transitioned := now
+	now = now.Add(time.Minute)
+	if err := r.Reconcile(ctx, 2, spec, &status); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got, reason := conditionStatus(t, &status, ConditionSchedulesReady); got != ConditionFalse || reason != ReasonUnexpectedSchedules {
+		t.Errorf("SchedulesReady = %s/%s, want False/%s", got, reason, ReasonUnexpectedSchedules)
+	}
+	if schedule, _ := client.Schedules().Get(ctx, id); schedule.CronExpression != "0 0 2 * * *" {
+		t.Errorf("drifted cron = %q, want it restored", schedule.CronExpression)
+	}
+	if queueReady := FindCondition(status.Conditions, ConditionQueueReady); !queueReady.LastTransitionTime.Equal(transitioned) || queueReady.ObservedGeneration != 2 {
+		t.Errorf("QueueReady = %+v, want unchanged since %v at generation 2", queueReady, transitioned)
+	}
+
+	pruning := NewReconciler(client, ReconcilerOptions{PruneSchedules: true})
+	if err := pruning.Reconcile(ctx, 2, spec, &status); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got, _ := conditionStatus(t, &status, ConditionReady); got != ConditionTrue {
+		t.Errorf("Ready = %s after pruning, want True", got)
+	}
+	if list, _ := client.Schedules().List(ctx, nil); len(list) != 1 {
+		t.Errorf("%d schedules after pruning, want 1", len(list))
+	}
+}
+
+func TestWorkerDeploymentSpec_DesiredReplicas(t *testing.T) {
+	age := func(seconds int) *int { return &seconds }
+	spec := WorkerDeploymentSpec{
+		QueueName:   "emails",
+		Autoscaling: &AutoscalingSpec{MinReplicas: 2, MaxReplicas: 10, MaxPendingAgeSeconds: 300},
+	}
+	tests := []struct {
+		name  string
+		stats resources.QueueStats
+		want  int32
+	}{
+		{"idle", resources.QueueStats{}, 2},
+		{"backlog", resources.QueueStats{PendingJobs: 18, ProcessingJobs: 5}, 5},
+		{"capped", resources.QueueStats{PendingJobs: 500}, 10},
+		{"stale", resources.QueueStats{PendingJobs: 1, OldestPendingAgeSeconds: age(600)}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spec.DesiredReplicas(&tt.stats); got != tt.want {
+				t.Errorf("DesiredReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if err := (&WorkerDeploymentSpec{Schedules: []ScheduleSpec{{Name: "a", Cron: "bad"}}}).Validate(); err == nil {
+		t.Error("Validate() should reject a missing queue and a bad cron expression")
+	}
+}
@@ -0,0 +1,148 @@
+// Package k8s provides the types and reconciliation logic for running Spooled
+// workers on Kubernetes, as groundwork for a Spooled operator or Helm-driven
+// controller.
+//
+// WorkerDeploymentSpec and WorkerDeploymentStatus are shaped as the spec and
+// status of a custom resource, with camelCase JSON fields and
+// Kubernetes-style conditions, but the package does not depend on the
+// Kubernetes libraries. A controller embeds them in its own CRD types and
+// calls Reconciler.Reconcile from its reconcile loop:
+//
+//	r := k8s.NewReconciler(client, k8s.ReconcilerOptions{})
+//	err := r.Reconcile(ctx, obj.Generation, obj.Spec, &obj.Status)
+//	// Scale the worker Deployment to obj.Status.DesiredReplicas and
+//	// update the object's status
+//
+// Worker pods build their worker from the same spec:
+//
+//	w := spooled.NewSpooledWorker(client, spec.WorkerOptions())
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/cron"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// DefaultConcurrency is the per-replica concurrency of a spec that sets none,
+// matching the worker default.
+const DefaultConcurrency = 5
+
+// WorkerDeploymentSpec is the desired state of a deployment of workers for
+// one queue.
+type WorkerDeploymentSpec struct {
+	// QueueName is the queue the workers process.
+	QueueName string `json:"queueName"`
+	// Concurrency is the number of jobs each replica processes at once
+	// (default: DefaultConcurrency).
+	Concurrency int `json:"concurrency,omitempty"`
+	// Autoscaling sizes the deployment from the queue's backlog (optional;
+	// without it DesiredReplicas is left to the deployment).
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// Schedules are registered on the queue by the reconciler.
+	Schedules []ScheduleSpec `json:"schedules,omitempty"`
+}
+
+// AutoscalingSpec sets the thresholds DesiredReplicas scales by.
+type AutoscalingSpec struct {
+	// MinReplicas is the fewest replicas to run, even when idle (default: 1).
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the most replicas to run.
+	MaxReplicas int32 `json:"maxReplicas"`
+	// TargetJobsPerReplica is the pending and processing jobs each replica
+	// should have (default: the spec's concurrency).
+	TargetJobsPerReplica int `json:"targetJobsPerReplica,omitempty"`
+	// MaxPendingAgeSeconds scales to MaxReplicas once the oldest pending job
+	// has waited longer, whatever the backlog size (optional).
+	MaxPendingAgeSeconds int `json:"maxPendingAgeSeconds,omitempty"`
+}
+
+// ScheduleSpec is a schedule the reconciler registers on the spec's queue.
+// Schedules are matched to existing ones by name.
+type ScheduleSpec struct {
+	Name string `json:"name"`
+	// Cron is the schedule's cron expression.
+	Cron string `json:"cron"`
+	// Timezone is the IANA timezone Cron is evaluated in (default: UTC).
+	Timezone string         `json:"timezone,omitempty"`
+	Payload  map[string]any `json:"payload,omitempty"`
+	Priority int            `json:"priority,omitempty"`
+}
+
+// Validate reports the problems with the spec, joined into one error.
+func (s *WorkerDeploymentSpec) Validate() error {
+	var errs []error
+	if s.QueueName == "" {
+		errs = append(errs, errors.New("queueName is required"))
+	}
+	if s.Concurrency < 0 {
+		errs = append(errs, fmt.Errorf("concurrency must not be negative, got %d", s.Concurrency))
+	}
+	if a := s.Autoscaling; a != nil {
+		if a.MaxReplicas < 1 {
+			errs = append(errs, fmt.Errorf("autoscaling.maxReplicas must be at least 1, got %d", a.MaxReplicas))
+		}
+		if a.MinReplicas < 0 || a.MinReplicas > a.MaxReplicas {
+			errs = append(errs, fmt.Errorf("autoscaling.minReplicas must be between 0 and maxReplicas, got %d", a.MinReplicas))
+		}
+		if a.TargetJobsPerReplica < 0 || a.MaxPendingAgeSeconds < 0 {
+			errs = append(errs, errors.New("autoscaling thresholds must not be negative"))
+		}
+	}
+	names := make(map[string]bool, len(s.Schedules))
+	for i, schedule := range s.Schedules {
+		switch {
+		case schedule.Name == "":
+			errs = append(errs, fmt.Errorf("schedules[%d].name is required", i))
+		case names[schedule.Name]:
+			errs = append(errs, fmt.Errorf("schedules[%d]: duplicate name %q", i, schedule.Name))
+		}
+		names[schedule.Name] = true
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			errs = append(errs, fmt.Errorf("schedules[%d].cron: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WorkerOptions returns the options for a worker pod of the deployment.
+// Set the remaining fields, such as PollInterval, on the result.
+func (s *WorkerDeploymentSpec) WorkerOptions() spooled.SpooledWorkerOptions {
+	return spooled.SpooledWorkerOptions{QueueName: s.QueueName, Concurrency: s.concurrency()}
+}
+
+// DesiredReplicas returns the replica count for the queue's current stats:
+// enough replicas for TargetJobsPerReplica pending and processing jobs each,
+// or MaxReplicas when the oldest pending job is older than
+// MaxPendingAgeSeconds, clamped to [MinReplicas, MaxReplicas]. It returns 0
+// if the spec has no autoscaling.
+func (s *WorkerDeploymentSpec) DesiredReplicas(stats *resources.QueueStats) int32 {
+	a := s.Autoscaling
+	if a == nil {
+		return 0
+	}
+	minReplicas := a.MinReplicas
+	if minReplicas == 0 {
+		minReplicas = 1
+	}
+	if a.MaxPendingAgeSeconds > 0 && stats.OldestPendingAgeSeconds != nil && *stats.OldestPendingAgeSeconds > a.MaxPendingAgeSeconds {
+		return max(a.MaxReplicas, minReplicas)
+	}
+	target := a.TargetJobsPerReplica
+	if target == 0 {
+		target = s.concurrency()
+	}
+	replicas := math.Ceil(float64(stats.PendingJobs+stats.ProcessingJobs) / float64(target))
+	return int32(min(max(replicas, float64(minReplicas)), float64(max(a.MaxReplicas, minReplicas))))
+}
+
+func (s *WorkerDeploymentSpec) concurrency() int {
+	if s.Concurrency == 0 {
+		return DefaultConcurrency
+	}
+	return s.Concurrency
+}
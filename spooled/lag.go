@@ -0,0 +1,107 @@
+package spooled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
+)
+
+// DefaultLagPollInterval is how often WatchLag checks a queue's lag.
+const DefaultLagPollInterval = 30 * time.Second
+
+// LagWatchOptions configures WatchLag. At least one threshold must be set.
+type LagWatchOptions struct {
+	// MaxAge is the longest the oldest pending job may wait.
+	MaxAge time.Duration
+	// MaxDrainTime is the longest the backlog may take to drain. A stalled
+	// queue, with jobs pending but none completing, exceeds it.
+	MaxDrainTime time.Duration
+	// Interval is how often lag is checked (default: DefaultLagPollInterval).
+	Interval time.Duration
+}
+
+// WatchLag checks a queue's consumer lag in the background until ctx is
+// cancelled, emitting a queue.lag_exceeded event when it crosses a threshold
+// and queue.lag_recovered when it falls back within them. The first check runs
+// before WatchLag returns, so a queue that is already lagging is reported.
+//
+// Example:
+//
+//	client.OnEvent(func(e sdkevents.Event) {
+//		if e.Type == sdkevents.TypeQueueLagExceeded {
+//			lag := e.Data.(sdkevents.QueueLagData)
+//			log.Printf("%s is %s behind", lag.QueueName, lag.OldestPendingAge)
+//		}
+//	})
+//	err := client.WatchLag(ctx, "emails", spooled.LagWatchOptions{MaxAge: 5 * time.Minute})
+func (c *Client) WatchLag(ctx context.Context, queue string, opts LagWatchOptions) error {
+	if opts.MaxAge <= 0 && opts.MaxDrainTime <= 0 {
+		return errors.New("lag watch requires MaxAge or MaxDrainTime")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultLagPollInterval
+	}
+	w := &lagWatcher{client: c, queue: queue, opts: opts}
+	lag, err := c.Queues().GetLag(ctx, queue)
+	if err != nil {
+		return fmt.Errorf("get queue lag: %w", err)
+	}
+	w.observe(lag)
+
+	go w.run(ctx)
+	return nil
+}
+
+type lagWatcher struct {
+	client   *Client
+	queue    string
+	opts     LagWatchOptions
+	exceeded bool
+}
+
+func (w *lagWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lag, err := w.client.Queues().GetLag(ctx, w.queue)
+			if err != nil {
+				w.client.debug("queue lag check failed", "queue", w.queue, "error", err)
+				continue
+			}
+			w.observe(lag)
+		}
+	}
+}
+
+// observe emits an event when lag crosses the thresholds in either direction.
+func (w *lagWatcher) observe(lag *resources.QueueLag) {
+	exceeded := (w.opts.MaxAge > 0 && lag.OldestPendingAge > w.opts.MaxAge) ||
+		(w.opts.MaxDrainTime > 0 && (lag.Stalled || lag.EstimatedDrainTime > w.opts.MaxDrainTime))
+	if exceeded == w.exceeded {
+		return
+	}
+	w.exceeded = exceeded
+
+	eventType := sdkevents.TypeQueueLagRecovered
+	if exceeded {
+		eventType = sdkevents.TypeQueueLagExceeded
+	}
+	w.client.events.Emit(eventType, sdkevents.QueueLagData{
+		QueueName:          w.queue,
+		PendingJobs:        lag.PendingJobs,
+		OldestPendingAge:   lag.OldestPendingAge,
+		EstimatedDrainTime: lag.EstimatedDrainTime,
+		Stalled:            lag.Stalled,
+		MaxAge:             w.opts.MaxAge,
+		MaxDrainTime:       w.opts.MaxDrainTime,
+	})
+}
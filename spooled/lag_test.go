@@ -0,0 +1,72 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
+)
+
+func TestWatchLag(t *testing.T) {
+	var mu sync.Mutex
+	var stats map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(stats)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan sdkevents.Event, 10)
+	client.OnEvent(func(e sdkevents.Event) {
+		if e.Type == sdkevents.TypeQueueLagExceeded || e.Type == sdkevents.TypeQueueLagRecovered {
+			events <- e
+		}
+	})
+	if err := client.WatchLag(ctx, "emails", LagWatchOptions{}); err == nil {
+		t.Error("WatchLag without thresholds should fail")
+	}
+
+	mu.Lock()
+	stats = map[string]any{"queue_name": "emails", "pending_jobs": 120, "oldest_pending_age_seconds": 600, "throughput_per_minute": 60.0}
+	mu.Unlock()
+	if err := client.WatchLag(ctx, "emails", LagWatchOptions{MaxAge: 5 * time.Minute, Interval: 5 * time.Millisecond}); err != nil {
+		t.Fatalf("WatchLag: %v", err)
+	}
+	select {
+	case e := <-events:
+		data := e.Data.(sdkevents.QueueLagData)
+		if e.Type != sdkevents.TypeQueueLagExceeded || data.QueueName != "emails" || data.OldestPendingAge != 10*time.Minute {
+			t.Errorf("first event = %s %+v, want lag_exceeded for emails", e.Type, data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no lag_exceeded event")
+	}
+
+	mu.Lock()
+	stats = map[string]any{"queue_name": "emails", "pending_jobs": 0, "throughput_per_minute": 60.0}
+	mu.Unlock()
+	select {
+	case e := <-events:
+		if e.Type != sdkevents.TypeQueueLagRecovered {
+			t.Errorf("second event = %s, want lag_recovered", e.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no lag_recovered event")
+	}
+
+	// No repeat events while lag stays within the thresholds.
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %s", e.Type)
+	default:
+	}
+}
@@ -0,0 +1,31 @@
+package spooled
+
+import (
+	"context"
+	"time"
+)
+
+// OfflineSpoolInterval is how often a client created WithOfflineSpool replays
+// its spool.
+const OfflineSpoolInterval = 10 * time.Second
+
+// replaySpool replays the offline spool now and every OfflineSpoolInterval
+// until the client is closed.
+func (c *Client) replaySpool() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopSpool = cancel
+	go func() {
+		ticker := time.NewTicker(OfflineSpoolInterval)
+		defer ticker.Stop()
+		for {
+			if n, err := c.jobs.ReplaySpool(ctx); n > 0 || err != nil {
+				c.debug("replayed offline spool", "delivered", n, "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
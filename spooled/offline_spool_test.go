@@ -0,0 +1,97 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestJobs_OfflineSpool(t *testing.T) {
+	var down atomic.Bool
+	var createKeys, bulkKeys []string
+	var mu sync.Mutex
+	dir := t.TempDir()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/jobs":
+			var req resources.CreateJobRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			createKeys = append(createKeys, *req.IdempotencyKey)
+			_, _ = w.Write([]byte(`{"id":"job-1","created":true}`))
+		case "/api/v1/jobs/bulk":
+			var req resources.BulkEnqueueRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			for _, job := range req.Jobs {
+				bulkKeys = append(bulkKeys, *job.IdempotencyKey)
+			}
+			_, _ = w.Write([]byte(`{"succeeded":[],"failed":[],"total":2,"success_count":2,"failure_count":0}`))
+		}
+	},
+		WithRetry(RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond}),
+		WithCircuitBreaker(CircuitBreakerConfig{Enabled: false}),
+		WithOfflineSpool(dir),
+	)
+
+	ctx := context.Background()
+
+	down.Store(true)
+	created, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{"to": "a@example.com"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v, want spooled", err)
+	}
+	if !created.Spooled {
+		t.Error("Create() Spooled = false, want true")
+	}
+	bulk, err := client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{
+		QueueName: "emails",
+		Jobs:      []resources.BulkJobItem{{Payload: map[string]any{"n": 1}}, {Payload: map[string]any{"n": 2}}},
+	})
+	if err != nil {
+		t.Fatalf("BulkEnqueue() error = %v, want spooled", err)
+	}
+	if !bulk.Spooled || bulk.Total != 2 || bulk.BatchID == "" {
+		t.Errorf("BulkEnqueue() = %+v, want spooled batch of 2", bulk)
+	}
+
+	if _, err := client.Jobs().ReplaySpool(ctx); err == nil {
+		t.Error("ReplaySpool() while down: expected error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if n := len(entries) - 1; n != 2 { // minus the failed directory
+		t.Fatalf("spooled %d entries, want 2", n)
+	}
+
+	down.Store(false)
+	if _, err := client.Jobs().ReplaySpool(ctx); err != nil {
+		t.Fatalf("ReplaySpool() error = %v", err)
+	}
+	entries, _ = os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("spool has %d entries after replay, want only the failed directory", len(entries))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(createKeys) != 1 || !strings.HasPrefix(createKeys[0], "offline:") {
+		t.Errorf("create idempotency keys = %v, want one offline: key", createKeys)
+	}
+	if len(bulkKeys) != 2 || bulkKeys[0] != "bulk:"+bulk.BatchID+":0" {
+		t.Errorf("bulk idempotency keys = %v, want keys derived from batch %s", bulkKeys, bulk.BatchID)
+	}
+}
@@ -0,0 +1,146 @@
+// Package payload provides typed accessors for job payloads and results.
+//
+// Payloads decoded into map[string]any hold JSON numbers as float64 by
+// default, which silently corrupts integers above 2^53 such as 64-bit IDs.
+// Enable spooled.WithUseNumber to decode them as json.Number instead, and read
+// values through Map so handlers work with either representation:
+//
+//	userID, err := ctx.Payload.Int64("user_id")
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// maxExactFloat is 2^53, above which float64 cannot represent every integer.
+const maxExactFloat = 1 << 53
+
+// ErrMissing is returned when a key is not present.
+var ErrMissing = errors.New("payload key not found")
+
+// ErrPrecisionLoss is returned when an integer was decoded as a float64 too
+// large to hold it exactly. Decode with spooled.WithUseNumber to avoid it.
+var ErrPrecisionLoss = errors.New("integer exceeds float64 precision; decode payloads with UseNumber")
+
+// Map is a job payload or result.
+type Map map[string]any
+
+// Int64 returns the integer at key. It accepts json.Number, float64 values
+// that hold an exact integer, Go integer types, and decimal strings.
+func (m Map) Int64(key string) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrMissing, key)
+	}
+	n, err := ToInt64(v)
+	if err != nil {
+		return 0, fmt.Errorf("payload key %q: %w", key, err)
+	}
+	return n, nil
+}
+
+// Float64 returns the number at key.
+func (m Map) Float64(key string) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrMissing, key)
+	}
+
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("payload key %q: %T is not a number", key, v)
+	}
+}
+
+// String returns the string at key.
+func (m Map) String(key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrMissing, key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("payload key %q: %T is not a string", key, v)
+	}
+	return s, nil
+}
+
+// Bool returns the boolean at key.
+func (m Map) Bool(key string) (bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return false, fmt.Errorf("%w: %q", ErrMissing, key)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("payload key %q: %T is not a bool", key, v)
+	}
+	return b, nil
+}
+
+// Map returns the object at key.
+func (m Map) Map(key string) (Map, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissing, key)
+	}
+	switch nested := v.(type) {
+	case map[string]any:
+		return nested, nil
+	case Map:
+		return nested, nil
+	default:
+		return nil, fmt.Errorf("payload key %q: %T is not an object", key, v)
+	}
+}
+
+// ToInt64 converts a decoded JSON value to an int64 without silently losing
+// precision.
+func ToInt64(v any) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Int64()
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		// Beyond ±2^53 the decoded value may already be rounded
+		if v >= maxExactFloat || v <= -maxExactFloat {
+			return 0, ErrPrecisionLoss
+		}
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("%T is not an integer", v)
+	}
+}
@@ -0,0 +1,52 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotObject is returned by Encode for values that do not encode to a JSON
+// object, such as strings and slices.
+var ErrNotObject = errors.New("payload is not a JSON object")
+
+// Encode converts a struct, or anything else that encodes to a JSON object,
+// into a payload. Integers keep full precision.
+//
+//	p, err := payload.Encode(SendEmail{To: "a@example.com"})
+//	client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: p})
+func Encode(v any) (Map, error) {
+	switch v := v.(type) {
+	case Map:
+		return v, nil
+	case map[string]any:
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+	var m Map
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("encode payload: %w: %T", ErrNotObject, v)
+	}
+	return m, nil
+}
+
+// Decode converts a payload or result into T, using T's json tags.
+//
+//	email, err := payload.Decode[SendEmail](job.Payload)
+func Decode[T any](m map[string]any) (T, error) {
+	var v T
+	data, err := json.Marshal(m)
+	if err != nil {
+		return v, fmt.Errorf("decode payload: %w", err)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("decode payload into %T: %w", v, err)
+	}
+	return v, nil
+}
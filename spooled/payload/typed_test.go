@@ -0,0 +1,16 @@
+package payload
+
+import (
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	type receipt struct {
+		MessageID string `json:"message_id"`
+	}
+
+	decoded, err := Decode[receipt](map[string]any{"message_id": "m-2"})
+	if err != nil || decoded.MessageID != "m-2" {
+		t.Errorf("Decode = %+v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package propagation carries trace context and baggage from producers to
+// workers through job tags.
+//
+// At enqueue time a Propagator injects the caller's trace context into a
+// Carrier stored under the TagKey job tag; at claim time the worker extracts
+// it into the handler's context. Carrier is a map[string]string, so an
+// OpenTelemetry propagator can be adapted directly:
+//
+//	type otelPropagator struct{}
+//
+//	func (otelPropagator) Inject(ctx context.Context, c propagation.Carrier) {
+//		otel.GetTextMapPropagator().Inject(ctx, otelprop.MapCarrier(c))
+//	}
+//
+//	func (otelPropagator) Extract(ctx context.Context, c propagation.Carrier) context.Context {
+//		return otel.GetTextMapPropagator().Extract(ctx, otelprop.MapCarrier(c))
+//	}
+package propagation
+
+import (
+	"context"
+	"strings"
+)
+
+// TagKey is the job tag that holds propagated fields.
+const TagKey = "_trace"
+
+// W3C trace context and baggage header names.
+const (
+	TraceparentKey = "traceparent"
+	TracestateKey  = "tracestate"
+	BaggageKey     = "baggage"
+)
+
+// Carrier holds propagated fields, keyed by lower-case header name.
+type Carrier map[string]string
+
+// Propagator injects trace context into a Carrier and extracts it again.
+type Propagator interface {
+	Inject(ctx context.Context, carrier Carrier)
+	Extract(ctx context.Context, carrier Carrier) context.Context
+}
+
+type headersKey struct{}
+
+// ContextWithHeaders returns a context carrying W3C trace headers
+// (traceparent, tracestate, baggage), e.g. copied from an incoming request.
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	fields := make(map[string]string, len(headers))
+	for k, v := range headers {
+		fields[strings.ToLower(k)] = v
+	}
+	return context.WithValue(ctx, headersKey{}, fields)
+}
+
+// HeadersFromContext returns the W3C trace headers stored by ContextWithHeaders
+// or restored by W3C.Extract, or nil.
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
+// W3C propagates traceparent, tracestate, and baggage values stored in the
+// context with ContextWithHeaders. It needs no tracing library; use an
+// OpenTelemetry adapter instead when spans live in the context.
+type W3C struct{}
+
+// Inject copies the context's trace headers into carrier.
+func (W3C) Inject(ctx context.Context, carrier Carrier) {
+	headers := HeadersFromContext(ctx)
+	for _, key := range []string{TraceparentKey, TracestateKey, BaggageKey} {
+		if v := headers[key]; v != "" {
+			carrier[key] = v
+		}
+	}
+}
+
+// Extract stores the carrier's trace headers in the returned context.
+func (W3C) Extract(ctx context.Context, carrier Carrier) context.Context {
+	if carrier[TraceparentKey] == "" && carrier[BaggageKey] == "" {
+		return ctx
+	}
+	return ContextWithHeaders(ctx, carrier)
+}
+
+// InjectTags returns a copy of tags with the context's trace fields added
+// under TagKey. tags is returned unchanged if there is nothing to propagate.
+func InjectTags(ctx context.Context, p Propagator, tags map[string]any) map[string]any {
+	carrier := Carrier{}
+	p.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return tags
+	}
+
+	fields := make(map[string]any, len(carrier))
+	for k, v := range carrier {
+		fields[k] = v
+	}
+	result := make(map[string]any, len(tags)+1)
+	for k, v := range tags {
+		result[k] = v
+	}
+	result[TagKey] = fields
+	return result
+}
+
+// ExtractTags restores trace fields stored under TagKey into ctx.
+func ExtractTags(ctx context.Context, p Propagator, tags map[string]any) context.Context {
+	fields, ok := tags[TagKey].(map[string]any)
+	if !ok || len(fields) == 0 {
+		return ctx
+	}
+
+	carrier := make(Carrier, len(fields))
+	for k, v := range fields {
+		if s, ok := v.(string); ok {
+			carrier[k] = s
+		}
+	}
+	return p.Extract(ctx, carrier)
+}
@@ -0,0 +1,116 @@
+// Package ratelimit provides spooled.DistributedLimiter implementations that
+// let many clients sharing one API key cooperatively respect the
+// organization's rate limit:
+//
+//	limiter := ratelimit.NewRedis(eval, "spooled:ratelimit:prod", 50, 100)
+//	client, err := spooled.NewClient(
+//		spooled.WithAPIKey(key),
+//		spooled.WithSharedLimiter(limiter),
+//	)
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// EvalFunc runs a Lua script on Redis, like the EVAL command, and returns its
+// reply. It keeps this package free of a Redis client dependency; with
+// go-redis:
+//
+//	eval := func(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return rdb.Eval(ctx, script, keys, args...).Result()
+//	}
+type EvalFunc func(ctx context.Context, script string, keys []string, args ...any) (any, error)
+
+// tokenBucketScript takes one token from the bucket at KEYS[1], refilled at
+// ARGV[1] tokens per second up to ARGV[2], and returns 0 or the number of
+// milliseconds to wait before a token is available. Redis's clock is used so
+// clients with skewed clocks agree.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return wait
+`
+
+// Redis is a token bucket shared through Redis.
+type Redis struct {
+	eval  EvalFunc
+	key   string
+	rate  float64
+	burst int
+
+	// FailClosed rejects requests while Redis is unreachable. By default they
+	// are sent unthrottled, so a Redis outage does not stop API traffic.
+	FailClosed bool
+}
+
+// NewRedis creates a limiter allowing rate requests per second, with bursts
+// of up to burst, across every client using key.
+func NewRedis(eval EvalFunc, key string, rate float64, burst int) *Redis {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Redis{eval: eval, key: key, rate: rate, burst: burst}
+}
+
+// Wait implements spooled.DistributedLimiter.
+func (l *Redis) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	rate := strconv.FormatFloat(l.rate, 'f', -1, 64)
+	for {
+		reply, err := l.eval(ctx, tokenBucketScript, []string{l.key}, rate, l.burst)
+		if err != nil {
+			if l.FailClosed {
+				return fmt.Errorf("rate limiter unavailable: %w", err)
+			}
+			return nil
+		}
+		wait, err := millis(reply)
+		if err != nil {
+			return err
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(time.Duration(wait) * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// millis converts an integer script reply.
+func millis(reply any) (int64, error) {
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected rate limiter reply %T", reply)
+}
@@ -0,0 +1,83 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+)
+
+// eventTarget holds the fields of an event's data that filters match on.
+type eventTarget struct {
+	QueueName string `json:"queue_name"`
+	JobID     string `json:"job_id"`
+	WorkerID  string `json:"worker_id"`
+}
+
+// validate checks that the filter's queue pattern is well formed.
+func (f SubscriptionFilter) validate() error {
+	if f.QueuePattern == "" {
+		return nil
+	}
+	if _, err := path.Match(f.QueuePattern, ""); err != nil {
+		return fmt.Errorf("invalid queue pattern %q: %w", f.QueuePattern, err)
+	}
+	return nil
+}
+
+// matches reports whether an event with the given type and data passes f.
+func (f SubscriptionFilter) matches(eventType EventType, target eventTarget) bool {
+	if f.QueueName != "" && f.QueueName != target.QueueName {
+		return false
+	}
+	if f.QueuePattern != "" {
+		if ok, _ := path.Match(f.QueuePattern, target.QueueName); !ok {
+			return false
+		}
+	}
+	if f.JobID != "" && f.JobID != target.JobID {
+		return false
+	}
+	if f.WorkerID != "" && f.WorkerID != target.WorkerID {
+		return false
+	}
+	if len(f.Events) > 0 && !slices.Contains(f.Events, string(eventType)) {
+		return false
+	}
+	return true
+}
+
+// serverFilter is the broader filter sent to servers that reject queue
+// patterns; events are then narrowed client-side.
+func (f SubscriptionFilter) serverFilter() SubscriptionFilter {
+	f.QueuePattern = ""
+	return f
+}
+
+// matchesAny reports whether an event passes any of filters. Events whose
+// data cannot be decoded are let through.
+func matchesAny(filters []SubscriptionFilter, event *Event) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	var target eventTarget
+	if err := json.Unmarshal(event.Data, &target); err != nil {
+		return true
+	}
+	for _, f := range filters {
+		if f.matches(event.Type, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPattern reports whether any filter uses a queue pattern.
+func hasPattern(filters []SubscriptionFilter) bool {
+	for _, f := range filters {
+		if f.QueuePattern != "" {
+			return true
+		}
+	}
+	return false
+}
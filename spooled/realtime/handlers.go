@@ -0,0 +1,40 @@
+package realtime
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// handlerIDs numbers handler registrations so they can be removed.
+var handlerIDs atomic.Uint64
+
+type registeredHandler[H any] struct {
+	id      uint64
+	handler H
+}
+
+// handlerList is a list of event handlers that can be removed individually.
+type handlerList[H any] []registeredHandler[H]
+
+// add returns l with handler appended and the ID that removes it.
+func (l handlerList[H]) add(handler H) (handlerList[H], uint64) {
+	id := handlerIDs.Add(1)
+	return append(l, registeredHandler[H]{id: id, handler: handler}), id
+}
+
+// remove returns l without the handler registered as id.
+func (l handlerList[H]) remove(id uint64) handlerList[H] {
+	return slices.DeleteFunc(slices.Clone(l), func(r registeredHandler[H]) bool {
+		return r.id == id
+	})
+}
+
+// handlers returns a copy of the handlers in registration order, to be
+// called after releasing the client's lock.
+func (l handlerList[H]) handlers() []H {
+	handlers := make([]H, len(l))
+	for i, r := range l {
+		handlers[i] = r.handler
+	}
+	return handlers
+}
@@ -0,0 +1,617 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PollingClient implements RealtimeClient by polling the REST API and
+// synthesizing job events from state changes. It is meant for local
+// development against mock servers without WebSocket or SSE support, so
+// code built on OnJobEvent handlers works unmodified.
+//
+// Events are derived by diffing successive job listings, so transitions that
+// happen entirely between two polls (e.g. started and completed) may be
+// reported as a single event.
+type PollingClient struct {
+	opts       ConnectionOptions
+	state      ConnectionState
+	httpClient *http.Client
+	filters    map[string]SubscriptionFilter
+	// jobs is the last listing of each polled queue ("" for all jobs)
+	jobs map[string]map[string]polledJob
+
+	// Event handlers
+	eventHandlers       map[EventType]handlerList[JobEventHandler]
+	queueEventHandlers  map[EventType]handlerList[QueueEventHandler]
+	workerEventHandlers map[EventType]handlerList[WorkerEventHandler]
+	allEventHandlers    handlerList[EventHandler]
+	stateChangeHandlers handlerList[StateChangeHandler]
+
+	mu     sync.RWMutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// polledJob is the subset of job fields needed to synthesize events.
+type polledJob struct {
+	ID               string         `json:"id"`
+	QueueName        string         `json:"queue_name"`
+	Status           string         `json:"status"`
+	Priority         int            `json:"priority"`
+	RetryCount       int            `json:"retry_count"`
+	LastError        *string        `json:"last_error,omitempty"`
+	Result           map[string]any `json:"result,omitempty"`
+	AssignedWorkerID *string        `json:"assigned_worker_id,omitempty"`
+	ScheduledAt      *time.Time     `json:"scheduled_at,omitempty"`
+	StartedAt        *time.Time     `json:"started_at,omitempty"`
+	CompletedAt      *time.Time     `json:"completed_at,omitempty"`
+}
+
+// NewPollingClient creates a realtime client that emulates events by polling.
+func NewPollingClient(opts ConnectionOptions) *PollingClient {
+	defaults := DefaultConnectionOptions()
+	if opts.BaseURL == "" {
+		opts.BaseURL = defaults.BaseURL
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+
+	return &PollingClient{
+		opts:                opts,
+		state:               StateDisconnected,
+		httpClient:          &http.Client{Timeout: 30 * time.Second},
+		filters:             make(map[string]SubscriptionFilter),
+		eventHandlers:       make(map[EventType]handlerList[JobEventHandler]),
+		queueEventHandlers:  make(map[EventType]handlerList[QueueEventHandler]),
+		workerEventHandlers: make(map[EventType]handlerList[WorkerEventHandler]),
+	}
+}
+
+// Connect performs an initial poll and starts the polling loop.
+// Jobs present at connect time do not produce events.
+func (c *PollingClient) Connect() error {
+	c.mu.Lock()
+	if c.state == StateConnected || c.state == StateConnecting {
+		c.mu.Unlock()
+		return nil
+	}
+	c.setState(StateConnecting)
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs, err := c.fetchJobs(ctx)
+	if err != nil {
+		cancel()
+		c.mu.Lock()
+		c.setState(StateDisconnected)
+		c.mu.Unlock()
+		return fmt.Errorf("polling connection failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.jobs = jobs
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.setState(StateConnected)
+	c.mu.Unlock()
+
+	go c.pollLoop(ctx)
+
+	return nil
+}
+
+// Disconnect stops polling.
+func (c *PollingClient) Disconnect() error {
+	c.mu.Lock()
+	if c.state == StateDisconnected {
+		c.mu.Unlock()
+		return nil
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+	done := c.done
+	c.setState(StateDisconnected)
+	c.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	return nil
+}
+
+// State returns the current connection state.
+func (c *PollingClient) State() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// Subscribe adds a subscription filter. Without filters all jobs are watched.
+// Filters with a QueuePattern watch all jobs and match the pattern locally.
+// Jobs already in a newly watched queue do not produce events.
+func (c *PollingClient) Subscribe(filter SubscriptionFilter) error {
+	return c.SubscribeAll(filter)
+}
+
+// SubscribeAll adds several subscription filters.
+func (c *PollingClient) SubscribeAll(filters ...SubscriptionFilter) error {
+	for _, filter := range filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
+	}
+
+	// List newly watched queues before adding their filters, so the next poll
+	// diffs against this baseline instead of reporting every existing job
+	var unlisted []string
+	c.mu.RLock()
+	if c.state == StateConnected || c.state == StateReconnecting {
+		subscribed := maps.Clone(c.filters)
+		for _, filter := range filters {
+			subscribed[subscriptionKey(filter)] = filter
+		}
+		for _, queue := range polledQueues(subscribed) {
+			if _, ok := c.jobs[queue]; !ok {
+				unlisted = append(unlisted, queue)
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	baselines := make(map[string]map[string]polledJob, len(unlisted))
+	for _, queue := range unlisted {
+		jobs, err := c.listJobs(context.Background(), queue)
+		if err != nil {
+			// The next poll takes the baseline instead
+			c.log("Listing queue %q failed: %v", queue, err)
+			continue
+		}
+		baselines[queue] = jobs
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, filter := range filters {
+		c.filters[subscriptionKey(filter)] = filter
+	}
+	for queue, jobs := range baselines {
+		if _, ok := c.jobs[queue]; !ok && c.jobs != nil {
+			c.jobs[queue] = jobs
+		}
+	}
+	return nil
+}
+
+// Unsubscribe removes a subscription filter.
+func (c *PollingClient) Unsubscribe(filter SubscriptionFilter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.filters, subscriptionKey(filter))
+	return nil
+}
+
+// OnEvent registers a handler for all events and
+// returns a func that removes it.
+func (c *PollingClient) OnEvent(handler EventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint64
+	c.allEventHandlers, id = c.allEventHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.allEventHandlers = c.allEventHandlers.remove(id)
+	}
+}
+
+// OnJobEvent registers a handler for job events and
+// returns a func that removes it.
+func (c *PollingClient) OnJobEvent(eventType EventType, handler JobEventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint64
+	c.eventHandlers[eventType], id = c.eventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.eventHandlers[eventType] = c.eventHandlers[eventType].remove(id)
+	}
+}
+
+// OnQueueEvent registers a handler for queue events and
+// returns a func that removes it.
+// Queue events are not emulated; the handler is never called.
+func (c *PollingClient) OnQueueEvent(eventType EventType, handler QueueEventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint64
+	c.queueEventHandlers[eventType], id = c.queueEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.queueEventHandlers[eventType] = c.queueEventHandlers[eventType].remove(id)
+	}
+}
+
+// OnWorkerEvent registers a handler for worker events and
+// returns a func that removes it.
+// Worker events are not emulated; the handler is never called.
+func (c *PollingClient) OnWorkerEvent(eventType EventType, handler WorkerEventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint64
+	c.workerEventHandlers[eventType], id = c.workerEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.workerEventHandlers[eventType] = c.workerEventHandlers[eventType].remove(id)
+	}
+}
+
+// OnStateChange registers a handler for state changes and
+// returns a func that removes it.
+func (c *PollingClient) OnStateChange(handler StateChangeHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint64
+	c.stateChangeHandlers, id = c.stateChangeHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.stateChangeHandlers = c.stateChangeHandlers.remove(id)
+	}
+}
+
+func (c *PollingClient) pollLoop(ctx context.Context) {
+	c.mu.RLock()
+	done := c.done
+	c.mu.RUnlock()
+	defer close(done)
+
+	ticker := time.NewTicker(c.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		listing, err := c.fetchJobs(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.log("Poll failed: %v", err)
+			c.mu.Lock()
+			c.setState(StateReconnecting)
+			c.mu.Unlock()
+			continue
+		}
+
+		var events []*Event
+		c.mu.Lock()
+		next := make(map[string]map[string]polledJob)
+		for _, queue := range polledQueues(c.filters) {
+			jobs, polled := listing[queue]
+			previous, known := c.jobs[queue]
+			switch {
+			case !polled:
+				// Subscribed while this poll was in flight
+				if known {
+					next[queue] = previous
+				}
+			case !known:
+				// Newly watched: this listing is the baseline
+				next[queue] = jobs
+			default:
+				next[queue] = jobs
+				events = append(events, diffJobs(previous, jobs)...)
+			}
+		}
+		c.jobs = next
+		c.setState(StateConnected)
+		c.mu.Unlock()
+
+		for _, event := range events {
+			c.dispatchEvent(event)
+		}
+	}
+}
+
+// polledQueues returns the queues to list for filters: each subscribed queue,
+// or "" for all jobs when there are no filters or a filter spans queues.
+func polledQueues(filters map[string]SubscriptionFilter) []string {
+	queues := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if f.QueueName == "" {
+			return []string{""}
+		}
+		if !slices.Contains(queues, f.QueueName) {
+			queues = append(queues, f.QueueName)
+		}
+	}
+	if len(queues) == 0 {
+		return []string{""}
+	}
+	return queues
+}
+
+// fetchJobs lists jobs for every polled queue.
+func (c *PollingClient) fetchJobs(ctx context.Context) (map[string]map[string]polledJob, error) {
+	c.mu.RLock()
+	queues := polledQueues(c.filters)
+	c.mu.RUnlock()
+
+	listing := make(map[string]map[string]polledJob, len(queues))
+	for _, queue := range queues {
+		jobs, err := c.listJobs(ctx, queue)
+		if err != nil {
+			return nil, err
+		}
+		listing[queue] = jobs
+	}
+	return listing, nil
+}
+
+// pollPageSize is the number of jobs requested per page when listing a queue.
+const pollPageSize = 100
+
+// listJobs lists every job in queue, following cursors when the server
+// returns them and offsets otherwise.
+func (c *PollingClient) listJobs(ctx context.Context, queue string) (map[string]polledJob, error) {
+	jobs := make(map[string]polledJob)
+	offset, cursor := 0, ""
+	for {
+		page, next, err := c.listPage(ctx, queue, offset, cursor)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, job := range page {
+			if _, seen := jobs[job.ID]; !seen {
+				added++
+			}
+			jobs[job.ID] = job
+		}
+		// A short page ends the listing; so does a repeated one, from servers
+		// that ignore offsets
+		if len(page) < pollPageSize || added == 0 {
+			return jobs, nil
+		}
+		if next != "" {
+			cursor = next
+		} else {
+			offset += len(page)
+		}
+	}
+}
+
+// listPage fetches one page of jobs and the cursor for the next page, if any.
+func (c *PollingClient) listPage(ctx context.Context, queue string, offset int, cursor string) ([]polledJob, string, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(pollPageSize))
+	if queue != "" {
+		params.Set("queue_name", queue)
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	} else if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+	listURL := strings.TrimSuffix(c.opts.BaseURL, "/") + "/api/v1/jobs?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	} else if c.opts.APIKey != "" {
+		req.Header.Set("X-API-Key", c.opts.APIKey)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("list jobs failed with status: %d", resp.StatusCode)
+	}
+
+	// Servers with cursor support wrap the jobs in a page object
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, "", fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	var page struct {
+		Jobs       []polledJob `json:"jobs"`
+		NextCursor string      `json:"next_cursor"`
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(raw, &page.Jobs)
+	} else {
+		err = json.Unmarshal(raw, &page)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	if page.NextCursor == "" {
+		page.NextCursor = resp.Header.Get("X-Next-Cursor")
+	}
+	return page.Jobs, page.NextCursor, nil
+}
+
+// diffJobs synthesizes events from two successive job snapshots.
+func diffJobs(previous, current map[string]polledJob) []*Event {
+	var events []*Event
+	for id, job := range current {
+		old, known := previous[id]
+		if !known {
+			events = append(events, job.event(EventJobCreated))
+			if t, ok := statusEvent(job.Status); ok && t != EventJobCreated {
+				events = append(events, job.event(t))
+			}
+			continue
+		}
+		if job.Status == old.Status && job.RetryCount == old.RetryCount {
+			continue
+		}
+		if job.RetryCount > old.RetryCount && (job.Status == "pending" || job.Status == "scheduled") {
+			events = append(events, job.event(EventJobRetrying))
+			continue
+		}
+		if job.Status != old.Status {
+			if t, ok := statusEvent(job.Status); ok {
+				events = append(events, job.event(t))
+			}
+		}
+	}
+	return events
+}
+
+// statusEvent maps a job status to the event announcing it.
+func statusEvent(status string) (EventType, bool) {
+	switch status {
+	case "pending", "scheduled":
+		return EventJobCreated, true
+	case "processing":
+		return EventJobStarted, true
+	case "completed":
+		return EventJobCompleted, true
+	case "failed":
+		return EventJobFailed, true
+	case "deadletter":
+		return EventJobDeadletter, true
+	}
+	return "", false
+}
+
+func (j polledJob) event(eventType EventType) *Event {
+	data := JobEvent{
+		JobID:       j.ID,
+		QueueName:   j.QueueName,
+		Status:      j.Status,
+		Priority:    j.Priority,
+		RetryCount:  j.RetryCount,
+		Result:      j.Result,
+		ScheduledAt: j.ScheduledAt,
+		StartedAt:   j.StartedAt,
+		CompletedAt: j.CompletedAt,
+	}
+	if j.LastError != nil {
+		data.Error = *j.LastError
+	}
+	if j.AssignedWorkerID != nil {
+		data.WorkerID = *j.AssignedWorkerID
+	}
+	if eventType == EventJobFailed || eventType == EventJobDeadletter {
+		data.FailedAt = j.CompletedAt
+	}
+
+	raw, _ := json.Marshal(data)
+	return &Event{Type: eventType, Timestamp: time.Now(), Data: raw}
+}
+
+func (c *PollingClient) dispatchEvent(event *Event) {
+	c.mu.RLock()
+	allHandlers := c.allEventHandlers.handlers()
+	jobHandlers := c.eventHandlers[event.Type].handlers()
+	filters := make([]SubscriptionFilter, 0, len(c.filters))
+	for _, f := range c.filters {
+		filters = append(filters, f)
+	}
+	c.mu.RUnlock()
+
+	var jobEvent JobEvent
+	if err := json.Unmarshal(event.Data, &jobEvent); err != nil {
+		return
+	}
+	if !matchesFilters(filters, event.Type, &jobEvent) {
+		return
+	}
+
+	for _, handler := range allHandlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.log("Event handler panic: %v", r)
+				}
+			}()
+			handler(event)
+		}()
+	}
+
+	for _, handler := range jobHandlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.log("Job event handler panic: %v", r)
+				}
+			}()
+			handler(&jobEvent)
+		}()
+	}
+}
+
+// matchesFilters reports whether a job event passes any subscription filter.
+func matchesFilters(filters []SubscriptionFilter, eventType EventType, event *JobEvent) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	target := eventTarget{QueueName: event.QueueName, JobID: event.JobID, WorkerID: event.WorkerID}
+	for _, f := range filters {
+		if f.matches(eventType, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *PollingClient) setState(state ConnectionState) {
+	if c.state == state {
+		return
+	}
+	c.state = state
+
+	// Make a copy of handlers to call outside the lock
+	handlers := c.stateChangeHandlers.handlers()
+
+	go func() {
+		for _, handler := range handlers {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						c.log("State change handler panic: %v", r)
+					}
+				}()
+				handler(state)
+			}()
+		}
+	}()
+}
+
+func (c *PollingClient) log(format string, args ...any) {
+	if c.opts.Logger != nil {
+		c.opts.Logger(format, args...)
+	} else if c.opts.Debug {
+		fmt.Printf("[spooled-poll] "+format+"\n", args...)
+	}
+}
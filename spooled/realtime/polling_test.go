@@ -0,0 +1,183 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobList is a REST job listing that pages with offsets, or with cursors
+// when cursors is set.
+type jobList struct {
+	mu      sync.Mutex
+	jobs    []polledJob
+	cursors bool
+}
+
+func (l *jobList) add(id, queue, status string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.jobs = append(l.jobs, polledJob{ID: id, QueueName: queue, Status: status})
+}
+
+func (l *jobList) setStatus(id, status string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.jobs {
+		if l.jobs[i].ID == id {
+			l.jobs[i].Status = status
+		}
+	}
+}
+
+func (l *jobList) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	query := r.URL.Query()
+	var matched []polledJob
+	for _, job := range l.jobs {
+		if q := query.Get("queue_name"); q == "" || q == job.QueueName {
+			matched = append(matched, job)
+		}
+	}
+	start, _ := strconv.Atoi(query.Get("offset"))
+	if l.cursors {
+		start, _ = strconv.Atoi(query.Get("cursor"))
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	start = min(start, len(matched))
+	end := min(start+limit, len(matched))
+
+	w.Header().Set("Content-Type", "application/json")
+	if !l.cursors {
+		_ = json.NewEncoder(w).Encode(matched[start:end])
+		return
+	}
+	page := map[string]any{"jobs": matched[start:end]}
+	if end < len(matched) {
+		page["next_cursor"] = strconv.Itoa(end)
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// connect starts a PollingClient against list that records job events,
+// subscribed to filters before connecting.
+func connect(t *testing.T, list *jobList, filters ...SubscriptionFilter) (*PollingClient, chan string) {
+	t.Helper()
+	srv := httptest.NewServer(list)
+	t.Cleanup(srv.Close)
+
+	client := NewPollingClient(ConnectionOptions{BaseURL: srv.URL, APIKey: "sp_test_key", PollInterval: 10 * time.Millisecond})
+	events := make(chan string, 100)
+	for _, eventType := range []EventType{EventJobCreated, EventJobStarted, EventJobCompleted} {
+		client.OnJobEvent(eventType, func(event *JobEvent) {
+			events <- fmt.Sprintf("%s %s", eventType, event.JobID)
+		})
+	}
+	if err := client.SubscribeAll(filters...); err != nil {
+		t.Fatalf("SubscribeAll: %v", err)
+	}
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect() })
+	return client, events
+}
+
+// expectEvents waits for want, in any order, and fails on any other event
+// until a few more polls have passed.
+func expectEvents(t *testing.T, events chan string, want ...string) {
+	t.Helper()
+	pending := make(map[string]bool, len(want))
+	for _, w := range want {
+		pending[w] = true
+	}
+	deadline := time.After(5 * time.Second)
+	quiet := time.After(time.Hour)
+	for {
+		select {
+		case event := <-events:
+			if !pending[event] {
+				t.Errorf("unexpected event %q", event)
+				continue
+			}
+			delete(pending, event)
+			if len(pending) == 0 {
+				quiet = time.After(100 * time.Millisecond)
+			}
+		case <-quiet:
+			return
+		case <-deadline:
+			t.Fatalf("missing events %v", pending)
+		}
+	}
+}
+
+func TestPollingClient_SubscribeAfterConnect(t *testing.T) {
+	list := &jobList{}
+	list.add("existing", "emails", "pending")
+	list.add("other", "reports", "pending")
+	client, events := connect(t, list, SubscriptionFilter{QueueName: "reports"})
+
+	// Jobs already in a queue subscribed after connecting are not reported
+	if err := client.Subscribe(SubscriptionFilter{QueueName: "emails"}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	list.add("new", "emails", "pending")
+	list.add("elsewhere", "reports", "pending")
+	list.setStatus("existing", "processing")
+	expectEvents(t, events, "job.created new", "job.created elsewhere", "job.started existing")
+
+	list.setStatus("new", "completed")
+	expectEvents(t, events, "job.completed new")
+}
+
+func TestPollingClient_Pagination(t *testing.T) {
+	for _, cursors := range []bool{false, true} {
+		t.Run(fmt.Sprintf("cursors=%v", cursors), func(t *testing.T) {
+			list := &jobList{cursors: cursors}
+			for i := range 2*pollPageSize + 10 {
+				list.add(fmt.Sprintf("job-%d", i), "emails", "pending")
+			}
+			_, events := connect(t, list)
+
+			// Jobs beyond the first page are listed too, so only real changes are reported
+			list.add("latest", "emails", "pending")
+			list.setStatus("job-205", "processing")
+			expectEvents(t, events, "job.created latest", "job.started job-205")
+		})
+	}
+}
+
+func TestPollingClient_NoFilters(t *testing.T) {
+	list := &jobList{}
+	list.add("existing", "emails", "pending")
+	_, events := connect(t, list)
+
+	list.add("a", "emails", "pending")
+	list.add("b", "reports", "processing")
+	expectEvents(t, events, "job.created a", "job.created b", "job.started b")
+}
+
+func TestPollingClient_RemoveHandler(t *testing.T) {
+	list := &jobList{}
+	client, events := connect(t, list)
+	removed := make(chan string, 10)
+	remove := client.OnJobEvent(EventJobCreated, func(event *JobEvent) {
+		removed <- event.JobID
+	})
+	remove()
+	remove()
+
+	// Other handlers are still called
+	list.add("a", "emails", "pending")
+	expectEvents(t, events, "job.created a")
+	if len(removed) != 0 {
+		t.Errorf("removed handler called for %s", <-removed)
+	}
+}
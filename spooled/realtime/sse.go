@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/resume"
 )
 
 // SSEClient implements RealtimeClient using Server-Sent Events.
@@ -19,15 +21,16 @@ type SSEClient struct {
 	resp              *http.Response
 	state             ConnectionState
 	reconnectAttempts int
-	filter            *SubscriptionFilter
+	filters           []SubscriptionFilter
 	httpClient        *http.Client
+	cursor            *resume.Cursor
 
 	// Event handlers
-	eventHandlers       map[EventType][]JobEventHandler
-	queueEventHandlers  map[EventType][]QueueEventHandler
-	workerEventHandlers map[EventType][]WorkerEventHandler
-	allEventHandlers    []EventHandler
-	stateChangeHandlers []StateChangeHandler
+	eventHandlers       map[EventType]handlerList[JobEventHandler]
+	queueEventHandlers  map[EventType]handlerList[QueueEventHandler]
+	workerEventHandlers map[EventType]handlerList[WorkerEventHandler]
+	allEventHandlers    handlerList[EventHandler]
+	stateChangeHandlers handlerList[StateChangeHandler]
 
 	mu     sync.RWMutex
 	ctx    context.Context
@@ -55,9 +58,10 @@ func NewSSEClient(opts ConnectionOptions) *SSEClient {
 		opts:                opts,
 		state:               StateDisconnected,
 		httpClient:          &http.Client{Timeout: 0}, // No timeout for SSE
-		eventHandlers:       make(map[EventType][]JobEventHandler),
-		queueEventHandlers:  make(map[EventType][]QueueEventHandler),
-		workerEventHandlers: make(map[EventType][]WorkerEventHandler),
+		cursor:              resume.NewCursor(opts.ResumeToken, 0),
+		eventHandlers:       make(map[EventType]handlerList[JobEventHandler]),
+		queueEventHandlers:  make(map[EventType]handlerList[QueueEventHandler]),
+		workerEventHandlers: make(map[EventType]handlerList[WorkerEventHandler]),
 	}
 }
 
@@ -69,12 +73,28 @@ func (c *SSEClient) Connect() error {
 
 // ConnectWithFilter establishes the SSE connection with a subscription filter.
 func (c *SSEClient) ConnectWithFilter(filter *SubscriptionFilter) error {
+	if filter == nil {
+		return c.ConnectWithFilters()
+	}
+	return c.ConnectWithFilters(*filter)
+}
+
+// ConnectWithFilters establishes the SSE connection receiving events that
+// match any of filters. A single filter is applied by the server; with
+// several, or with a QueuePattern the server may not support, the stream is
+// narrowed to what the server can filter and events are matched client-side.
+func (c *SSEClient) ConnectWithFilters(filters ...SubscriptionFilter) error {
+	for _, filter := range filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
+	}
 	c.mu.Lock()
 	if c.state == StateConnected || c.state == StateConnecting {
 		c.mu.Unlock()
 		return nil
 	}
-	c.filter = filter
+	c.filters = filters
 	c.setState(StateConnecting)
 	c.mu.Unlock()
 
@@ -104,6 +124,11 @@ func (c *SSEClient) doConnect() error {
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
+	// Resume after the last event delivered, so a reconnect neither misses
+	// nor repeats events
+	if token := c.cursor.Token(); token != "" {
+		req.Header.Set("Last-Event-ID", token)
+	}
 
 	c.mu.Lock()
 	c.ctx, c.cancel = context.WithCancel(context.Background())
@@ -169,6 +194,13 @@ func (c *SSEClient) State() ConnectionState {
 	return c.state
 }
 
+// ResumeToken returns the token of the last event delivered, which the
+// client resumes from after reconnecting. Save it and pass it as
+// ConnectionOptions.ResumeToken to resume in a new client.
+func (c *SSEClient) ResumeToken() string {
+	return c.cursor.Token()
+}
+
 // Subscribe is not supported for SSE - use ConnectWithFilter instead.
 func (c *SSEClient) Subscribe(filter SubscriptionFilter) error {
 	return fmt.Errorf("SSE does not support runtime subscriptions; use ConnectWithFilter instead")
@@ -179,39 +211,74 @@ func (c *SSEClient) Unsubscribe(filter SubscriptionFilter) error {
 	return fmt.Errorf("SSE does not support runtime unsubscriptions; reconnect with a new filter")
 }
 
-// OnEvent registers a handler for all events.
-func (c *SSEClient) OnEvent(handler EventHandler) {
+// OnEvent registers a handler for all events and
+// returns a func that removes it.
+func (c *SSEClient) OnEvent(handler EventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.allEventHandlers = append(c.allEventHandlers, handler)
+	var id uint64
+	c.allEventHandlers, id = c.allEventHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.allEventHandlers = c.allEventHandlers.remove(id)
+	}
 }
 
-// OnJobEvent registers a handler for job events.
-func (c *SSEClient) OnJobEvent(eventType EventType, handler JobEventHandler) {
+// OnJobEvent registers a handler for job events and
+// returns a func that removes it.
+func (c *SSEClient) OnJobEvent(eventType EventType, handler JobEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.eventHandlers[eventType] = append(c.eventHandlers[eventType], handler)
+	var id uint64
+	c.eventHandlers[eventType], id = c.eventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.eventHandlers[eventType] = c.eventHandlers[eventType].remove(id)
+	}
 }
 
-// OnQueueEvent registers a handler for queue events.
-func (c *SSEClient) OnQueueEvent(eventType EventType, handler QueueEventHandler) {
+// OnQueueEvent registers a handler for queue events and
+// returns a func that removes it.
+func (c *SSEClient) OnQueueEvent(eventType EventType, handler QueueEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.queueEventHandlers[eventType] = append(c.queueEventHandlers[eventType], handler)
+	var id uint64
+	c.queueEventHandlers[eventType], id = c.queueEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.queueEventHandlers[eventType] = c.queueEventHandlers[eventType].remove(id)
+	}
 }
 
-// OnWorkerEvent registers a handler for worker events.
-func (c *SSEClient) OnWorkerEvent(eventType EventType, handler WorkerEventHandler) {
+// OnWorkerEvent registers a handler for worker events and
+// returns a func that removes it.
+func (c *SSEClient) OnWorkerEvent(eventType EventType, handler WorkerEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.workerEventHandlers[eventType] = append(c.workerEventHandlers[eventType], handler)
+	var id uint64
+	c.workerEventHandlers[eventType], id = c.workerEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.workerEventHandlers[eventType] = c.workerEventHandlers[eventType].remove(id)
+	}
 }
 
-// OnStateChange registers a handler for state changes.
-func (c *SSEClient) OnStateChange(handler StateChangeHandler) {
+// OnStateChange registers a handler for state changes and
+// returns a func that removes it.
+func (c *SSEClient) OnStateChange(handler StateChangeHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.stateChangeHandlers = append(c.stateChangeHandlers, handler)
+	var id uint64
+	c.stateChangeHandlers, id = c.stateChangeHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.stateChangeHandlers = c.stateChangeHandlers.remove(id)
+	}
 }
 
 func (c *SSEClient) readLoop() {
@@ -232,7 +299,7 @@ func (c *SSEClient) readLoop() {
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
-	var eventType string
+	var eventType, eventID string
 	var data strings.Builder
 
 	for scanner.Scan() {
@@ -241,8 +308,8 @@ func (c *SSEClient) readLoop() {
 		// Empty line signals end of event
 		if line == "" {
 			if data.Len() > 0 {
-				c.handleSSEEvent(eventType, data.String())
-				eventType = ""
+				c.handleSSEEvent(eventType, eventID, data.String())
+				eventType, eventID = "", ""
 				data.Reset()
 			}
 			continue
@@ -251,6 +318,8 @@ func (c *SSEClient) readLoop() {
 		// Parse SSE fields
 		if strings.HasPrefix(line, "event:") {
 			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		} else if strings.HasPrefix(line, "id:") {
+			eventID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
 		} else if strings.HasPrefix(line, "data:") {
 			if data.Len() > 0 {
 				data.WriteString("\n")
@@ -269,7 +338,7 @@ func (c *SSEClient) readLoop() {
 	c.handleDisconnect()
 }
 
-func (c *SSEClient) handleSSEEvent(eventType string, data string) {
+func (c *SSEClient) handleSSEEvent(eventType, eventID, data string) {
 	c.log("Received SSE event: type=%s data=%s", eventType, data)
 
 	// Parse the event data as JSON
@@ -287,18 +356,31 @@ func (c *SSEClient) handleSSEEvent(eventType string, data string) {
 	if eventType != "" && event.Type == "" {
 		event.Type = EventType(eventType)
 	}
+	if event.ResumeToken == "" {
+		event.ResumeToken = eventID
+	}
 
 	c.dispatchEvent(&event)
 }
 
 func (c *SSEClient) dispatchEvent(event *Event) {
+	// Drop events replayed by the server after resuming
+	if !c.cursor.Advance(event.ResumeToken, event.ResumeToken) {
+		return
+	}
+
 	c.mu.RLock()
-	allHandlers := c.allEventHandlers
-	jobHandlers := c.eventHandlers[event.Type]
-	queueHandlers := c.queueEventHandlers[event.Type]
-	workerHandlers := c.workerEventHandlers[event.Type]
+	allHandlers := c.allEventHandlers.handlers()
+	jobHandlers := c.eventHandlers[event.Type].handlers()
+	queueHandlers := c.queueEventHandlers[event.Type].handlers()
+	workerHandlers := c.workerEventHandlers[event.Type].handlers()
+	filters := c.filters
 	c.mu.RUnlock()
 
+	if (len(filters) > 1 || hasPattern(filters)) && !matchesAny(filters, event) {
+		return
+	}
+
 	// Call all-event handlers
 	for _, handler := range allHandlers {
 		func() {
@@ -408,18 +490,23 @@ func (c *SSEClient) buildSSEURL() string {
 	sseURL := baseURL + "/api/v1/events"
 
 	c.mu.RLock()
-	filter := c.filter
+	filters := c.filters
 	c.mu.RUnlock()
 
-	if filter == nil {
+	// The query can only express one filter; several are matched client-side
+	if len(filters) != 1 {
 		return sseURL
 	}
+	filter := filters[0]
 
 	// Build query parameters from filter
 	params := url.Values{}
 	if filter.QueueName != "" {
 		params.Set("queue", filter.QueueName)
 	}
+	if filter.QueuePattern != "" {
+		params.Set("queue_pattern", filter.QueuePattern)
+	}
 	if filter.JobID != "" {
 		params.Set("job_id", filter.JobID)
 	}
@@ -444,8 +531,7 @@ func (c *SSEClient) setState(state ConnectionState) {
 	c.state = state
 
 	// Make a copy of handlers to call outside the lock
-	handlers := make([]StateChangeHandler, len(c.stateChangeHandlers))
-	copy(handlers, c.stateChangeHandlers)
+	handlers := c.stateChangeHandlers.handlers()
 
 	// Call handlers outside the lock (we're already holding it)
 	go func() {
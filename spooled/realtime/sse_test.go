@@ -0,0 +1,128 @@
+package realtime
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSSEClient_QueuePatternFilters(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Ignore the filters, like servers without pattern support
+		for _, queue := range []string{"emails-acme", "billing", "emails-globex", "reports"} {
+			_, _ = fmt.Fprintf(w, "data: {\"type\":\"job.created\",\"data\":{\"job_id\":\"job-%s\",\"queue_name\":%q}}\n\n", queue, queue)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	rt := NewSSEClient(ConnectionOptions{BaseURL: server.URL})
+	var mu sync.Mutex
+	var got []string
+	done := make(chan struct{})
+	rt.OnJobEvent(EventJobCreated, func(e *JobEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.QueueName)
+		if len(got) == 3 {
+			close(done)
+		}
+	})
+
+	if err := rt.ConnectWithFilters(SubscriptionFilter{QueuePattern: "emails-[*"}); err == nil {
+		t.Fatal("ConnectWithFilters with a malformed pattern should fail")
+	}
+	if err := rt.ConnectWithFilters(
+		SubscriptionFilter{QueuePattern: "emails-*"},
+		SubscriptionFilter{QueueName: "reports"},
+	); err != nil {
+		t.Fatalf("ConnectWithFilters: %v", err)
+	}
+	defer rt.Disconnect()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for events")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"emails-acme", "emails-globex", "reports"}; !slices.Equal(got, want) {
+		t.Errorf("events for %v, want %v", got, want)
+	}
+	if len(query) != 0 {
+		t.Errorf("several filters sent query %v, want none", query)
+	}
+}
+
+func TestSSEClient_ResumesAfterReconnect(t *testing.T) {
+	var connections atomic.Int32
+	var resumedFrom atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		send := func(id int) {
+			_, _ = fmt.Fprintf(w, "id: %d\ndata: {\"type\":\"job.created\",\"data\":{\"job_id\":\"job-%d\"}}\n\n", id, id)
+		}
+		if connections.Add(1) == 1 {
+			// Drop the connection after two events
+			send(1)
+			send(2)
+			return
+		}
+		resumedFrom.Store(r.Header.Get("Last-Event-ID"))
+		// Replay the last event, as servers may when resuming
+		send(2)
+		send(3)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	rt := NewSSEClient(ConnectionOptions{
+		BaseURL:        server.URL,
+		AutoReconnect:  true,
+		ReconnectDelay: time.Millisecond,
+	})
+	var mu sync.Mutex
+	var got []string
+	done := make(chan struct{})
+	rt.OnJobEvent(EventJobCreated, func(e *JobEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.JobID)
+		if e.JobID == "job-3" {
+			close(done)
+		}
+	})
+	if err := rt.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer rt.Disconnect()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for events after reconnecting")
+	}
+	if from, _ := resumedFrom.Load().(string); from != "2" {
+		t.Errorf("reconnected with Last-Event-ID %q, want 2", from)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"job-1", "job-2", "job-3"}; !slices.Equal(got, want) {
+		t.Errorf("events for %v, want %v", got, want)
+	}
+	if token := rt.ResumeToken(); token != "3" {
+		t.Errorf("ResumeToken() = %q, want 3", token)
+	}
+}
@@ -26,6 +26,7 @@ const (
 	EventJobFailed      EventType = "job.failed"
 	EventJobRetrying    EventType = "job.retrying"
 	EventJobProgress    EventType = "job.progress"
+	EventJobDeadletter  EventType = "job.deadletter"
 	EventQueuePaused    EventType = "queue.paused"
 	EventQueueResumed   EventType = "queue.resumed"
 	EventWorkerJoined   EventType = "worker.joined"
@@ -34,11 +35,21 @@ const (
 	EventWorkerInactive EventType = "worker.inactive"
 )
 
+// Workflow SLA events are delivered to OnEvent handlers; decode Data as a
+// WorkflowEvent.
+const (
+	EventWorkflowSLAAtRisk   EventType = "workflow.sla_at_risk"
+	EventWorkflowSLABreached EventType = "workflow.sla_breached"
+)
+
 // Event represents a realtime event from the Spooled API.
 type Event struct {
 	Type      EventType       `json:"type"`
 	Timestamp time.Time       `json:"timestamp"`
 	Data      json.RawMessage `json:"data"`
+	// ResumeToken is the server's cursor for the event (the SSE event ID).
+	// After a reconnect, the client resumes from the last one delivered.
+	ResumeToken string `json:"resume_token,omitempty"`
 }
 
 // JobEvent contains data for job-related events.
@@ -57,6 +68,10 @@ type JobEvent struct {
 	CompletedAt *time.Time        `json:"completed_at,omitempty"`
 	FailedAt    *time.Time        `json:"failed_at,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	// CancellationReason and CancelledBy are set on job.cancelled events
+	// for jobs cancelled with Jobs().CancelWithReason.
+	CancellationReason string `json:"cancellation_reason,omitempty"`
+	CancelledBy        string `json:"cancelled_by,omitempty"`
 }
 
 // QueueEvent contains data for queue-related events.
@@ -74,12 +89,26 @@ type WorkerEvent struct {
 	LastSeenAt time.Time `json:"last_seen_at,omitempty"`
 }
 
-// SubscriptionFilter specifies which events to receive.
+// WorkflowEvent contains data for workflow SLA events. JobKey is empty when
+// the workflow timeout itself is at risk.
+type WorkflowEvent struct {
+	WorkflowID string    `json:"workflow_id"`
+	JobKey     string    `json:"job_key,omitempty"`
+	JobID      string    `json:"job_id,omitempty"`
+	Deadline   time.Time `json:"deadline"`
+}
+
+// SubscriptionFilter specifies which events to receive. Set fields must all
+// match; empty fields match anything.
 type SubscriptionFilter struct {
-	QueueName string   `json:"queue_name,omitempty"`
-	JobID     string   `json:"job_id,omitempty"`
-	WorkerID  string   `json:"worker_id,omitempty"`
-	Events    []string `json:"events,omitempty"`
+	QueueName string `json:"queue_name,omitempty"`
+	// QueuePattern matches queue names with path.Match syntax, e.g.
+	// "emails-*" or "tenant-?-jobs". Servers without pattern support are
+	// subscribed to all queues and events are matched client-side.
+	QueuePattern string   `json:"queue_pattern,omitempty"`
+	JobID        string   `json:"job_id,omitempty"`
+	WorkerID     string   `json:"worker_id,omitempty"`
+	Events       []string `json:"events,omitempty"`
 }
 
 // ConnectionOptions configures a realtime connection.
@@ -100,6 +129,11 @@ type ConnectionOptions struct {
 	ReconnectDelay time.Duration
 	// MaxReconnectDelay is the maximum delay between reconnect attempts
 	MaxReconnectDelay time.Duration
+	// ResumeToken starts the stream after the event with this token, e.g. one
+	// saved from ResumeToken before a restart (WebSocket and SSE only)
+	ResumeToken string
+	// PollInterval is the REST polling interval used by PollingClient (default: 1s)
+	PollInterval time.Duration
 	// Debug enables debug logging
 	Debug bool
 	// Logger is a custom logger function
@@ -115,6 +149,7 @@ func DefaultConnectionOptions() ConnectionOptions {
 		MaxReconnectAttempts: 10,
 		ReconnectDelay:       1 * time.Second,
 		MaxReconnectDelay:    30 * time.Second,
+		PollInterval:         1 * time.Second,
 	}
 }
 
@@ -145,23 +180,24 @@ type RealtimeClient interface {
 	Subscribe(filter SubscriptionFilter) error
 	// Unsubscribe removes a subscription (WebSocket only)
 	Unsubscribe(filter SubscriptionFilter) error
-	// OnEvent registers a handler for all events
-	OnEvent(handler EventHandler)
-	// OnJobEvent registers a handler for job events
-	OnJobEvent(eventType EventType, handler JobEventHandler)
-	// OnQueueEvent registers a handler for queue events
-	OnQueueEvent(eventType EventType, handler QueueEventHandler)
-	// OnWorkerEvent registers a handler for worker events
-	OnWorkerEvent(eventType EventType, handler WorkerEventHandler)
-	// OnStateChange registers a handler for state changes
-	OnStateChange(handler StateChangeHandler)
+	// OnEvent registers a handler for all events and returns a func that removes it
+	OnEvent(handler EventHandler) func()
+	// OnJobEvent registers a handler for job events and returns a func that removes it
+	OnJobEvent(eventType EventType, handler JobEventHandler) func()
+	// OnQueueEvent registers a handler for queue events and returns a func that removes it
+	OnQueueEvent(eventType EventType, handler QueueEventHandler) func()
+	// OnWorkerEvent registers a handler for worker events and returns a func that removes it
+	OnWorkerEvent(eventType EventType, handler WorkerEventHandler) func()
+	// OnStateChange registers a handler for state changes and returns a func that removes it
+	OnStateChange(handler StateChangeHandler) func()
 }
 
 // WebSocket command types
 type wsCommand struct {
-	Type      string              `json:"type"`
-	RequestID string              `json:"request_id,omitempty"`
-	Filter    *SubscriptionFilter `json:"filter,omitempty"`
+	Type        string              `json:"type"`
+	RequestID   string              `json:"request_id,omitempty"`
+	Filter      *SubscriptionFilter `json:"filter,omitempty"`
+	ResumeToken string              `json:"resume_token,omitempty"`
 }
 
 type wsResponse struct {
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"nhooyr.io/websocket"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/resume"
 )
 
 // WebSocketClient implements RealtimeClient using WebSocket.
@@ -18,14 +20,18 @@ type WebSocketClient struct {
 	state             ConnectionState
 	reconnectAttempts int
 	subscriptions     map[string]SubscriptionFilter
-	pendingCommands   map[string]chan error
+	// fallbacks holds the filters sent for subscriptions whose queue pattern
+	// the server rejected, keyed like subscriptions.
+	fallbacks       map[string]SubscriptionFilter
+	pendingCommands map[string]chan error
+	cursor          *resume.Cursor
 
 	// Event handlers
-	eventHandlers       map[EventType][]JobEventHandler
-	queueEventHandlers  map[EventType][]QueueEventHandler
-	workerEventHandlers map[EventType][]WorkerEventHandler
-	allEventHandlers    []EventHandler
-	stateChangeHandlers []StateChangeHandler
+	eventHandlers       map[EventType]handlerList[JobEventHandler]
+	queueEventHandlers  map[EventType]handlerList[QueueEventHandler]
+	workerEventHandlers map[EventType]handlerList[WorkerEventHandler]
+	allEventHandlers    handlerList[EventHandler]
+	stateChangeHandlers handlerList[StateChangeHandler]
 
 	mu     sync.RWMutex
 	cmdMu  sync.Mutex
@@ -55,10 +61,12 @@ func NewWebSocketClient(opts ConnectionOptions) *WebSocketClient {
 		opts:                opts,
 		state:               StateDisconnected,
 		subscriptions:       make(map[string]SubscriptionFilter),
+		fallbacks:           make(map[string]SubscriptionFilter),
 		pendingCommands:     make(map[string]chan error),
-		eventHandlers:       make(map[EventType][]JobEventHandler),
-		queueEventHandlers:  make(map[EventType][]QueueEventHandler),
-		workerEventHandlers: make(map[EventType][]WorkerEventHandler),
+		cursor:              resume.NewCursor(opts.ResumeToken, 0),
+		eventHandlers:       make(map[EventType]handlerList[JobEventHandler]),
+		queueEventHandlers:  make(map[EventType]handlerList[QueueEventHandler]),
+		workerEventHandlers: make(map[EventType]handlerList[WorkerEventHandler]),
 	}
 }
 
@@ -80,18 +88,18 @@ func (c *WebSocketClient) doConnect() error {
 
 	// Build headers for authentication
 	headers := http.Header{}
+	c.mu.RLock()
 	if c.opts.Token != "" {
 		headers.Set("Authorization", "Bearer "+c.opts.Token)
 	} else if c.opts.APIKey != "" {
 		headers.Set("X-API-Key", c.opts.APIKey)
 	}
+	c.mu.RUnlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	conn, _, err := websocket.Dial(ctx, c.opts.WSURL, &websocket.DialOptions{
-		HTTPHeader: headers,
-	})
+	conn, err := dialWebSocket(ctx, c.opts.WSURL, headers)
 	if err != nil {
 		c.mu.Lock()
 		c.setState(StateDisconnected)
@@ -110,7 +118,8 @@ func (c *WebSocketClient) doConnect() error {
 	// Start message reader
 	go c.readLoop()
 
-	// Resubscribe to previous subscriptions
+	// Resubscribe to previous subscriptions; they resume after the last
+	// event delivered
 	c.mu.RLock()
 	subs := make([]SubscriptionFilter, 0, len(c.subscriptions))
 	for _, sub := range c.subscriptions {
@@ -151,6 +160,20 @@ func (c *WebSocketClient) Disconnect() error {
 	return nil
 }
 
+// SetCredentials replaces the token and API key the client authenticates
+// with. An open connection is closed so that it reconnects with them,
+// restoring its subscriptions; without AutoReconnect it stays disconnected.
+func (c *WebSocketClient) SetCredentials(token, apiKey string) {
+	c.mu.Lock()
+	c.opts.Token = token
+	c.opts.APIKey = apiKey
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		_ = conn.Close(websocket.StatusServiceRestart, "credentials changed")
+	}
+}
+
 // State returns the current connection state.
 func (c *WebSocketClient) State() ConnectionState {
 	c.mu.RLock()
@@ -158,77 +181,113 @@ func (c *WebSocketClient) State() ConnectionState {
 	return c.state
 }
 
-// Subscribe adds a subscription filter.
+// Subscribe adds a subscription filter. If the server rejects a filter's
+// QueuePattern, the client subscribes to all queues instead and matches the
+// pattern itself. Subscriptions start after the last event delivered, so
+// those restored after a reconnect miss nothing.
 func (c *WebSocketClient) Subscribe(filter SubscriptionFilter) error {
-	c.cmdMu.Lock()
-	c.cmdSeq++
-	requestID := fmt.Sprintf("sub-%d", c.cmdSeq)
-	c.cmdMu.Unlock()
-
-	cmd := wsCommand{
-		Type:      "subscribe",
-		RequestID: requestID,
-		Filter:    &filter,
+	if err := filter.validate(); err != nil {
+		return err
+	}
+	key := subscriptionKey(filter)
+	err := c.sendCommand("subscribe", "sub", filter)
+	fellBack := false
+	if err != nil && filter.QueuePattern != "" {
+		c.log("Queue pattern %q rejected (%v), matching client-side", filter.QueuePattern, err)
+		err = c.sendCommand("subscribe", "sub", filter.serverFilter())
+		fellBack = true
 	}
-
-	respCh := make(chan error, 1)
-	c.cmdMu.Lock()
-	c.pendingCommands[requestID] = respCh
-	c.cmdMu.Unlock()
-
-	defer func() {
-		c.cmdMu.Lock()
-		delete(c.pendingCommands, requestID)
-		c.cmdMu.Unlock()
-	}()
-
-	data, err := json.Marshal(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to marshal subscribe command: %w", err)
+		return err
 	}
 
-	c.mu.RLock()
-	conn := c.conn
-	ctx := c.ctx
-	c.mu.RUnlock()
-
-	if conn == nil {
-		return fmt.Errorf("not connected")
+	// Store subscription
+	c.mu.Lock()
+	c.subscriptions[key] = filter
+	if fellBack {
+		c.fallbacks[key] = filter.serverFilter()
+	} else {
+		delete(c.fallbacks, key)
 	}
+	c.mu.Unlock()
+	return nil
+}
 
-	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
-		return fmt.Errorf("failed to send subscribe command: %w", err)
+// SubscribeAll adds several subscription filters, e.g. one pattern per
+// tenant. If any fails, those already added are removed again.
+func (c *WebSocketClient) SubscribeAll(filters ...SubscriptionFilter) error {
+	for _, filter := range filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
 	}
-
-	// Wait for response with timeout
-	select {
-	case err := <-respCh:
-		if err != nil {
+	for i, filter := range filters {
+		if err := c.Subscribe(filter); err != nil {
+			for _, added := range filters[:i] {
+				if uerr := c.Unsubscribe(added); uerr != nil {
+					c.log("Failed to remove subscription: %v", uerr)
+				}
+			}
 			return err
 		}
-		// Store subscription
-		key := subscriptionKey(filter)
-		c.mu.Lock()
-		c.subscriptions[key] = filter
-		c.mu.Unlock()
-		return nil
-	case <-time.After(10 * time.Second):
-		return fmt.Errorf("subscribe timeout")
 	}
+	return nil
 }
 
 // Unsubscribe removes a subscription.
 func (c *WebSocketClient) Unsubscribe(filter SubscriptionFilter) error {
+	key := subscriptionKey(filter)
+	c.mu.RLock()
+	sent := c.sentFilter(key, filter)
+	shared := false
+	for other, f := range c.subscriptions {
+		if other != key && subscriptionKey(c.sentFilter(other, f)) == subscriptionKey(sent) {
+			shared = true
+		}
+	}
+	c.mu.RUnlock()
+
+	// Keep server subscriptions that other filters still rely on, e.g. the
+	// all-queues fallback shared by several patterns
+	if !shared {
+		if err := c.sendCommand("unsubscribe", "unsub", sent); err != nil {
+			return err
+		}
+	}
+
+	// Remove subscription
+	c.mu.Lock()
+	delete(c.subscriptions, key)
+	delete(c.fallbacks, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// sentFilter returns the filter the server was sent for a subscription.
+// Callers must hold c.mu.
+func (c *WebSocketClient) sentFilter(key string, filter SubscriptionFilter) SubscriptionFilter {
+	if fallback, ok := c.fallbacks[key]; ok {
+		return fallback
+	}
+	return filter
+}
+
+// sendCommand sends a subscribe or unsubscribe command and waits for the
+// server's response.
+func (c *WebSocketClient) sendCommand(cmdType, idPrefix string, filter SubscriptionFilter) error {
 	c.cmdMu.Lock()
 	c.cmdSeq++
-	requestID := fmt.Sprintf("unsub-%d", c.cmdSeq)
+	requestID := fmt.Sprintf("%s-%d", idPrefix, c.cmdSeq)
 	c.cmdMu.Unlock()
 
 	cmd := wsCommand{
-		Type:      "unsubscribe",
+		Type:      cmdType,
 		RequestID: requestID,
 		Filter:    &filter,
 	}
+	if cmdType == "subscribe" {
+		cmd.ResumeToken = c.cursor.Token()
+	}
 
 	respCh := make(chan error, 1)
 	c.cmdMu.Lock()
@@ -243,7 +302,7 @@ func (c *WebSocketClient) Unsubscribe(filter SubscriptionFilter) error {
 
 	data, err := json.Marshal(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to marshal unsubscribe command: %w", err)
+		return fmt.Errorf("failed to marshal %s command: %w", cmdType, err)
 	}
 
 	c.mu.RLock()
@@ -256,59 +315,93 @@ func (c *WebSocketClient) Unsubscribe(filter SubscriptionFilter) error {
 	}
 
 	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
-		return fmt.Errorf("failed to send unsubscribe command: %w", err)
+		return fmt.Errorf("failed to send %s command: %w", cmdType, err)
 	}
 
 	// Wait for response with timeout
 	select {
 	case err := <-respCh:
-		if err != nil {
-			return err
-		}
-		// Remove subscription
-		key := subscriptionKey(filter)
-		c.mu.Lock()
-		delete(c.subscriptions, key)
-		c.mu.Unlock()
-		return nil
+		return err
 	case <-time.After(10 * time.Second):
-		return fmt.Errorf("unsubscribe timeout")
+		return fmt.Errorf("%s timeout", cmdType)
 	}
 }
 
-// OnEvent registers a handler for all events.
-func (c *WebSocketClient) OnEvent(handler EventHandler) {
+// ResumeToken returns the token of the last event delivered, which
+// subscriptions resume from after reconnecting. Save it and pass it as
+// ConnectionOptions.ResumeToken to resume in a new client.
+func (c *WebSocketClient) ResumeToken() string {
+	return c.cursor.Token()
+}
+
+// OnEvent registers a handler for all events and
+// returns a func that removes it.
+func (c *WebSocketClient) OnEvent(handler EventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.allEventHandlers = append(c.allEventHandlers, handler)
+	var id uint64
+	c.allEventHandlers, id = c.allEventHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.allEventHandlers = c.allEventHandlers.remove(id)
+	}
 }
 
-// OnJobEvent registers a handler for job events.
-func (c *WebSocketClient) OnJobEvent(eventType EventType, handler JobEventHandler) {
+// OnJobEvent registers a handler for job events and
+// returns a func that removes it.
+func (c *WebSocketClient) OnJobEvent(eventType EventType, handler JobEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.eventHandlers[eventType] = append(c.eventHandlers[eventType], handler)
+	var id uint64
+	c.eventHandlers[eventType], id = c.eventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.eventHandlers[eventType] = c.eventHandlers[eventType].remove(id)
+	}
 }
 
-// OnQueueEvent registers a handler for queue events.
-func (c *WebSocketClient) OnQueueEvent(eventType EventType, handler QueueEventHandler) {
+// OnQueueEvent registers a handler for queue events and
+// returns a func that removes it.
+func (c *WebSocketClient) OnQueueEvent(eventType EventType, handler QueueEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.queueEventHandlers[eventType] = append(c.queueEventHandlers[eventType], handler)
+	var id uint64
+	c.queueEventHandlers[eventType], id = c.queueEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.queueEventHandlers[eventType] = c.queueEventHandlers[eventType].remove(id)
+	}
 }
 
-// OnWorkerEvent registers a handler for worker events.
-func (c *WebSocketClient) OnWorkerEvent(eventType EventType, handler WorkerEventHandler) {
+// OnWorkerEvent registers a handler for worker events and
+// returns a func that removes it.
+func (c *WebSocketClient) OnWorkerEvent(eventType EventType, handler WorkerEventHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.workerEventHandlers[eventType] = append(c.workerEventHandlers[eventType], handler)
+	var id uint64
+	c.workerEventHandlers[eventType], id = c.workerEventHandlers[eventType].add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.workerEventHandlers[eventType] = c.workerEventHandlers[eventType].remove(id)
+	}
 }
 
-// OnStateChange registers a handler for state changes.
-func (c *WebSocketClient) OnStateChange(handler StateChangeHandler) {
+// OnStateChange registers a handler for state changes and
+// returns a func that removes it.
+func (c *WebSocketClient) OnStateChange(handler StateChangeHandler) func() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.stateChangeHandlers = append(c.stateChangeHandlers, handler)
+	var id uint64
+	c.stateChangeHandlers, id = c.stateChangeHandlers.add(handler)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.stateChangeHandlers = c.stateChangeHandlers.remove(id)
+	}
 }
 
 func (c *WebSocketClient) readLoop() {
@@ -384,13 +477,27 @@ func (c *WebSocketClient) handleCommandResponse(resp wsResponse) {
 }
 
 func (c *WebSocketClient) dispatchEvent(event *Event) {
+	// Drop events replayed by the server after resuming
+	if !c.cursor.Advance(event.ResumeToken, event.ResumeToken) {
+		return
+	}
+
 	c.mu.RLock()
-	allHandlers := c.allEventHandlers
-	jobHandlers := c.eventHandlers[event.Type]
-	queueHandlers := c.queueEventHandlers[event.Type]
-	workerHandlers := c.workerEventHandlers[event.Type]
+	allHandlers := c.allEventHandlers.handlers()
+	jobHandlers := c.eventHandlers[event.Type].handlers()
+	queueHandlers := c.queueEventHandlers[event.Type].handlers()
+	workerHandlers := c.workerEventHandlers[event.Type].handlers()
+	filters := make([]SubscriptionFilter, 0, len(c.subscriptions))
+	for _, f := range c.subscriptions {
+		filters = append(filters, f)
+	}
 	c.mu.RUnlock()
 
+	// Servers may ignore or reject queue patterns, so match them here
+	if hasPattern(filters) && !matchesAny(filters, event) {
+		return
+	}
+
 	// Call all-event handlers
 	for _, handler := range allHandlers {
 		func() {
@@ -498,8 +605,7 @@ func (c *WebSocketClient) setState(state ConnectionState) {
 	c.state = state
 
 	// Make a copy of handlers to call outside the lock
-	handlers := make([]StateChangeHandler, len(c.stateChangeHandlers))
-	copy(handlers, c.stateChangeHandlers)
+	handlers := c.stateChangeHandlers.handlers()
 
 	// Call handlers outside the lock (we're already holding it)
 	go func() {
@@ -525,12 +631,12 @@ func (c *WebSocketClient) log(format string, args ...any) {
 }
 
 func subscriptionKey(filter SubscriptionFilter) string {
-	return fmt.Sprintf("%s:%s:%s", filter.QueueName, filter.JobID, filter.WorkerID)
+	return fmt.Sprintf("%s:%s:%s:%s", filter.QueueName, filter.QueuePattern, filter.JobID, filter.WorkerID)
 }
 
 func isJobEvent(t EventType) bool {
 	switch t {
-	case EventJobCreated, EventJobStarted, EventJobCompleted, EventJobFailed, EventJobRetrying, EventJobProgress:
+	case EventJobCreated, EventJobStarted, EventJobCompleted, EventJobFailed, EventJobRetrying, EventJobProgress, EventJobDeadletter:
 		return true
 	}
 	return false
@@ -0,0 +1,18 @@
+//go:build !js
+
+package realtime
+
+import (
+	"context"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// dialWebSocket opens a WebSocket connection authenticated with headers.
+func dialWebSocket(ctx context.Context, wsURL string, headers http.Header) (*websocket.Conn, error) {
+	conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+		HTTPHeader: headers,
+	})
+	return conn, err
+}
@@ -0,0 +1,35 @@
+//go:build js
+
+package realtime
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"nhooyr.io/websocket"
+)
+
+// dialWebSocket opens a WebSocket connection from the browser. The browser
+// WebSocket API cannot set request headers, so the credential is sent as the
+// token query parameter instead.
+func dialWebSocket(ctx context.Context, wsURL string, headers http.Header) (*websocket.Conn, error) {
+	token := strings.TrimPrefix(headers.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = headers.Get("X-API-Key")
+	}
+	if token != "" {
+		u, err := url.Parse(wsURL)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		query.Set("token", token)
+		u.RawQuery = query.Encode()
+		wsURL = u.String()
+	}
+
+	conn, _, err := websocket.Dial(ctx, wsURL, nil)
+	return conn, err
+}
@@ -2,7 +2,11 @@ package resources
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
@@ -18,6 +22,21 @@ func NewAPIKeysResource(transport *httpx.Transport) *APIKeysResource {
 	return &APIKeysResource{base: NewBase(transport)}
 }
 
+// Scope is an operation an API key may perform.
+type Scope string
+
+const (
+	ScopeJobsClaim       Scope = "jobs:claim"
+	ScopeJobsHeartbeat   Scope = "jobs:heartbeat"
+	ScopeJobsComplete    Scope = "jobs:complete"
+	ScopeWorkersRegister Scope = "workers:register"
+)
+
+// WorkerScopes are the scopes of keys created by CreateWorkerKey: claiming
+// jobs, renewing their leases and reporting progress, completing or failing
+// them, and registering and heartbeating as a worker.
+var WorkerScopes = []Scope{ScopeJobsClaim, ScopeJobsHeartbeat, ScopeJobsComplete, ScopeWorkersRegister}
+
 // APIKey represents an API key.
 type APIKey struct {
 	ID             string     `json:"id"`
@@ -25,6 +44,8 @@ type APIKey struct {
 	Name           string     `json:"name"`
 	KeyPrefix      *string    `json:"key_prefix,omitempty"`
 	Queues         []string   `json:"queues,omitempty"`
+	WorkerID       *string    `json:"worker_id,omitempty"`
+	Scopes         []Scope    `json:"scopes,omitempty"`
 	RateLimit      *int       `json:"rate_limit,omitempty"`
 	IsActive       bool       `json:"is_active"`
 	CreatedAt      time.Time  `json:"created_at"`
@@ -43,8 +64,13 @@ func (r *APIKeysResource) List(ctx context.Context) ([]APIKey, error) {
 
 // CreateAPIKeyRequest is the request to create an API key.
 type CreateAPIKeyRequest struct {
-	Name      string     `json:"name"`
-	Queues    []string   `json:"queues,omitempty"`
+	Name   string   `json:"name"`
+	Queues []string `json:"queues,omitempty"`
+	// WorkerID restricts the key to registering and acting as this worker,
+	// e.g. one key per edge device.
+	WorkerID *string `json:"worker_id,omitempty"`
+	// Scopes limits the operations the key may perform (default: all).
+	Scopes    []Scope    `json:"scopes,omitempty"`
 	RateLimit *int       `json:"rate_limit,omitempty"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
@@ -54,6 +80,7 @@ type CreateAPIKeyResponse struct {
 	ID        string     `json:"id"`
 	Key       string     `json:"key"` // Raw key - only shown once!
 	Name      string     `json:"name"`
+	WorkerID  *string    `json:"worker_id,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
@@ -67,6 +94,29 @@ func (r *APIKeysResource) Create(ctx context.Context, req *CreateAPIKeyRequest)
 	return &result, nil
 }
 
+// CreateWorkerKey creates a key that can only process jobs from queue, as a
+// single worker identity: it has WorkerScopes and cannot enqueue, read, or
+// manage anything. If a worker host is compromised, the key cannot be used to
+// touch other queues or the organization.
+//
+// Pass the returned WorkerID to the worker's options along with the key.
+func (r *APIKeysResource) CreateWorkerKey(ctx context.Context, queue string) (*CreateAPIKeyResponse, error) {
+	workerID := queue + "-" + newWorkerSuffix()
+	return r.Create(ctx, &CreateAPIKeyRequest{
+		Name:     "worker:" + workerID,
+		Queues:   []string{r.base.queueName(ctx, queue)},
+		WorkerID: &workerID,
+		Scopes:   WorkerScopes,
+	})
+}
+
+// newWorkerSuffix returns a random suffix for worker identities.
+func newWorkerSuffix() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
 // Get retrieves a specific API key.
 func (r *APIKeysResource) Get(ctx context.Context, id string) (*APIKey, error) {
 	var result APIKey
@@ -97,3 +147,88 @@ func (r *APIKeysResource) Update(ctx context.Context, id string, req *UpdateAPIK
 func (r *APIKeysResource) Delete(ctx context.Context, id string) error {
 	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/api-keys/%s", id))
 }
+
+// bulkCreateConcurrency bounds concurrent creates when the server has no
+// bulk endpoint.
+const bulkCreateConcurrency = 8
+
+// BulkAPIKeyResult is the result of creating one key in BulkCreate.
+type BulkAPIKeyResult struct {
+	// Index is the position of the request in the BulkCreate input.
+	Index int `json:"index"`
+	// Key is the created key, or nil if creation failed. Key.Key is only
+	// available here.
+	Key   *CreateAPIKeyResponse `json:"key,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+// BulkCreateAPIKeysResponse is the response from bulk creating API keys.
+type BulkCreateAPIKeysResponse struct {
+	// Results has one entry per request, in request order.
+	Results      []BulkAPIKeyResult `json:"results"`
+	SuccessCount int                `json:"success_count"`
+	FailureCount int                `json:"failure_count"`
+}
+
+// Failed returns the results of the keys that could not be created.
+func (r *BulkCreateAPIKeysResponse) Failed() []BulkAPIKeyResult {
+	var failed []BulkAPIKeyResult
+	for _, result := range r.Results {
+		if result.Key == nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// BulkCreate creates many API keys in one request, e.g. a key per device in
+// a fleet, scoped with Queues and WorkerID. Keys are created independently:
+// a failed item is reported in its result and does not stop the others.
+//
+// Servers without a bulk endpoint get one Create per key, a few at a time.
+func (r *APIKeysResource) BulkCreate(ctx context.Context, reqs []CreateAPIKeyRequest) (*BulkCreateAPIKeysResponse, error) {
+	body := struct {
+		Keys []CreateAPIKeyRequest `json:"keys"`
+	}{Keys: reqs}
+
+	var result BulkCreateAPIKeysResponse
+	err := r.base.Post(ctx, "/api/v1/api-keys/bulk", &body, &result)
+	if httpx.IsNotFoundError(err) {
+		return r.createEach(ctx, reqs), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result.Results, func(i, j int) bool { return result.Results[i].Index < result.Results[j].Index })
+	return &result, nil
+}
+
+// createEach creates keys one request at a time.
+func (r *APIKeysResource) createEach(ctx context.Context, reqs []CreateAPIKeyRequest) *BulkCreateAPIKeysResponse {
+	result := &BulkCreateAPIKeysResponse{Results: make([]BulkAPIKeyResult, len(reqs))}
+	sem := make(chan struct{}, bulkCreateConcurrency)
+	var wg sync.WaitGroup
+	for i := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			key, err := r.Create(ctx, &reqs[i])
+			result.Results[i] = BulkAPIKeyResult{Index: i, Key: key}
+			if err != nil {
+				result.Results[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, res := range result.Results {
+		if res.Key != nil {
+			result.SuccessCount++
+		} else {
+			result.FailureCount++
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestAPIKeys_BulkCreate(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/api-keys/bulk", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[
+			{"index":1,"error":"name already exists"},
+			{"index":0,"key":{"id":"key-1","key":"sk_live_one","name":"device-1"}}
+		],"success_count":1,"failure_count":1}`))
+	})
+
+	worker := "device-1"
+	resp, err := NewAPIKeysResource(api.transport()).BulkCreate(context.Background(), []CreateAPIKeyRequest{
+		{Name: "device-1", Queues: []string{"telemetry"}, WorkerID: &worker},
+		{Name: "device-2", Queues: []string{"telemetry"}},
+	})
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+	var body struct {
+		Keys []CreateAPIKeyRequest `json:"keys"`
+	}
+	api.received("POST /api/v1/api-keys/bulk")[0].decode(t, &body)
+	if len(body.Keys) != 2 || body.Keys[0].WorkerID == nil || *body.Keys[0].WorkerID != "device-1" {
+		t.Errorf("bulk body = %+v, want both keys", body)
+	}
+	if resp.Results[0].Key == nil || resp.Results[0].Key.Key != "sk_live_one" {
+		t.Errorf("Results[0] = %+v, want the raw key", resp.Results[0])
+	}
+	if failed := resp.Failed(); len(failed) != 1 || failed[0].Index != 1 {
+		t.Errorf("Failed() = %+v, want index 1", failed)
+	}
+}
+
+func TestAPIKeys_BulkCreate_Fallback(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/api-keys", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateAPIKeyRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Name == "bad" {
+			writeTestJSON(w, http.StatusBadRequest, map[string]any{"code": "validation_error", "message": "invalid name"})
+			return
+		}
+		writeTestJSON(w, http.StatusCreated, map[string]any{"id": "id-" + req.Name, "key": "sk_live_" + req.Name, "name": req.Name})
+	})
+
+	reqs := make([]CreateAPIKeyRequest, 20)
+	for i := range reqs {
+		reqs[i].Name = "d" + strconv.Itoa(i)
+	}
+	reqs[5].Name = "bad"
+	resp, err := NewAPIKeysResource(api.transport()).BulkCreate(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+	if creates := len(api.received("POST /api/v1/api-keys")); creates != 20 || resp.SuccessCount != 19 || resp.FailureCount != 1 {
+		t.Errorf("creates=%d success=%d failure=%d, want 20/19/1", creates, resp.SuccessCount, resp.FailureCount)
+	}
+	if resp.Results[7].Index != 7 || resp.Results[7].Key.Key != "sk_live_d7" {
+		t.Errorf("Results[7] = %+v", resp.Results[7])
+	}
+	if resp.Results[5].Key != nil || resp.Results[5].Error == "" {
+		t.Errorf("Results[5] = %+v, want a failure", resp.Results[5])
+	}
+}
@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+const testAPIKey = "sp_test_123456789012345678901234567890"
+
+// apiRequest is a request received by a fakeAPI.
+type apiRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// decode unmarshals the request body into v.
+func (r apiRequest) decode(t *testing.T, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("%s %s body %q: %v", r.Method, r.Path, r.Body, err)
+	}
+}
+
+// fakeAPI is a REST API stub for resource tests. Routes are keyed by
+// "METHOD /path"; unrouted requests get 404. Every request is recorded.
+type fakeAPI struct {
+	*httptest.Server
+	mu       sync.Mutex
+	routes   map[string]http.HandlerFunc
+	requests []apiRequest
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+	api := &fakeAPI{routes: make(map[string]http.HandlerFunc)}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func (a *fakeAPI) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	route := r.Method + " " + r.URL.Path
+	a.mu.Lock()
+	a.requests = append(a.requests, apiRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	handler := a.routes[route]
+	a.mu.Unlock()
+
+	if handler == nil {
+		writeTestJSON(w, http.StatusNotFound, map[string]any{"code": "not_found", "message": route + " not found"})
+		return
+	}
+	handler(w, r)
+}
+
+// handle routes "METHOD /path" to handler.
+func (a *fakeAPI) handle(route string, handler http.HandlerFunc) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.routes[route] = handler
+}
+
+// respond answers "METHOD /path" with status and body encoded as JSON.
+func (a *fakeAPI) respond(route string, status int, body any) {
+	a.handle(route, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, status, body)
+	})
+}
+
+// received returns the requests made to "METHOD /path", or all requests
+// when route is "".
+func (a *fakeAPI) received(route string) []apiRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var matched []apiRequest
+	for _, req := range a.requests {
+		if route == "" || req.Method+" "+req.Path == route {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// transport returns a transport for the API that does not retry.
+func (a *fakeAPI) transport() *httpx.Transport {
+	return httpx.NewTransport(httpx.Config{BaseURL: a.URL, APIKey: testAPIKey})
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
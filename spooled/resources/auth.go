@@ -71,6 +71,8 @@ type MeResponse struct {
 	OrganizationID string    `json:"organization_id"`
 	APIKeyID       string    `json:"api_key_id"`
 	Queues         []string  `json:"queues"`
+	WorkerID       string    `json:"worker_id,omitempty"`
+	Scopes         []Scope   `json:"scopes,omitempty"`
 	IssuedAt       time.Time `json:"issued_at"`
 	ExpiresAt      time.Time `json:"expires_at"`
 }
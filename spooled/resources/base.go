@@ -15,6 +15,10 @@ import (
 // Base provides common functionality for all resources.
 type Base struct {
 	transport *httpx.Transport
+	// headers and query are sent with every request made through this Base;
+	// values set by the request itself take precedence.
+	headers map[string]string
+	query   map[string]string
 }
 
 // NewBase creates a new Base resource.
@@ -24,7 +28,7 @@ func NewBase(transport *httpx.Transport) *Base {
 
 // Get performs a GET request.
 func (b *Base) Get(ctx context.Context, path string, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodGet,
 		Path:   path,
 	})
@@ -36,7 +40,7 @@ func (b *Base) Get(ctx context.Context, path string, result any) error {
 
 // GetWithQuery performs a GET request with query parameters.
 func (b *Base) GetWithQuery(ctx context.Context, path string, query url.Values, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodGet,
 		Path:   path,
 		Query:  valuestoMap(query),
@@ -47,9 +51,19 @@ func (b *Base) GetWithQuery(ctx context.Context, path string, query url.Values,
 	return decodeResponse(resp, result)
 }
 
+// getRaw performs a GET request with query parameters and returns the raw
+// response, for callers that need headers as well as the body.
+func (b *Base) getRaw(ctx context.Context, path string, query url.Values) (*httpx.Response, error) {
+	return b.do(ctx, &httpx.Request{
+		Method: http.MethodGet,
+		Path:   path,
+		Query:  valuestoMap(query),
+	})
+}
+
 // Post performs a POST request.
 func (b *Base) Post(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodPost,
 		Path:   path,
 		Body:   body,
@@ -62,7 +76,7 @@ func (b *Base) Post(ctx context.Context, path string, body any, result any) erro
 
 // PostIdempotent performs an idempotent POST request (can be retried).
 func (b *Base) PostIdempotent(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method:     http.MethodPost,
 		Path:       path,
 		Body:       body,
@@ -74,9 +88,25 @@ func (b *Base) PostIdempotent(ctx context.Context, path string, body any, result
 	return decodeResponse(resp, result)
 }
 
+// postPayload performs a POST request carrying job payloads, whose body is
+// compressed when it reaches the transport's compression threshold.
+func (b *Base) postPayload(ctx context.Context, path string, body any, result any, idempotent bool) error {
+	resp, err := b.do(ctx, &httpx.Request{
+		Method:     http.MethodPost,
+		Path:       path,
+		Body:       body,
+		Idempotent: idempotent,
+		Compress:   true,
+	})
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, result)
+}
+
 // Put performs a PUT request.
 func (b *Base) Put(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodPut,
 		Path:   path,
 		Body:   body,
@@ -89,7 +119,7 @@ func (b *Base) Put(ctx context.Context, path string, body any, result any) error
 
 // Patch performs a PATCH request.
 func (b *Base) Patch(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodPatch,
 		Path:   path,
 		Body:   body,
@@ -102,7 +132,7 @@ func (b *Base) Patch(ctx context.Context, path string, body any, result any) err
 
 // Delete performs a DELETE request.
 func (b *Base) Delete(ctx context.Context, path string) error {
-	_, err := b.transport.Do(ctx, &httpx.Request{
+	_, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodDelete,
 		Path:   path,
 	})
@@ -111,7 +141,7 @@ func (b *Base) Delete(ctx context.Context, path string) error {
 
 // DeleteWithBody performs a DELETE request with a body.
 func (b *Base) DeleteWithBody(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method: http.MethodDelete,
 		Path:   path,
 		Body:   body,
@@ -124,7 +154,7 @@ func (b *Base) DeleteWithBody(ctx context.Context, path string, body any, result
 
 // AdminGet performs a GET request with admin key.
 func (b *Base) AdminGet(ctx context.Context, path string, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method:      http.MethodGet,
 		Path:        path,
 		UseAdminKey: true,
@@ -137,7 +167,7 @@ func (b *Base) AdminGet(ctx context.Context, path string, result any) error {
 
 // AdminPost performs a POST request with admin key.
 func (b *Base) AdminPost(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method:      http.MethodPost,
 		Path:        path,
 		Body:        body,
@@ -151,7 +181,7 @@ func (b *Base) AdminPost(ctx context.Context, path string, body any, result any)
 
 // AdminPut performs a PUT request with admin key.
 func (b *Base) AdminPut(ctx context.Context, path string, body any, result any) error {
-	resp, err := b.transport.Do(ctx, &httpx.Request{
+	resp, err := b.do(ctx, &httpx.Request{
 		Method:      http.MethodPut,
 		Path:        path,
 		Body:        body,
@@ -165,7 +195,7 @@ func (b *Base) AdminPut(ctx context.Context, path string, body any, result any)
 
 // AdminDelete performs a DELETE request with admin key.
 func (b *Base) AdminDelete(ctx context.Context, path string) error {
-	_, err := b.transport.Do(ctx, &httpx.Request{
+	_, err := b.do(ctx, &httpx.Request{
 		Method:      http.MethodDelete,
 		Path:        path,
 		UseAdminKey: true,
@@ -173,6 +203,110 @@ func (b *Base) AdminDelete(ctx context.Context, path string) error {
 	return err
 }
 
+// do sends req with the Base's default headers and query parameters.
+func (b *Base) do(ctx context.Context, req *httpx.Request) (*httpx.Response, error) {
+	if len(b.headers) > 0 {
+		req.Headers = mergeDefaults(b.headers, req.Headers)
+	}
+	if len(b.query) > 0 {
+		req.Query = mergeDefaults(b.query, req.Query)
+	}
+	if req.Method != http.MethodGet && req.IdempotencyKey == "" {
+		req.IdempotencyKey = idempotencyKey(ctx)
+	}
+	return b.transport.Do(ctx, req)
+}
+
+// withDefaults returns a copy of b that also sends headers and query.
+func (b *Base) withDefaults(headers map[string]string, query url.Values) *Base {
+	canonical := make(map[string]string, len(headers))
+	for k, v := range headers {
+		canonical[http.CanonicalHeaderKey(k)] = v
+	}
+	return &Base{
+		transport: b.transport,
+		headers:   mergeDefaults(b.headers, canonical),
+		query:     mergeDefaults(b.query, valuestoMap(query)),
+	}
+}
+
+// mergeDefaults returns defaults overlaid with values, without modifying either.
+func mergeDefaults(defaults, values map[string]string) map[string]string {
+	if len(defaults) == 0 && len(values) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(defaults)+len(values))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged
+}
+
+// rawQueueNamesKey marks a context whose queue names must not be prefixed.
+type rawQueueNamesKey struct{}
+
+// WithRawQueueNames returns a context that disables the client's queue prefix
+// for calls made with it, e.g. to reach a queue shared across environments.
+func WithRawQueueNames(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawQueueNamesKey{}, true)
+}
+
+// RawQueueNames reports whether ctx was returned by WithRawQueueNames.
+func RawQueueNames(ctx context.Context) bool {
+	raw, _ := ctx.Value(rawQueueNamesKey{}).(bool)
+	return raw
+}
+
+// idempotencyKeyKey carries the idempotency key set by WithIdempotencyKey.
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context that sends key in the Idempotency-Key
+// header of mutating calls made with it. The server applies a call with a
+// given key at most once, and the transport retries such calls like
+// idempotent ones, so e.g. Webhooks().Create in a retried HTTP handler does
+// not create duplicates. Use each key for a single call.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// idempotencyKey returns the key set by WithIdempotencyKey, or "".
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// queueName applies the configured queue prefix to name. The prefix is
+// always added, even to names that already start with it, so every name maps
+// to exactly one queue; names taken from API responses already carry it and
+// must be passed back with WithRawQueueNames.
+func (b *Base) queueName(ctx context.Context, name string) string {
+	prefix := b.queuePrefix(ctx)
+	if prefix == "" || name == "" {
+		return name
+	}
+	return prefix + name
+}
+
+// queueNamePtr applies the configured queue prefix to an optional name.
+func (b *Base) queueNamePtr(ctx context.Context, name *string) *string {
+	if name == nil {
+		return nil
+	}
+	prefixed := b.queueName(ctx, *name)
+	return &prefixed
+}
+
+// queuePrefix returns the prefix in effect for ctx.
+func (b *Base) queuePrefix(ctx context.Context) string {
+	if RawQueueNames(ctx) {
+		return ""
+	}
+	return b.transport.QueuePrefix()
+}
+
 // decodeResponse decodes a response into the result if result is not nil.
 func decodeResponse(resp *httpx.Response, result any) error {
 	if result == nil {
@@ -182,13 +316,17 @@ func decodeResponse(resp *httpx.Response, result any) error {
 	if err != nil {
 		return err
 	}
+	if decoded != nil && resp.LegacyFieldNames() {
+		httpx.NormalizeFieldNames(*decoded, result)
+	}
 	// We need to re-unmarshal into the correct type
 	// This is a bit inefficient but works for all types
-	return remarshal(decoded, result)
+	return remarshal(decoded, result, resp.UsesNumber())
 }
 
-// remarshal re-marshals a value into a target type.
-func remarshal(src, dst any) error {
+// remarshal re-marshals a value into a target type. With useNumber, numbers
+// in untyped fields such as payloads stay json.Number.
+func remarshal(src, dst any, useNumber bool) error {
 	if src == nil {
 		return nil
 	}
@@ -197,6 +335,9 @@ func remarshal(src, dst any) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal: %w", err)
 	}
+	if useNumber {
+		return httpx.UnmarshalUseNumber(data, dst)
+	}
 	return json.Unmarshal(data, dst)
 }
 
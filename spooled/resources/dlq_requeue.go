@@ -0,0 +1,201 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// dlqPageSize is how many dead jobs Stream and RequeueWithTransform fetch at a
+// time by default.
+const dlqPageSize = 100
+
+// Stream sends every dead letter queue job matching params on the returned
+// channel, fetching the next page of params.Limit jobs (default 100) from
+// params.Offset only as the previous one is consumed. Both channels are
+// closed when the DLQ is exhausted; the error channel receives at most one
+// error. Cancel ctx to stop early.
+//
+//	jobs, errs := client.Jobs().DLQ().Stream(ctx, &resources.ListDLQParams{QueueName: &queue})
+//	for job := range jobs {
+//		triage(job)
+//	}
+//	if err := <-errs; err != nil { ... }
+func (r *DLQResource) Stream(ctx context.Context, params *ListDLQParams) (<-chan Job, <-chan error) {
+	items := make(chan Job)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(items)
+		if err := r.eachPage(ctx, params, func(page []Job) (int, error) {
+			for _, job := range page {
+				select {
+				case items <- job:
+				case <-ctx.Done():
+					return 0, ctx.Err()
+				}
+			}
+			return 0, nil
+		}); err != nil {
+			errs <- err
+		}
+	}()
+	return items, errs
+}
+
+// eachPage calls fn with successive pages of the DLQ. fn returns how many of
+// the page's jobs it removed from the DLQ, so the next page starts after the
+// jobs that are left.
+func (r *DLQResource) eachPage(ctx context.Context, params *ListDLQParams, fn func([]Job) (int, error)) error {
+	p := ListDLQParams{}
+	if params != nil {
+		p = *params
+	}
+	limit, offset := dlqPageSize, 0
+	if p.Limit != nil && *p.Limit > 0 {
+		limit = *p.Limit
+	}
+	if p.Offset != nil {
+		offset = *p.Offset
+	}
+	for {
+		p.Limit, p.Offset = &limit, &offset
+		page, err := r.List(ctx, &p)
+		if err != nil {
+			return err
+		}
+		removed, err := fn(page)
+		if err != nil {
+			return err
+		}
+		if len(page) < limit {
+			return nil
+		}
+		offset += len(page) - removed
+	}
+}
+
+// DLQTransform decides what RequeueWithTransform does with a dead job. It
+// returns false to leave the job in the DLQ, true with a nil request to retry
+// it unchanged, or true with a request to replace it with a new job, such as
+// one built by RequeueRequest with an edited payload.
+type DLQTransform func(job Job) (*CreateJobRequest, bool)
+
+// RequeueDLQResult summarizes a RequeueWithTransform run.
+type RequeueDLQResult struct {
+	// Examined is the number of dead jobs passed to the transform.
+	Examined int
+	// Retried are the IDs of the dead jobs retried unchanged.
+	Retried []string
+	// Replaced maps the IDs of replaced dead jobs to the IDs of the jobs
+	// created for them.
+	Replaced map[string]string
+	// Skipped is the number of dead jobs left in the DLQ.
+	Skipped int
+}
+
+// RequeueRequest returns a request that recreates job as it was submitted,
+// for a DLQTransform to edit. The payload and tags are copies.
+func RequeueRequest(job Job) *CreateJobRequest {
+	priority, maxRetries, timeout := job.Priority, job.MaxRetries, job.TimeoutSeconds
+	req := &CreateJobRequest{
+		QueueName:         job.QueueName,
+		Payload:           maps.Clone(job.Payload),
+		Priority:          &priority,
+		MaxRetries:        &maxRetries,
+		Tags:              maps.Clone(job.Tags),
+		ParentJobID:       job.ParentJobID,
+		CompletionWebhook: job.CompletionWebhook,
+		RunbookURL:        job.RunbookURL,
+		OwnerTeam:         job.OwnerTeam,
+	}
+	if timeout > 0 {
+		req.TimeoutSeconds = &timeout
+	}
+	return req
+}
+
+// RequeueWithTransform walks the whole dead letter queue in batches and
+// passes each job to transform to be left, retried, or replaced, so that
+// dead jobs can be remediated selectively instead of retrying or purging a
+// whole queue:
+//
+//	result, err := client.Jobs().DLQ().RequeueWithTransform(ctx, func(job resources.Job) (*resources.CreateJobRequest, bool) {
+//		if job.LastError == nil || !strings.Contains(*job.LastError, "invalid email") {
+//			return nil, false // leave it for someone else
+//		}
+//		req := resources.RequeueRequest(job)
+//		req.Payload["email"] = strings.TrimSpace(req.Payload["email"].(string))
+//		return req, true
+//	})
+//
+// Jobs retried unchanged are requeued with one Retry call per batch. A
+// replacement is created with Jobs().Create, using an idempotency key derived
+// from the dead job's ID unless it sets its own, and the dead job is then
+// cancelled with a reason naming its replacement; running the remediation
+// again after a failure does not create a job twice. If the API is
+// unreachable and the replacement is spooled, the dead job is left in the
+// DLQ.
+//
+// On error, the result so far is returned with the error.
+func (r *DLQResource) RequeueWithTransform(ctx context.Context, transform DLQTransform) (*RequeueDLQResult, error) {
+	result := &RequeueDLQResult{Replaced: map[string]string{}}
+	seen := map[string]bool{}
+	err := r.eachPage(ctx, nil, func(page []Job) (int, error) {
+		var retry []string
+		removed := 0
+		for _, job := range page {
+			if seen[job.ID] {
+				// Still listed after being requeued; skip past it
+				continue
+			}
+			seen[job.ID] = true
+			result.Examined++
+			req, ok := transform(job)
+			switch {
+			case !ok:
+				result.Skipped++
+			case req == nil:
+				retry = append(retry, job.ID)
+			default:
+				replaced, err := r.replace(ctx, job, req)
+				if err != nil {
+					return 0, fmt.Errorf("requeue dead job %s: %w", job.ID, err)
+				}
+				result.Replaced[job.ID] = replaced.ID
+				if !replaced.Spooled {
+					removed++
+				}
+			}
+		}
+		if len(retry) > 0 {
+			if _, err := r.Retry(ctx, &RetryDLQRequest{JobIDs: retry}); err != nil {
+				return 0, err
+			}
+			result.Retried = append(result.Retried, retry...)
+			removed += len(retry)
+		}
+		return removed, nil
+	})
+	return result, err
+}
+
+// replace creates req in place of the dead job and cancels the dead job.
+func (r *DLQResource) replace(ctx context.Context, job Job, req *CreateJobRequest) (*CreateJobResponse, error) {
+	body := *req
+	if body.IdempotencyKey == nil && body.ID == nil {
+		key := "dlq-requeue-" + job.ID
+		body.IdempotencyKey = &key
+	}
+	created, err := r.jobs.Create(ctx, &body)
+	if err != nil {
+		return nil, err
+	}
+	if created.Spooled {
+		return created, nil
+	}
+	if err := r.jobs.CancelWithReason(ctx, job.ID, "requeued from the DLQ as job "+created.ID, ""); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDLQ_RequeueWithTransform(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		dead     []map[string]any
+		replaced int
+	)
+	for i := 0; i < 150; i++ {
+		dead = append(dead, map[string]any{"id": fmt.Sprintf("job-%d", i), "queue_name": "emails", "status": "deadletter", "payload": map[string]any{"n": i}, "priority": 5, "max_retries": 3})
+	}
+	remove := func(id string) {
+		for i, job := range dead {
+			if job["id"] == id {
+				dead = append(dead[:i], dead[i+1:]...)
+				return
+			}
+		}
+	}
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs/dlq", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		writeTestJSON(w, http.StatusOK, dead[min(offset, len(dead)):min(offset+limit, len(dead))])
+	})
+	api.handle("POST /api/v1/jobs/dlq/retry", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req RetryDLQRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, id := range req.JobIDs {
+			remove(id)
+		}
+		writeTestJSON(w, http.StatusOK, map[string]any{"retried_count": len(req.JobIDs)})
+	})
+	api.handle("POST /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		replaced++
+		writeTestJSON(w, http.StatusOK, map[string]any{"id": fmt.Sprintf("new-%d", replaced), "created": true})
+	})
+	for _, job := range dead {
+		api.handle("DELETE /api/v1/jobs/"+job["id"].(string), func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			remove(strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/"))
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	dlq := NewJobsResource(api.transport()).DLQ()
+	ctx := context.Background()
+
+	// Leave every third job, retry the next, and replace the one after with
+	// an edited payload
+	result, err := dlq.RequeueWithTransform(ctx, func(job Job) (*CreateJobRequest, bool) {
+		n := int(job.Payload["n"].(float64))
+		switch n % 3 {
+		case 0:
+			return nil, false
+		case 1:
+			return nil, true
+		}
+		req := RequeueRequest(job)
+		req.Payload["n"] = n * 10
+		return req, true
+	})
+	if err != nil {
+		t.Fatalf("RequeueWithTransform: %v", err)
+	}
+	if result.Examined != 150 || result.Skipped != 50 || len(result.Retried) != 50 || len(result.Replaced) != 50 {
+		t.Fatalf("result = examined %d, skipped %d, retried %d, replaced %d; want 150, 50, 50, 50",
+			result.Examined, result.Skipped, len(result.Retried), len(result.Replaced))
+	}
+	if retryCalls := len(api.received("POST /api/v1/jobs/dlq/retry")); retryCalls != 2 {
+		t.Errorf("retry calls = %d, want one per batch (2)", retryCalls)
+	}
+	var req CreateJobRequest
+	api.received("POST /api/v1/jobs")[0].decode(t, &req)
+	if req.QueueName != "emails" || req.Payload["n"] != float64(20) || req.Priority == nil || *req.Priority != 5 ||
+		req.IdempotencyKey == nil || *req.IdempotencyKey != "dlq-requeue-job-2" {
+		t.Errorf("replacement = %+v, want job-2 with an edited payload and derived key", req)
+	}
+	var cancel struct {
+		Reason string `json:"reason"`
+	}
+	api.received("DELETE /api/v1/jobs/job-2")[0].decode(t, &cancel)
+	if id := result.Replaced["job-2"]; id == "" || cancel.Reason != "requeued from the DLQ as job "+id {
+		t.Errorf("job-2 replaced by %q, cancelled with %q", id, cancel.Reason)
+	}
+
+	// Only the skipped jobs remain, streamed page by page
+	limit := 7
+	jobs, errs := dlq.Stream(ctx, &ListDLQParams{Limit: &limit})
+	count := 0
+	for job := range jobs {
+		if int(job.Payload["n"].(float64))%3 != 0 {
+			t.Errorf("streamed %s, which was requeued", job.ID)
+		}
+		count++
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if count != 50 {
+		t.Errorf("streamed %d jobs, want 50", count)
+	}
+}
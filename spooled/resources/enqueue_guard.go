@@ -0,0 +1,211 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// GuardAction is what an EnqueueGuard does when a queue is over its limit.
+type GuardAction string
+
+const (
+	// GuardReject fails the enqueue with a *QueueOverloadedError.
+	GuardReject GuardAction = "reject"
+	// GuardDelay waits for the queue to drain below the limit, up to MaxDelay.
+	GuardDelay GuardAction = "delay"
+	// GuardSpill hands the job to GuardConfig.Spill instead of enqueueing it.
+	GuardSpill GuardAction = "spill"
+)
+
+// Default enqueue guard settings.
+const (
+	DefaultGuardSampleInterval = 5 * time.Second
+	DefaultGuardMaxDelay       = 30 * time.Second
+)
+
+// GuardConfig configures producer-side protection against runaway queues.
+type GuardConfig struct {
+	// MaxPendingPerQueue is the pending job count at which a queue is considered overloaded.
+	MaxPendingPerQueue int
+	// Action is taken when a queue is overloaded (default: GuardReject).
+	Action GuardAction
+	// SampleInterval is how long a queue stats sample is reused (default: 5s).
+	SampleInterval time.Duration
+	// MaxDelay bounds how long GuardDelay waits before rejecting (default: 30s).
+	MaxDelay time.Duration
+	// Spill receives jobs that were not enqueued under GuardSpill, e.g. to write
+	// them to an outbox. Without it, GuardSpill behaves like GuardReject.
+	Spill func(ctx context.Context, req *CreateJobRequest) error
+}
+
+// QueueOverloadedError is returned when an enqueue is blocked by an EnqueueGuard.
+type QueueOverloadedError struct {
+	QueueName string
+	Pending   int
+	Limit     int
+	// Spilled is true when the job was handed to GuardConfig.Spill instead.
+	Spilled bool
+}
+
+func (e *QueueOverloadedError) Error() string {
+	if e.Spilled {
+		return fmt.Sprintf("queue %q overloaded (%d pending, limit %d): job spilled", e.QueueName, e.Pending, e.Limit)
+	}
+	return fmt.Sprintf("queue %q overloaded (%d pending, limit %d)", e.QueueName, e.Pending, e.Limit)
+}
+
+// EnqueueGuard samples queue depth and blocks enqueues to overloaded queues.
+// Stats are sampled at most once per SampleInterval per queue. If sampling
+// fails the guard lets enqueues through.
+type EnqueueGuard struct {
+	queues *QueuesResource
+	cfg    GuardConfig
+
+	mu      sync.Mutex
+	samples map[string]guardSample
+}
+
+type guardSample struct {
+	pending int
+	at      time.Time
+}
+
+// NewEnqueueGuard creates an enqueue guard that reads stats from queues.
+func NewEnqueueGuard(queues *QueuesResource, cfg GuardConfig) *EnqueueGuard {
+	if cfg.Action == "" {
+		cfg.Action = GuardReject
+	}
+	if cfg.SampleInterval == 0 {
+		cfg.SampleInterval = DefaultGuardSampleInterval
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = DefaultGuardMaxDelay
+	}
+	return &EnqueueGuard{
+		queues:  queues,
+		cfg:     cfg,
+		samples: make(map[string]guardSample),
+	}
+}
+
+// Check returns nil if a job may be enqueued to queueName. Under GuardDelay it
+// blocks until the queue drains or MaxDelay passes. It does not spill; callers
+// that support spilling use Admit.
+func (g *EnqueueGuard) Check(ctx context.Context, queueName string) error {
+	if g == nil || g.cfg.MaxPendingPerQueue <= 0 {
+		return nil
+	}
+
+	pending := g.pending(ctx, queueName, false)
+	if pending < g.cfg.MaxPendingPerQueue {
+		return nil
+	}
+
+	if g.cfg.Action == GuardDelay {
+		deadline := time.NewTimer(g.cfg.MaxDelay)
+		defer deadline.Stop()
+		ticker := time.NewTicker(g.cfg.SampleInterval)
+		defer ticker.Stop()
+
+		for pending >= g.cfg.MaxPendingPerQueue {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-deadline.C:
+				return g.overloaded(queueName, pending)
+			case <-ticker.C:
+				pending = g.pending(ctx, queueName, true)
+			}
+		}
+		return nil
+	}
+
+	return g.overloaded(queueName, pending)
+}
+
+// Admit checks req against the guard. It returns spilled=true when the job
+// was handed to GuardConfig.Spill, in which case err is a *QueueOverloadedError
+// with Spilled set, or the error returned by Spill.
+func (g *EnqueueGuard) Admit(ctx context.Context, req *CreateJobRequest) (spilled bool, err error) {
+	err = g.Check(ctx, req.QueueName)
+	if err == nil || g.cfg.Action != GuardSpill || g.cfg.Spill == nil {
+		return false, err
+	}
+
+	var overloaded *QueueOverloadedError
+	if !errors.As(err, &overloaded) {
+		return false, err
+	}
+	if spillErr := g.cfg.Spill(ctx, req); spillErr != nil {
+		return true, fmt.Errorf("failed to spill job: %w", spillErr)
+	}
+	overloaded.Spilled = true
+	return true, overloaded
+}
+
+// AdmitBulk is Admit for a bulk enqueue. When spilling, every item is passed
+// to GuardConfig.Spill as a CreateJobRequest using the request defaults.
+func (g *EnqueueGuard) AdmitBulk(ctx context.Context, req *BulkEnqueueRequest) (spilled bool, err error) {
+	err = g.Check(ctx, req.QueueName)
+	if err == nil || g.cfg.Action != GuardSpill || g.cfg.Spill == nil {
+		return false, err
+	}
+
+	var overloaded *QueueOverloadedError
+	if !errors.As(err, &overloaded) {
+		return false, err
+	}
+	for i, item := range req.Jobs {
+		jobReq := &CreateJobRequest{
+			QueueName:      req.QueueName,
+			Payload:        item.Payload,
+			Priority:       item.Priority,
+			MaxRetries:     req.DefaultMaxRetries,
+			TimeoutSeconds: req.DefaultTimeoutSeconds,
+			ScheduledAt:    item.ScheduledAt,
+			IdempotencyKey: item.IdempotencyKey,
+		}
+		if jobReq.Priority == nil {
+			jobReq.Priority = req.DefaultPriority
+		}
+		if spillErr := g.cfg.Spill(ctx, jobReq); spillErr != nil {
+			return true, fmt.Errorf("failed to spill job %d: %w", i, spillErr)
+		}
+	}
+	overloaded.Spilled = true
+	return true, overloaded
+}
+
+// pending returns the sampled pending count for queueName, refreshing the
+// sample when it is stale or refresh is set.
+func (g *EnqueueGuard) pending(ctx context.Context, queueName string, refresh bool) int {
+	g.mu.Lock()
+	sample, ok := g.samples[queueName]
+	g.mu.Unlock()
+
+	if ok && !refresh && time.Since(sample.at) < g.cfg.SampleInterval {
+		return sample.pending
+	}
+
+	stats, err := g.queues.GetStats(ctx, queueName)
+	if err != nil {
+		// Fail open: a stats outage must not stop producers
+		return 0
+	}
+
+	g.mu.Lock()
+	g.samples[queueName] = guardSample{pending: stats.PendingJobs, at: time.Now()}
+	g.mu.Unlock()
+	return stats.PendingJobs
+}
+
+func (g *EnqueueGuard) overloaded(queueName string, pending int) *QueueOverloadedError {
+	return &QueueOverloadedError{
+		QueueName: queueName,
+		Pending:   pending,
+		Limit:     g.cfg.MaxPendingPerQueue,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -24,6 +25,10 @@ func NewIngestResource(transport *httpx.Transport) *IngestResource {
 }
 
 // CustomWebhookRequest is the request to ingest a custom webhook.
+//
+// Set IdempotencyKey (e.g. to the upstream event ID) when the sender may
+// redeliver: the request is then retried automatically on transient failures,
+// and redeliveries are reported as duplicates instead of creating new jobs.
 type CustomWebhookRequest struct {
 	QueueName      string         `json:"queue_name"`
 	EventType      *string        `json:"event_type,omitempty"`
@@ -36,28 +41,40 @@ type CustomWebhookRequest struct {
 type CustomWebhookResponse struct {
 	JobID   string `json:"job_id"`
 	Created bool   `json:"created"`
+	// Duplicate is true when a job with the same IdempotencyKey already
+	// existed; JobID is that job's ID when the server reports it.
+	Duplicate bool `json:"duplicate"`
 }
 
 // Custom ingests a custom webhook for an organization.
 func (r *IngestResource) Custom(ctx context.Context, orgID string, req *CustomWebhookRequest) (*CustomWebhookResponse, error) {
-	var result CustomWebhookResponse
-	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/webhooks/%s/custom", orgID), req, &result); err != nil {
-		return nil, err
-	}
-	return &result, nil
+	return r.custom(ctx, orgID, nil, req)
 }
 
 // CustomWithToken ingests a custom webhook using a webhook token via the X-Webhook-Token header.
 func (r *IngestResource) CustomWithToken(ctx context.Context, orgID, webhookToken string, req *CustomWebhookRequest) (*CustomWebhookResponse, error) {
-	resp, err := r.base.transport.Do(ctx, &httpx.Request{
-		Method: http.MethodPost,
-		Path:   fmt.Sprintf("/api/v1/webhooks/%s/custom", orgID),
-		Body:   req,
-		Headers: map[string]string{
-			"X-Webhook-Token": webhookToken,
-		},
+	return r.custom(ctx, orgID, map[string]string{"X-Webhook-Token": webhookToken}, req)
+}
+
+func (r *IngestResource) custom(ctx context.Context, orgID string, headers map[string]string, req *CustomWebhookRequest) (*CustomWebhookResponse, error) {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	keyed := req.IdempotencyKey != nil && *req.IdempotencyKey != ""
+
+	resp, err := r.base.do(ctx, &httpx.Request{
+		Method:  http.MethodPost,
+		Path:    fmt.Sprintf("/api/v1/webhooks/%s/custom", orgID),
+		Body:    &body,
+		Headers: headers,
+		// The server dedupes on the key, so a retry cannot create a second job
+		Idempotent: keyed,
 	})
 	if err != nil {
+		var conflict *httpx.ConflictError
+		if keyed && errors.As(err, &conflict) {
+			jobID, _ := conflict.Details["job_id"].(string)
+			return &CustomWebhookResponse{JobID: jobID, Duplicate: true}, nil
+		}
 		return nil, err
 	}
 	out, err := httpx.JSON[CustomWebhookResponse](resp)
@@ -67,6 +84,9 @@ func (r *IngestResource) CustomWithToken(ctx context.Context, orgID, webhookToke
 	if out == nil {
 		return nil, fmt.Errorf("empty response")
 	}
+	if keyed && !out.Created {
+		out.Duplicate = true
+	}
 	return out, nil
 }
 
@@ -128,7 +148,7 @@ func (r *IngestResource) GitHub(ctx context.Context, orgID string, rawBody []byt
 		headers["X-Forwarded-Proto"] = opts.ForwardedProto
 	}
 
-	_, err := r.base.transport.Do(ctx, &httpx.Request{
+	_, err := r.base.do(ctx, &httpx.Request{
 		Method:  http.MethodPost,
 		Path:    fmt.Sprintf("/api/v1/webhooks/%s/github", orgID),
 		RawBody: rawBody,
@@ -168,7 +188,7 @@ func (r *IngestResource) Stripe(ctx context.Context, orgID string, rawBody []byt
 		headers["X-Forwarded-Proto"] = opts.ForwardedProto
 	}
 
-	_, err := r.base.transport.Do(ctx, &httpx.Request{
+	_, err := r.base.do(ctx, &httpx.Request{
 		Method:  http.MethodPost,
 		Path:    fmt.Sprintf("/api/v1/webhooks/%s/stripe", orgID),
 		RawBody: rawBody,
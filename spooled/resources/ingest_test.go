@@ -0,0 +1,45 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+func TestIngest_Custom_Duplicate(t *testing.T) {
+	var calls atomic.Int32
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/webhooks/org-1/custom", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Transient failure after the job was created
+			writeTestJSON(w, http.StatusServiceUnavailable, nil)
+			return
+		}
+		writeTestJSON(w, http.StatusConflict, map[string]any{"code": "duplicate", "details": map[string]any{"job_id": "job-1"}})
+	})
+	transport := httpx.NewTransport(httpx.Config{
+		BaseURL: api.URL,
+		APIKey:  testAPIKey,
+		Retry:   httpx.RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1},
+	})
+
+	key := "evt_123"
+	resp, err := NewIngestResource(transport).Custom(context.Background(), "org-1", &CustomWebhookRequest{
+		QueueName:      "events",
+		Payload:        map[string]any{"id": "evt_123"},
+		IdempotencyKey: &key,
+	})
+	if err != nil {
+		t.Fatalf("Custom: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+	if !resp.Duplicate || resp.JobID != "job-1" {
+		t.Errorf("response = %+v, want duplicate of job-1", resp)
+	}
+}
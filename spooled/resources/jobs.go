@@ -2,27 +2,63 @@ package resources
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
 )
 
 // JobsResource provides access to job operations.
 type JobsResource struct {
-	base *Base
-	dlq  *DLQResource
+	base       *Base
+	dlq        *DLQResource
+	guard      *EnqueueGuard
+	propagator propagation.Propagator
+	schemas    *SchemaRegistry
+	policies   *PolicyRegistry
+	spool      *OfflineSpool
+	watcher    JobWatcher
+	// maxPayloadBytes limits the JSON-encoded size of job payloads (0: no limit).
+	maxPayloadBytes int
+	transforms      *PayloadTransforms
 }
 
 // NewJobsResource creates a new JobsResource.
 func NewJobsResource(transport *httpx.Transport) *JobsResource {
-	base := NewBase(transport)
-	return &JobsResource{
-		base: base,
-		dlq:  &DLQResource{base: base},
-	}
+	r := &JobsResource{base: NewBase(transport)}
+	r.dlq = &DLQResource{base: r.base, jobs: r}
+	return r
+}
+
+// SetEnqueueGuard installs a guard checked before Create and BulkEnqueue.
+// Passing nil removes it.
+func (r *JobsResource) SetEnqueueGuard(guard *EnqueueGuard) {
+	r.guard = guard
+}
+
+// SetTracePropagator makes Create inject the caller's trace context into the
+// job's tags. Passing nil disables propagation.
+func (r *JobsResource) SetTracePropagator(p propagation.Propagator) {
+	r.propagator = p
+}
+
+// SetMaxPayloadBytes makes Create and BulkEnqueue reject payloads larger than
+// n bytes when encoded as JSON with a *httpx.PayloadTooLargeError, before
+// anything is sent. Zero removes the limit.
+func (r *JobsResource) SetMaxPayloadBytes(n int) {
+	r.maxPayloadBytes = n
+}
+
+// SetProtection guards DLQ purges with protection and audits them. Passing
+// nil removes it.
+func (r *JobsResource) SetProtection(protection *Protection) {
+	r.dlq.protection = protection
 }
 
 // DLQ returns the Dead Letter Queue resource.
@@ -72,10 +108,23 @@ type Job struct {
 	WorkflowID        *string        `json:"workflow_id,omitempty"`
 	DependencyMode    *string        `json:"dependency_mode,omitempty"`
 	DependenciesMet   *bool          `json:"dependencies_met,omitempty"`
+	RunbookURL        *string        `json:"runbook_url,omitempty"`
+	OwnerTeam         *string        `json:"owner_team,omitempty"`
+	// CancellationReason and CancelledBy are recorded by CancelWithReason.
+	CancellationReason *string `json:"cancellation_reason,omitempty"`
+	CancelledBy        *string `json:"cancelled_by,omitempty"`
 }
 
-// CreateJobRequest is the request to create a new job.
+// CreateJobRequest is the request to create a new job. A CompletionWebhook
+// overrides the queue's default set with QueuesResource.SetCompletionWebhook.
+// RunbookURL and OwnerTeam tell whoever triages a failure where the runbook is
+// and who owns the job; they default to the queue's and are carried into DLQ
+// entries and webhook payloads.
 type CreateJobRequest struct {
+	// ID sets the job's ID instead of letting the server assign one, so it
+	// can be referenced before Create returns (see DeterministicID). Servers
+	// without client-provided IDs assign their own; use CreateJobResponse.ID.
+	ID                *string        `json:"id,omitempty"`
 	QueueName         string         `json:"queue_name"`
 	Payload           map[string]any `json:"payload"`
 	Priority          *int           `json:"priority,omitempty"`
@@ -87,18 +136,95 @@ type CreateJobRequest struct {
 	Tags              map[string]any `json:"tags,omitempty"`
 	ParentJobID       *string        `json:"parent_job_id,omitempty"`
 	CompletionWebhook *string        `json:"completion_webhook,omitempty"`
+	// CompletionWebhookSigning signs this job's completion webhook deliveries,
+	// overriding the queue's signing.
+	CompletionWebhookSigning *WebhookSigning `json:"completion_webhook_signing,omitempty"`
+	RunbookURL               *string         `json:"runbook_url,omitempty"`
+	OwnerTeam                *string         `json:"owner_team,omitempty"`
+	// ResultTTLSeconds is how long the job's result is kept after it
+	// finishes, overriding the queue's RetentionPolicy.ResultTTL.
+	ResultTTLSeconds *int `json:"result_ttl_seconds,omitempty"`
+	// RetryBackoff overrides the queue's retry backoff for this job.
+	RetryBackoff *Backoff `json:"retry_backoff,omitempty"`
+	// Delay schedules the job relative to now when ScheduledAt is nil. It is
+	// converted using the server's clock, so local clock skew does not shift it.
+	Delay time.Duration `json:"-"`
 }
 
 // CreateJobResponse is the response from creating a job.
 type CreateJobResponse struct {
 	ID      string `json:"id"`
 	Created bool   `json:"created"`
+	// Spooled is set when the API was unreachable and the job was stored in
+	// the offline spool for replay; ID is empty unless the request set one.
+	Spooled bool `json:"-"`
 }
 
 // Create creates a new job.
 func (r *JobsResource) Create(ctx context.Context, req *CreateJobRequest) (*CreateJobResponse, error) {
+	if req.ID != nil && *req.ID == "" {
+		return nil, fmt.Errorf("job ID must not be empty")
+	}
+	if err := req.CompletionWebhookSigning.validate(); err != nil {
+		return nil, err
+	}
+	req = r.policies.Apply(r.transformOutbound(req))
+	if err := httpx.CheckPayloadSize(req.Payload, r.maxPayloadBytes); err != nil {
+		return nil, err
+	}
+	if err := r.validatePayload(ctx, req.Payload); err != nil {
+		return nil, err
+	}
+	if r.guard != nil {
+		if _, err := r.guard.Admit(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
 	var result CreateJobResponse
-	if err := r.base.Post(ctx, "/api/v1/jobs", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	if r.propagator != nil {
+		body.Tags = propagation.InjectTags(ctx, r.propagator, body.Tags)
+	}
+	if body.ScheduledAt == nil && body.Delay > 0 {
+		scheduledAt := r.base.transport.Now().Add(body.Delay)
+		body.ScheduledAt = &scheduledAt
+	}
+	if body.ID != nil && body.IdempotencyKey == nil {
+		// Retries are then deduplicated even by servers that ignore the ID
+		key := "id:" + *body.ID
+		body.IdempotencyKey = &key
+	}
+	if r.spool != nil {
+		// A key makes the request safe to retry and to replay from the spool
+		if body.IdempotencyKey == nil {
+			key := "offline:" + newBatchID()
+			body.IdempotencyKey = &key
+		}
+		err := r.base.postPayload(ctx, "/api/v1/jobs", &body, &result, true)
+		if err != nil && isUnreachable(err) {
+			if spoolErr := r.spool.add(&spoolEntry{Create: &body, SpooledAt: time.Now()}); spoolErr != nil {
+				return nil, errors.Join(err, spoolErr)
+			}
+			spooled := &CreateJobResponse{Spooled: true}
+			if body.ID != nil {
+				spooled.ID = *body.ID
+			}
+			return spooled, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	if body.ID != nil {
+		if err := r.base.postPayload(ctx, "/api/v1/jobs", &body, &result, true); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	if err := r.base.postPayload(ctx, "/api/v1/jobs", &body, &result, false); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -119,6 +245,7 @@ func (r *JobsResource) Get(ctx context.Context, id string) (*Job, error) {
 	if err := r.base.Get(ctx, fmt.Sprintf("/api/v1/jobs/%s", id), &result); err != nil {
 		return nil, err
 	}
+	result.Payload = r.transforms.ApplyInbound(result.Payload)
 	return &result, nil
 }
 
@@ -129,29 +256,220 @@ type ListJobsParams struct {
 	Tag       *string    `json:"tag,omitempty"` // Filter by a single tag
 	Limit     *int       `json:"limit,omitempty"`
 	Offset    *int       `json:"offset,omitempty"`
+	// Cursor resumes listing after a previous page's NextCursor. When set,
+	// Offset is ignored by servers that support cursors.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// NextCursorHeader carries the next page cursor when the list body is a bare array.
+const NextCursorHeader = "X-Next-Cursor"
+
+// DefaultListPageSize is the page size used by ListAll when Limit is unset.
+const DefaultListPageSize = 100
+
+// JobPage is a page of jobs.
+type JobPage struct {
+	Jobs []Job `json:"jobs"`
+	// NextCursor is the cursor for the next page, or "" if the server does not
+	// support cursors or there are no more pages.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // List retrieves a list of jobs.
 func (r *JobsResource) List(ctx context.Context, params *ListJobsParams) ([]Job, error) {
+	page, err := r.ListPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return page.Jobs, nil
+}
+
+// ListStream lists jobs like List, but decodes the response incrementally and
+// sends each job on the returned channel as it arrives, instead of buffering
+// the whole list. Use it with a large Limit to export hundreds of thousands of
+// jobs with flat memory. Both channels are closed when the list ends; the
+// error channel receives at most one error. Cancel ctx to stop early.
+//
+//	jobs, errs := client.Jobs().ListStream(ctx, &resources.ListJobsParams{Limit: &limit})
+//	for job := range jobs {
+//		export(job)
+//	}
+//	if err := <-errs; err != nil { ... }
+func (r *JobsResource) ListStream(ctx context.Context, params *ListJobsParams) (<-chan Job, <-chan error) {
+	return streamList(ctx, r.base, "/api/v1/jobs", r.listQuery(ctx, params), "jobs", func(job *Job) {
+		job.Payload = r.transforms.ApplyInbound(job.Payload)
+	})
+}
+
+// ListPage retrieves a page of jobs along with the cursor for the next page.
+func (r *JobsResource) ListPage(ctx context.Context, params *ListJobsParams) (*JobPage, error) {
+	return r.listPage(ctx, r.listQuery(ctx, params))
+}
+
+// listPage retrieves a page of jobs matching query.
+func (r *JobsResource) listPage(ctx context.Context, query url.Values) (*JobPage, error) {
+	resp, err := r.base.getRaw(ctx, "/api/v1/jobs", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := decodeResponse(resp, &decoded); err != nil {
+		return nil, err
+	}
+
+	// Servers without cursor support return a bare array
+	var page JobPage
+	target := any(&page)
+	if _, isArray := decoded.([]any); isArray {
+		target = &page.Jobs
+	}
+	if resp.LegacyFieldNames() {
+		httpx.NormalizeFieldNames(decoded, target)
+	}
+	if err := remarshal(decoded, target, resp.UsesNumber()); err != nil {
+		return nil, err
+	}
+	if page.NextCursor == "" {
+		page.NextCursor = resp.Headers.Get(NextCursorHeader)
+	}
+	r.transformInbound(page.Jobs)
+	return &page, nil
+}
+
+// listQuery builds the query parameters for listing jobs.
+func (r *JobsResource) listQuery(ctx context.Context, params *ListJobsParams) url.Values {
 	query := url.Values{}
+	if params == nil {
+		return query
+	}
+	if params.QueueName != nil {
+		query.Set("queue_name", r.base.queueName(ctx, *params.QueueName))
+	}
+	if params.Status != nil {
+		query.Set("status", string(*params.Status))
+	}
+	if params.Tag != nil {
+		query.Set("tag", *params.Tag)
+	}
+	AddPaginationParams(query, params.Limit, params.Offset)
+	if params.Cursor != nil {
+		query.Set("cursor", *params.Cursor)
+	}
+	return query
+}
+
+// JobIterator walks every job matching a ListJobsParams, page by page.
+// It follows cursors when the server provides them and falls back to offsets
+// otherwise.
+//
+//	it := client.Jobs().ListAll(ctx, &resources.ListJobsParams{Status: &status})
+//	for it.Next() {
+//		export(it.Job())
+//	}
+//	if err := it.Err(); err != nil { ... }
+type JobIterator struct {
+	r      *JobsResource
+	ctx    context.Context
+	params ListJobsParams
+
+	page   []Job
+	index  int
+	cursor string
+	offset int
+	done   bool
+	err    error
+}
+
+// ListAll returns an iterator over all jobs matching params.
+// params.Limit sets the page size (default: DefaultListPageSize).
+func (r *JobsResource) ListAll(ctx context.Context, params *ListJobsParams) *JobIterator {
+	it := &JobIterator{r: r, ctx: ctx}
 	if params != nil {
-		if params.QueueName != nil {
-			query.Set("queue_name", *params.QueueName)
-		}
-		if params.Status != nil {
-			query.Set("status", string(*params.Status))
+		it.params = *params
+	}
+	if it.params.Limit == nil {
+		limit := DefaultListPageSize
+		it.params.Limit = &limit
+	}
+	if it.params.Cursor != nil {
+		it.cursor = *it.params.Cursor
+	}
+	if it.params.Offset != nil {
+		it.offset = *it.params.Offset
+	}
+	return it
+}
+
+// Next advances to the next job, fetching a new page when needed.
+// It returns false when there are no more jobs or an error occurred.
+func (it *JobIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
 		}
-		if params.Tag != nil {
-			query.Set("tag", *params.Tag)
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
 		}
-		AddPaginationParams(query, params.Limit, params.Offset)
 	}
+	return true
+}
 
-	var result []Job
-	if err := r.base.GetWithQuery(ctx, "/api/v1/jobs", query, &result); err != nil {
-		return nil, err
+// Job returns the current job.
+func (it *JobIterator) Job() *Job {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
 	}
-	return result, nil
+	return &it.page[it.index]
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *JobIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the cursor of the next page, which can be saved to resume
+// a long export. It is "" when the server does not support cursors.
+func (it *JobIterator) Cursor() string {
+	return it.cursor
+}
+
+// fetch loads the next page.
+func (it *JobIterator) fetch() error {
+	params := it.params
+	if it.cursor != "" {
+		cursor := it.cursor
+		params.Cursor = &cursor
+		params.Offset = nil
+	} else {
+		offset := it.offset
+		params.Cursor = nil
+		params.Offset = &offset
+	}
+
+	page, err := it.r.ListPage(it.ctx, &params)
+	if err != nil {
+		return err
+	}
+
+	it.page = page.Jobs
+	it.index = 0
+	it.offset += len(page.Jobs)
+	usedCursor := it.cursor != ""
+	it.cursor = page.NextCursor
+
+	switch {
+	case page.NextCursor != "":
+		// More pages are available via the cursor
+	case usedCursor, len(page.Jobs) < *params.Limit:
+		it.done = true
+	}
+	return nil
 }
 
 // Cancel cancels a job.
@@ -159,6 +477,23 @@ func (r *JobsResource) Cancel(ctx context.Context, id string) error {
 	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/jobs/%s", id))
 }
 
+// cancelJobRequest records why a job was cancelled.
+type cancelJobRequest struct {
+	Reason string `json:"reason"`
+	Actor  string `json:"actor,omitempty"`
+}
+
+// CancelWithReason cancels a job and records why and by whom (actor, e.g. a
+// user or automation name; optional). Both are stored on the job as
+// CancellationReason and CancelledBy and included in job.cancelled webhook
+// and realtime events, so manual interventions can be audited.
+func (r *JobsResource) CancelWithReason(ctx context.Context, id, reason, actor string) error {
+	if reason == "" {
+		return fmt.Errorf("cancellation reason is required")
+	}
+	return r.base.DeleteWithBody(ctx, fmt.Sprintf("/api/v1/jobs/%s", id), &cancelJobRequest{Reason: reason, Actor: actor}, nil)
+}
+
 // Retry retries a failed job.
 func (r *JobsResource) Retry(ctx context.Context, id string) (*Job, error) {
 	var result Job
@@ -190,6 +525,35 @@ func (r *JobsResource) BoostPriority(ctx context.Context, id string, req *BoostP
 	return &result, nil
 }
 
+// ExtendTimeoutRequest is the request to extend a job's execution timeout.
+type ExtendTimeoutRequest struct {
+	ExtendBySecs int `json:"extend_by_secs"`
+}
+
+// ExtendTimeoutResponse is the response from extending a job's execution timeout.
+type ExtendTimeoutResponse struct {
+	JobID          string     `json:"job_id"`
+	TimeoutSeconds int        `json:"timeout_seconds"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+}
+
+// ExtendTimeout adds extra to a running job's execution budget (TimeoutSeconds).
+// The timeout bounds total execution time and is separate from the lease:
+// renewing the lease keeps the job assigned to its worker but never lets it
+// run past the timeout. extra is rounded up to whole seconds.
+func (r *JobsResource) ExtendTimeout(ctx context.Context, id string, extra time.Duration) (*ExtendTimeoutResponse, error) {
+	if extra <= 0 {
+		return nil, fmt.Errorf("timeout extension must be positive")
+	}
+	req := &ExtendTimeoutRequest{ExtendBySecs: int((extra + time.Second - 1) / time.Second)}
+
+	var result ExtendTimeoutResponse
+	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/jobs/%s/timeout", id), req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // JobStats represents job statistics.
 type JobStats struct {
 	Pending    int `json:"pending"`
@@ -200,12 +564,78 @@ type JobStats struct {
 	Deadletter int `json:"deadletter"`
 	Cancelled  int `json:"cancelled"`
 	Total      int `json:"total"`
+
+	// Duration and throughput metrics over the stats window. Older servers
+	// omit them.
+	AvgProcessingTimeMs     *int     `json:"avg_processing_time_ms,omitempty"`
+	P95ProcessingTimeMs     *int     `json:"p95_processing_time_ms,omitempty"`
+	OldestPendingAgeSeconds *int     `json:"oldest_pending_age_seconds,omitempty"`
+	ThroughputPerMinute     *float64 `json:"throughput_per_minute,omitempty"`
+	// Groups breaks the stats down when StatsOptions.GroupBy is set.
+	Groups []StatsGroup `json:"groups,omitempty"`
+}
+
+// StatsGroupBy selects how GetStatsWithOptions breaks stats down.
+type StatsGroupBy string
+
+const (
+	StatsGroupByQueue  StatsGroupBy = "queue"
+	StatsGroupByStatus StatsGroupBy = "status"
+	StatsGroupByHour   StatsGroupBy = "hour"
+)
+
+// StatsOptions configures Jobs().GetStatsWithOptions and
+// Queues().GetStatsWithOptions.
+type StatsOptions struct {
+	// Window limits windowed counts and metrics to the trailing period,
+	// in whole seconds (default: the server's, 24 hours).
+	Window time.Duration
+	// GroupBy adds a per-queue, per-status, or per-hour breakdown.
+	GroupBy StatsGroupBy
+}
+
+// StatsGroup is the stats of one group in a GroupBy breakdown.
+type StatsGroup struct {
+	// Key is the queue name, the job status, or the start of the hour
+	// (RFC 3339), depending on GroupBy.
+	Key                     string   `json:"key"`
+	Pending                 int      `json:"pending"`
+	Processing              int      `json:"processing"`
+	Completed               int      `json:"completed"`
+	Failed                  int      `json:"failed"`
+	Total                   int      `json:"total"`
+	AvgProcessingTimeMs     *int     `json:"avg_processing_time_ms,omitempty"`
+	P95ProcessingTimeMs     *int     `json:"p95_processing_time_ms,omitempty"`
+	OldestPendingAgeSeconds *int     `json:"oldest_pending_age_seconds,omitempty"`
+	ThroughputPerMinute     *float64 `json:"throughput_per_minute,omitempty"`
+}
+
+// query returns the query parameters for opts.
+func (o *StatsOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Window > 0 {
+		query.Set("window_seconds", strconv.Itoa(int(o.Window/time.Second)))
+	}
+	if o.GroupBy != "" {
+		query.Set("group_by", string(o.GroupBy))
+	}
+	return query
 }
 
 // GetStats retrieves job statistics.
 func (r *JobsResource) GetStats(ctx context.Context) (*JobStats, error) {
+	return r.GetStatsWithOptions(ctx, nil)
+}
+
+// GetStatsWithOptions retrieves job statistics over a time window, optionally
+// broken down by queue, status, or hour, with the processing duration and
+// throughput metrics shown on the dashboard.
+func (r *JobsResource) GetStatsWithOptions(ctx context.Context, opts *StatsOptions) (*JobStats, error) {
 	var result JobStats
-	if err := r.base.Get(ctx, "/api/v1/jobs/stats", &result); err != nil {
+	if err := r.base.GetWithQuery(ctx, "/api/v1/jobs/stats", opts.query(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -242,6 +672,9 @@ type BulkJobItem struct {
 	Priority       *int           `json:"priority,omitempty"`
 	IdempotencyKey *string        `json:"idempotency_key,omitempty"`
 	ScheduledAt    *time.Time     `json:"scheduled_at,omitempty"`
+	// Delay schedules the job relative to now when ScheduledAt is nil, as
+	// CreateJobRequest.Delay does.
+	Delay time.Duration `json:"-"`
 }
 
 // BulkEnqueueRequest is the request to bulk enqueue jobs.
@@ -251,6 +684,16 @@ type BulkEnqueueRequest struct {
 	DefaultPriority       *int          `json:"default_priority,omitempty"`
 	DefaultMaxRetries     *int          `json:"default_max_retries,omitempty"`
 	DefaultTimeoutSeconds *int          `json:"default_timeout_seconds,omitempty"`
+	// FailOnPartial makes BulkEnqueue return a *BulkPartialError alongside the
+	// response when any job fails. Not sent to the API.
+	FailOnPartial bool `json:"-"`
+	// Idempotent gives every job without an IdempotencyKey one derived from
+	// BatchID and its index, and lets the SDK retry the request after network
+	// failures without enqueuing the batch twice. Not sent to the API.
+	Idempotent bool `json:"-"`
+	// BatchID seeds the derived keys; a random ID is generated if empty. Pass
+	// BulkEnqueueResponse.BatchID to resend the same batch. Not sent to the API.
+	BatchID string `json:"-"`
 }
 
 // BulkJobSuccess represents a successfully enqueued job.
@@ -260,10 +703,76 @@ type BulkJobSuccess struct {
 	Created bool   `json:"created"`
 }
 
+// BulkFailureCode classifies why a job in a bulk enqueue failed.
+type BulkFailureCode string
+
+const (
+	BulkFailureDuplicate     BulkFailureCode = "duplicate"
+	BulkFailureValidation    BulkFailureCode = "validation"
+	BulkFailureQueuePaused   BulkFailureCode = "queue_paused"
+	BulkFailureRateLimited   BulkFailureCode = "rate_limited"
+	BulkFailureQuotaExceeded BulkFailureCode = "quota_exceeded"
+	BulkFailureInternal      BulkFailureCode = "internal"
+	BulkFailureUnknown       BulkFailureCode = "unknown"
+)
+
+// Retryable reports whether retrying a job that failed with this code may succeed.
+func (c BulkFailureCode) Retryable() bool {
+	switch c {
+	case BulkFailureDuplicate, BulkFailureValidation:
+		return false
+	default:
+		return true
+	}
+}
+
 // BulkJobFailure represents a failed job in bulk enqueue.
 type BulkJobFailure struct {
 	Index int    `json:"index"`
 	Error string `json:"error"`
+	// Code is the failure classification. Older servers omit it; BulkEnqueue
+	// then derives it from Error.
+	Code BulkFailureCode `json:"code,omitempty"`
+}
+
+// classifyBulkFailure derives a failure code from an error message.
+func classifyBulkFailure(msg string) BulkFailureCode {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "duplicate"), strings.Contains(lower, "idempotency"), strings.Contains(lower, "already exists"):
+		return BulkFailureDuplicate
+	case strings.Contains(lower, "paused"):
+		return BulkFailureQueuePaused
+	case strings.Contains(lower, "rate limit"), strings.Contains(lower, "too many"):
+		return BulkFailureRateLimited
+	case strings.Contains(lower, "quota"), strings.Contains(lower, "limit exceeded"):
+		return BulkFailureQuotaExceeded
+	case strings.Contains(lower, "invalid"), strings.Contains(lower, "validation"), strings.Contains(lower, "required"), strings.Contains(lower, "too large"):
+		return BulkFailureValidation
+	case strings.Contains(lower, "internal"), strings.Contains(lower, "database"):
+		return BulkFailureInternal
+	default:
+		return BulkFailureUnknown
+	}
+}
+
+// BulkPartialError reports the jobs that failed in a bulk enqueue.
+type BulkPartialError struct {
+	Total    int
+	Failures []BulkJobFailure
+}
+
+func (e *BulkPartialError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "bulk enqueue: %d of %d jobs failed", len(e.Failures), e.Total)
+	for i, f := range e.Failures {
+		if i == 3 {
+			fmt.Fprintf(&b, "; and %d more", len(e.Failures)-i)
+			break
+		}
+		fmt.Fprintf(&b, "; [%d] %s: %s", f.Index, f.Code, f.Error)
+	}
+	return b.String()
 }
 
 // BulkEnqueueResponse is the response from bulk enqueueing jobs.
@@ -273,26 +782,154 @@ type BulkEnqueueResponse struct {
 	Total        int              `json:"total"`
 	SuccessCount int              `json:"success_count"`
 	FailureCount int              `json:"failure_count"`
+	// BatchID is the batch ID used for Idempotent requests.
+	BatchID string `json:"-"`
+	// Spooled is set when the API was unreachable and the batch was stored in
+	// the offline spool for replay; no per-job results are available.
+	Spooled bool `json:"-"`
+}
+
+// Err returns a *BulkPartialError if any job failed, or nil.
+func (r *BulkEnqueueResponse) Err() error {
+	if r.FailureCount == 0 && len(r.Failed) == 0 {
+		return nil
+	}
+	return &BulkPartialError{Total: r.Total, Failures: r.Failed}
 }
 
 // BulkEnqueue bulk enqueues multiple jobs.
 func (r *JobsResource) BulkEnqueue(ctx context.Context, req *BulkEnqueueRequest) (*BulkEnqueueResponse, error) {
+	req = r.policies.applyBulk(r.transformOutboundBulk(req))
+	for i, job := range req.Jobs {
+		if err := httpx.CheckPayloadSize(job.Payload, r.maxPayloadBytes); err != nil {
+			return nil, fmt.Errorf("job %d: %w", i, err)
+		}
+		if err := r.validatePayload(ctx, job.Payload); err != nil {
+			return nil, fmt.Errorf("job %d: %w", i, err)
+		}
+	}
+	if r.guard != nil {
+		if _, err := r.guard.AdmitBulk(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
 	var result BulkEnqueueResponse
-	if err := r.base.Post(ctx, "/api/v1/jobs/bulk", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	idempotent := req.Idempotent || r.spool != nil
+	if idempotent && body.BatchID == "" {
+		body.BatchID = newBatchID()
+	}
+	body.Jobs = make([]BulkJobItem, len(req.Jobs))
+	for i, job := range req.Jobs {
+		if job.ScheduledAt == nil && job.Delay > 0 {
+			scheduledAt := r.base.transport.Now().Add(job.Delay)
+			job.ScheduledAt = &scheduledAt
+		}
+		if idempotent && job.IdempotencyKey == nil {
+			key := bulkItemKey(body.BatchID, i)
+			job.IdempotencyKey = &key
+		}
+		body.Jobs[i] = job
+	}
+	if idempotent {
+		if err := r.base.postPayload(ctx, "/api/v1/jobs/bulk", &body, &result, true); err != nil {
+			if r.spool == nil || !isUnreachable(err) {
+				return nil, err
+			}
+			if spoolErr := r.spool.add(&spoolEntry{Bulk: &body, SpooledAt: time.Now()}); spoolErr != nil {
+				return nil, errors.Join(err, spoolErr)
+			}
+			return &BulkEnqueueResponse{Total: len(body.Jobs), BatchID: body.BatchID, Spooled: true}, nil
+		}
+		result.BatchID = body.BatchID
+	} else if err := r.base.postPayload(ctx, "/api/v1/jobs/bulk", &body, &result, false); err != nil {
 		return nil, err
 	}
+	for i := range result.Failed {
+		if result.Failed[i].Code == "" {
+			result.Failed[i].Code = classifyBulkFailure(result.Failed[i].Error)
+		}
+	}
+	if req.FailOnPartial {
+		return &result, result.Err()
+	}
 	return &result, nil
 }
 
+// newBatchID returns a random UUID identifying an idempotent bulk request.
+func newBatchID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// bulkItemKey derives the idempotency key of the job at index in a batch.
+func bulkItemKey(batchID string, index int) string {
+	return fmt.Sprintf("bulk:%s:%d", batchID, index)
+}
+
+// RetryFailed re-enqueues the jobs from req that failed in resp and have a
+// retryable failure code. Indices in the returned response refer to req.Jobs,
+// so it can be merged with resp. Returns an empty response if nothing is retryable.
+func (r *JobsResource) RetryFailed(ctx context.Context, req *BulkEnqueueRequest, resp *BulkEnqueueResponse) (*BulkEnqueueResponse, error) {
+	retry := *req
+	retry.Jobs = nil
+	var indices []int
+	for _, f := range resp.Failed {
+		if !f.Code.Retryable() || f.Index < 0 || f.Index >= len(req.Jobs) {
+			continue
+		}
+		job := req.Jobs[f.Index]
+		// Keep the key the job had in the original batch, not one derived from
+		// its position in the retry
+		if req.Idempotent && job.IdempotencyKey == nil && resp.BatchID != "" {
+			key := bulkItemKey(resp.BatchID, f.Index)
+			job.IdempotencyKey = &key
+		}
+		retry.Jobs = append(retry.Jobs, job)
+		indices = append(indices, f.Index)
+	}
+	if len(retry.Jobs) == 0 {
+		return &BulkEnqueueResponse{}, nil
+	}
+
+	result, err := r.BulkEnqueue(ctx, &retry)
+	if result == nil {
+		return nil, err
+	}
+	for i := range result.Succeeded {
+		if idx := result.Succeeded[i].Index; idx >= 0 && idx < len(indices) {
+			result.Succeeded[i].Index = indices[idx]
+		}
+	}
+	for i := range result.Failed {
+		if idx := result.Failed[i].Index; idx >= 0 && idx < len(indices) {
+			result.Failed[i].Index = indices[idx]
+		}
+	}
+	return result, err
+}
+
 // ClaimJobsRequest is the request to claim jobs.
 type ClaimJobsRequest struct {
 	QueueName        string `json:"queue_name"`
 	WorkerID         string `json:"worker_id"`
 	Limit            *int   `json:"limit,omitempty"`
 	LeaseDurationSec *int   `json:"lease_duration_secs,omitempty"`
+	// CanaryFraction asks for only jobs in the canary sample; see
+	// worker.Options.CanaryFraction.
+	CanaryFraction *float64 `json:"canary_fraction,omitempty"`
 }
 
 // ClaimedJob is a job that has been claimed by a worker.
+//
+// TimeoutSeconds is the total execution budget for the attempt. The lease
+// (LeaseExpiresAt) only keeps the job assigned to the worker and is renewed by
+// heartbeats; renewing it does not extend the timeout. Use ExtendTimeout for that.
 type ClaimedJob struct {
 	ID             string         `json:"id"`
 	QueueName      string         `json:"queue_name"`
@@ -301,6 +938,7 @@ type ClaimedJob struct {
 	MaxRetries     int            `json:"max_retries"`
 	TimeoutSeconds int            `json:"timeout_seconds"`
 	LeaseExpiresAt *time.Time     `json:"lease_expires_at,omitempty"`
+	Tags           map[string]any `json:"tags,omitempty"`
 }
 
 // ClaimJobsResponse is the response from claiming jobs.
@@ -311,9 +949,14 @@ type ClaimJobsResponse struct {
 // Claim claims jobs for a worker.
 func (r *JobsResource) Claim(ctx context.Context, req *ClaimJobsRequest) (*ClaimJobsResponse, error) {
 	var result ClaimJobsResponse
-	if err := r.base.Post(ctx, "/api/v1/jobs/claim", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	if err := r.base.Post(ctx, "/api/v1/jobs/claim", &body, &result); err != nil {
 		return nil, err
 	}
+	for i := range result.Jobs {
+		result.Jobs[i].Payload = r.transforms.ApplyInbound(result.Jobs[i].Payload)
+	}
 	return &result, nil
 }
 
@@ -389,12 +1032,16 @@ func (r *JobsResource) UpdateProgress(ctx context.Context, id string, req *Updat
 
 // DLQResource provides access to Dead Letter Queue operations.
 type DLQResource struct {
-	base *Base
+	base       *Base
+	protection *Protection
+	// jobs creates and cancels jobs for RequeueWithTransform.
+	jobs *JobsResource
 }
 
 // ListDLQParams are parameters for listing DLQ jobs.
 type ListDLQParams struct {
 	QueueName *string `json:"queue_name,omitempty"`
+	OwnerTeam *string `json:"owner_team,omitempty"`
 	Limit     *int    `json:"limit,omitempty"`
 	Offset    *int    `json:"offset,omitempty"`
 }
@@ -404,7 +1051,10 @@ func (r *DLQResource) List(ctx context.Context, params *ListDLQParams) ([]Job, e
 	query := url.Values{}
 	if params != nil {
 		if params.QueueName != nil {
-			query.Set("queue_name", *params.QueueName)
+			query.Set("queue_name", r.base.queueName(ctx, *params.QueueName))
+		}
+		if params.OwnerTeam != nil {
+			query.Set("owner_team", *params.OwnerTeam)
 		}
 		AddPaginationParams(query, params.Limit, params.Offset)
 	}
@@ -413,9 +1063,15 @@ func (r *DLQResource) List(ctx context.Context, params *ListDLQParams) ([]Job, e
 	if err := r.base.GetWithQuery(ctx, "/api/v1/jobs/dlq", query, &result); err != nil {
 		return nil, err
 	}
+	r.jobs.transformInbound(result)
 	return result, nil
 }
 
+// ListByOwner retrieves dead letter queue jobs owned by team, across queues.
+func (r *DLQResource) ListByOwner(ctx context.Context, team string) ([]Job, error) {
+	return r.List(ctx, &ListDLQParams{OwnerTeam: &team})
+}
+
 // RetryDLQRequest is the request to retry DLQ jobs.
 type RetryDLQRequest struct {
 	QueueName *string  `json:"queue_name,omitempty"`
@@ -431,7 +1087,9 @@ type RetryDLQResponse struct {
 // Retry retries jobs in the dead letter queue.
 func (r *DLQResource) Retry(ctx context.Context, req *RetryDLQRequest) (*RetryDLQResponse, error) {
 	var result RetryDLQResponse
-	if err := r.base.Post(ctx, "/api/v1/jobs/dlq/retry", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueNamePtr(ctx, req.QueueName)
+	if err := r.base.Post(ctx, "/api/v1/jobs/dlq/retry", &body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -440,19 +1098,67 @@ func (r *DLQResource) Retry(ctx context.Context, req *RetryDLQRequest) (*RetryDL
 // PurgeDLQRequest is the request to purge DLQ jobs.
 type PurgeDLQRequest struct {
 	QueueName *string `json:"queue_name,omitempty"`
+	// Confirm acknowledges the purge when the client uses WithProtection.
+	// Not sent to the API.
+	Confirm bool `json:"-"`
+	// DryRun counts the jobs that would be purged without removing them.
+	// Under protection it also authorizes the same purge for DryRunValidity.
+	// Not sent to the API.
+	DryRun bool `json:"-"`
 }
 
 // PurgeDLQResponse is the response from purging DLQ jobs.
 type PurgeDLQResponse struct {
-	PurgedCount int `json:"purged_count"`
+	// PurgedCount is the number of jobs removed, or that would be for a dry run.
+	PurgedCount int  `json:"purged_count"`
+	DryRun      bool `json:"-"`
 }
 
 // Purge removes jobs from the dead letter queue.
 func (r *DLQResource) Purge(ctx context.Context, req *PurgeDLQRequest) (*PurgeDLQResponse, error) {
+	target := "*"
+	if req.QueueName != nil {
+		target = *req.QueueName
+	}
+	if req.DryRun {
+		count, err := r.count(ctx, req.QueueName)
+		r.protection.audit(OperationPurgeDLQ, target, true, false, count, err)
+		if err != nil {
+			return nil, err
+		}
+		r.protection.recordDryRun(OperationPurgeDLQ, target)
+		return &PurgeDLQResponse{PurgedCount: count, DryRun: true}, nil
+	}
+	if err := r.protection.check(OperationPurgeDLQ, target, req.Confirm); err != nil {
+		r.protection.audit(OperationPurgeDLQ, target, false, false, 0, err)
+		return nil, err
+	}
+
 	var result PurgeDLQResponse
+	body := *req
+	body.QueueName = r.base.queueNamePtr(ctx, req.QueueName)
 	// Parity with Node/Python: POST /jobs/dlq/purge
-	if err := r.base.Post(ctx, "/api/v1/jobs/dlq/purge", req, &result); err != nil {
+	err := r.base.Post(ctx, "/api/v1/jobs/dlq/purge", &body, &result)
+	r.protection.audit(OperationPurgeDLQ, target, false, req.Confirm, result.PurgedCount, err)
+	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
+
+// count pages through the DLQ to count the jobs in queueName (or all queues).
+func (r *DLQResource) count(ctx context.Context, queueName *string) (int, error) {
+	const pageSize = 1000
+	total := 0
+	for {
+		limit, offset := pageSize, total
+		jobs, err := r.List(ctx, &ListDLQParams{QueueName: queueName, Limit: &limit, Offset: &offset})
+		if err != nil {
+			return 0, err
+		}
+		total += len(jobs)
+		if len(jobs) < pageSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DeterministicIDNamespace is the UUID namespace DeterministicID derives
+// name-based (version 5) UUIDs in.
+const DeterministicIDNamespace = "8a1f3e52-6c0d-4b7e-9f21-5d4c3b2a1e0f"
+
+// DeterministicID derives a job ID from a payload, for CreateJobRequest.ID:
+// the same payload always yields the same ID, so a producer can record the ID
+// before creating the job and retries cannot create a second one. Payloads are
+// encoded as JSON with sorted keys, so map order does not matter.
+//
+// Job IDs are unique across queues. Pass scope values such as the queue name
+// or a tenant ID to tell apart otherwise identical payloads:
+//
+//	id, err := resources.DeterministicID(payload, "invoices")
+func DeterministicID(payload map[string]any, scope ...string) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("encode payload for deterministic ID: %w", err)
+	}
+	name := strings.Join(scope, "\x00") + "\x00" + string(data)
+	return uuidV5(DeterministicIDNamespace, name), nil
+}
+
+// uuidV5 returns the RFC 4122 name-based UUID of name in namespace.
+func uuidV5(namespace, name string) string {
+	ns, _ := hex.DecodeString(strings.ReplaceAll(namespace, "-", ""))
+	h := sha1.New()
+	h.Write(ns)
+	h.Write([]byte(name))
+	b := h.Sum(nil)[:16]
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
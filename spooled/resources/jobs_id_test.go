@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+func TestJobs_Create_ClientProvidedID(t *testing.T) {
+	id, err := DeterministicID(map[string]any{"order": 42, "items": []any{"a", "b"}}, "invoices")
+	if err != nil {
+		t.Fatalf("DeterministicID: %v", err)
+	}
+	same, _ := DeterministicID(map[string]any{"items": []any{"a", "b"}, "order": 42}, "invoices")
+	otherScope, _ := DeterministicID(map[string]any{"order": 42, "items": []any{"a", "b"}}, "refunds")
+	if id != same || id == otherScope {
+		t.Errorf("DeterministicID = %s, reordered %s, other scope %s", id, same, otherScope)
+	}
+	if len(id) != 36 || id[14] != '5' || !strings.ContainsAny(id[19:20], "89ab") {
+		t.Errorf("DeterministicID = %s, want a version 5 UUID", id)
+	}
+	if _, err := DeterministicID(map[string]any{"f": func() {}}); err == nil {
+		t.Error("DeterministicID with unencodable payload succeeded")
+	}
+
+	var attempts atomic.Int32
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			writeTestJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "unavailable"})
+			return
+		}
+		writeTestJSON(w, http.StatusOK, map[string]any{"id": id, "created": true})
+	})
+	transport := httpx.NewTransport(httpx.Config{
+		BaseURL: api.URL,
+		APIKey:  testAPIKey,
+		Retry:   httpx.RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1},
+	})
+	jobs := NewJobsResource(transport)
+	ctx := context.Background()
+
+	resp, err := jobs.Create(ctx, &CreateJobRequest{
+		ID:        &id,
+		QueueName: "invoices",
+		Payload:   map[string]any{"order": 42},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if resp.ID != id || attempts.Load() != 2 {
+		t.Errorf("Create = %+v after %d attempts, want ID %s after a retry", resp, attempts.Load(), id)
+	}
+	var body map[string]any
+	api.received("POST /api/v1/jobs")[1].decode(t, &body)
+	if body["id"] != id || body["idempotency_key"] != "id:"+id {
+		t.Errorf("body = %v, want id and derived idempotency key", body)
+	}
+
+	empty := ""
+	if _, err := jobs.Create(ctx, &CreateJobRequest{ID: &empty, QueueName: "invoices"}); err == nil {
+		t.Error("Create with empty ID succeeded")
+	}
+}
@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrIdempotencyKeyRequired is returned by CreateOrGetResult for requests
+// without an IdempotencyKey or ID.
+var ErrIdempotencyKeyRequired = errors.New("request has no idempotency key or job ID")
+
+// CreateOrGetResultResponse is the outcome of CreateOrGetResult.
+type CreateOrGetResultResponse struct {
+	JobID string
+	// Created is set when this call created the job.
+	Created bool
+	// Spooled is set when the API was unreachable and the job was stored in
+	// the offline spool for replay.
+	Spooled bool
+	// Job is the job that already existed for the key; nil when Created.
+	Job *Job
+	// Completed is set when the existing job has completed, and Result holds
+	// its result.
+	Completed bool
+	Result    map[string]any
+}
+
+// CreateOrGetResult creates a job, or returns the one already created with the
+// same idempotency key, so that a retried upstream request, such as a
+// redelivered webhook, is handled exactly once in a single call:
+//
+//	resp, err := client.Jobs().CreateOrGetResult(ctx, &resources.CreateJobRequest{
+//		QueueName:      "payments",
+//		Payload:        payload,
+//		IdempotencyKey: &deliveryID,
+//	})
+//	switch {
+//	case err != nil:
+//		return err
+//	case resp.Completed:
+//		reply(resp.Result) // handled before; reply with the stored result
+//	default:
+//		accept(resp.JobID) // created now, or still in progress
+//	}
+//
+// The request must set IdempotencyKey or ID. If the existing job failed, was
+// dead-lettered, or was cancelled, the response is returned with an error
+// wrapping ErrJobNotCompleted. Use WaitForCompletion to wait for a job still
+// in progress.
+func (r *JobsResource) CreateOrGetResult(ctx context.Context, req *CreateJobRequest) (*CreateOrGetResultResponse, error) {
+	if (req.IdempotencyKey == nil || *req.IdempotencyKey == "") && req.ID == nil {
+		return nil, ErrIdempotencyKeyRequired
+	}
+
+	created, err := r.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &CreateOrGetResultResponse{JobID: created.ID, Created: created.Created, Spooled: created.Spooled}
+	if created.Created || created.Spooled {
+		return resp, nil
+	}
+
+	job, err := r.Get(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Job = job
+	switch {
+	case job.Status == JobStatusCompleted:
+		resp.Completed = true
+		resp.Result = job.Result
+	case job.Status.IsTerminal():
+		return resp, fmt.Errorf("%w: job %s is %s", ErrJobNotCompleted, job.ID, job.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestJobs_CreateOrGetResult(t *testing.T) {
+	var mu sync.Mutex
+	keys := map[string]bool{}
+	status := "processing"
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req CreateJobRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		created := !keys[*req.IdempotencyKey]
+		keys[*req.IdempotencyKey] = true
+		writeTestJSON(w, http.StatusOK, map[string]any{"id": "job-1", "created": created})
+	})
+	api.handle("GET /api/v1/jobs/job-1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeTestJSON(w, http.StatusOK, map[string]any{
+			"id": "job-1", "queue_name": "payments", "status": status, "result": map[string]any{"charge": "ch_1"},
+		})
+	})
+	jobs := NewJobsResource(api.transport())
+	ctx := context.Background()
+	key := "delivery-1"
+	req := &CreateJobRequest{QueueName: "payments", Payload: map[string]any{"n": 1}, IdempotencyKey: &key}
+
+	if _, err := jobs.CreateOrGetResult(ctx, &CreateJobRequest{QueueName: "payments"}); !errors.Is(err, ErrIdempotencyKeyRequired) {
+		t.Errorf("err = %v, want ErrIdempotencyKeyRequired", err)
+	}
+
+	resp, err := jobs.CreateOrGetResult(ctx, req)
+	if err != nil || !resp.Created || resp.JobID != "job-1" || resp.Job != nil {
+		t.Fatalf("first call = %+v, %v; want the job created", resp, err)
+	}
+
+	// A retry while the job runs reports it in progress
+	resp, err = jobs.CreateOrGetResult(ctx, req)
+	if err != nil || resp.Created || resp.Completed || resp.Job == nil || resp.Job.Status != JobStatusProcessing {
+		t.Fatalf("retry = %+v, %v; want the existing job in progress", resp, err)
+	}
+
+	mu.Lock()
+	status = "completed"
+	mu.Unlock()
+	resp, err = jobs.CreateOrGetResult(ctx, req)
+	if err != nil || !resp.Completed || resp.Result["charge"] != "ch_1" {
+		t.Fatalf("retry = %+v, %v; want the stored result", resp, err)
+	}
+
+	mu.Lock()
+	status = "deadletter"
+	mu.Unlock()
+	resp, err = jobs.CreateOrGetResult(ctx, req)
+	if !errors.Is(err, ErrJobNotCompleted) || resp == nil || resp.Job.Status != JobStatusDeadletter {
+		t.Fatalf("retry = %+v, %v; want ErrJobNotCompleted with the job", resp, err)
+	}
+}
@@ -0,0 +1,86 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListScheduledParams are parameters for listing scheduled jobs.
+type ListScheduledParams struct {
+	QueueName *string
+	// DueBefore and DueAfter bound the jobs' ScheduledAt (optional).
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Limit     *int
+	Offset    *int
+}
+
+// ListScheduled lists jobs waiting for their ScheduledAt time. The due-time
+// bounds are also applied to the returned page, so servers that ignore them
+// still return only matching jobs, though pages may then be short.
+func (r *JobsResource) ListScheduled(ctx context.Context, params *ListScheduledParams) ([]Job, error) {
+	if params == nil {
+		params = &ListScheduledParams{}
+	}
+	status := JobStatusScheduled
+	query := r.listQuery(ctx, &ListJobsParams{
+		QueueName: params.QueueName,
+		Status:    &status,
+		Limit:     params.Limit,
+		Offset:    params.Offset,
+	})
+	if params.DueBefore != nil {
+		query.Set("scheduled_before", params.DueBefore.UTC().Format(time.RFC3339))
+	}
+	if params.DueAfter != nil {
+		query.Set("scheduled_after", params.DueAfter.UTC().Format(time.RFC3339))
+	}
+
+	page, err := r.listPage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	jobs := page.Jobs[:0]
+	for _, job := range page.Jobs {
+		if job.ScheduledAt != nil {
+			if params.DueBefore != nil && !job.ScheduledAt.Before(*params.DueBefore) {
+				continue
+			}
+			if params.DueAfter != nil && job.ScheduledAt.Before(*params.DueAfter) {
+				continue
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// TriggerNow makes a scheduled job due immediately, so the next claim can
+// pick it up. It fails with a 409 conflict if the job is not scheduled.
+func (r *JobsResource) TriggerNow(ctx context.Context, id string) (*Job, error) {
+	var result Job
+	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/jobs/%s/trigger", id), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// rescheduleJobRequest is the request to move a scheduled job.
+type rescheduleJobRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// Reschedule moves a scheduled job to run at t. It fails with a
+// 409 conflict if the job is not scheduled; use TriggerNow to run it
+// immediately.
+func (r *JobsResource) Reschedule(ctx context.Context, id string, t time.Time) (*Job, error) {
+	if t.IsZero() {
+		return nil, fmt.Errorf("reschedule time is required")
+	}
+	var result Job
+	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/jobs/%s/schedule", id), &rescheduleJobRequest{ScheduledAt: t.UTC()}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
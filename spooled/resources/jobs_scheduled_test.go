@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJobs_ScheduledLifecycle(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		// Ignore the due-time filters, like older servers
+		_, _ = w.Write([]byte(`[
+			{"id":"job-1","queue_name":"emails","status":"scheduled","scheduled_at":"2026-01-01T10:00:00Z"},
+			{"id":"job-2","queue_name":"emails","status":"scheduled","scheduled_at":"2026-01-01T14:00:00Z"}
+		]`))
+	})
+	api.respond("POST /api/v1/jobs/job-1/trigger", http.StatusOK, map[string]any{"id": "job-1", "queue_name": "emails", "status": "pending"})
+	api.handle("PUT /api/v1/jobs/job-2/schedule", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		writeTestJSON(w, http.StatusOK, map[string]any{"id": "job-2", "queue_name": "emails", "status": "scheduled", "scheduled_at": body["scheduled_at"]})
+	})
+	jobs := NewJobsResource(api.transport())
+	ctx := context.Background()
+
+	queue := "emails"
+	dueBefore := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduled, err := jobs.ListScheduled(ctx, &ListScheduledParams{QueueName: &queue, DueBefore: &dueBefore})
+	if err != nil {
+		t.Fatalf("ListScheduled: %v", err)
+	}
+	query := api.received("GET /api/v1/jobs")[0].Query
+	if query.Get("status") != "scheduled" || query.Get("queue_name") != "emails" || query.Get("scheduled_before") != "2026-01-01T12:00:00Z" {
+		t.Errorf("query = %v", query)
+	}
+	if len(scheduled) != 1 || scheduled[0].ID != "job-1" {
+		t.Errorf("ListScheduled = %+v, want only job-1", scheduled)
+	}
+
+	job, err := jobs.TriggerNow(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("TriggerNow: %v", err)
+	}
+	if job.Status != JobStatusPending {
+		t.Errorf("TriggerNow = %+v", job)
+	}
+
+	at := time.Date(2026, 1, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	job, err = jobs.Reschedule(ctx, "job-2", at)
+	if err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+	var rescheduled map[string]any
+	api.received("PUT /api/v1/jobs/job-2/schedule")[0].decode(t, &rescheduled)
+	if rescheduled["scheduled_at"] != "2026-01-02T08:00:00Z" || job.ScheduledAt == nil || !job.ScheduledAt.Equal(at) {
+		t.Errorf("Reschedule sent %v, got %+v", rescheduled, job)
+	}
+	if _, err := jobs.Reschedule(ctx, "job-2", time.Time{}); err == nil {
+		t.Error("Reschedule with zero time should fail")
+	}
+}
@@ -0,0 +1,318 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+func TestJobs_BulkEnqueue_PartialFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/jobs/bulk", func(w http.ResponseWriter, r *http.Request) {
+		if len(api.received("POST /api/v1/jobs/bulk")) == 1 {
+			writeTestJSON(w, http.StatusOK, map[string]any{
+				"succeeded":     []map[string]any{{"index": 0, "job_id": "a", "created": true}},
+				"failed":        []map[string]any{{"index": 1, "error": "invalid payload"}, {"index": 2, "error": "queue is paused"}},
+				"total":         3,
+				"success_count": 1,
+				"failure_count": 2,
+			})
+			return
+		}
+		writeTestJSON(w, http.StatusOK, map[string]any{
+			"succeeded": []map[string]any{{"index": 0, "job_id": "c", "created": true}},
+			"failed":    []map[string]any{}, "total": 1, "success_count": 1, "failure_count": 0,
+		})
+	})
+	jobs := NewJobsResource(api.transport())
+
+	req := &BulkEnqueueRequest{
+		QueueName:     "emails",
+		Jobs:          []BulkJobItem{{Payload: map[string]any{"n": 0}}, {Payload: map[string]any{"n": 1}}, {Payload: map[string]any{"n": 2}}},
+		FailOnPartial: true,
+	}
+	resp, err := jobs.BulkEnqueue(context.Background(), req)
+	var partial *BulkPartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v, want *BulkPartialError", err)
+	}
+	if resp == nil || len(partial.Failures) != 2 {
+		t.Fatalf("Failures = %v, want 2", partial.Failures)
+	}
+	if resp.Failed[0].Code != BulkFailureValidation {
+		t.Errorf("Failed[0].Code = %v, want %v", resp.Failed[0].Code, BulkFailureValidation)
+	}
+	if resp.Failed[1].Code != BulkFailureQueuePaused {
+		t.Errorf("Failed[1].Code = %v, want %v", resp.Failed[1].Code, BulkFailureQueuePaused)
+	}
+
+	retried, err := jobs.RetryFailed(context.Background(), req, resp)
+	if err != nil {
+		t.Fatalf("RetryFailed: %v", err)
+	}
+	calls := api.received("POST /api/v1/jobs/bulk")
+	var body BulkEnqueueRequest
+	calls[len(calls)-1].decode(t, &body)
+	if len(calls) != 2 || len(body.Jobs) != 1 {
+		t.Errorf("retry sent %d jobs in %d calls, want only the retryable one", len(body.Jobs), len(calls))
+	}
+	if len(retried.Succeeded) != 1 || retried.Succeeded[0].Index != 2 {
+		t.Errorf("Succeeded = %+v, want index 2", retried.Succeeded)
+	}
+}
+
+func TestJobs_BulkEnqueueDelay(t *testing.T) {
+	api := newFakeAPI(t)
+	serverClock := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// The server's clock is an hour ahead
+			w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+			next(w, r)
+		}
+	}
+	api.handle("GET /health", serverClock(func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]any{"status": "healthy"})
+	}))
+	api.handle("POST /api/v1/jobs/bulk", serverClock(func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]any{"total": 3, "success_count": 3})
+	}))
+	transport := api.transport()
+	ctx := context.Background()
+
+	if _, err := NewHealthResource(transport).Get(ctx); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := NewJobsResource(transport).BulkEnqueue(ctx, &BulkEnqueueRequest{
+		QueueName: "emails",
+		Jobs: []BulkJobItem{
+			{Payload: map[string]any{"n": 0}, Delay: 10 * time.Minute},
+			{Payload: map[string]any{"n": 1}, Delay: 10 * time.Minute, ScheduledAt: &at},
+			{Payload: map[string]any{"n": 2}},
+		},
+	}); err != nil {
+		t.Fatalf("BulkEnqueue: %v", err)
+	}
+
+	var sent BulkEnqueueRequest
+	api.received("POST /api/v1/jobs/bulk")[0].decode(t, &sent)
+	if len(sent.Jobs) != 3 {
+		t.Fatalf("sent %d jobs, want 3", len(sent.Jobs))
+	}
+	want := time.Now().Add(time.Hour + 10*time.Minute)
+	if got := sent.Jobs[0].ScheduledAt; got == nil || got.Sub(want).Abs() > 5*time.Second {
+		t.Errorf("delayed job scheduled_at = %v, want ~%v on the server's clock", got, want)
+	}
+	if got := sent.Jobs[1].ScheduledAt; got == nil || !got.Equal(at) {
+		t.Errorf("scheduled job scheduled_at = %v, want %v", got, at)
+	}
+	if got := sent.Jobs[2].ScheduledAt; got != nil {
+		t.Errorf("immediate job scheduled_at = %v, want none", got)
+	}
+}
+
+func TestJobs_ListAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"cursor", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("cursor") {
+			case "":
+				_, _ = w.Write([]byte(`{"jobs":[{"id":"1"},{"id":"2"}],"next_cursor":"c2"}`))
+			case "c2":
+				_, _ = w.Write([]byte(`{"jobs":[{"id":"3"},{"id":"4"}],"next_cursor":"c3"}`))
+			default:
+				_, _ = w.Write([]byte(`{"jobs":[{"id":"5"}]}`))
+			}
+		}},
+		{"cursor header", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("cursor") {
+			case "":
+				w.Header().Set(NextCursorHeader, "c2")
+				_, _ = w.Write([]byte(`[{"id":"1"},{"id":"2"},{"id":"3"}]`))
+			default:
+				_, _ = w.Write([]byte(`[{"id":"4"},{"id":"5"}]`))
+			}
+		}},
+		{"offset", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("offset") {
+			case "0":
+				_, _ = w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+			case "2":
+				_, _ = w.Write([]byte(`[{"id":"3"},{"id":"4"}]`))
+			default:
+				_, _ = w.Write([]byte(`[{"id":"5"}]`))
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.handle("GET /api/v1/jobs", tt.handler)
+
+			limit := 2
+			it := NewJobsResource(api.transport()).ListAll(context.Background(), &ListJobsParams{Limit: &limit})
+			var ids []string
+			for it.Next() {
+				ids = append(ids, it.Job().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("ListAll: %v", err)
+			}
+			if got := strings.Join(ids, ","); got != "1,2,3,4,5" {
+				t.Errorf("ids = %s, want 1,2,3,4,5", got)
+			}
+		})
+	}
+}
+
+func TestJobs_ListStream(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+	}{
+		{"array", `[{"id":"job-1"},{"id":"job-2"},{"id":"job-3"}]`},
+		{"object", `{"next_cursor":"c1","jobs":[{"id":"job-1"},{"id":"job-2"},{"id":"job-3"}],"total":3}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.handle("GET /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.body))
+			})
+			transport := httpx.NewTransport(httpx.Config{BaseURL: api.URL, APIKey: testAPIKey, QueuePrefix: "staging-"})
+
+			queue, limit := "emails", 100000
+			jobs, errs := NewJobsResource(transport).ListStream(context.Background(), &ListJobsParams{QueueName: &queue, Limit: &limit})
+			var ids []string
+			for job := range jobs {
+				ids = append(ids, job.ID)
+			}
+			if err := <-errs; err != nil {
+				t.Fatalf("ListStream: %v", err)
+			}
+			if strings.Join(ids, ",") != "job-1,job-2,job-3" {
+				t.Errorf("ids = %v, want job-1..3", ids)
+			}
+			if query := api.received("GET /api/v1/jobs")[0].Query; query.Get("queue_name") != "staging-emails" || query.Get("limit") != "100000" {
+				t.Errorf("query = %v", query)
+			}
+		})
+	}
+
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"job-1"},{"id":`))
+	})
+	jobs, errs := NewJobsResource(api.transport()).ListStream(context.Background(), nil)
+	n := 0
+	for range jobs {
+		n++
+	}
+	if err := <-errs; err == nil || n != 1 {
+		t.Errorf("truncated body: got %d jobs, error %v; want 1 job and an error", n, err)
+	}
+}
+
+func TestJobs_CancelWithReason(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("DELETE /api/v1/jobs/job-1", http.StatusNoContent, nil)
+	jobs := NewJobsResource(api.transport())
+
+	if err := jobs.CancelWithReason(context.Background(), "job-1", "duplicate order", "alice@example.com"); err != nil {
+		t.Fatalf("CancelWithReason: %v", err)
+	}
+	var body map[string]string
+	api.received("DELETE /api/v1/jobs/job-1")[0].decode(t, &body)
+	if body["reason"] != "duplicate order" || body["actor"] != "alice@example.com" {
+		t.Errorf("cancel body = %v, want the reason and actor", body)
+	}
+	if err := jobs.CancelWithReason(context.Background(), "job-1", "", ""); err == nil {
+		t.Error("CancelWithReason() without a reason: expected error")
+	}
+}
+
+func TestJobs_ExtendTimeout(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("PUT /api/v1/jobs/job-1/timeout", http.StatusOK, map[string]any{"job_id": "job-1", "timeout_seconds": 390})
+	jobs := NewJobsResource(api.transport())
+
+	resp, err := jobs.ExtendTimeout(context.Background(), "job-1", 89500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ExtendTimeout: %v", err)
+	}
+	// Extensions are rounded up to whole seconds
+	var body map[string]any
+	api.received("PUT /api/v1/jobs/job-1/timeout")[0].decode(t, &body)
+	if body["extend_by_secs"] != float64(90) {
+		t.Errorf("extend_by_secs = %v, want 90", body["extend_by_secs"])
+	}
+	if resp.TimeoutSeconds != 390 {
+		t.Errorf("TimeoutSeconds = %d, want 390", resp.TimeoutSeconds)
+	}
+
+	if _, err := jobs.ExtendTimeout(context.Background(), "job-1", 0); err == nil {
+		t.Error("expected error for non-positive extension")
+	}
+}
+
+func TestJobs_GetStatsWithOptions(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs/stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"pending":4,"completed":10,"total":14,"avg_processing_time_ms":120,"p95_processing_time_ms":480,` +
+			`"oldest_pending_age_seconds":30,"throughput_per_minute":2.5,` +
+			`"groups":[{"key":"pending","pending":4,"total":4},{"key":"completed","completed":10,"total":10,"p95_processing_time_ms":480}]}`))
+	})
+	jobs := NewJobsResource(api.transport())
+
+	stats, err := jobs.GetStatsWithOptions(context.Background(), &StatsOptions{
+		Window:  time.Hour,
+		GroupBy: StatsGroupByStatus,
+	})
+	if err != nil {
+		t.Fatalf("GetStatsWithOptions: %v", err)
+	}
+	if query := api.received("GET /api/v1/jobs/stats")[0].Query; query.Get("window_seconds") != "3600" || query.Get("group_by") != "status" {
+		t.Errorf("query = %v, want window_seconds=3600 and group_by=status", query)
+	}
+	if stats.P95ProcessingTimeMs == nil || *stats.P95ProcessingTimeMs != 480 {
+		t.Errorf("P95ProcessingTimeMs = %v, want 480", stats.P95ProcessingTimeMs)
+	}
+	if stats.ThroughputPerMinute == nil || *stats.ThroughputPerMinute != 2.5 {
+		t.Errorf("ThroughputPerMinute = %v, want 2.5", stats.ThroughputPerMinute)
+	}
+	if len(stats.Groups) != 2 || stats.Groups[1].Key != "completed" || stats.Groups[1].Completed != 10 {
+		t.Errorf("Groups = %+v", stats.Groups)
+	}
+
+	if _, err := jobs.GetStats(context.Background()); err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if query := api.received("GET /api/v1/jobs/stats")[1].Query; len(query) != 0 {
+		t.Errorf("GetStats() query = %v, want none", query)
+	}
+}
+
+func TestDLQ_ListByOwner(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs/dlq", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"job-1","queue_name":"billing","status":"deadletter","payload":{},"owner_team":"payments","runbook_url":"https://runbooks.example.com/billing"}]`))
+	})
+
+	jobs, err := NewJobsResource(api.transport()).DLQ().ListByOwner(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("ListByOwner: %v", err)
+	}
+	if owner := api.received("GET /api/v1/jobs/dlq")[0].Query.Get("owner_team"); owner != "payments" {
+		t.Errorf("owner_team = %q, want payments", owner)
+	}
+	if len(jobs) != 1 || jobs[0].RunbookURL == nil || *jobs[0].RunbookURL != "https://runbooks.example.com/billing" {
+		t.Errorf("jobs = %+v, want one job with its runbook URL", jobs)
+	}
+}
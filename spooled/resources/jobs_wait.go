@@ -0,0 +1,134 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrJobNotCompleted is returned by WaitForCompletion when a job finishes
+// without completing: it failed, was dead-lettered, or was cancelled.
+var ErrJobNotCompleted = errors.New("job did not complete")
+
+// Wait defaults.
+const (
+	DefaultWaitPollInterval    = 500 * time.Millisecond
+	DefaultWaitMaxPollInterval = 5 * time.Second
+)
+
+// IsTerminal reports whether a job in status s will not run again.
+func (s JobStatus) IsTerminal() bool {
+	switch s {
+	case JobStatusCompleted, JobStatusFailed, JobStatusDeadletter, JobStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// WaitOptions configures WaitForCompletion.
+type WaitOptions struct {
+	// PollInterval is the delay before the first re-check (default:
+	// DefaultWaitPollInterval).
+	PollInterval time.Duration
+	// MaxPollInterval caps the delay between checks (default:
+	// DefaultWaitMaxPollInterval).
+	MaxPollInterval time.Duration
+	// Multiplier grows the delay after each check (default: 1.5). Use 1 to
+	// poll at a fixed interval.
+	Multiplier float64
+	// Timeout bounds the wait (default: until ctx is done).
+	Timeout time.Duration
+	// OnUpdate is called with the job whenever its status or update time
+	// changes, including the first check.
+	OnUpdate func(job *Job)
+}
+
+// JobWatcher reports changes to jobs, so WaitForCompletion can re-check a job
+// as soon as it changes instead of waiting out its poll interval.
+type JobWatcher interface {
+	// WatchJob returns a channel that receives when the job may have changed
+	// and a function that stops watching. ok is false when changes cannot be
+	// watched right now.
+	WatchJob(jobID string) (changes <-chan struct{}, stop func(), ok bool)
+}
+
+// SetJobWatcher makes WaitForCompletion wake on changes reported by watcher.
+// Passing nil makes it poll only.
+func (r *JobsResource) SetJobWatcher(watcher JobWatcher) {
+	r.watcher = watcher
+}
+
+// WaitForCompletion polls a job until it reaches a terminal status and
+// returns it. Polling backs off from PollInterval to MaxPollInterval; when a
+// JobWatcher is available, changes to the job are picked up immediately.
+//
+// A job that fails, is dead-lettered, or is cancelled is returned with an
+// error wrapping ErrJobNotCompleted. If the wait times out or ctx is done, the
+// last fetched job is returned with an error wrapping the context's error.
+//
+//	job, err := client.Jobs().WaitForCompletion(ctx, id, &resources.WaitOptions{Timeout: time.Minute})
+func (r *JobsResource) WaitForCompletion(ctx context.Context, id string, opts *WaitOptions) (*Job, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxPollInterval
+	}
+	maxInterval = max(maxInterval, interval)
+	multiplier := opts.Multiplier
+	if multiplier < 1 {
+		multiplier = 1.5
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var changes <-chan struct{}
+	if r.watcher != nil {
+		if ch, stop, ok := r.watcher.WatchJob(id); ok {
+			changes = ch
+			defer stop()
+		}
+	}
+
+	var last *Job
+	for {
+		job, err := r.Get(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil && last != nil {
+				return last, fmt.Errorf("job %s still %s: %w", id, last.Status, ctx.Err())
+			}
+			return last, err
+		}
+		if opts.OnUpdate != nil && (last == nil || job.Status != last.Status || !job.UpdatedAt.Equal(last.UpdatedAt)) {
+			opts.OnUpdate(job)
+		}
+		last = job
+
+		if job.Status.IsTerminal() {
+			if job.Status != JobStatusCompleted {
+				return job, fmt.Errorf("%w: job %s is %s", ErrJobNotCompleted, id, job.Status)
+			}
+			return job, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return job, fmt.Errorf("job %s still %s: %w", id, job.Status, ctx.Err())
+		case <-changes:
+			timer.Stop()
+		case <-timer.C:
+		}
+		interval = min(time.Duration(float64(interval)*multiplier), maxInterval)
+	}
+}
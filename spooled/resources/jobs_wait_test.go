@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chanJobWatcher is a JobWatcher that reports a change for every value sent
+// on changes, whichever job is watched.
+type chanJobWatcher struct {
+	changes chan struct{}
+	stopped atomic.Bool
+}
+
+func (w *chanJobWatcher) WatchJob(jobID string) (<-chan struct{}, func(), bool) {
+	return w.changes, func() { w.stopped.Store(true) }, true
+}
+
+func TestJobs_WaitForCompletion(t *testing.T) {
+	var mu sync.Mutex
+	statuses := map[string][]string{
+		"ok":     {"pending", "processing", "processing", "completed"},
+		"broken": {"processing", "deadletter"},
+		"stuck":  {"processing"},
+		"pushed": {"processing", "completed"},
+	}
+	api := newFakeAPI(t)
+	for id := range statuses {
+		api.handle("GET /api/v1/jobs/"+id, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			seq := statuses[id]
+			status := seq[0]
+			if len(seq) > 1 {
+				statuses[id] = seq[1:]
+			}
+			mu.Unlock()
+			updated := "2024-01-01T00:00:00Z"
+			if status == "completed" {
+				updated = "2024-01-01T00:00:05Z"
+			}
+			writeTestJSON(w, http.StatusOK, map[string]any{"id": id, "status": status, "updated_at": updated, "created_at": updated})
+		})
+	}
+	jobs := NewJobsResource(api.transport())
+	ctx := context.Background()
+	fast := &WaitOptions{PollInterval: time.Millisecond, MaxPollInterval: 5 * time.Millisecond}
+
+	var updates []JobStatus
+	opts := *fast
+	opts.OnUpdate = func(job *Job) { updates = append(updates, job.Status) }
+	job, err := jobs.WaitForCompletion(ctx, "ok", &opts)
+	if err != nil || job.Status != JobStatusCompleted {
+		t.Fatalf("WaitForCompletion(ok) = %+v, %v", job, err)
+	}
+	want := []JobStatus{JobStatusPending, JobStatusProcessing, JobStatusCompleted}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %v, want %v", updates, want)
+	}
+
+	job, err = jobs.WaitForCompletion(ctx, "broken", fast)
+	if !errors.Is(err, ErrJobNotCompleted) || job == nil || job.Status != JobStatusDeadletter {
+		t.Errorf("WaitForCompletion(broken) = %+v, %v; want deadletter job and ErrJobNotCompleted", job, err)
+	}
+
+	timeout := *fast
+	timeout.Timeout = 30 * time.Millisecond
+	job, err = jobs.WaitForCompletion(ctx, "stuck", &timeout)
+	if !errors.Is(err, context.DeadlineExceeded) || job == nil || job.Status != JobStatusProcessing {
+		t.Errorf("WaitForCompletion(stuck) = %+v, %v; want processing job and DeadlineExceeded", job, err)
+	}
+
+	// A watcher wakes the wait long before the poll interval.
+	watcher := &chanJobWatcher{changes: make(chan struct{}, 1)}
+	jobs.SetJobWatcher(watcher)
+	watcher.changes <- struct{}{}
+	start := time.Now()
+	job, err = jobs.WaitForCompletion(ctx, "pushed", &WaitOptions{PollInterval: time.Minute})
+	if err != nil || job.Status != JobStatusCompleted {
+		t.Fatalf("WaitForCompletion(pushed) = %+v, %v", job, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("watched wait took %v", elapsed)
+	}
+	if !watcher.stopped.Load() {
+		t.Error("watch was not stopped")
+	}
+}
@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// OfflineSpoolFailedDir is the subdirectory of an offline spool that holds
+// entries the API rejected on replay, for inspection.
+const OfflineSpoolFailedDir = "failed"
+
+// OfflineSpool durably stores enqueues made while the API is unreachable, one
+// JSON file per request, so they can be replayed once it is reachable again.
+// Every stored request carries idempotency keys, so a request that reached the
+// API before the connection failed is not enqueued twice.
+type OfflineSpool struct {
+	dir string
+	// mu serializes replays, so concurrent ones don't send an entry twice.
+	mu sync.Mutex
+}
+
+// spoolEntry is a stored request, exactly as it is sent to the API.
+type spoolEntry struct {
+	Create    *CreateJobRequest   `json:"create,omitempty"`
+	Bulk      *BulkEnqueueRequest `json:"bulk,omitempty"`
+	SpooledAt time.Time           `json:"spooled_at"`
+}
+
+// NewOfflineSpool creates an OfflineSpool in dir, creating the directory if
+// needed.
+func NewOfflineSpool(dir string) (*OfflineSpool, error) {
+	if err := os.MkdirAll(filepath.Join(dir, OfflineSpoolFailedDir), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create offline spool: %w", err)
+	}
+	return &OfflineSpool{dir: dir}, nil
+}
+
+// Dir returns the spool directory.
+func (s *OfflineSpool) Dir() string {
+	return s.dir
+}
+
+// Pending returns the number of requests waiting to be replayed.
+func (s *OfflineSpool) Pending() (int, error) {
+	names, err := s.entries()
+	return len(names), err
+}
+
+// add writes e to the spool. The file is synced before it is renamed into
+// place, so a crash leaves either the whole entry or none of it.
+func (s *OfflineSpool) add(e *spoolEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%s.json", e.SpooledAt.UnixNano(), newBatchID())
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to spool request: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to spool request: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to spool request: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to spool request: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return fmt.Errorf("failed to spool request: %w", err)
+	}
+	return nil
+}
+
+// entries returns the names of stored entries, oldest first.
+func (s *OfflineSpool) entries() ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, d := range dirEntries {
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
+			names = append(names, d.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetOfflineSpool stores Create and BulkEnqueue requests in spool instead of
+// failing when the API is unreachable. Passing nil disables spooling.
+func (r *JobsResource) SetOfflineSpool(spool *OfflineSpool) {
+	r.spool = spool
+}
+
+// ReplaySpool sends the requests stored in the offline spool, oldest first,
+// and returns how many were delivered. It stops at the first request that
+// fails with a network, retryable, or authentication error, leaving it and
+// later ones for the next replay. Requests the API rejects are moved to the
+// spool's OfflineSpoolFailedDir.
+func (r *JobsResource) ReplaySpool(ctx context.Context) (int, error) {
+	if r.spool == nil {
+		return 0, nil
+	}
+	r.spool.mu.Lock()
+	defer r.spool.mu.Unlock()
+
+	names, err := r.spool.entries()
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, name := range names {
+		path := filepath.Join(r.spool.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return delivered, err
+		}
+		var e spoolEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			if err := os.Rename(path, filepath.Join(r.spool.dir, OfflineSpoolFailedDir, name)); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+
+		switch {
+		case e.Create != nil:
+			err = r.base.postPayload(ctx, "/api/v1/jobs", e.Create, nil, true)
+		case e.Bulk != nil:
+			err = r.base.postPayload(ctx, "/api/v1/jobs/bulk", e.Bulk, nil, true)
+		}
+		if err != nil {
+			if isUnreachable(err) || httpx.IsRetryable(err) || httpx.IsAuthenticationError(err) || ctx.Err() != nil {
+				return delivered, err
+			}
+			if err := os.Rename(path, filepath.Join(r.spool.dir, OfflineSpoolFailedDir, name)); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// isUnreachable reports whether err means the request may not have reached
+// the API at all.
+func isUnreachable(err error) bool {
+	var netErr *httpx.NetworkError
+	var timeoutErr *httpx.TimeoutError
+	var breakerErr *httpx.CircuitBreakerOpenError
+	return errors.As(err, &netErr) || errors.As(err, &timeoutErr) || errors.As(err, &breakerErr)
+}
@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// Feature is a plan-gated capability.
+type Feature string
+
+const (
+	FeatureWorkflows Feature = "workflows"
+	FeatureSchedules Feature = "schedules"
+	FeatureWebhooks  Feature = "webhooks"
+	FeatureGRPC      Feature = "grpc"
+	FeatureRealtime  Feature = "realtime"
+)
+
+// OrganizationFeatures is what an organization's plan includes.
+type OrganizationFeatures struct {
+	PlanTier PlanTier `json:"plan_tier"`
+	// Features reports whether each feature is enabled. Features the server
+	// does not list are unknown; see Enabled.
+	Features map[Feature]bool `json:"features"`
+	// RequiredTiers is the lowest tier that includes each feature missing
+	// from the plan, for upgrade prompts.
+	RequiredTiers map[Feature]PlanTier `json:"required_tiers,omitempty"`
+	Limits        PlanLimits           `json:"limits"`
+}
+
+// Enabled reports whether the plan includes feature. Features the server did
+// not report are assumed enabled, so the server has the final say.
+func (f *OrganizationFeatures) Enabled(feature Feature) bool {
+	enabled, ok := f.Features[feature]
+	return !ok || enabled
+}
+
+// GetFeatures returns the feature flags and limits of an organization's plan,
+// so apps can hide or badge features before a call fails with a
+// PlanLimitError.
+//
+//	features, err := client.Organizations().GetFeatures(ctx, orgID)
+//	if !features.Enabled(resources.FeatureWorkflows) {
+//		showUpgrade(features.RequiredTiers[resources.FeatureWorkflows])
+//	}
+//
+// Servers without the features endpoint report the workflow, schedule, and
+// webhook flags from the organization's usage instead.
+func (r *OrganizationsResource) GetFeatures(ctx context.Context, orgID string) (*OrganizationFeatures, error) {
+	var result OrganizationFeatures
+	err := r.base.Get(ctx, fmt.Sprintf("/api/v1/organizations/%s/features", orgID), &result)
+	var notFound *httpx.NotFoundError
+	if errors.As(err, &notFound) {
+		return r.featuresFromUsage(ctx, orgID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *OrganizationsResource) featuresFromUsage(ctx context.Context, orgID string) (*OrganizationFeatures, error) {
+	usage, err := r.Usage(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return &OrganizationFeatures{
+		PlanTier: PlanTier(usage.Plan),
+		Features: map[Feature]bool{
+			FeatureWorkflows: !usage.Usage.Workflows.IsDisabled,
+			FeatureSchedules: !usage.Usage.Schedules.IsDisabled,
+			FeatureWebhooks:  !usage.Usage.Webhooks.IsDisabled,
+		},
+		Limits: usage.Limits,
+	}, nil
+}
@@ -249,3 +249,56 @@ func (r *OrganizationsResource) RegenerateWebhookToken(ctx context.Context, id s
 func (r *OrganizationsResource) ClearWebhookToken(ctx context.Context, id string) error {
 	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/organizations/%s/webhook-token", id))
 }
+
+// WebhookToken represents one of an organization's inbound webhook tokens.
+type WebhookToken struct {
+	ID          string     `json:"id"`
+	Label       *string    `json:"label,omitempty"`
+	TokenPrefix *string    `json:"token_prefix,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ListWebhookTokens retrieves all webhook tokens for an organization.
+func (r *OrganizationsResource) ListWebhookTokens(ctx context.Context, id string) ([]WebhookToken, error) {
+	var result []WebhookToken
+	if err := r.base.Get(ctx, fmt.Sprintf("/api/v1/organizations/%s/webhook-tokens", id), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateWebhookTokenRequest is the request to create a webhook token.
+type CreateWebhookTokenRequest struct {
+	Label     *string    `json:"label,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateWebhookTokenResponse is the response from creating a webhook token.
+type CreateWebhookTokenResponse struct {
+	WebhookToken
+	Token      string  `json:"token"` // Raw token - only shown once!
+	WebhookURL *string `json:"webhook_url,omitempty"`
+}
+
+// CreateWebhookToken creates an additional webhook token for an organization.
+// Existing tokens stay valid, so callers can rotate by creating a new token,
+// switching senders over, and then revoking the old one.
+func (r *OrganizationsResource) CreateWebhookToken(ctx context.Context, id string, req *CreateWebhookTokenRequest) (*CreateWebhookTokenResponse, error) {
+	var result CreateWebhookTokenResponse
+	body := req
+	if body == nil {
+		body = &CreateWebhookTokenRequest{}
+	}
+	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/organizations/%s/webhook-tokens", id), body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeWebhookToken revokes a single webhook token for an organization.
+func (r *OrganizationsResource) RevokeWebhookToken(ctx context.Context, id, tokenID string) error {
+	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/organizations/%s/webhook-tokens/%s", id, tokenID))
+}
@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+func TestOrganizations_WebhookTokens(t *testing.T) {
+	api := newFakeAPI(t)
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := created.Add(30 * 24 * time.Hour)
+	api.respond("GET /api/v1/organizations/org-1/webhook-tokens", http.StatusOK, []map[string]any{
+		{"id": "tok-1", "label": "github", "token_prefix": "wh_ab", "created_at": created},
+		{"id": "tok-2", "created_at": created, "expires_at": expires, "revoked_at": created},
+	})
+	api.respond("POST /api/v1/organizations/org-1/webhook-tokens", http.StatusCreated, map[string]any{
+		"id": "tok-3", "label": "stripe", "created_at": created, "expires_at": expires,
+		"token": "wh_secret", "webhook_url": "https://api.example.com/webhooks/org-1",
+	})
+	api.respond("DELETE /api/v1/organizations/org-1/webhook-tokens/tok-1", http.StatusNoContent, nil)
+	orgs := NewOrganizationsResource(api.transport())
+	ctx := context.Background()
+
+	tokens, err := orgs.ListWebhookTokens(ctx, "org-1")
+	if err != nil {
+		t.Fatalf("ListWebhookTokens: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].ID != "tok-1" || tokens[0].Label == nil || *tokens[0].Label != "github" ||
+		tokens[0].RevokedAt != nil || tokens[1].RevokedAt == nil || !tokens[1].ExpiresAt.Equal(expires) {
+		t.Errorf("ListWebhookTokens() = %+v", tokens)
+	}
+
+	label := "stripe"
+	token, err := orgs.CreateWebhookToken(ctx, "org-1", &CreateWebhookTokenRequest{Label: &label, ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("CreateWebhookToken: %v", err)
+	}
+	if token.ID != "tok-3" || token.Token != "wh_secret" || token.WebhookURL == nil || !token.ExpiresAt.Equal(expires) {
+		t.Errorf("CreateWebhookToken() = %+v", token)
+	}
+	var body map[string]any
+	api.received("POST /api/v1/organizations/org-1/webhook-tokens")[0].decode(t, &body)
+	if body["label"] != "stripe" || body["expires_at"] != expires.Format(time.RFC3339) {
+		t.Errorf("create body = %v, want the label and expiry", body)
+	}
+
+	// Without options an empty object is sent
+	if _, err := orgs.CreateWebhookToken(ctx, "org-1", nil); err != nil {
+		t.Fatalf("CreateWebhookToken(nil): %v", err)
+	}
+	if got := string(api.received("POST /api/v1/organizations/org-1/webhook-tokens")[1].Body); got != "{}" {
+		t.Errorf("create body = %q, want {}", got)
+	}
+
+	if err := orgs.RevokeWebhookToken(ctx, "org-1", "tok-1"); err != nil {
+		t.Fatalf("RevokeWebhookToken: %v", err)
+	}
+	if got := len(api.received("DELETE /api/v1/organizations/org-1/webhook-tokens/tok-1")); got != 1 {
+		t.Errorf("revoke requests = %d, want 1", got)
+	}
+}
+
+func TestOrganizations_UsageByQueue(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/organizations/org-1/usage/queues", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"period":"2024-06","from":"2024-06-01T00:00:00Z","to":"2024-07-01T00:00:00Z","queues":[
+			{"queue_name":"emails","owner_team":"growth","jobs_created":100,"jobs_completed":90,"jobs_failed":10,"api_calls":300,"storage_bytes":1000},
+			{"queue_name":"digests","owner_team":"growth","jobs_created":50,"api_calls":120,"storage_bytes":500},
+			{"queue_name":"scratch","jobs_created":5,"api_calls":7}
+		]}`))
+	})
+	orgs := NewOrganizationsResource(api.transport())
+	ctx := context.Background()
+
+	report, err := orgs.UsageByQueue(ctx, "org-1", UsageMonth(2024, time.June))
+	if err != nil {
+		t.Fatalf("UsageByQueue: %v", err)
+	}
+	if got := api.received("")[0].Query.Get("period"); got != "2024-06" {
+		t.Errorf("period = %q, want 2024-06", got)
+	}
+	if len(report.Queues) != 3 || report.Queues[0].APICalls != 300 || *report.Queues[0].OwnerTeam != "growth" {
+		t.Fatalf("report = %+v, want three queues", report)
+	}
+
+	teams := report.ByOwnerTeam()
+	if growth := teams["growth"]; growth.JobsCreated != 150 || growth.APICalls != 420 || growth.StorageBytes != 1500 {
+		t.Errorf("growth = %+v, want emails and digests summed", growth)
+	}
+	if unowned := teams[""]; unowned.JobsCreated != 5 {
+		t.Errorf("unowned = %+v, want scratch", unowned)
+	}
+
+	if _, err := orgs.UsageByQueue(ctx, "org-1", ""); err != nil {
+		t.Fatalf("UsageByQueue: %v", err)
+	}
+	if query := api.received("")[1].Query; query.Has("period") {
+		t.Errorf("UsageByQueue() with no period sent %v, want none", query)
+	}
+}
+
+func TestOrganizations_GetFeaturesAndPlanLimits(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/organizations/org-1/features", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"plan_tier":"free","features":{"workflows":false,"webhooks":true},"required_tiers":{"workflows":"starter"},"limits":{"tier":"free","max_queues":5}}`))
+	})
+	api.handle("GET /api/v1/organizations/org-1/usage", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"plan":"free","limits":{"tier":"free"},"usage":{"workflows":{"current":0,"is_disabled":true},"schedules":{"current":1,"is_disabled":false}}}`))
+	})
+	api.handle("POST /api/v1/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"code":"plan_upgrade_required","message":"Workflows are not available on the free plan","details":{"feature":"workflows","current_tier":"free","required_tier":"starter"}}`))
+	})
+	orgs := NewOrganizationsResource(api.transport())
+	ctx := context.Background()
+
+	features, err := orgs.GetFeatures(ctx, "org-1")
+	if err != nil {
+		t.Fatalf("GetFeatures: %v", err)
+	}
+	if features.PlanTier != PlanTierFree || features.Enabled(FeatureWorkflows) || !features.Enabled(FeatureWebhooks) {
+		t.Errorf("features = %+v, want free plan without workflows", features)
+	}
+	if features.RequiredTiers[FeatureWorkflows] != PlanTierStarter {
+		t.Errorf("RequiredTiers = %v, want workflows on starter", features.RequiredTiers)
+	}
+	if !features.Enabled(FeatureGRPC) {
+		t.Error("unreported features should be assumed enabled")
+	}
+
+	// Without the features endpoint, flags come from usage.
+	api.handle("GET /api/v1/organizations/org-1/features", nil)
+	features, err = orgs.GetFeatures(ctx, "org-1")
+	if err != nil {
+		t.Fatalf("GetFeatures fallback: %v", err)
+	}
+	if features.Enabled(FeatureWorkflows) || !features.Enabled(FeatureSchedules) {
+		t.Errorf("fallback features = %+v, want schedules without workflows", features.Features)
+	}
+
+	_, err = NewWorkflowsResource(api.transport()).Create(ctx, &CreateWorkflowRequest{Name: "etl"})
+	var planErr *httpx.PlanLimitError
+	if !errors.As(err, &planErr) {
+		t.Fatalf("Create error = %T %v, want *PlanLimitError", err, err)
+	}
+	if planErr.RequiredTier != "starter" || planErr.Feature != "workflows" {
+		t.Errorf("PlanLimitError = %+v, want workflows on starter", planErr)
+	}
+}
@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DefaultSampleWindow is how many recent jobs SamplePayloads draws from.
+const DefaultSampleWindow = 500
+
+// RedactedValue replaces values removed by RedactKeys.
+const RedactedValue = "[REDACTED]"
+
+// PayloadRedactor rewrites a sampled payload or result before it is returned,
+// e.g. to strip personal data. It may modify data in place.
+type PayloadRedactor func(data map[string]any) map[string]any
+
+// RedactKeys returns a PayloadRedactor that replaces the value of every key
+// matching one of keys (case-insensitively, at any depth) with RedactedValue.
+func RedactKeys(keys ...string) PayloadRedactor {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = true
+	}
+
+	var redact func(v any) any
+	redact = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, inner := range v {
+				if set[strings.ToLower(k)] {
+					v[k] = RedactedValue
+				} else {
+					v[k] = redact(inner)
+				}
+			}
+		case []any:
+			for i, inner := range v {
+				v[i] = redact(inner)
+			}
+		}
+		return v
+	}
+	return func(data map[string]any) map[string]any {
+		if data == nil {
+			return nil
+		}
+		return redact(data).(map[string]any)
+	}
+}
+
+// PayloadSample is the payload and result of a sampled job.
+type PayloadSample struct {
+	JobID     string         `json:"job_id"`
+	Status    JobStatus      `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+	Payload   map[string]any `json:"payload"`
+	Result    map[string]any `json:"result,omitempty"`
+}
+
+// SetPayloadRedactor sets the redactor applied to SamplePayloads results.
+func (r *QueuesResource) SetPayloadRedactor(redactor PayloadRedactor) {
+	r.redactor = redactor
+}
+
+// SamplePayloads returns up to n payloads (and results) chosen at random from
+// the queue's DefaultSampleWindow most recent jobs, optionally filtered by
+// status ("" for any). Use it to inspect real message shapes without exporting
+// the whole queue. The redactor set with SetPayloadRedactor is applied to
+// every payload and result.
+func (r *QueuesResource) SamplePayloads(ctx context.Context, name string, n int, status JobStatus) ([]PayloadSample, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("sample size must be positive")
+	}
+
+	params := &ListJobsParams{QueueName: &name}
+	if status != "" {
+		params.Status = &status
+	}
+	jobs := &JobsResource{base: r.base}
+
+	var window []*Job
+	it := jobs.ListAll(ctx, params)
+	for len(window) < DefaultSampleWindow && it.Next() {
+		window = append(window, it.Job())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	rand.Shuffle(len(window), func(i, j int) { window[i], window[j] = window[j], window[i] })
+	if len(window) > n {
+		window = window[:n]
+	}
+
+	samples := make([]PayloadSample, 0, len(window))
+	for _, job := range window {
+		sample := PayloadSample{
+			JobID:     job.ID,
+			Status:    job.Status,
+			CreatedAt: job.CreatedAt,
+			Payload:   job.Payload,
+			Result:    job.Result,
+		}
+		if r.redactor != nil {
+			sample.Payload = r.redactor(sample.Payload)
+			if sample.Result != nil {
+				sample.Result = r.redactor(sample.Result)
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
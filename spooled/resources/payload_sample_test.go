@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestQueues_SamplePayloads(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"1","status":"failed","payload":{"email":"a@example.com","user":{"SSN":"123"}}},
+			{"id":"2","status":"failed","payload":{"email":"b@example.com","items":[{"ssn":"456"}]}},
+			{"id":"3","status":"failed","payload":{"email":"c@example.com"}}
+		]`))
+	})
+	queues := NewQueuesResource(api.transport())
+	queues.SetPayloadRedactor(RedactKeys("email", "ssn"))
+
+	samples, err := queues.SamplePayloads(context.Background(), "emails", 2, JobStatusFailed)
+	if err != nil {
+		t.Fatalf("SamplePayloads: %v", err)
+	}
+	if query := api.received("GET /api/v1/jobs")[0].Query; query.Get("status") != "failed" || query.Get("queue_name") != "emails" {
+		t.Errorf("query = %v, want queue and status filters", query)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("len(samples) = %d, want 2", len(samples))
+	}
+	for _, s := range samples {
+		encoded, _ := json.Marshal(s.Payload)
+		if strings.Contains(string(encoded), "example.com") || strings.Contains(string(encoded), "123") || strings.Contains(string(encoded), "456") {
+			t.Errorf("payload not redacted: %s", encoded)
+		}
+	}
+
+	if _, err := queues.SamplePayloads(context.Background(), "emails", 0, ""); err == nil {
+		t.Error("expected error for non-positive sample size")
+	}
+}
@@ -0,0 +1,86 @@
+package resources
+
+import "maps"
+
+// PayloadTransform rewrites a job payload, e.g. to inject a tenant ID or
+// rename legacy fields. It may modify and return the map it is passed.
+type PayloadTransform func(payload map[string]any) map[string]any
+
+// PayloadTransforms are hooks applied to every job payload sent to or
+// received from the API, so organization-wide payload conventions live in
+// one place instead of at every call site. Either may be nil.
+type PayloadTransforms struct {
+	// Outbound is applied to the payloads of jobs being enqueued by
+	// Jobs().Create, Jobs().BulkEnqueue, and Workflows().Create. It is passed
+	// a shallow copy, so the caller's payload is left as is.
+	Outbound PayloadTransform
+	// Inbound is applied to the payloads of jobs returned by Jobs().Get,
+	// List, ListStream, Claim, and the DLQ, and to jobs claimed by workers.
+	Inbound PayloadTransform
+}
+
+// ApplyOutbound returns payload as transformed by Outbound. A nil
+// PayloadTransforms, Outbound, or payload leaves it unchanged.
+func (t *PayloadTransforms) ApplyOutbound(payload map[string]any) map[string]any {
+	if t == nil || t.Outbound == nil || payload == nil {
+		return payload
+	}
+	return t.Outbound(maps.Clone(payload))
+}
+
+// ApplyInbound returns payload as transformed by Inbound. A nil
+// PayloadTransforms, Inbound, or payload leaves it unchanged.
+func (t *PayloadTransforms) ApplyInbound(payload map[string]any) map[string]any {
+	if t == nil || t.Inbound == nil || payload == nil {
+		return payload
+	}
+	return t.Inbound(payload)
+}
+
+// SetPayloadTransforms applies transforms to the payloads of jobs enqueued
+// and read through the resource and its DLQ. Passing nil removes them.
+func (r *JobsResource) SetPayloadTransforms(transforms *PayloadTransforms) {
+	r.transforms = transforms
+}
+
+// SetPayloadTransforms applies transforms.Outbound to the payloads of
+// workflow jobs passed to Create. Passing nil removes it.
+func (r *WorkflowsResource) SetPayloadTransforms(transforms *PayloadTransforms) {
+	r.transforms = transforms
+}
+
+// transformOutbound returns req with its payload transformed, without
+// modifying req.
+func (r *JobsResource) transformOutbound(req *CreateJobRequest) *CreateJobRequest {
+	if r.transforms == nil || r.transforms.Outbound == nil {
+		return req
+	}
+	transformed := *req
+	transformed.Payload = r.transforms.ApplyOutbound(req.Payload)
+	return &transformed
+}
+
+// transformOutboundBulk returns req with every job's payload transformed,
+// without modifying req.
+func (r *JobsResource) transformOutboundBulk(req *BulkEnqueueRequest) *BulkEnqueueRequest {
+	if r.transforms == nil || r.transforms.Outbound == nil {
+		return req
+	}
+	transformed := *req
+	transformed.Jobs = make([]BulkJobItem, len(req.Jobs))
+	for i, job := range req.Jobs {
+		job.Payload = r.transforms.ApplyOutbound(job.Payload)
+		transformed.Jobs[i] = job
+	}
+	return &transformed
+}
+
+// transformInbound transforms the payloads of jobs in place.
+func (r *JobsResource) transformInbound(jobs []Job) {
+	if r.transforms == nil || r.transforms.Inbound == nil {
+		return
+	}
+	for i := range jobs {
+		jobs[i].Payload = r.transforms.ApplyInbound(jobs[i].Payload)
+	}
+}
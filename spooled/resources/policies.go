@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+)
+
+// PolicyTypeKey is the payload key naming a job's type. Create looks the
+// type up in the client's PolicyRegistry.
+const PolicyTypeKey = "type"
+
+// Backoff is a per-job retry backoff: the nth retry waits Initial *
+// Multiplier^(n-1), capped at Max. Servers without per-job backoff use the
+// queue's.
+type Backoff struct {
+	Initial time.Duration
+	// Max caps the delay (optional).
+	Max time.Duration
+	// Multiplier grows the delay between retries (default: 2).
+	Multiplier float64
+}
+
+// backoffJSON is the wire form of Backoff, in whole seconds.
+type backoffJSON struct {
+	InitialDelaySeconds int     `json:"initial_delay_seconds"`
+	MaxDelaySeconds     int     `json:"max_delay_seconds,omitempty"`
+	Multiplier          float64 `json:"multiplier,omitempty"`
+}
+
+// MarshalJSON encodes the delays as seconds, rounded up.
+func (b Backoff) MarshalJSON() ([]byte, error) {
+	return json.Marshal(backoffJSON{
+		InitialDelaySeconds: int(math.Ceil(b.Initial.Seconds())),
+		MaxDelaySeconds:     int(math.Ceil(b.Max.Seconds())),
+		Multiplier:          b.Multiplier,
+	})
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON.
+func (b *Backoff) UnmarshalJSON(data []byte) error {
+	var wire backoffJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*b = Backoff{
+		Initial:    time.Duration(wire.InitialDelaySeconds) * time.Second,
+		Max:        time.Duration(wire.MaxDelaySeconds) * time.Second,
+		Multiplier: wire.Multiplier,
+	}
+	return nil
+}
+
+// Policy is the retry and timeout behavior for a type of job. Zero fields
+// are left to the request or the queue's defaults.
+type Policy struct {
+	MaxRetries int
+	Timeout    time.Duration
+	Backoff    *Backoff
+}
+
+// PolicyRegistry maps job types to policies, so retry and timeout settings
+// live in one place instead of at every call site. Create applies the policy
+// named by a payload's PolicyTypeKey to fields the request leaves unset:
+//
+//	client.Policies().Register("send-email", resources.Policy{MaxRetries: 5, Timeout: 30 * time.Second})
+//	client.Jobs().Create(ctx, &resources.CreateJobRequest{
+//		QueueName: "emails",
+//		Payload:   map[string]any{"type": "send-email", "to": "a@example.com"},
+//	})
+//
+// It is safe for concurrent use.
+type PolicyRegistry struct {
+	mu       sync.RWMutex
+	policies map[string]Policy
+}
+
+// NewPolicyRegistry creates an empty PolicyRegistry.
+func NewPolicyRegistry() *PolicyRegistry {
+	return &PolicyRegistry{policies: make(map[string]Policy)}
+}
+
+// Register sets the policy for jobType, replacing any previous one.
+func (r *PolicyRegistry) Register(jobType string, policy Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies[jobType] = policy
+}
+
+// Unregister removes the policy for jobType.
+func (r *PolicyRegistry) Unregister(jobType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.policies, jobType)
+}
+
+// Lookup returns the policy for jobType.
+func (r *PolicyRegistry) Lookup(jobType string) (Policy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy, ok := r.policies[jobType]
+	return policy, ok
+}
+
+// lookupPayload returns the policy named by payload's PolicyTypeKey.
+func (r *PolicyRegistry) lookupPayload(payload map[string]any) (Policy, bool) {
+	if r == nil {
+		return Policy{}, false
+	}
+	jobType, ok := payload[PolicyTypeKey].(string)
+	if !ok {
+		return Policy{}, false
+	}
+	return r.Lookup(jobType)
+}
+
+// Apply returns req with its payload type's policy filled into unset
+// MaxRetries, TimeoutSeconds, and RetryBackoff. req is not modified; it is
+// returned as is when no policy applies.
+func (r *PolicyRegistry) Apply(req *CreateJobRequest) *CreateJobRequest {
+	policy, ok := r.lookupPayload(req.Payload)
+	if !ok {
+		return req
+	}
+	applied := *req
+	if applied.MaxRetries == nil && policy.MaxRetries > 0 {
+		maxRetries := policy.MaxRetries
+		applied.MaxRetries = &maxRetries
+	}
+	if applied.TimeoutSeconds == nil && policy.Timeout > 0 {
+		timeout := int(math.Ceil(policy.Timeout.Seconds()))
+		applied.TimeoutSeconds = &timeout
+	}
+	if applied.RetryBackoff == nil && policy.Backoff != nil {
+		backoff := *policy.Backoff
+		applied.RetryBackoff = &backoff
+	}
+	return &applied
+}
+
+// applyBulk fills a bulk request's unset defaults from the policy shared by
+// all its jobs. Bulk requests have no per-job backoff, so Backoff is not
+// applied; jobs of different types are left to their queue's defaults.
+func (r *PolicyRegistry) applyBulk(req *BulkEnqueueRequest) *BulkEnqueueRequest {
+	if r == nil || len(req.Jobs) == 0 {
+		return req
+	}
+	jobType, _ := req.Jobs[0].Payload[PolicyTypeKey].(string)
+	for _, job := range req.Jobs[1:] {
+		if t, _ := job.Payload[PolicyTypeKey].(string); t != jobType {
+			return req
+		}
+	}
+	policy, ok := r.lookupPayload(req.Jobs[0].Payload)
+	if !ok {
+		return req
+	}
+	applied := *req
+	if applied.DefaultMaxRetries == nil && policy.MaxRetries > 0 {
+		maxRetries := policy.MaxRetries
+		applied.DefaultMaxRetries = &maxRetries
+	}
+	if applied.DefaultTimeoutSeconds == nil && policy.Timeout > 0 {
+		timeout := int(math.Ceil(policy.Timeout.Seconds()))
+		applied.DefaultTimeoutSeconds = &timeout
+	}
+	return &applied
+}
+
+// SetPolicyRegistry makes Create and BulkEnqueue apply policies from
+// registry. Passing nil disables them.
+func (r *JobsResource) SetPolicyRegistry(registry *PolicyRegistry) {
+	r.policies = registry
+}
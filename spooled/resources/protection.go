@@ -0,0 +1,89 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
+)
+
+// ErrConfirmationRequired is returned by destructive operations when
+// protection is enabled and the call is neither confirmed nor preceded by a
+// matching dry run.
+var ErrConfirmationRequired = errors.New("destructive operation requires confirmation")
+
+// DryRunValidity is how long a dry run authorizes the same operation on the
+// same target under protection.
+const DryRunValidity = 5 * time.Minute
+
+// Destructive operation names reported in audit events.
+const (
+	OperationPurgeDLQ    = "dlq.purge"
+	OperationDeleteQueue = "queue.delete"
+)
+
+// Protection guards destructive operations and emits an
+// audit.destructive_operation event for each attempt. When enabled, an
+// operation must set Confirm or follow a dry run of the same operation and
+// target within DryRunValidity; the dry run is used up by the real call.
+type Protection struct {
+	enabled bool
+	events  *sdkevents.Bus
+
+	mu      sync.Mutex
+	dryRuns map[string]time.Time
+}
+
+// NewProtection creates a Protection. Audit events are emitted on events even
+// when enabled is false.
+func NewProtection(enabled bool, events *sdkevents.Bus) *Protection {
+	return &Protection{enabled: enabled, events: events, dryRuns: make(map[string]time.Time)}
+}
+
+// Enabled reports whether destructive operations need confirmation.
+func (p *Protection) Enabled() bool {
+	return p != nil && p.enabled
+}
+
+// recordDryRun notes a dry run, authorizing the real call for a while.
+func (p *Protection) recordDryRun(operation, target string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dryRuns[operation+"\x00"+target] = time.Now().Add(DryRunValidity)
+}
+
+// check returns ErrConfirmationRequired unless the operation is allowed.
+func (p *Protection) check(operation, target string, confirm bool) error {
+	if !p.Enabled() || confirm {
+		return nil
+	}
+	key := operation + "\x00" + target
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until, ok := p.dryRuns[key]; ok && time.Now().Before(until) {
+		delete(p.dryRuns, key)
+		return nil
+	}
+	return fmt.Errorf("%w: %s on %q; set Confirm or run it with DryRun first", ErrConfirmationRequired, operation, target)
+}
+
+// audit emits an audit.destructive_operation event.
+func (p *Protection) audit(operation, target string, dryRun, confirmed bool, affected int, err error) {
+	if p == nil {
+		return
+	}
+	p.events.Emit(sdkevents.TypeDestructiveOperation, sdkevents.DestructiveOperationData{
+		Operation: operation,
+		Target:    target,
+		DryRun:    dryRun,
+		Confirmed: confirmed,
+		Protected: p.enabled,
+		Affected:  affected,
+		Error:     err,
+	})
+}
@@ -0,0 +1,110 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// QueueConfigConflictError is returned by Ensure when a queue exists with a
+// configuration different from the desired one.
+type QueueConfigConflictError struct {
+	QueueName string
+	// Differences describes each mismatched field, e.g.
+	// "max_retries: have 3, want 5".
+	Differences []string
+}
+
+func (e *QueueConfigConflictError) Error() string {
+	return fmt.Sprintf("queue %q exists with a different configuration: %s", e.QueueName, strings.Join(e.Differences, "; "))
+}
+
+// Ensure makes sure the queue exists with config: it creates the queue if it
+// is missing, returns it unchanged if its configuration matches, and returns
+// a *QueueConfigConflictError if it does not. Only the fields set in config
+// are compared, so queue configuration can be defined in code next to the
+// workers that use it. A nil config only checks that the queue exists,
+// creating it with the organization defaults.
+func (r *QueuesResource) Ensure(ctx context.Context, name string, config *UpdateQueueConfigRequest) (*QueueConfig, error) {
+	if config == nil {
+		config = &UpdateQueueConfigRequest{}
+	}
+	existing, err := r.Get(ctx, name)
+	if httpx.IsNotFoundError(err) {
+		return r.UpdateConfig(ctx, name, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if diffs := queueConfigDiff(existing, config); len(diffs) > 0 {
+		return nil, &QueueConfigConflictError{QueueName: existing.QueueName, Differences: diffs}
+	}
+	return existing, nil
+}
+
+// queueConfigDiff describes the fields set in want that differ in have.
+func queueConfigDiff(have *QueueConfig, want *UpdateQueueConfigRequest) []string {
+	var diffs []string
+	diff := func(field string, have, want any) {
+		diffs = append(diffs, fmt.Sprintf("%s: have %v, want %v", field, have, want))
+	}
+	if want.MaxRetries != nil && *want.MaxRetries != have.MaxRetries {
+		diff("max_retries", have.MaxRetries, *want.MaxRetries)
+	}
+	if want.DefaultTimeout != nil && *want.DefaultTimeout != have.DefaultTimeout {
+		diff("default_timeout", have.DefaultTimeout, *want.DefaultTimeout)
+	}
+	if want.RateLimit != nil && (have.RateLimit == nil || *have.RateLimit != *want.RateLimit) {
+		diff("rate_limit", formatOptional(have.RateLimit), *want.RateLimit)
+	}
+	if want.Enabled != nil && *want.Enabled != have.Enabled {
+		diff("enabled", have.Enabled, *want.Enabled)
+	}
+	if want.RunbookURL != nil && (have.RunbookURL == nil || *have.RunbookURL != *want.RunbookURL) {
+		diff("runbook_url", formatOptional(have.RunbookURL), *want.RunbookURL)
+	}
+	if want.OwnerTeam != nil && (have.OwnerTeam == nil || *have.OwnerTeam != *want.OwnerTeam) {
+		diff("owner_team", formatOptional(have.OwnerTeam), *want.OwnerTeam)
+	}
+
+	keys := make([]string, 0, len(want.Settings))
+	for k := range want.Settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		haveJSON, wantJSON := canonicalJSON(have.Settings[k]), canonicalJSON(want.Settings[k])
+		if !bytes.Equal(haveJSON, wantJSON) {
+			diff("settings."+k, string(haveJSON), string(wantJSON))
+		}
+	}
+	return diffs
+}
+
+// canonicalJSON encodes v with sorted object keys, so values decoded from
+// the API compare equal to the Go values they were created from.
+func canonicalJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return data
+	}
+	data, _ = json.Marshal(generic)
+	return data
+}
+
+// formatOptional formats an optional field for a conflict message.
+func formatOptional[T any](v *T) any {
+	if v == nil {
+		return "unset"
+	}
+	return *v
+}
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// QueueEventType identifies a queue state or configuration change.
+type QueueEventType string
+
+const (
+	QueueEventCreated       QueueEventType = "created"
+	QueueEventPaused        QueueEventType = "paused"
+	QueueEventResumed       QueueEventType = "resumed"
+	QueueEventConfigChanged QueueEventType = "config_changed"
+	QueueEventDeleted       QueueEventType = "deleted"
+)
+
+// QueueConfigChange is a single configuration field changed by an event.
+type QueueConfigChange struct {
+	Field    string `json:"field"`
+	OldValue any    `json:"old_value"`
+	NewValue any    `json:"new_value"`
+}
+
+// QueueHistoryEvent is an entry in a queue's audit history. Actor identifies
+// who made the change (an API key ID, user, or "system" for automatic pauses).
+type QueueHistoryEvent struct {
+	ID        string              `json:"id"`
+	QueueName string              `json:"queue_name"`
+	Type      QueueEventType      `json:"type"`
+	Actor     string              `json:"actor,omitempty"`
+	Reason    *string             `json:"reason,omitempty"`
+	Changes   []QueueConfigChange `json:"changes,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// QueueHistoryParams are parameters for listing queue history.
+type QueueHistoryParams struct {
+	Type   *QueueEventType
+	Since  *time.Time
+	Until  *time.Time
+	Limit  *int
+	Offset *int
+}
+
+// History retrieves a queue's pause, resume, and configuration change events,
+// newest first, e.g. to find out why a queue stopped processing.
+func (r *QueuesResource) History(ctx context.Context, name string, params *QueueHistoryParams) ([]QueueHistoryEvent, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Type != nil {
+			query.Set("type", string(*params.Type))
+		}
+		if params.Since != nil {
+			query.Set("since", params.Since.UTC().Format(time.RFC3339))
+		}
+		if params.Until != nil {
+			query.Set("until", params.Until.UTC().Format(time.RFC3339))
+		}
+		AddPaginationParams(query, params.Limit, params.Offset)
+	}
+
+	var result []QueueHistoryEvent
+	if err := r.base.GetWithQuery(ctx, fmt.Sprintf("/api/v1/queues/%s/history", r.base.queueName(ctx, name)), query, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
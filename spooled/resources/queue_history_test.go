@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueues_History(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/queues/emails/history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"ev-2","queue_name":"emails","type":"paused","actor":"key-ops","reason":"incident","created_at":"2024-01-01T03:00:00Z"},
+			{"id":"ev-1","queue_name":"emails","type":"config_changed","actor":"user-1","changes":[{"field":"max_retries","old_value":3,"new_value":0}],"created_at":"2024-01-01T02:00:00Z"}
+		]`))
+	})
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events, err := NewQueuesResource(api.transport()).History(context.Background(), "emails", &QueueHistoryParams{Since: &since})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if got := api.received("GET /api/v1/queues/emails/history")[0].Query.Get("since"); got != "2024-01-01T00:00:00Z" {
+		t.Errorf("since = %q, want %q", got, "2024-01-01T00:00:00Z")
+	}
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(events))
+	}
+	if events[0].Type != QueueEventPaused || events[0].Actor != "key-ops" {
+		t.Errorf("events[0] = %+v, want pause by key-ops", events[0])
+	}
+	if len(events[1].Changes) != 1 || events[1].Changes[0].Field != "max_retries" {
+		t.Errorf("events[1].Changes = %+v, want max_retries change", events[1].Changes)
+	}
+}
@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"context"
+	"time"
+)
+
+// LagThroughputWindow is the trailing window GetLag measures throughput over.
+const LagThroughputWindow = 15 * time.Minute
+
+// QueueLag is how far a queue's consumers are behind.
+type QueueLag struct {
+	QueueName     string
+	PendingJobs   int
+	ActiveWorkers int
+	// OldestPendingAge is how long the oldest pending job has waited (0 when
+	// nothing is pending).
+	OldestPendingAge time.Duration
+	// ThroughputPerMinute is the completion rate over LagThroughputWindow.
+	ThroughputPerMinute float64
+	// EstimatedDrainTime is how long the pending jobs will take at the current
+	// throughput. It is 0 when nothing is pending or when Stalled.
+	EstimatedDrainTime time.Duration
+	// Stalled is set when jobs are pending but none completed recently, so the
+	// drain time cannot be estimated.
+	Stalled bool
+}
+
+// GetLag returns a queue's consumer lag: the age of its oldest pending job and
+// how long its backlog will take to drain at recent throughput.
+//
+//	lag, err := client.Queues().GetLag(ctx, "emails")
+//	if lag.OldestPendingAge > 5*time.Minute { ... }
+func (r *QueuesResource) GetLag(ctx context.Context, name string) (*QueueLag, error) {
+	stats, err := r.GetStatsWithOptions(ctx, name, &StatsOptions{Window: LagThroughputWindow})
+	if err != nil {
+		return nil, err
+	}
+	return lagOf(stats), nil
+}
+
+// lagOf computes lag from queue stats. Older servers report neither the oldest
+// pending age nor throughput; the maximum job age and the completed count over
+// the window stand in for them.
+func lagOf(stats *QueueStats) *QueueLag {
+	lag := &QueueLag{
+		QueueName:     stats.QueueName,
+		PendingJobs:   stats.PendingJobs,
+		ActiveWorkers: stats.ActiveWorkers,
+	}
+	if stats.PendingJobs > 0 {
+		switch {
+		case stats.OldestPendingAgeSeconds != nil:
+			lag.OldestPendingAge = time.Duration(*stats.OldestPendingAgeSeconds) * time.Second
+		case stats.MaxJobAgeSeconds != nil:
+			lag.OldestPendingAge = time.Duration(*stats.MaxJobAgeSeconds) * time.Second
+		}
+	}
+	if stats.ThroughputPerMinute != nil {
+		lag.ThroughputPerMinute = *stats.ThroughputPerMinute
+	} else {
+		lag.ThroughputPerMinute = float64(stats.CompletedJobs24h) / LagThroughputWindow.Minutes()
+	}
+	if stats.PendingJobs > 0 {
+		if lag.ThroughputPerMinute > 0 {
+			lag.EstimatedDrainTime = time.Duration(float64(stats.PendingJobs) / lag.ThroughputPerMinute * float64(time.Minute))
+		} else {
+			lag.Stalled = true
+		}
+	}
+	return lag
+}
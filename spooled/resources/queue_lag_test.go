@@ -0,0 +1,37 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueues_GetLag(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("GET /api/v1/queues/emails/stats", http.StatusOK, map[string]any{
+		"queue_name": "emails", "pending_jobs": 120, "oldest_pending_age_seconds": 600, "throughput_per_minute": 60.0,
+	})
+	queues := NewQueuesResource(api.transport())
+	ctx := context.Background()
+
+	lag, err := queues.GetLag(ctx, "emails")
+	if err != nil {
+		t.Fatalf("GetLag: %v", err)
+	}
+	if lag.OldestPendingAge != 10*time.Minute || lag.EstimatedDrainTime != 2*time.Minute || lag.Stalled {
+		t.Errorf("GetLag = %+v, want 10m old and 2m to drain", lag)
+	}
+	if got := api.received("GET /api/v1/queues/emails/stats")[0].Query.Get("window_seconds"); got != "900" {
+		t.Errorf("window_seconds = %q, want 900", got)
+	}
+
+	// Older servers: fall back to max job age and completions over the window.
+	api.respond("GET /api/v1/queues/emails/stats", http.StatusOK, map[string]any{
+		"queue_name": "emails", "pending_jobs": 5, "max_job_age_seconds": 30, "completed_jobs_24h": 0,
+	})
+	lag, err = queues.GetLag(ctx, "emails")
+	if err != nil || lag.OldestPendingAge != 30*time.Second || !lag.Stalled || lag.EstimatedDrainTime != 0 {
+		t.Errorf("GetLag(old server) = %+v, %v; want 30s old and stalled", lag, err)
+	}
+}
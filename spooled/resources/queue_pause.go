@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QueueResumeQueue is the queue that holds resume jobs scheduled by
+// PauseUntil on servers without auto-resume support. Run a worker for it with
+// spooled.NewQueueResumeWorker.
+const QueueResumeQueue = "spooled-queue-resume"
+
+// queueResumePayload is the payload of a resume job.
+type queueResumePayload struct {
+	QueueName string `json:"queue_name"`
+}
+
+// PauseFor pauses a queue and resumes it automatically after d, so a
+// maintenance pause cannot be forgotten. See PauseUntil.
+func (r *QueuesResource) PauseFor(ctx context.Context, name string, d time.Duration) (*PauseQueueResponse, error) {
+	return r.PauseUntil(ctx, name, r.base.transport.Now().Add(d))
+}
+
+// PauseUntil pauses a queue and resumes it automatically at t. The server
+// resumes the queue itself when it supports auto-resume. Otherwise a resume
+// job is scheduled in QueueResumeQueue for t and its ID is returned in
+// ResumeJobID; cancel that job to keep the queue paused.
+func (r *QueuesResource) PauseUntil(ctx context.Context, name string, t time.Time) (*PauseQueueResponse, error) {
+	if !t.After(r.base.transport.Now()) {
+		return nil, fmt.Errorf("resume time %s is not in the future", t.Format(time.RFC3339))
+	}
+	t = t.UTC().Truncate(time.Second)
+	result, err := r.Pause(ctx, name, &PauseQueueRequest{ResumeAt: &t})
+	if err != nil {
+		return nil, err
+	}
+	if result.ResumeAt != nil {
+		return result, nil
+	}
+
+	// The server ignored resume_at; schedule the resume ourselves
+	queue := r.base.queueName(ctx, name)
+	key := fmt.Sprintf("queue-resume:%s:%d", queue, t.Unix())
+	job := &CreateJobRequest{
+		QueueName:      r.base.queueName(ctx, QueueResumeQueue),
+		Payload:        map[string]any{"queue_name": queue, "resume_at": t.Unix()},
+		ScheduledAt:    &t,
+		IdempotencyKey: &key,
+	}
+	var created CreateJobResponse
+	if err := r.base.PostIdempotent(ctx, "/api/v1/jobs", job, &created); err != nil {
+		return nil, fmt.Errorf("queue %s paused, but scheduling its resume failed: %w", queue, err)
+	}
+	result.ResumeAt = &t
+	result.ResumeJobID = &created.ID
+	return result, nil
+}
+
+// HandleResumeJob resumes the queue named by a resume job scheduled by
+// PauseUntil.
+func (r *QueuesResource) HandleResumeJob(ctx context.Context, job *Job) (*ResumeQueueResponse, error) {
+	var payload queueResumePayload
+	if err := remarshal(job.Payload, &payload, false); err != nil || payload.QueueName == "" {
+		return nil, fmt.Errorf("job %s is not a queue resume job", job.ID)
+	}
+	// The payload holds the prefixed name
+	return r.Resume(WithRawQueueNames(ctx), payload.QueueName)
+}
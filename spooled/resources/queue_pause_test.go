@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueues_PauseUntil(t *testing.T) {
+	var serverResume atomic.Bool
+	serverResume.Store(true)
+	api := newFakeAPI(t)
+	api.handle("POST /api/v1/queues/emails/pause", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		resp := map[string]any{"queue_name": "emails", "paused": true, "paused_at": "2026-01-01T00:00:00Z"}
+		if serverResume.Load() {
+			resp["resume_at"] = body["resume_at"]
+		}
+		writeTestJSON(w, http.StatusOK, resp)
+	})
+	api.respond("POST /api/v1/jobs", http.StatusOK, map[string]any{"id": "job-resume", "created": true})
+	api.respond("POST /api/v1/queues/emails/resume", http.StatusOK, map[string]any{"queue_name": "emails", "resumed": true})
+	queues := NewQueuesResource(api.transport())
+	ctx := context.Background()
+
+	resp, err := queues.PauseFor(ctx, "emails", time.Hour)
+	if err != nil {
+		t.Fatalf("PauseFor: %v", err)
+	}
+	var pauseBody map[string]any
+	api.received("POST /api/v1/queues/emails/pause")[0].decode(t, &pauseBody)
+	if pauseBody["resume_at"] == nil || resp.ResumeAt == nil || resp.ResumeJobID != nil || len(api.received("POST /api/v1/jobs")) != 0 {
+		t.Errorf("server-side resume: got body %v, response %+v", pauseBody, resp)
+	}
+
+	// Servers without auto-resume get a scheduled resume job
+	serverResume.Store(false)
+	until := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	resp, err = queues.PauseUntil(ctx, "emails", until)
+	if err != nil {
+		t.Fatalf("PauseUntil: %v", err)
+	}
+	if resp.ResumeJobID == nil || *resp.ResumeJobID != "job-resume" || !resp.ResumeAt.Equal(until) {
+		t.Errorf("fallback response = %+v, want resume job job-resume at %s", resp, until)
+	}
+	var job map[string]any
+	api.received("POST /api/v1/jobs")[0].decode(t, &job)
+	if job["queue_name"] != QueueResumeQueue || job["scheduled_at"] == nil || job["idempotency_key"] == nil {
+		t.Errorf("resume job = %v", job)
+	}
+
+	payload, _ := job["payload"].(map[string]any)
+	if _, err := queues.HandleResumeJob(ctx, &Job{ID: "job-resume", Payload: payload}); err != nil {
+		t.Fatalf("HandleResumeJob: %v", err)
+	}
+	if len(api.received("POST /api/v1/queues/emails/resume")) != 1 {
+		t.Errorf("HandleResumeJob did not resume the queue")
+	}
+
+	if _, err := queues.PauseUntil(ctx, "emails", time.Now().Add(-time.Minute)); err == nil {
+		t.Error("PauseUntil in the past: expected error")
+	}
+}
@@ -0,0 +1,52 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueues_SetRetention(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("PUT /api/v1/queues/thumbnails/retention", http.StatusOK, map[string]any{"completed_ttl_seconds": 3600, "result_ttl_seconds": 600})
+	api.respond("POST /api/v1/jobs", http.StatusOK, map[string]any{"id": "job-1", "created": true})
+	queues := NewQueuesResource(api.transport())
+
+	policy, err := queues.SetRetention(context.Background(), "thumbnails", RetentionPolicy{
+		CompletedTTL: time.Hour,
+		ResultTTL:    10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("SetRetention: %v", err)
+	}
+	var body map[string]any
+	api.received("PUT /api/v1/queues/thumbnails/retention")[0].decode(t, &body)
+	if body["completed_ttl_seconds"] != float64(3600) || body["result_ttl_seconds"] != float64(600) {
+		t.Errorf("body = %v", body)
+	}
+	if _, ok := body["failed_ttl_seconds"]; ok {
+		t.Errorf("body = %v, want failed_ttl_seconds omitted", body)
+	}
+	if policy.CompletedTTL != time.Hour || policy.ResultTTL != 10*time.Minute || policy.FailedTTL != 0 {
+		t.Errorf("policy = %+v", policy)
+	}
+
+	if _, err := queues.SetRetention(context.Background(), "thumbnails", RetentionPolicy{FailedTTL: -time.Second}); err == nil {
+		t.Error("SetRetention() with a negative TTL: expected error")
+	}
+
+	ttl := 60
+	if _, err := NewJobsResource(api.transport()).Create(context.Background(), &CreateJobRequest{
+		QueueName:        "thumbnails",
+		Payload:          map[string]any{},
+		ResultTTLSeconds: &ttl,
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var job CreateJobRequest
+	api.received("POST /api/v1/jobs")[0].decode(t, &job)
+	if job.ResultTTLSeconds == nil || *job.ResultTTLSeconds != 60 {
+		t.Errorf("ResultTTLSeconds = %v, want 60", job.ResultTTLSeconds)
+	}
+}
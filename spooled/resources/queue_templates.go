@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// QueueDLQPolicy configures dead-letter handling for a queue.
+type QueueDLQPolicy struct {
+	Enabled       bool `json:"enabled"`
+	RetentionDays *int `json:"retention_days,omitempty"`
+}
+
+// QueueTemplate bundles everything needed to provision a queue.
+type QueueTemplate struct {
+	MaxRetries     *int
+	DefaultTimeout *int
+	RateLimit      *int
+	Enabled        *bool
+	DeadLetter     *QueueDLQPolicy
+	// Settings are additional queue settings merged into the queue config.
+	Settings map[string]any
+	// Webhooks are created alongside the queue.
+	Webhooks []CreateOutgoingWebhookRequest
+	// Schedules are created alongside the queue. QueueName is set to the new queue.
+	Schedules []CreateScheduleRequest
+}
+
+// QueueProvisionResult contains everything created from a template.
+type QueueProvisionResult struct {
+	Queue     *QueueConfig
+	Webhooks  []OutgoingWebhook
+	Schedules []Schedule
+}
+
+// QueueTemplateError is returned when provisioning from a template fails.
+// Everything created before the failure has been rolled back; any rollback
+// failures are reported in RollbackErrors.
+type QueueTemplateError struct {
+	QueueName      string
+	Step           string
+	Err            error
+	RollbackErrors []error
+}
+
+func (e *QueueTemplateError) Error() string {
+	msg := fmt.Sprintf("provision queue %q: %s: %v", e.QueueName, e.Step, e.Err)
+	if len(e.RollbackErrors) > 0 {
+		parts := make([]string, len(e.RollbackErrors))
+		for i, err := range e.RollbackErrors {
+			parts[i] = err.Error()
+		}
+		msg += fmt.Sprintf(" (rollback failed: %s)", strings.Join(parts, "; "))
+	}
+	return msg
+}
+
+func (e *QueueTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// CreateFromTemplate provisions a queue together with its webhooks and schedules.
+// If any step fails, everything created so far is deleted again so the
+// organization is left unchanged. The queue must not already exist.
+func (r *QueuesResource) CreateFromTemplate(ctx context.Context, name string, template QueueTemplate) (*QueueProvisionResult, error) {
+	if _, err := r.Get(ctx, name); err == nil {
+		return nil, &QueueTemplateError{QueueName: name, Step: "check queue", Err: fmt.Errorf("queue already exists")}
+	} else if !httpx.IsNotFoundError(err) {
+		return nil, &QueueTemplateError{QueueName: name, Step: "check queue", Err: err}
+	}
+
+	webhooks := NewWebhooksResource(r.base.transport)
+	schedules := NewSchedulesResource(r.base.transport)
+
+	result := &QueueProvisionResult{}
+	var undo []func(context.Context) error
+
+	fail := func(step string, err error) (*QueueProvisionResult, error) {
+		// Roll back even if the caller's context was cancelled
+		rollbackCtx := context.WithoutCancel(ctx)
+		tErr := &QueueTemplateError{QueueName: name, Step: step, Err: err}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if rbErr := undo[i](rollbackCtx); rbErr != nil && !httpx.IsNotFoundError(rbErr) {
+				tErr.RollbackErrors = append(tErr.RollbackErrors, rbErr)
+			}
+		}
+		return nil, tErr
+	}
+
+	settings := make(map[string]any, len(template.Settings)+1)
+	for k, v := range template.Settings {
+		settings[k] = v
+	}
+	if template.DeadLetter != nil {
+		settings["dead_letter"] = template.DeadLetter
+	}
+	queueReq := &UpdateQueueConfigRequest{
+		MaxRetries:     template.MaxRetries,
+		DefaultTimeout: template.DefaultTimeout,
+		RateLimit:      template.RateLimit,
+		Enabled:        template.Enabled,
+	}
+	if len(settings) > 0 {
+		queueReq.Settings = settings
+	}
+
+	// Registered up front: the queue may exist even if the response was lost.
+	// Confirmed, since under protection rolling back must still remove it
+	undo = append(undo, func(ctx context.Context) error {
+		return r.DeleteWithOptions(ctx, name, &DeleteQueueOptions{Confirm: true})
+	})
+	queue, err := r.UpdateConfig(ctx, name, queueReq)
+	if err != nil {
+		return fail("create queue", err)
+	}
+	result.Queue = queue
+
+	for i := range template.Webhooks {
+		req := template.Webhooks[i]
+		webhook, err := webhooks.Create(ctx, &req)
+		if err != nil {
+			return fail(fmt.Sprintf("create webhook %q", req.Name), err)
+		}
+		result.Webhooks = append(result.Webhooks, *webhook)
+		id := webhook.ID
+		undo = append(undo, func(ctx context.Context) error { return webhooks.Delete(ctx, id) })
+	}
+
+	for i := range template.Schedules {
+		req := template.Schedules[i]
+		req.QueueName = name
+		schedule, err := schedules.Create(ctx, &req)
+		if err != nil {
+			return fail(fmt.Sprintf("create schedule %q", req.Name), err)
+		}
+		result.Schedules = append(result.Schedules, *schedule)
+		id := schedule.ID
+		undo = append(undo, func(ctx context.Context) error { return schedules.Delete(ctx, id) })
+	}
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCreateFromTemplate_RollsBack(t *testing.T) {
+	template := QueueTemplate{
+		Webhooks:  []CreateOutgoingWebhookRequest{{Name: "alerts", URL: "https://hooks.example.com"}},
+		Schedules: []CreateScheduleRequest{{Name: "nightly", CronExpression: "0 0 * * *"}},
+	}
+	tests := []struct {
+		name        string
+		failAt      string
+		protected   bool
+		wantStep    string
+		wantDeleted []string
+	}{
+		{"webhook", "POST /api/v1/outgoing-webhooks", false, `create webhook "alerts"`, []string{
+			"DELETE /api/v1/queues/emails",
+		}},
+		{"schedule", "POST /api/v1/schedules", false, `create schedule "nightly"`, []string{
+			"DELETE /api/v1/outgoing-webhooks/wh-1",
+			"DELETE /api/v1/queues/emails",
+		}},
+		{"schedule with protection", "POST /api/v1/schedules", true, `create schedule "nightly"`, []string{
+			"DELETE /api/v1/outgoing-webhooks/wh-1",
+			"DELETE /api/v1/queues/emails",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.respond("PUT /api/v1/queues/emails", http.StatusOK, QueueConfig{QueueName: "emails"})
+			api.respond("POST /api/v1/outgoing-webhooks", http.StatusCreated, OutgoingWebhook{ID: "wh-1", Name: "alerts"})
+			api.respond("POST /api/v1/schedules", http.StatusCreated, Schedule{ID: "sched-1", Name: "nightly"})
+			api.respond(tt.failAt, http.StatusUnprocessableEntity, map[string]any{"code": "validation_error", "message": "invalid"})
+			for _, route := range tt.wantDeleted {
+				api.respond(route, http.StatusNoContent, nil)
+			}
+
+			queues := NewQueuesResource(api.transport())
+			if tt.protected {
+				queues.SetProtection(NewProtection(true, nil))
+			}
+			_, err := queues.CreateFromTemplate(context.Background(), "emails", template)
+			var tErr *QueueTemplateError
+			if !errors.As(err, &tErr) || tErr.Step != tt.wantStep {
+				t.Fatalf("CreateFromTemplate() = %v, want a failure at %s", err, tt.wantStep)
+			}
+			if len(tErr.RollbackErrors) > 0 {
+				t.Errorf("RollbackErrors = %v, want none", tErr.RollbackErrors)
+			}
+
+			var deleted []string
+			for _, req := range api.received("") {
+				if req.Method == http.MethodDelete {
+					deleted = append(deleted, req.Method+" "+req.Path)
+				}
+			}
+			if len(deleted) != len(tt.wantDeleted) {
+				t.Fatalf("deleted %v, want %v", deleted, tt.wantDeleted)
+			}
+			for i := range deleted {
+				if deleted[i] != tt.wantDeleted[i] {
+					t.Errorf("deleted %v, want %v", deleted, tt.wantDeleted)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCreateFromTemplate_ExistingQueue(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("GET /api/v1/queues/emails", http.StatusOK, QueueConfig{QueueName: "emails"})
+
+	_, err := NewQueuesResource(api.transport()).CreateFromTemplate(context.Background(), "emails", QueueTemplate{})
+	var tErr *QueueTemplateError
+	if !errors.As(err, &tErr) || tErr.Step != "check queue" {
+		t.Fatalf("CreateFromTemplate() = %v, want a check queue failure", err)
+	}
+	if got := len(api.received("PUT /api/v1/queues/emails")); got != 0 {
+		t.Errorf("queue updated %d times, want 0", got)
+	}
+}
@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DefaultCompletionWebhookEvents are the events a queue completion webhook
+// receives when none are given.
+var DefaultCompletionWebhookEvents = []WebhookEvent{WebhookEventJobCompleted, WebhookEventJobFailed}
+
+// WebhookSigningFormat selects how completion webhook deliveries are signed.
+type WebhookSigningFormat string
+
+const (
+	// WebhookSigningHMAC signs deliveries with the X-Spooled-Signature,
+	// X-Spooled-Timestamp, and X-Spooled-Delivery-Id headers, like outgoing
+	// webhooks.
+	WebhookSigningHMAC WebhookSigningFormat = "hmac-sha256"
+	// WebhookSigningJWS signs deliveries with a detached HS256 JWS in the
+	// X-Spooled-JWS header, for receivers that verify with a JOSE library.
+	WebhookSigningJWS WebhookSigningFormat = "jws"
+)
+
+// WebhookSigning makes the server sign completion webhook deliveries, so the
+// receiver can authenticate them with the webhook package's Verifier.
+type WebhookSigning struct {
+	// Secret is the shared signing key (required). It is never returned by
+	// the API.
+	Secret string `json:"secret"`
+	// Format defaults to WebhookSigningHMAC.
+	Format WebhookSigningFormat `json:"format,omitempty"`
+}
+
+// validate checks a signing configuration before it is sent.
+func (s *WebhookSigning) validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.Secret == "" {
+		return fmt.Errorf("webhook signing secret is required")
+	}
+	switch s.Format {
+	case "", WebhookSigningHMAC, WebhookSigningJWS:
+		return nil
+	}
+	return fmt.Errorf("unknown webhook signing format %q", s.Format)
+}
+
+// QueueCompletionWebhook is a queue's default completion webhook. It is called
+// for every job in the queue that does not set its own CompletionWebhook; a
+// job-level URL always takes precedence.
+type QueueCompletionWebhook struct {
+	QueueName string         `json:"queue_name"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	// Signing is the format deliveries are signed in, or empty if they are
+	// unsigned.
+	Signing   WebhookSigningFormat `json:"signing,omitempty"`
+	UpdatedAt *time.Time           `json:"updated_at,omitempty"`
+}
+
+// CompletionWebhookOptions configures SetCompletionWebhookWithOptions.
+type CompletionWebhookOptions struct {
+	// Events selects the job events delivered (default:
+	// DefaultCompletionWebhookEvents).
+	Events []WebhookEvent
+	// Signing signs deliveries (optional). Jobs in the queue that set their
+	// own CompletionWebhookSigning use that instead.
+	Signing *WebhookSigning
+}
+
+// setCompletionWebhookRequest is the request to bind a completion webhook to a queue.
+type setCompletionWebhookRequest struct {
+	URL     string          `json:"url"`
+	Events  []WebhookEvent  `json:"events"`
+	Signing *WebhookSigning `json:"signing,omitempty"`
+}
+
+// SetCompletionWebhook binds a completion webhook to a queue, so producers
+// don't need to set CompletionWebhook on each Create. events selects the job
+// events delivered (default: DefaultCompletionWebhookEvents). Jobs created
+// with their own CompletionWebhook are delivered there instead.
+func (r *QueuesResource) SetCompletionWebhook(ctx context.Context, name, webhookURL string, events []WebhookEvent) (*QueueCompletionWebhook, error) {
+	return r.SetCompletionWebhookWithOptions(ctx, name, webhookURL, &CompletionWebhookOptions{Events: events})
+}
+
+// SetCompletionWebhookWithOptions binds a completion webhook to a queue like
+// SetCompletionWebhook, optionally signing deliveries so the receiver can
+// authenticate them:
+//
+//	client.Queues().SetCompletionWebhookWithOptions(ctx, "invoices", "https://partner.example.com/spooled", &resources.CompletionWebhookOptions{
+//		Signing: &resources.WebhookSigning{Secret: secret},
+//	})
+func (r *QueuesResource) SetCompletionWebhookWithOptions(ctx context.Context, name, webhookURL string, opts *CompletionWebhookOptions) (*QueueCompletionWebhook, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid completion webhook URL %q", webhookURL)
+	}
+	if opts == nil {
+		opts = &CompletionWebhookOptions{}
+	}
+	if err := opts.Signing.validate(); err != nil {
+		return nil, err
+	}
+	events := opts.Events
+	if len(events) == 0 {
+		events = DefaultCompletionWebhookEvents
+	}
+
+	req := &setCompletionWebhookRequest{URL: webhookURL, Events: events, Signing: opts.Signing}
+	var result QueueCompletionWebhook
+	if err := r.base.Put(ctx, r.completionWebhookPath(ctx, name), req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCompletionWebhook retrieves a queue's completion webhook binding.
+func (r *QueuesResource) GetCompletionWebhook(ctx context.Context, name string) (*QueueCompletionWebhook, error) {
+	var result QueueCompletionWebhook
+	if err := r.base.Get(ctx, r.completionWebhookPath(ctx, name), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteCompletionWebhook removes a queue's completion webhook binding. Jobs
+// that set their own CompletionWebhook are unaffected.
+func (r *QueuesResource) DeleteCompletionWebhook(ctx context.Context, name string) error {
+	return r.base.Delete(ctx, r.completionWebhookPath(ctx, name))
+}
+
+func (r *QueuesResource) completionWebhookPath(ctx context.Context, name string) string {
+	return fmt.Sprintf("/api/v1/queues/%s/completion-webhook", r.base.queueName(ctx, name))
+}
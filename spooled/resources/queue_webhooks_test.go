@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestQueues_SetCompletionWebhook(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("PUT /api/v1/queues/emails/completion-webhook", http.StatusOK, map[string]any{
+		"queue_name": "emails", "url": "https://example.com/hook", "events": []string{"job.completed", "job.failed"},
+	})
+	queues := NewQueuesResource(api.transport())
+
+	hook, err := queues.SetCompletionWebhook(context.Background(), "emails", "https://example.com/hook", nil)
+	if err != nil {
+		t.Fatalf("SetCompletionWebhook: %v", err)
+	}
+	// Events default to completion and failure
+	var body map[string]any
+	api.received("PUT /api/v1/queues/emails/completion-webhook")[0].decode(t, &body)
+	if events, _ := body["events"].([]any); len(events) != 2 {
+		t.Errorf("events = %v, want [job.completed job.failed]", body["events"])
+	}
+	if hook.URL != "https://example.com/hook" {
+		t.Errorf("URL = %q, want %q", hook.URL, "https://example.com/hook")
+	}
+
+	if _, err := queues.SetCompletionWebhook(context.Background(), "emails", "not a url", nil); err == nil {
+		t.Error("expected error for invalid URL")
+	}
+}
+
+func TestCompletionWebhookSigning(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("PUT /api/v1/queues/invoices/completion-webhook", http.StatusOK, map[string]any{
+		"queue_name": "invoices", "url": "https://partner.example.com/hook", "events": []string{"job.completed"}, "signing": "jws",
+	})
+	api.respond("POST /api/v1/jobs", http.StatusOK, map[string]any{"id": "job-1", "created": true})
+	ctx := context.Background()
+
+	hook, err := NewQueuesResource(api.transport()).SetCompletionWebhookWithOptions(ctx, "invoices", "https://partner.example.com/hook", &CompletionWebhookOptions{
+		Events:  []WebhookEvent{WebhookEventJobCompleted},
+		Signing: &WebhookSigning{Secret: "whsec_partner", Format: WebhookSigningJWS},
+	})
+	if err != nil || hook.Signing != WebhookSigningJWS {
+		t.Fatalf("SetCompletionWebhookWithOptions = %+v, %v", hook, err)
+	}
+	var queueBody map[string]any
+	api.received("PUT /api/v1/queues/invoices/completion-webhook")[0].decode(t, &queueBody)
+	if signing, _ := queueBody["signing"].(map[string]any); signing["secret"] != "whsec_partner" || signing["format"] != "jws" {
+		t.Errorf("queue signing sent = %v", signing)
+	}
+
+	jobs := NewJobsResource(api.transport())
+	_, err = jobs.Create(ctx, &CreateJobRequest{
+		QueueName:                "invoices",
+		Payload:                  map[string]any{"invoice": 7},
+		CompletionWebhook:        &hook.URL,
+		CompletionWebhookSigning: &WebhookSigning{Secret: "whsec_job"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var jobBody map[string]any
+	api.received("POST /api/v1/jobs")[0].decode(t, &jobBody)
+	if signing, _ := jobBody["completion_webhook_signing"].(map[string]any); signing["secret"] != "whsec_job" {
+		t.Errorf("job signing sent = %v", signing)
+	}
+
+	for _, bad := range []*WebhookSigning{{}, {Secret: "s", Format: "rsa"}} {
+		_, err := jobs.Create(ctx, &CreateJobRequest{QueueName: "invoices", Payload: map[string]any{}, CompletionWebhookSigning: bad})
+		if err == nil {
+			t.Errorf("Create with signing %+v should fail", bad)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
@@ -10,7 +11,9 @@ import (
 
 // QueuesResource provides access to queue operations.
 type QueuesResource struct {
-	base *Base
+	base       *Base
+	redactor   PayloadRedactor
+	protection *Protection
 }
 
 // NewQueuesResource creates a new QueuesResource.
@@ -37,23 +40,35 @@ type QueueConfig struct {
 	RateLimit      *int           `json:"rate_limit,omitempty"`
 	Enabled        bool           `json:"enabled"`
 	Settings       map[string]any `json:"settings"`
+	RunbookURL     *string        `json:"runbook_url,omitempty"`
+	OwnerTeam      *string        `json:"owner_team,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
 // List retrieves all queue configurations.
+// With a queue prefix configured, only queues carrying the prefix are returned.
 func (r *QueuesResource) List(ctx context.Context) ([]QueueListItem, error) {
 	var result []QueueListItem
 	if err := r.base.Get(ctx, "/api/v1/queues", &result); err != nil {
 		return nil, err
 	}
+	if prefix := r.base.queuePrefix(ctx); prefix != "" {
+		filtered := result[:0]
+		for _, q := range result {
+			if strings.HasPrefix(q.QueueName, prefix) {
+				filtered = append(filtered, q)
+			}
+		}
+		result = filtered
+	}
 	return result, nil
 }
 
 // Get retrieves a specific queue configuration.
 func (r *QueuesResource) Get(ctx context.Context, name string) (*QueueConfig, error) {
 	var result QueueConfig
-	if err := r.base.Get(ctx, fmt.Sprintf("/api/v1/queues/%s", name), &result); err != nil {
+	if err := r.base.Get(ctx, fmt.Sprintf("/api/v1/queues/%s", r.base.queueName(ctx, name)), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -61,16 +76,21 @@ func (r *QueuesResource) Get(ctx context.Context, name string) (*QueueConfig, er
 
 // UpdateQueueConfigRequest is the request to update queue configuration.
 type UpdateQueueConfigRequest struct {
-	MaxRetries     *int  `json:"max_retries,omitempty"`
-	DefaultTimeout *int  `json:"default_timeout,omitempty"`
-	RateLimit      *int  `json:"rate_limit,omitempty"`
-	Enabled        *bool `json:"enabled,omitempty"`
+	MaxRetries     *int           `json:"max_retries,omitempty"`
+	DefaultTimeout *int           `json:"default_timeout,omitempty"`
+	RateLimit      *int           `json:"rate_limit,omitempty"`
+	Enabled        *bool          `json:"enabled,omitempty"`
+	Settings       map[string]any `json:"settings,omitempty"`
+	// RunbookURL and OwnerTeam are the defaults for jobs in the queue that do
+	// not set their own.
+	RunbookURL *string `json:"runbook_url,omitempty"`
+	OwnerTeam  *string `json:"owner_team,omitempty"`
 }
 
 // UpdateConfig updates a queue's configuration.
 func (r *QueuesResource) UpdateConfig(ctx context.Context, name string, req *UpdateQueueConfigRequest) (*QueueConfig, error) {
 	var result QueueConfig
-	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/queues/%s", name), req, &result); err != nil {
+	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/queues/%s", r.base.queueName(ctx, name)), req, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -86,12 +106,27 @@ type QueueStats struct {
 	AvgProcessingTimeMs *int   `json:"avg_processing_time_ms,omitempty"`
 	MaxJobAgeSeconds    *int   `json:"max_job_age_seconds,omitempty"`
 	ActiveWorkers       int    `json:"active_workers"`
+
+	// Metrics over the stats window. Older servers omit them.
+	P95ProcessingTimeMs     *int     `json:"p95_processing_time_ms,omitempty"`
+	OldestPendingAgeSeconds *int     `json:"oldest_pending_age_seconds,omitempty"`
+	ThroughputPerMinute     *float64 `json:"throughput_per_minute,omitempty"`
+	// Groups breaks the stats down when StatsOptions.GroupBy is set.
+	Groups []StatsGroup `json:"groups,omitempty"`
 }
 
 // GetStats retrieves statistics for a queue.
 func (r *QueuesResource) GetStats(ctx context.Context, name string) (*QueueStats, error) {
+	return r.GetStatsWithOptions(ctx, name, nil)
+}
+
+// GetStatsWithOptions retrieves statistics for a queue over a time window,
+// optionally broken down by status or hour. The CompletedJobs24h and
+// FailedJobs24h counts cover the window when one is set.
+func (r *QueuesResource) GetStatsWithOptions(ctx context.Context, name string, opts *StatsOptions) (*QueueStats, error) {
 	var result QueueStats
-	if err := r.base.Get(ctx, fmt.Sprintf("/api/v1/queues/%s/stats", name), &result); err != nil {
+	path := fmt.Sprintf("/api/v1/queues/%s/stats", r.base.queueName(ctx, name))
+	if err := r.base.GetWithQuery(ctx, path, opts.query(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -100,6 +135,8 @@ func (r *QueuesResource) GetStats(ctx context.Context, name string) (*QueueStats
 // PauseQueueRequest is the request to pause a queue.
 type PauseQueueRequest struct {
 	Reason *string `json:"reason,omitempty"`
+	// ResumeAt makes the server resume the queue automatically; see PauseUntil.
+	ResumeAt *time.Time `json:"resume_at,omitempty"`
 }
 
 // PauseQueueResponse is the response from pausing a queue.
@@ -108,6 +145,11 @@ type PauseQueueResponse struct {
 	Paused    bool      `json:"paused"`
 	PausedAt  time.Time `json:"paused_at"`
 	Reason    *string   `json:"reason,omitempty"`
+	// ResumeAt is when the queue resumes automatically, if scheduled.
+	ResumeAt *time.Time `json:"resume_at,omitempty"`
+	// ResumeJobID is the resume job PauseUntil scheduled because the server
+	// does not support auto-resume.
+	ResumeJobID *string `json:"-"`
 }
 
 // Pause pauses a queue.
@@ -117,7 +159,7 @@ func (r *QueuesResource) Pause(ctx context.Context, name string, req *PauseQueue
 	if body == nil {
 		body = &PauseQueueRequest{}
 	}
-	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/queues/%s/pause", name), body, &result); err != nil {
+	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/queues/%s/pause", r.base.queueName(ctx, name)), body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -133,13 +175,54 @@ type ResumeQueueResponse struct {
 // Resume resumes a paused queue.
 func (r *QueuesResource) Resume(ctx context.Context, name string) (*ResumeQueueResponse, error) {
 	var result ResumeQueueResponse
-	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/queues/%s/resume", name), nil, &result); err != nil {
+	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/queues/%s/resume", r.base.queueName(ctx, name)), nil, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// Delete deletes a queue configuration.
+// Delete deletes a queue configuration. Under WithProtection it fails with
+// ErrConfirmationRequired; use DeleteWithOptions.
 func (r *QueuesResource) Delete(ctx context.Context, name string) error {
-	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/queues/%s", name))
+	return r.DeleteWithOptions(ctx, name, nil)
+}
+
+// DeleteQueueOptions configures DeleteWithOptions.
+type DeleteQueueOptions struct {
+	// Confirm acknowledges the deletion when the client uses WithProtection.
+	Confirm bool
+	// DryRun checks that the queue exists without deleting it. Under
+	// protection it also authorizes the same deletion for DryRunValidity.
+	DryRun bool
+}
+
+// DeleteWithOptions deletes a queue configuration, or with DryRun only checks
+// that it exists.
+func (r *QueuesResource) DeleteWithOptions(ctx context.Context, name string, opts *DeleteQueueOptions) error {
+	if opts == nil {
+		opts = &DeleteQueueOptions{}
+	}
+	if opts.DryRun {
+		_, err := r.Get(ctx, name)
+		r.protection.audit(OperationDeleteQueue, name, true, false, 0, err)
+		if err != nil {
+			return err
+		}
+		r.protection.recordDryRun(OperationDeleteQueue, name)
+		return nil
+	}
+	if err := r.protection.check(OperationDeleteQueue, name, opts.Confirm); err != nil {
+		r.protection.audit(OperationDeleteQueue, name, false, false, 0, err)
+		return err
+	}
+
+	err := r.base.Delete(ctx, fmt.Sprintf("/api/v1/queues/%s", r.base.queueName(ctx, name)))
+	r.protection.audit(OperationDeleteQueue, name, false, opts.Confirm, 0, err)
+	return err
+}
+
+// SetProtection guards queue deletion with protection and audits it.
+// Passing nil removes it.
+func (r *QueuesResource) SetProtection(protection *Protection) {
+	r.protection = protection
 }
@@ -0,0 +1,106 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/cron"
+)
+
+// QuietPeriodHorizon is how far ahead NextQuietPeriod searches.
+const QuietPeriodHorizon = 7 * 24 * time.Hour
+
+// MinScheduledRunDuration is how long a scheduled run is assumed to keep its
+// queue busy when the schedule's timeout is shorter or unset.
+const MinScheduledRunDuration = time.Minute
+
+// ErrNoQuietPeriod is returned when schedules leave no quiet period of the
+// requested length within QuietPeriodHorizon.
+var ErrNoQuietPeriod = errors.New("no quiet period found")
+
+// QuietPeriod is a time range in which no schedule runs.
+type QuietPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NextQuietPeriod returns the first period of length d, starting at or after
+// from, that does not overlap a run of any active schedule. Each run is
+// assumed to last the schedule's TimeoutSeconds, but at least
+// MinScheduledRunDuration.
+func NextQuietPeriod(schedules []Schedule, d time.Duration, from time.Time) (*QuietPeriod, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("quiet period duration must be positive")
+	}
+
+	type busy struct {
+		cron     *cron.Schedule
+		loc      *time.Location
+		duration time.Duration
+	}
+	var runs []busy
+	for _, s := range schedules {
+		if !s.IsActive {
+			continue
+		}
+		parsed, err := cron.Parse(s.CronExpression)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		loc := time.UTC
+		if s.Timezone != "" {
+			if loc, err = time.LoadLocation(s.Timezone); err != nil {
+				return nil, fmt.Errorf("schedule %s: %w", s.Name, err)
+			}
+		}
+		runs = append(runs, busy{
+			cron:     parsed,
+			loc:      loc,
+			duration: max(time.Duration(s.TimeoutSeconds)*time.Second, MinScheduledRunDuration),
+		})
+	}
+
+	start := from.Truncate(time.Second)
+	limit := from.Add(QuietPeriodHorizon)
+	for !start.After(limit) {
+		// Find the earliest run still busy at start or starting within the period
+		var conflictEnd time.Time
+		var conflictStart time.Time
+		for _, b := range runs {
+			next := b.cron.Next(start.Add(-b.duration).In(b.loc))
+			if next.IsZero() || !next.Before(start.Add(d)) {
+				continue
+			}
+			if conflictStart.IsZero() || next.Before(conflictStart) {
+				conflictStart, conflictEnd = next, next.Add(b.duration)
+			}
+		}
+		if conflictStart.IsZero() {
+			return &QuietPeriod{Start: start, End: start.Add(d)}, nil
+		}
+		start = conflictEnd
+	}
+	return nil, ErrNoQuietPeriod
+}
+
+// FindQuietPeriod returns the next period of length d in which none of the
+// queue's active schedules runs, e.g. to place a maintenance job or pause the
+// queue without colliding with scheduled load.
+func (r *SchedulesResource) FindQuietPeriod(ctx context.Context, queue string, d time.Duration) (*QuietPeriod, error) {
+	active := true
+	limit := 100
+	var schedules []Schedule
+	for offset := 0; ; offset += limit {
+		page, err := r.List(ctx, &ListSchedulesParams{QueueName: &queue, IsActive: &active, Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, page...)
+		if len(page) < limit {
+			break
+		}
+	}
+	return NextQuietPeriod(schedules, d, r.base.transport.Now())
+}
@@ -0,0 +1,52 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNextQuietPeriod(t *testing.T) {
+	from := time.Date(2025, 1, 15, 9, 55, 0, 0, time.UTC)
+	schedules := []Schedule{
+		// Busy 10:00-10:30 every day
+		{Name: "etl", CronExpression: "0 10 * * *", TimeoutSeconds: 1800, IsActive: true},
+		// Busy every 15 minutes for a minute
+		{Name: "sync", CronExpression: "*/15 * * * *", IsActive: true},
+		{Name: "paused", CronExpression: "* * * * *", IsActive: false},
+	}
+
+	got, err := NextQuietPeriod(schedules, 10*time.Minute, from)
+	if err != nil {
+		t.Fatalf("NextQuietPeriod: %v", err)
+	}
+	if want := time.Date(2025, 1, 15, 10, 31, 0, 0, time.UTC); !got.Start.Equal(want) || !got.End.Equal(want.Add(10*time.Minute)) {
+		t.Errorf("NextQuietPeriod() = %v-%v, want start %v", got.Start, got.End, want)
+	}
+
+	schedules = append(schedules, Schedule{Name: "busy", CronExpression: "* * * * *", IsActive: true})
+	if _, err := NextQuietPeriod(schedules, time.Minute, from); !errors.Is(err, ErrNoQuietPeriod) {
+		t.Errorf("NextQuietPeriod() error = %v, want ErrNoQuietPeriod", err)
+	}
+}
+
+func TestSchedules_FindQuietPeriod(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/schedules", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"hourly","cron_expression":"0 * * * *","timezone":"UTC","timeout_seconds":3000,"is_active":true}]`))
+	})
+
+	got, err := NewSchedulesResource(api.transport()).FindQuietPeriod(context.Background(), "reports", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("FindQuietPeriod: %v", err)
+	}
+	if query := api.received("GET /api/v1/schedules")[0].Query; query.Get("queue_name") != "reports" || query.Get("is_active") != "true" {
+		t.Errorf("query = %v, want active schedules of reports", query)
+	}
+	// Runs keep the queue busy from :00 to :50
+	if m := got.Start.Minute(); m < 50 || m > 55 || got.End.Sub(got.Start) != 5*time.Minute {
+		t.Errorf("FindQuietPeriod() = %v-%v, want a 5m period between :50 and :00", got.Start, got.End)
+	}
+}
@@ -0,0 +1,216 @@
+package resources
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ReportsResource builds analytics reports from job and queue data.
+type ReportsResource struct {
+	jobs   *JobsResource
+	queues *QueuesResource
+}
+
+// NewReportsResource creates a new ReportsResource that reads through jobs
+// and queues, so their configuration, such as payload transforms, applies.
+func NewReportsResource(jobs *JobsResource, queues *QueuesResource) *ReportsResource {
+	return &ReportsResource{jobs: jobs, queues: queues}
+}
+
+// ReportFormat is the output format of a report.
+type ReportFormat string
+
+const (
+	ReportFormatCSV  ReportFormat = "csv"
+	ReportFormatJSON ReportFormat = "json"
+)
+
+// QueueSummaryParams are parameters for a queue summary report.
+type QueueSummaryParams struct {
+	// From and To bound the report by job creation time. A zero From starts at
+	// the oldest job; a zero To ends now.
+	From time.Time
+	To   time.Time
+	// Queues limits the report to these queues (default: all queues).
+	Queues []string
+}
+
+// QueueReportRow is one queue's row in a queue summary report.
+type QueueReportRow struct {
+	QueueName  string `json:"queue_name"`
+	Total      int    `json:"total"`
+	Completed  int    `json:"completed"`
+	Failed     int    `json:"failed"`
+	Deadletter int    `json:"deadletter"`
+	// ThroughputPerHour is completed jobs per hour over the report range.
+	ThroughputPerHour float64 `json:"throughput_per_hour"`
+	// FailureRate is the share of finished jobs that failed or were dead-lettered.
+	FailureRate float64 `json:"failure_rate"`
+	// P95DurationMs is the 95th percentile processing time of completed jobs.
+	P95DurationMs int64 `json:"p95_duration_ms"`
+}
+
+var queueSummaryColumns = []string{
+	"queue_name", "total", "completed", "failed", "deadletter",
+	"throughput_per_hour", "failure_rate", "p95_duration_ms",
+}
+
+// QueueSummary writes a per-queue report of throughput, failure rate, p95
+// processing time, and dead-letter counts for jobs created in the params
+// range. Rows are streamed to w as each queue is aggregated: as CSV with a
+// header row, or as a JSON array.
+//
+// The report is computed client-side by listing every job in each queue, so
+// it costs one request per page of jobs.
+func (r *ReportsResource) QueueSummary(ctx context.Context, params *QueueSummaryParams, w io.Writer, format ReportFormat) error {
+	if params == nil {
+		params = &QueueSummaryParams{}
+	}
+
+	var write func(QueueReportRow) error
+	var finish func() error
+	switch format {
+	case ReportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(queueSummaryColumns); err != nil {
+			return err
+		}
+		write = func(s QueueReportRow) error {
+			if err := cw.Write(s.csvRecord()); err != nil {
+				return err
+			}
+			cw.Flush()
+			return cw.Error()
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case ReportFormatJSON:
+		sep := "["
+		write = func(s QueueReportRow) error {
+			row, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
+			sep = ","
+			_, err = w.Write(row)
+			return err
+		}
+		finish = func() error {
+			closing := "]\n"
+			if sep == "[" {
+				// No rows were written
+				closing = "[]\n"
+			}
+			_, err := io.WriteString(w, closing)
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	queues := params.Queues
+	if len(queues) == 0 {
+		items, err := r.queues.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list queues: %w", err)
+		}
+		for _, q := range items {
+			queues = append(queues, q.QueueName)
+		}
+	}
+
+	for _, name := range queues {
+		row, err := r.summarizeQueue(ctx, name, params.From, params.To)
+		if err != nil {
+			return fmt.Errorf("failed to summarize queue %q: %w", name, err)
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	return finish()
+}
+
+// summarizeQueue aggregates the jobs in one queue created within [from, to).
+func (r *ReportsResource) summarizeQueue(ctx context.Context, name string, from, to time.Time) (QueueReportRow, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	row := QueueReportRow{QueueName: name}
+	start := from
+	var durations []time.Duration
+
+	it := r.jobs.ListAll(ctx, &ListJobsParams{QueueName: &name})
+	for it.Next() {
+		job := it.Job()
+		if job.CreatedAt.Before(from) || !job.CreatedAt.Before(to) {
+			continue
+		}
+		if from.IsZero() && (start.IsZero() || job.CreatedAt.Before(start)) {
+			start = job.CreatedAt
+		}
+
+		row.Total++
+		switch job.Status {
+		case JobStatusCompleted:
+			row.Completed++
+			if job.StartedAt != nil && job.CompletedAt != nil {
+				durations = append(durations, job.CompletedAt.Sub(*job.StartedAt))
+			}
+		case JobStatusFailed:
+			row.Failed++
+		case JobStatusDeadletter:
+			row.Deadletter++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return row, err
+	}
+
+	if hours := to.Sub(start).Hours(); hours > 0 {
+		row.ThroughputPerHour = float64(row.Completed) / hours
+	}
+	if finished := row.Completed + row.Failed + row.Deadletter; finished > 0 {
+		row.FailureRate = float64(row.Failed+row.Deadletter) / float64(finished)
+	}
+	row.P95DurationMs = percentile(durations, 0.95).Milliseconds()
+	return row, nil
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of durations.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := int(math.Ceil(p*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank]
+}
+
+func (s QueueReportRow) csvRecord() []string {
+	return []string{
+		s.QueueName,
+		strconv.Itoa(s.Total),
+		strconv.Itoa(s.Completed),
+		strconv.Itoa(s.Failed),
+		strconv.Itoa(s.Deadletter),
+		strconv.FormatFloat(s.ThroughputPerHour, 'f', 2, 64),
+		strconv.FormatFloat(s.FailureRate, 'f', 4, 64),
+		strconv.FormatInt(s.P95DurationMs, 10),
+	}
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReports_QueueSummary(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"1","queue_name":"emails","status":"completed","created_at":"2026-01-01T10:00:00Z","started_at":"2026-01-01T10:00:00Z","completed_at":"2026-01-01T10:00:02Z"},
+			{"id":"2","queue_name":"emails","status":"completed","created_at":"2026-01-01T11:00:00Z","started_at":"2026-01-01T11:00:00Z","completed_at":"2026-01-01T11:00:05Z"},
+			{"id":"3","queue_name":"emails","status":"failed","created_at":"2026-01-01T11:30:00Z"},
+			{"id":"4","queue_name":"emails","status":"deadletter","created_at":"2026-01-01T11:45:00Z"},
+			{"id":"5","queue_name":"emails","status":"completed","created_at":"2026-01-02T10:00:00Z"}
+		]`))
+	})
+	transport := api.transport()
+	reports := NewReportsResource(NewJobsResource(transport), NewQueuesResource(transport))
+
+	params := &QueueSummaryParams{
+		From:   time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Queues: []string{"emails"},
+	}
+
+	var csvOut strings.Builder
+	if err := reports.QueueSummary(context.Background(), params, &csvOut, ReportFormatCSV); err != nil {
+		t.Fatalf("QueueSummary: %v", err)
+	}
+	want := "queue_name,total,completed,failed,deadletter,throughput_per_hour,failure_rate,p95_duration_ms\n" +
+		"emails,4,2,1,1,1.00,0.5000,5000\n"
+	if csvOut.String() != want {
+		t.Errorf("CSV report = %q, want %q", csvOut.String(), want)
+	}
+
+	var jsonOut strings.Builder
+	if err := reports.QueueSummary(context.Background(), params, &jsonOut, ReportFormatJSON); err != nil {
+		t.Fatalf("QueueSummary: %v", err)
+	}
+	var rows []QueueReportRow
+	if err := json.Unmarshal([]byte(jsonOut.String()), &rows); err != nil {
+		t.Fatalf("invalid JSON report %q: %v", jsonOut.String(), err)
+	}
+	if len(rows) != 1 || rows[0].Deadletter != 1 || rows[0].P95DurationMs != 5000 {
+		t.Errorf("JSON report = %+v", rows)
+	}
+}
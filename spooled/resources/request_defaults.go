@@ -0,0 +1,201 @@
+package resources
+
+import "net/url"
+
+// Each resource can be decorated with default headers and query parameters,
+// e.g. to satisfy gateways that require audit headers on a route family:
+//
+//	admin := client.Admin().WithHeaders(map[string]string{"X-Reason": "incident-42"})
+//
+// The decorated copy shares the client's transport; the original resource is
+// unchanged. Headers set by the SDK for a specific call take precedence.
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *AdminResource) WithHeaders(headers map[string]string) *AdminResource {
+	return &AdminResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *AdminResource) WithQuery(query url.Values) *AdminResource {
+	return &AdminResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *APIKeysResource) WithHeaders(headers map[string]string) *APIKeysResource {
+	return &APIKeysResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *APIKeysResource) WithQuery(query url.Values) *APIKeysResource {
+	return &APIKeysResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *AuthResource) WithHeaders(headers map[string]string) *AuthResource {
+	return &AuthResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *AuthResource) WithQuery(query url.Values) *AuthResource {
+	return &AuthResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *BillingResource) WithHeaders(headers map[string]string) *BillingResource {
+	return &BillingResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *BillingResource) WithQuery(query url.Values) *BillingResource {
+	return &BillingResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *DashboardResource) WithHeaders(headers map[string]string) *DashboardResource {
+	return &DashboardResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *DashboardResource) WithQuery(query url.Values) *DashboardResource {
+	return &DashboardResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *HealthResource) WithHeaders(headers map[string]string) *HealthResource {
+	return &HealthResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *HealthResource) WithQuery(query url.Values) *HealthResource {
+	return &HealthResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *IngestResource) WithHeaders(headers map[string]string) *IngestResource {
+	return &IngestResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *IngestResource) WithQuery(query url.Values) *IngestResource {
+	return &IngestResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *MetricsResource) WithHeaders(headers map[string]string) *MetricsResource {
+	return &MetricsResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *MetricsResource) WithQuery(query url.Values) *MetricsResource {
+	return &MetricsResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *OrganizationsResource) WithHeaders(headers map[string]string) *OrganizationsResource {
+	return &OrganizationsResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *OrganizationsResource) WithQuery(query url.Values) *OrganizationsResource {
+	return &OrganizationsResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *SchedulesResource) WithHeaders(headers map[string]string) *SchedulesResource {
+	return &SchedulesResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *SchedulesResource) WithQuery(query url.Values) *SchedulesResource {
+	return &SchedulesResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *WebhooksResource) WithHeaders(headers map[string]string) *WebhooksResource {
+	return &WebhooksResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *WebhooksResource) WithQuery(query url.Values) *WebhooksResource {
+	return &WebhooksResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *WorkersResource) WithHeaders(headers map[string]string) *WorkersResource {
+	return &WorkersResource{base: r.base.withDefaults(headers, nil)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *WorkersResource) WithQuery(query url.Values) *WorkersResource {
+	return &WorkersResource{base: r.base.withDefaults(nil, query)}
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *JobsResource) WithHeaders(headers map[string]string) *JobsResource {
+	return r.withBase(r.base.withDefaults(headers, nil))
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *JobsResource) WithQuery(query url.Values) *JobsResource {
+	return r.withBase(r.base.withDefaults(nil, query))
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *QueuesResource) WithHeaders(headers map[string]string) *QueuesResource {
+	return r.withBase(r.base.withDefaults(headers, nil))
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *QueuesResource) WithQuery(query url.Values) *QueuesResource {
+	return r.withBase(r.base.withDefaults(nil, query))
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *WorkflowsResource) WithHeaders(headers map[string]string) *WorkflowsResource {
+	return r.withBase(r.base.withDefaults(headers, nil))
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *WorkflowsResource) WithQuery(query url.Values) *WorkflowsResource {
+	return r.withBase(r.base.withDefaults(nil, query))
+}
+
+// WithHeaders returns a copy of the resource that sends headers with every request.
+func (r *ReportsResource) WithHeaders(headers map[string]string) *ReportsResource {
+	return &ReportsResource{jobs: r.jobs.WithHeaders(headers), queues: r.queues.WithHeaders(headers)}
+}
+
+// WithQuery returns a copy of the resource that sends query with every request.
+func (r *ReportsResource) WithQuery(query url.Values) *ReportsResource {
+	return &ReportsResource{jobs: r.jobs.WithQuery(query), queues: r.queues.WithQuery(query)}
+}
+
+// withBase returns a copy of the resource, and of its DLQ sub-resource, that
+// sends requests through base.
+func (r *JobsResource) withBase(base *Base) *JobsResource {
+	c := *r
+	c.base = base
+	dlq := *r.dlq
+	dlq.base = base
+	dlq.jobs = &c
+	c.dlq = &dlq
+	return &c
+}
+
+// withBase returns a copy of the resource that sends requests through base.
+func (r *QueuesResource) withBase(base *Base) *QueuesResource {
+	c := *r
+	c.base = base
+	return &c
+}
+
+// withBase returns a copy of the resource, and of its jobs sub-resource,
+// that sends requests through base.
+func (r *WorkflowsResource) withBase(base *Base) *WorkflowsResource {
+	c := *r
+	c.base = base
+	jobs := *r.jobs
+	jobs.base = base
+	c.jobs = &jobs
+	return &c
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWithHeaders_KeepsConfiguration(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("GET /api/v1/jobs/dlq", http.StatusOK, []Job{{ID: "job-1", QueueName: "emails", Payload: map[string]any{}}})
+	api.respond("POST /api/v1/workflows", http.StatusCreated, CreateWorkflowResponse{WorkflowID: "wf-1"})
+	api.respond("GET /api/v1/workflows/wf-1/jobs", http.StatusOK, []WorkflowJob{})
+	transforms := &PayloadTransforms{
+		Outbound: func(payload map[string]any) map[string]any { payload["encoded"] = true; return payload },
+		Inbound:  func(payload map[string]any) map[string]any { payload["decoded"] = true; return payload },
+	}
+	headers := map[string]string{"X-Reason": "incident-42"}
+	ctx := context.Background()
+
+	jobs := NewJobsResource(api.transport())
+	jobs.SetPayloadTransforms(transforms)
+	dlq, err := jobs.WithHeaders(headers).DLQ().List(ctx, nil)
+	if err != nil {
+		t.Fatalf("DLQ().List: %v", err)
+	}
+	if len(dlq) != 1 || dlq[0].Payload["decoded"] != true {
+		t.Errorf("DLQ().List() = %+v, want inbound-transformed jobs", dlq)
+	}
+
+	workflows := NewWorkflowsResource(api.transport())
+	workflows.SetPayloadTransforms(transforms)
+	decorated := workflows.WithHeaders(headers)
+	if _, err := decorated.Create(ctx, &CreateWorkflowRequest{Name: "signup", Jobs: []WorkflowJobDefinition{
+		{Key: "welcome", QueueName: "emails", Payload: map[string]any{}},
+	}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var body CreateWorkflowRequest
+	api.received("POST /api/v1/workflows")[0].decode(t, &body)
+	if body.Jobs[0].Payload["encoded"] != true {
+		t.Errorf("workflow job payload = %v, want outbound-transformed", body.Jobs[0].Payload)
+	}
+	if _, err := decorated.Jobs().ListJobs(ctx, "wf-1"); err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+
+	for _, req := range api.received("") {
+		if got := req.Header.Get("X-Reason"); got != "incident-42" {
+			t.Errorf("%s %s X-Reason = %q, want incident-42", req.Method, req.Path, got)
+		}
+	}
+}
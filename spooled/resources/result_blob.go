@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultResultChunkSize is the size of each chunk UploadResultBlob sends.
+const DefaultResultChunkSize = 1 << 20
+
+// ResultBlobKey is the result key under which a job completed with an uploaded
+// blob references it.
+const ResultBlobKey = "_result_blob"
+
+// ResultBlobRef identifies a job result stored through the result-blob API.
+type ResultBlobRef struct {
+	BlobID string `json:"blob_id"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// resultBlobChunk is one chunk of a result blob upload.
+type resultBlobChunk struct {
+	Index int    `json:"index"`
+	Data  []byte `json:"data"`
+}
+
+// resultBlobComplete finalizes a result blob upload.
+type resultBlobComplete struct {
+	Chunks int    `json:"chunks"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// UploadResultBlob stores data as the job's result blob, sending it in
+// DefaultResultChunkSize chunks so that results too large for a single request
+// can still be delivered. Complete the job with a result of
+// {ResultBlobKey: ref} to point at the blob.
+func (r *JobsResource) UploadResultBlob(ctx context.Context, jobID string, data []byte) (*ResultBlobRef, error) {
+	path := fmt.Sprintf("/api/v1/jobs/%s/result-blob", jobID)
+
+	chunks := 0
+	for offset := 0; offset < len(data) || chunks == 0; offset += DefaultResultChunkSize {
+		end := min(offset+DefaultResultChunkSize, len(data))
+		chunk := &resultBlobChunk{Index: chunks, Data: data[offset:end]}
+		// Chunks are addressed by index, so re-sending one is safe
+		if err := r.base.PostIdempotent(ctx, path+"/chunks", chunk, nil); err != nil {
+			return nil, fmt.Errorf("failed to upload result chunk %d: %w", chunks, err)
+		}
+		chunks++
+	}
+
+	sum := sha256.Sum256(data)
+	req := &resultBlobComplete{Chunks: chunks, Size: len(data), SHA256: hex.EncodeToString(sum[:])}
+	var ref ResultBlobRef
+	if err := r.base.PostIdempotent(ctx, path+"/complete", req, &ref); err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
@@ -0,0 +1,47 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestJobs_UploadResultBlob(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("POST /api/v1/jobs/job-1/result-blob/chunks", http.StatusOK, map[string]any{})
+	api.respond("POST /api/v1/jobs/job-1/result-blob/complete", http.StatusOK, map[string]any{"blob_id": "blob-1", "size": 2621440})
+
+	data := bytes.Repeat([]byte("x"), 2*DefaultResultChunkSize+DefaultResultChunkSize/2)
+	ref, err := NewJobsResource(api.transport()).UploadResultBlob(context.Background(), "job-1", data)
+	if err != nil {
+		t.Fatalf("UploadResultBlob: %v", err)
+	}
+	var received []byte
+	chunks := api.received("POST /api/v1/jobs/job-1/result-blob/chunks")
+	for i, req := range chunks {
+		var chunk struct {
+			Index int    `json:"index"`
+			Data  []byte `json:"data"`
+		}
+		req.decode(t, &chunk)
+		if chunk.Index != i {
+			t.Errorf("chunk index = %d, want %d", chunk.Index, i)
+		}
+		received = append(received, chunk.Data...)
+	}
+	if len(chunks) != 3 {
+		t.Errorf("chunks = %d, want 3", len(chunks))
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("received %d bytes, want %d", len(received), len(data))
+	}
+	var complete map[string]any
+	api.received("POST /api/v1/jobs/job-1/result-blob/complete")[0].decode(t, &complete)
+	if complete["chunks"] != float64(3) {
+		t.Errorf("complete chunks = %v, want 3", complete["chunks"])
+	}
+	if ref.BlobID != "blob-1" {
+		t.Errorf("BlobID = %q, want %q", ref.BlobID, "blob-1")
+	}
+}
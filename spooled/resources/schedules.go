@@ -55,7 +55,7 @@ func (r *SchedulesResource) List(ctx context.Context, params *ListSchedulesParam
 	query := url.Values{}
 	if params != nil {
 		if params.QueueName != nil {
-			query.Set("queue_name", *params.QueueName)
+			query.Set("queue_name", r.base.queueName(ctx, *params.QueueName))
 		}
 		if params.IsActive != nil {
 			query.Set("is_active", fmt.Sprintf("%t", *params.IsActive))
@@ -88,7 +88,9 @@ type CreateScheduleRequest struct {
 // Create creates a new schedule.
 func (r *SchedulesResource) Create(ctx context.Context, req *CreateScheduleRequest) (*Schedule, error) {
 	var result Schedule
-	if err := r.base.Post(ctx, "/api/v1/schedules", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	if err := r.base.Post(ctx, "/api/v1/schedules", &body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -122,7 +124,9 @@ type UpdateScheduleRequest struct {
 // Update updates a schedule.
 func (r *SchedulesResource) Update(ctx context.Context, id string, req *UpdateScheduleRequest) (*Schedule, error) {
 	var result Schedule
-	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/schedules/%s", id), req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueNamePtr(ctx, req.QueueName)
+	if err := r.base.Put(ctx, fmt.Sprintf("/api/v1/schedules/%s", id), &body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
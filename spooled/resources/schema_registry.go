@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/jsonschema"
+)
+
+// SchemaIDKey is the payload key naming the registered schema a payload
+// conforms to. Payloads without it are not validated.
+const SchemaIDKey = "_schema_id"
+
+// SchemaValidationError is returned when a payload does not match its
+// registered JSON Schema.
+type SchemaValidationError struct {
+	SchemaID string
+	// JobID is set when the payload was validated at claim time.
+	JobID      string
+	Violations []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	msg := fmt.Sprintf("payload does not match schema %s: %s", e.SchemaID, strings.Join(e.Violations, "; "))
+	if e.JobID != "" {
+		return "job " + e.JobID + ": " + msg
+	}
+	return msg
+}
+
+// SchemaRegistry validates payloads against JSON Schemas fetched from a
+// schema registry. Schemas are looked up by ID at GET {url}/schemas/ids/{id},
+// which returns either the schema or, as Confluent-compatible registries do,
+// {"schema": "<schema JSON>"}. Schemas are immutable per ID and cached.
+type SchemaRegistry struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	schemas map[string]*jsonschema.Schema
+}
+
+// NewSchemaRegistry creates a SchemaRegistry for the registry at registryURL.
+// A nil client uses one with a 10 second timeout.
+func NewSchemaRegistry(registryURL string, client *http.Client) *SchemaRegistry {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &SchemaRegistry{
+		url:     strings.TrimRight(registryURL, "/"),
+		client:  client,
+		schemas: make(map[string]*jsonschema.Schema),
+	}
+}
+
+// Validate checks payload against the schema named by its SchemaIDKey and
+// returns a *SchemaValidationError if it does not match. Payloads without a
+// schema ID are accepted.
+func (r *SchemaRegistry) Validate(ctx context.Context, payload map[string]any) error {
+	raw, ok := payload[SchemaIDKey]
+	if !ok {
+		return nil
+	}
+	id := schemaID(raw)
+	if id == "" {
+		return fmt.Errorf("invalid %s %v", SchemaIDKey, raw)
+	}
+
+	schema, err := r.schema(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// The schema describes the payload without the ID that points to it
+	doc := make(map[string]any, len(payload)-1)
+	for k, v := range payload {
+		if k != SchemaIDKey {
+			doc[k] = v
+		}
+	}
+	if violations := schema.Validate(doc); len(violations) > 0 {
+		return &SchemaValidationError{SchemaID: id, Violations: violations}
+	}
+	return nil
+}
+
+// schema returns the compiled schema for id, fetching it on first use.
+func (r *SchemaRegistry) schema(ctx context.Context, id string) (*jsonschema.Schema, error) {
+	r.mu.Lock()
+	schema, ok := r.schemas[id]
+	r.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+"/schemas/ids/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %s: %w", id, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned %d for schema %s", resp.StatusCode, id)
+	}
+
+	var wrapped struct {
+		Schema *string `json:"schema"`
+	}
+	if json.Unmarshal(body, &wrapped) == nil && wrapped.Schema != nil {
+		body = []byte(*wrapped.Schema)
+	}
+	schema, err = jsonschema.Compile(body)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", id, err)
+	}
+
+	r.mu.Lock()
+	r.schemas[id] = schema
+	r.mu.Unlock()
+	return schema, nil
+}
+
+// schemaID formats a schema ID decoded from JSON as a string or number.
+func schemaID(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
+
+// SetSchemaRegistry validates payloads that carry a SchemaIDKey before Create
+// and BulkEnqueue send them. Passing nil disables validation.
+func (r *JobsResource) SetSchemaRegistry(registry *SchemaRegistry) {
+	r.schemas = registry
+}
+
+// validatePayload checks payload against the schema registry, if one is set.
+func (r *JobsResource) validatePayload(ctx context.Context, payload map[string]any) error {
+	if r.schemas == nil {
+		return nil
+	}
+	return r.schemas.Validate(ctx, payload)
+}
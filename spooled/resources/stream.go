@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+)
+
+// streamList GETs path and sends each element of the returned JSON array to
+// the items channel as soon as it is decoded, so memory stays flat however
+// long the list is. The array may be the whole body or the value of key in a
+// JSON object. Each item is passed to prepare, if set, before it is sent.
+// Both channels are closed when the list ends; the error channel carries at
+// most one error. Cancelling ctx stops the stream.
+func streamList[T any](ctx context.Context, b *Base, path string, query url.Values, key string, prepare func(*T)) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(items)
+		if err := b.stream(ctx, path, query, key, func(dec *json.Decoder, resp *httpx.Response) error {
+			var item T
+			if err := decodeItem(dec, resp, &item); err != nil {
+				return fmt.Errorf("failed to decode list item: %w", err)
+			}
+			if prepare != nil {
+				prepare(&item)
+			}
+			select {
+			case items <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}); err != nil {
+			errs <- err
+		}
+	}()
+	return items, errs
+}
+
+// stream GETs path and calls each with the decoder positioned at every
+// element of the response's JSON array (see streamList).
+func (b *Base) stream(ctx context.Context, path string, query url.Values, key string, each func(*json.Decoder, *httpx.Response) error) error {
+	resp, err := b.do(ctx, &httpx.Request{
+		Method: http.MethodGet,
+		Path:   path,
+		Query:  valuestoMap(query),
+		Stream: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Stream.Close()
+
+	dec := json.NewDecoder(resp.Stream)
+	if resp.UsesNumber() {
+		dec.UseNumber()
+	}
+	found, err := seekArray(dec, key)
+	if err != nil || !found {
+		return err
+	}
+	for dec.More() {
+		if err := each(dec, resp); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to decode list: %w", err)
+	}
+	return nil
+}
+
+// decodeItem decodes the next value from a response stream into v.
+func decodeItem(dec *json.Decoder, resp *httpx.Response, v any) error {
+	if !resp.LegacyFieldNames() {
+		return dec.Decode(v)
+	}
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+	return remarshal(httpx.NormalizeFieldNames(data, v), v, resp.UsesNumber())
+}
+
+// seekArray advances dec past the opening bracket of the list, which is either
+// the top-level value or the value of key in a top-level object. It reports
+// false if the object has no such key.
+func seekArray(dec *json.Decoder, key string) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode list: %w", err)
+	}
+	if tok == json.Delim('[') {
+		return true, nil
+	}
+	if tok != json.Delim('{') {
+		return false, fmt.Errorf("failed to decode list: unexpected %v", tok)
+	}
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return false, fmt.Errorf("failed to decode list: %w", err)
+		}
+		if name == key {
+			tok, err := dec.Token()
+			if err != nil {
+				return false, fmt.Errorf("failed to decode list: %w", err)
+			}
+			if tok == nil {
+				return false, nil
+			}
+			if tok != json.Delim('[') {
+				return false, fmt.Errorf("failed to decode list: %s is not an array", key)
+			}
+			return true, nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return false, fmt.Errorf("failed to decode list: %w", err)
+		}
+	}
+	return false, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
@@ -110,21 +111,143 @@ func (r *WebhooksResource) Delete(ctx context.Context, id string) error {
 	return r.base.Delete(ctx, fmt.Sprintf("/api/v1/outgoing-webhooks/%s", id))
 }
 
+// WebhookFailureKind classifies why a webhook test failed.
+type WebhookFailureKind string
+
+const (
+	WebhookFailureDNS     WebhookFailureKind = "dns"
+	WebhookFailureTLS     WebhookFailureKind = "tls"
+	WebhookFailureConnect WebhookFailureKind = "connect"
+	WebhookFailureTimeout WebhookFailureKind = "timeout"
+	WebhookFailureHTTP    WebhookFailureKind = "http"
+	WebhookFailureUnknown WebhookFailureKind = "unknown"
+)
+
 // TestWebhookResponse is the response from testing a webhook.
 type TestWebhookResponse struct {
 	Success        bool    `json:"success"`
 	StatusCode     *int    `json:"status_code,omitempty"`
 	ResponseTimeMs int     `json:"response_time_ms"`
 	Error          *string `json:"error,omitempty"`
+
+	// FailureKind classifies the failure (dns, tls, connect, timeout, http).
+	// Derived from Error when the server does not report it.
+	FailureKind *WebhookFailureKind `json:"failure_kind,omitempty"`
+	// ResponseHeaders are the headers returned by the receiver.
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// ResponseBody is the (possibly truncated) body returned by the receiver.
+	ResponseBody *string `json:"response_body,omitempty"`
+	// RequestHeaders are the headers sent to the receiver, including the signature.
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// RequestBody is the exact payload that was signed and sent.
+	RequestBody *string `json:"request_body,omitempty"`
+	// Signature is the signature header value sent with the request.
+	Signature *string `json:"signature,omitempty"`
+	// Attempts is the number of test requests made by the SDK.
+	Attempts int `json:"-"`
+	// Latency is the round-trip time of the final attempt as seen by the SDK.
+	Latency time.Duration `json:"-"`
+}
+
+// TestWebhookOptions configures a webhook test.
+type TestWebhookOptions struct {
+	// Payload replaces the default test payload.
+	Payload map[string]any
+	// Retries is the number of additional attempts when the receiver fails
+	// with a transient error (connect, timeout, or 5xx). Default: 0.
+	Retries int
+	// RetryDelay is the initial delay between attempts, doubled each time (default: 500ms).
+	RetryDelay time.Duration
 }
 
 // Test sends a test request to a webhook.
 func (r *WebhooksResource) Test(ctx context.Context, id string) (*TestWebhookResponse, error) {
-	var result TestWebhookResponse
-	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/outgoing-webhooks/%s/test", id), nil, &result); err != nil {
-		return nil, err
+	return r.TestWithOptions(ctx, id, nil)
+}
+
+// TestWithPayload sends a test request with a custom payload to a webhook.
+// The response contains the exact signed body and headers sent, which helps
+// debug signature mismatches on the receiving side.
+func (r *WebhooksResource) TestWithPayload(ctx context.Context, id string, payload map[string]any) (*TestWebhookResponse, error) {
+	return r.TestWithOptions(ctx, id, &TestWebhookOptions{Payload: payload})
+}
+
+// TestWithOptions sends a test request to a webhook, retrying with
+// exponential backoff while the receiver fails transiently.
+func (r *WebhooksResource) TestWithOptions(ctx context.Context, id string, opts *TestWebhookOptions) (*TestWebhookResponse, error) {
+	if opts == nil {
+		opts = &TestWebhookOptions{}
 	}
-	return &result, nil
+	delay := opts.RetryDelay
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+
+	var body any
+	if opts.Payload != nil {
+		body = map[string]any{"payload": opts.Payload}
+	}
+
+	path := fmt.Sprintf("/api/v1/outgoing-webhooks/%s/test", id)
+	for attempt := 1; ; attempt++ {
+		var result TestWebhookResponse
+		start := time.Now()
+		if err := r.base.PostIdempotent(ctx, path, body, &result); err != nil {
+			return nil, err
+		}
+		result.Latency = time.Since(start)
+		result.Attempts = attempt
+		if !result.Success && result.FailureKind == nil {
+			kind := classifyWebhookFailure(&result)
+			result.FailureKind = &kind
+		}
+
+		if result.Success || attempt > opts.Retries || !result.transient() {
+			return &result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &result, nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// transient reports whether a failed test is worth retrying.
+func (r *TestWebhookResponse) transient() bool {
+	if r.FailureKind == nil {
+		return false
+	}
+	switch *r.FailureKind {
+	case WebhookFailureConnect, WebhookFailureTimeout:
+		return true
+	case WebhookFailureHTTP:
+		return r.StatusCode != nil && *r.StatusCode >= 500
+	}
+	return false
+}
+
+// classifyWebhookFailure derives a failure kind from the test response.
+func classifyWebhookFailure(r *TestWebhookResponse) WebhookFailureKind {
+	msg := ""
+	if r.Error != nil {
+		msg = strings.ToLower(*r.Error)
+	}
+	switch {
+	case strings.Contains(msg, "no such host") || strings.Contains(msg, "dns") || strings.Contains(msg, "resolve"):
+		return WebhookFailureDNS
+	case strings.Contains(msg, "tls") || strings.Contains(msg, "x509") || strings.Contains(msg, "certificate"):
+		return WebhookFailureTLS
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out") || strings.Contains(msg, "deadline"):
+		return WebhookFailureTimeout
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "connection reset") || strings.Contains(msg, "unreachable"):
+		return WebhookFailureConnect
+	case r.StatusCode != nil:
+		return WebhookFailureHTTP
+	}
+	return WebhookFailureUnknown
 }
 
 // WebhookDeliveryStatus represents the status of a webhook delivery.
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWebhooks_TestWithPayload(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("POST /api/v1/outgoing-webhooks/wh-1/test", http.StatusOK, map[string]any{
+		"success":          true,
+		"status_code":      200,
+		"response_headers": map[string]string{"X-Request-Id": "r-1"},
+		"request_body":     `{"order_id":"o-1"}`,
+		"signature":        "sha256=abc",
+	})
+
+	resp, err := NewWebhooksResource(api.transport()).TestWithPayload(context.Background(), "wh-1", map[string]any{"order_id": "o-1"})
+	if err != nil {
+		t.Fatalf("TestWithPayload: %v", err)
+	}
+	if !resp.Success || resp.Attempts != 1 || resp.FailureKind != nil || resp.ResponseHeaders["X-Request-Id"] != "r-1" ||
+		resp.RequestBody == nil || *resp.RequestBody != `{"order_id":"o-1"}` || resp.Signature == nil || *resp.Signature != "sha256=abc" {
+		t.Errorf("TestWithPayload() = %+v", resp)
+	}
+	var body struct {
+		Payload map[string]any `json:"payload"`
+	}
+	api.received("POST /api/v1/outgoing-webhooks/wh-1/test")[0].decode(t, &body)
+	if body.Payload["order_id"] != "o-1" {
+		t.Errorf("test body payload = %v, want the custom payload", body.Payload)
+	}
+}
+
+func TestWebhooks_TestFailureKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]any
+		want     WebhookFailureKind
+	}{
+		{"dns", map[string]any{"error": "dial tcp: lookup hooks.example.com: no such host"}, WebhookFailureDNS},
+		{"tls", map[string]any{"error": "x509: certificate signed by unknown authority"}, WebhookFailureTLS},
+		{"tls handshake", map[string]any{"error": "remote error: tls: handshake failure"}, WebhookFailureTLS},
+		{"timeout", map[string]any{"error": "context deadline exceeded"}, WebhookFailureTimeout},
+		{"connect", map[string]any{"error": "dial tcp 10.0.0.1:443: connect: connection refused"}, WebhookFailureConnect},
+		{"http", map[string]any{"status_code": 404}, WebhookFailureHTTP},
+		{"unknown", map[string]any{"error": "unexpected EOF"}, WebhookFailureUnknown},
+		{"reported by server", map[string]any{"error": "no such host", "failure_kind": "tls"}, WebhookFailureTLS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.respond("POST /api/v1/outgoing-webhooks/wh-1/test", http.StatusOK, tt.response)
+
+			resp, err := NewWebhooksResource(api.transport()).Test(context.Background(), "wh-1")
+			if err != nil {
+				t.Fatalf("Test: %v", err)
+			}
+			if resp.Success || resp.FailureKind == nil || *resp.FailureKind != tt.want {
+				t.Errorf("Test() = %+v, want failure kind %s", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhooks_TestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     map[string]any
+		wantAttempts int
+	}{
+		{"connect", map[string]any{"error": "connection refused"}, 3},
+		{"server error", map[string]any{"status_code": 503}, 3},
+		{"client error", map[string]any{"status_code": 400}, 1},
+		{"tls", map[string]any{"error": "x509: certificate has expired"}, 1},
+		{"dns", map[string]any{"error": "no such host"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.respond("POST /api/v1/outgoing-webhooks/wh-1/test", http.StatusOK, tt.response)
+
+			opts := &TestWebhookOptions{Retries: 2, RetryDelay: time.Millisecond}
+			resp, err := NewWebhooksResource(api.transport()).TestWithOptions(context.Background(), "wh-1", opts)
+			if err != nil {
+				t.Fatalf("TestWithOptions: %v", err)
+			}
+			if resp.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", resp.Attempts, tt.wantAttempts)
+			}
+			if got := len(api.received("POST /api/v1/outgoing-webhooks/wh-1/test")); got != tt.wantAttempts {
+				t.Errorf("test requests = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
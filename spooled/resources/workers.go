@@ -70,6 +70,9 @@ type RegisterWorkerRequest struct {
 	MaxConcurrency *int           `json:"max_concurrency,omitempty"`
 	Metadata       map[string]any `json:"metadata,omitempty"`
 	Version        *string        `json:"version,omitempty"`
+	// WorkerID is the identity to register as; required for keys bound to a
+	// worker identity (see APIKeysResource.CreateWorkerKey).
+	WorkerID *string `json:"worker_id,omitempty"`
 }
 
 // RegisterWorkerResponse is the response from registering a worker.
@@ -83,7 +86,9 @@ type RegisterWorkerResponse struct {
 // Register registers a new worker.
 func (r *WorkersResource) Register(ctx context.Context, req *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
 	var result RegisterWorkerResponse
-	if err := r.base.Post(ctx, "/api/v1/workers/register", req, &result); err != nil {
+	body := *req
+	body.QueueName = r.base.queueName(ctx, req.QueueName)
+	if err := r.base.Post(ctx, "/api/v1/workers/register", &body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -91,9 +96,24 @@ func (r *WorkersResource) Register(ctx context.Context, req *RegisterWorkerReque
 
 // WorkerHeartbeatRequest is the request for a worker heartbeat.
 type WorkerHeartbeatRequest struct {
-	CurrentJobs int            `json:"current_jobs"`
-	Status      *string        `json:"status,omitempty"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
+	CurrentJobs int                   `json:"current_jobs"`
+	Status      *string               `json:"status,omitempty"`
+	Metadata    map[string]any        `json:"metadata,omitempty"`
+	Metrics     *WorkerProcessMetrics `json:"metrics,omitempty"`
+}
+
+// WorkerProcessMetrics reports resource pressure on the worker process.
+type WorkerProcessMetrics struct {
+	// RSSBytes is the resident set size on Linux. Elsewhere it is estimated as
+	// the memory the Go runtime holds from the OS, which excludes memory
+	// allocated outside Go (such as by cgo).
+	RSSBytes uint64 `json:"rss_bytes"`
+	// CPUPercent is process CPU time used since the previous heartbeat, where
+	// 100 is one core. It is zero on platforms without process CPU accounting
+	// (js/wasm, wasip1, plan9).
+	CPUPercent        float64        `json:"cpu_percent"`
+	Goroutines        int            `json:"goroutines"`
+	ActiveJobsByQueue map[string]int `json:"active_jobs_by_queue,omitempty"`
 }
 
 // Heartbeat sends a heartbeat for a worker.
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+)
+
+// InheritPriorities sets the Priority of each job that has none to the highest
+// priority of the jobs it depends on, directly or through other inheriting
+// jobs. Jobs with a Priority keep it, and jobs whose dependencies have no
+// priority are left unset. Workflows created with InheritPriority have this
+// applied before they are sent.
+func InheritPriorities(jobs []WorkflowJobDefinition) {
+	index := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		index[job.Key] = i
+	}
+	resolved := make([]bool, len(jobs))
+	visiting := make([]bool, len(jobs))
+
+	var resolve func(i int) *int
+	resolve = func(i int) *int {
+		job := &jobs[i]
+		if resolved[i] || job.Priority != nil || visiting[i] {
+			// A cycle is left for the server to reject
+			return job.Priority
+		}
+		visiting[i] = true
+		for _, dep := range job.DependsOn {
+			d, ok := index[dep]
+			if !ok {
+				continue
+			}
+			if p := resolve(d); p != nil && (job.Priority == nil || *p > *job.Priority) {
+				inherited := *p
+				job.Priority = &inherited
+			}
+		}
+		visiting[i] = false
+		resolved[i] = true
+		return job.Priority
+	}
+	for i := range jobs {
+		resolve(i)
+	}
+}
+
+// CreateChild creates a job as a child of parentID. A child without a
+// Priority inherits the parent's, so the child of an urgent job is not
+// starved behind normal-priority work.
+func (r *JobsResource) CreateChild(ctx context.Context, parentID string, req *CreateJobRequest) (*CreateJobResponse, error) {
+	child := *req
+	child.ParentJobID = &parentID
+	if child.Priority == nil {
+		parent, err := r.Get(ctx, parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent job: %w", err)
+		}
+		priority := parent.Priority
+		child.Priority = &priority
+	}
+	return r.Create(ctx, &child)
+}
@@ -0,0 +1,58 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWorkflows_InheritPriority(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("POST /api/v1/workflows", http.StatusOK, map[string]any{"workflow_id": "wf-1"})
+	api.respond("GET /api/v1/jobs/parent-1", http.StatusOK, map[string]any{"id": "parent-1", "status": "processing", "priority": 8})
+	api.respond("POST /api/v1/jobs", http.StatusOK, map[string]any{"id": "child-1", "created": true})
+	ctx := context.Background()
+
+	high, low := 9, 2
+	_, err := NewWorkflowsResource(api.transport()).Create(ctx, &CreateWorkflowRequest{
+		Name:            "etl",
+		InheritPriority: true,
+		Jobs: []WorkflowJobDefinition{
+			{Key: "load", QueueName: "q", DependsOn: []string{"transform"}},
+			{Key: "extract", QueueName: "q", Priority: &high},
+			{Key: "transform", QueueName: "q", DependsOn: []string{"extract", "audit"}},
+			{Key: "audit", QueueName: "q", Priority: &low},
+			{Key: "report", QueueName: "q", DependsOn: []string{"audit"}, Priority: &low},
+			{Key: "cleanup", QueueName: "q"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var workflow CreateWorkflowRequest
+	api.received("POST /api/v1/workflows")[0].decode(t, &workflow)
+	if !workflow.InheritPriority {
+		t.Error("inherit_priority was not sent")
+	}
+	priorities := map[string]*int{}
+	for _, job := range workflow.Jobs {
+		priorities[job.Key] = job.Priority
+	}
+	for key, want := range map[string]int{"load": 9, "transform": 9, "report": 2} {
+		if got := priorities[key]; got == nil || *got != want {
+			t.Errorf("%s priority = %v, want %d", key, got, want)
+		}
+	}
+	if priorities["cleanup"] != nil {
+		t.Errorf("cleanup priority = %d, want unset", *priorities["cleanup"])
+	}
+
+	if _, err := NewJobsResource(api.transport()).CreateChild(ctx, "parent-1", &CreateJobRequest{QueueName: "q"}); err != nil {
+		t.Fatalf("CreateChild: %v", err)
+	}
+	var child CreateJobRequest
+	api.received("POST /api/v1/jobs")[0].decode(t, &child)
+	if child.ParentJobID == nil || *child.ParentJobID != "parent-1" || child.Priority == nil || *child.Priority != 8 {
+		t.Errorf("child = %+v, want parent-1's priority", child)
+	}
+}
@@ -0,0 +1,228 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrWorkflowNotCompleted is returned by Workflows().Wait when a workflow
+// finishes without completing: it failed or was cancelled.
+var ErrWorkflowNotCompleted = errors.New("workflow did not complete")
+
+// maxWatchedWorkflowJobs is the most jobs Wait watches over realtime; larger
+// workflows are polled.
+const maxWatchedWorkflowJobs = 100
+
+// IsTerminal reports whether a workflow in status s will not run again.
+func (s WorkflowStatus) IsTerminal() bool {
+	switch s {
+	case WorkflowStatusCompleted, WorkflowStatusFailed, WorkflowStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// WorkflowProgressEvent reports the state of a workflow's jobs.
+type WorkflowProgressEvent struct {
+	WorkflowID string
+	Status     WorkflowStatus
+	TotalJobs  int
+	// CompletedJobs are the jobs that have completed.
+	CompletedJobs []WorkflowJobStatus
+	// FailedJobs are the jobs that failed, were dead-lettered, or were
+	// cancelled.
+	FailedJobs []WorkflowJobStatus
+	// CurrentlyRunning are the jobs being processed by a worker.
+	CurrentlyRunning []WorkflowJobStatus
+}
+
+// WaitWorkflowOptions configures Workflows().Wait.
+type WaitWorkflowOptions struct {
+	// PollInterval is the delay before the first re-check (default:
+	// DefaultWaitPollInterval).
+	PollInterval time.Duration
+	// MaxPollInterval caps the delay between checks (default:
+	// DefaultWaitMaxPollInterval).
+	MaxPollInterval time.Duration
+	// Multiplier grows the delay after each check (default: 1.5). Use 1 to
+	// poll at a fixed interval.
+	Multiplier float64
+	// Timeout bounds the wait (default: until ctx is done).
+	Timeout time.Duration
+	// OnProgress is called whenever the workflow's or any job's status
+	// changes, including the first check.
+	OnProgress func(event *WorkflowProgressEvent)
+}
+
+// SetJobWatcher makes Wait wake on changes to the workflow's jobs reported by
+// watcher. Passing nil makes it poll only.
+func (r *WorkflowsResource) SetJobWatcher(watcher JobWatcher) {
+	r.watcher = watcher
+}
+
+// Wait polls a workflow and the status of its jobs until the workflow reaches
+// a terminal status, and returns it. Polling backs off like
+// Jobs().WaitForCompletion; when a JobWatcher is available, changes to the
+// workflow's jobs are picked up immediately.
+//
+// A workflow that fails or is cancelled is returned with an error wrapping
+// ErrWorkflowNotCompleted. If the wait times out or ctx is done, the last
+// fetched workflow is returned with an error wrapping the context's error.
+//
+//	wf, err := client.Workflows().Wait(ctx, workflowID, resources.WaitWorkflowOptions{
+//		OnProgress: func(e *resources.WorkflowProgressEvent) {
+//			log.Printf("%d/%d jobs done, %d running", len(e.CompletedJobs), e.TotalJobs, len(e.CurrentlyRunning))
+//		},
+//	})
+func (r *WorkflowsResource) Wait(ctx context.Context, workflowID string, opts WaitWorkflowOptions) (*Workflow, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxPollInterval
+	}
+	maxInterval = max(maxInterval, interval)
+	multiplier := opts.Multiplier
+	if multiplier < 1 {
+		multiplier = 1.5
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var (
+		last     *Workflow
+		lastJobs map[string]JobStatus
+		changes  <-chan struct{}
+		watched  bool
+	)
+	for {
+		wf, jobs, err := r.getProgress(ctx, workflowID)
+		if err != nil {
+			if ctx.Err() != nil && last != nil {
+				return last, fmt.Errorf("workflow %s still %s: %w", workflowID, last.Status, ctx.Err())
+			}
+			return last, err
+		}
+		if !watched && !wf.Status.IsTerminal() {
+			watched = true
+			var stop func()
+			if changes, stop = r.watchJobs(jobs); stop != nil {
+				defer stop()
+			}
+		}
+		statuses := make(map[string]JobStatus, len(jobs))
+		for _, job := range jobs {
+			statuses[job.JobID] = job.Status
+		}
+		if opts.OnProgress != nil && (last == nil || wf.Status != last.Status || !sameStatuses(statuses, lastJobs)) {
+			opts.OnProgress(newWorkflowProgressEvent(wf, jobs))
+		}
+		lastJobs = statuses
+		last = wf
+
+		if wf.Status.IsTerminal() {
+			if wf.Status != WorkflowStatusCompleted {
+				return wf, fmt.Errorf("%w: workflow %s is %s", ErrWorkflowNotCompleted, workflowID, wf.Status)
+			}
+			return wf, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return wf, fmt.Errorf("workflow %s still %s: %w", workflowID, wf.Status, ctx.Err())
+		case <-changes:
+			timer.Stop()
+		case <-timer.C:
+		}
+		interval = min(time.Duration(float64(interval)*multiplier), maxInterval)
+	}
+}
+
+// getProgress fetches a workflow and the status of its jobs.
+func (r *WorkflowsResource) getProgress(ctx context.Context, workflowID string) (*Workflow, []WorkflowJobStatus, error) {
+	wf, err := r.Get(ctx, workflowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	jobs, err := r.jobs.GetJobsStatus(ctx, workflowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wf, jobs.Jobs, nil
+}
+
+// watchJobs merges the JobWatcher's change channels for jobs into one. It
+// returns a nil stop function when the jobs cannot all be watched, in which
+// case Wait polls.
+func (r *WorkflowsResource) watchJobs(jobs []WorkflowJobStatus) (<-chan struct{}, func()) {
+	if r.watcher == nil || len(jobs) == 0 || len(jobs) > maxWatchedWorkflowJobs {
+		return nil, nil
+	}
+	merged := make(chan struct{}, 1)
+	done := make(chan struct{})
+	var stops []func()
+	stop := func() {
+		close(done)
+		for _, s := range stops {
+			s()
+		}
+	}
+	for _, job := range jobs {
+		ch, s, ok := r.watcher.WatchJob(job.JobID)
+		if !ok {
+			stop()
+			return nil, nil
+		}
+		stops = append(stops, s)
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case <-ch:
+					select {
+					case merged <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+	return merged, stop
+}
+
+func newWorkflowProgressEvent(wf *Workflow, jobs []WorkflowJobStatus) *WorkflowProgressEvent {
+	event := &WorkflowProgressEvent{WorkflowID: wf.ID, Status: wf.Status, TotalJobs: len(jobs)}
+	for _, job := range jobs {
+		switch job.Status {
+		case JobStatusCompleted:
+			event.CompletedJobs = append(event.CompletedJobs, job)
+		case JobStatusFailed, JobStatusDeadletter, JobStatusCancelled:
+			event.FailedJobs = append(event.FailedJobs, job)
+		case JobStatusProcessing:
+			event.CurrentlyRunning = append(event.CurrentlyRunning, job)
+		}
+	}
+	return event
+}
+
+func sameStatuses(a, b map[string]JobStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, status := range a {
+		if b[id] != status {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkflows_Wait(t *testing.T) {
+	steps := []struct {
+		workflow string
+		jobs     [2]string
+	}{
+		{"running", [2]string{"processing", "pending"}},
+		{"running", [2]string{"processing", "pending"}},
+		{"running", [2]string{"completed", "processing"}},
+		{"failed", [2]string{"completed", "failed"}},
+	}
+	var step atomic.Int32
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/workflows/wf-1", func(w http.ResponseWriter, r *http.Request) {
+		i := min(int(step.Add(1))-1, len(steps)-1)
+		writeTestJSON(w, http.StatusOK, map[string]any{"id": "wf-1", "status": steps[i].workflow, "total_jobs": 2})
+	})
+	api.handle("GET /api/v1/workflows/wf-1/jobs/status", func(w http.ResponseWriter, r *http.Request) {
+		i := min(int(step.Load())-1, len(steps)-1)
+		writeTestJSON(w, http.StatusOK, map[string]any{"jobs": []map[string]any{
+			{"key": "a", "job_id": "job-a", "status": steps[i].jobs[0]},
+			{"key": "b", "job_id": "job-b", "status": steps[i].jobs[1], "depends_on": []string{"a"}},
+		}})
+	})
+	workflows := NewWorkflowsResource(api.transport())
+	ctx := context.Background()
+
+	var events []*WorkflowProgressEvent
+	wf, err := workflows.Wait(ctx, "wf-1", WaitWorkflowOptions{
+		PollInterval: time.Millisecond,
+		Multiplier:   1,
+		OnProgress:   func(e *WorkflowProgressEvent) { events = append(events, e) },
+	})
+	if !errors.Is(err, ErrWorkflowNotCompleted) || wf == nil || wf.Status != WorkflowStatusFailed {
+		t.Fatalf("Wait() = %+v, %v; want the failed workflow with ErrWorkflowNotCompleted", wf, err)
+	}
+	// The unchanged second check is not reported
+	if len(events) != 3 {
+		t.Fatalf("got %d progress events, want 3", len(events))
+	}
+	if e := events[0]; e.TotalJobs != 2 || len(e.CurrentlyRunning) != 1 || e.CurrentlyRunning[0].Key != "a" {
+		t.Errorf("first event = %+v, want a running", e)
+	}
+	if e := events[2]; len(e.CompletedJobs) != 1 || len(e.FailedJobs) != 1 || e.FailedJobs[0].Key != "b" || e.Status != WorkflowStatusFailed {
+		t.Errorf("last event = %+v, want a completed and b failed", e)
+	}
+
+	// Job changes wake the wait instead of the poll interval
+	step.Store(0)
+	watcher := &chanJobWatcher{changes: make(chan struct{})}
+	workflows.SetJobWatcher(watcher)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case watcher.changes <- struct{}{}:
+			case <-done:
+				return
+			}
+		}
+	}()
+	if _, err := workflows.Wait(ctx, "wf-1", WaitWorkflowOptions{
+		PollInterval: time.Hour,
+		Timeout:      5 * time.Second,
+	}); !errors.Is(err, ErrWorkflowNotCompleted) {
+		t.Fatalf("Wait() with watcher error = %v, want ErrWorkflowNotCompleted", err)
+	}
+	if !watcher.stopped.Load() {
+		t.Error("job watches were not stopped")
+	}
+}
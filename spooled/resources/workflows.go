@@ -11,8 +11,10 @@ import (
 
 // WorkflowsResource provides access to workflow operations.
 type WorkflowsResource struct {
-	base *Base
-	jobs *WorkflowJobsResource
+	base       *Base
+	jobs       *WorkflowJobsResource
+	watcher    JobWatcher
+	transforms *PayloadTransforms
 }
 
 // NewWorkflowsResource creates a new WorkflowsResource.
@@ -54,6 +56,8 @@ type Workflow struct {
 	StartedAt      *time.Time     `json:"started_at,omitempty"`
 	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
 	Metadata       map[string]any `json:"metadata,omitempty"`
+	TimeoutSeconds *int           `json:"timeout_seconds,omitempty"`
+	Deadline       *time.Time     `json:"deadline,omitempty"`
 }
 
 // ListWorkflowsParams are parameters for listing workflows.
@@ -88,7 +92,9 @@ const (
 	DependencyModeAny DependencyMode = "any"
 )
 
-// WorkflowJobDefinition defines a job within a workflow.
+// WorkflowJobDefinition defines a job within a workflow. SLASeconds is the
+// time from workflow start by which the job should complete; missing it marks
+// the workflow as breaching its SLA but does not stop the job.
 type WorkflowJobDefinition struct {
 	Key            string          `json:"key"`
 	QueueName      string          `json:"queue_name"`
@@ -98,14 +104,22 @@ type WorkflowJobDefinition struct {
 	Priority       *int            `json:"priority,omitempty"`
 	MaxRetries     *int            `json:"max_retries,omitempty"`
 	TimeoutSeconds *int            `json:"timeout_seconds,omitempty"`
+	SLASeconds     *int            `json:"sla_seconds,omitempty"`
 }
 
-// CreateWorkflowRequest is the request to create a workflow.
+// CreateWorkflowRequest is the request to create a workflow. TimeoutSeconds
+// bounds the whole workflow: when it elapses, unfinished jobs are cancelled
+// and the workflow fails.
 type CreateWorkflowRequest struct {
-	Name        string                  `json:"name"`
-	Description *string                 `json:"description,omitempty"`
-	Jobs        []WorkflowJobDefinition `json:"jobs"`
-	Metadata    map[string]any          `json:"metadata,omitempty"`
+	Name           string                  `json:"name"`
+	Description    *string                 `json:"description,omitempty"`
+	Jobs           []WorkflowJobDefinition `json:"jobs"`
+	Metadata       map[string]any          `json:"metadata,omitempty"`
+	TimeoutSeconds *int                    `json:"timeout_seconds,omitempty"`
+	// InheritPriority gives jobs without a Priority the highest priority of
+	// the jobs they depend on, so work downstream of urgent jobs is not
+	// starved by normal-priority jobs. See InheritPriorities.
+	InheritPriority bool `json:"inherit_priority,omitempty"`
 }
 
 // WorkflowJobMapping maps a workflow job key to its job ID.
@@ -123,7 +137,18 @@ type CreateWorkflowResponse struct {
 // Create creates a new workflow.
 func (r *WorkflowsResource) Create(ctx context.Context, req *CreateWorkflowRequest) (*CreateWorkflowResponse, error) {
 	var result CreateWorkflowResponse
-	if err := r.base.Post(ctx, "/api/v1/workflows", req, &result); err != nil {
+	body := *req
+	body.Jobs = make([]WorkflowJobDefinition, len(req.Jobs))
+	for i, job := range req.Jobs {
+		job.QueueName = r.base.queueName(ctx, job.QueueName)
+		job.Payload = r.transforms.ApplyOutbound(job.Payload)
+		body.Jobs[i] = job
+	}
+	if body.InheritPriority {
+		// Servers without inheritance ignore the flag but get the priorities
+		InheritPriorities(body.Jobs)
+	}
+	if err := r.base.Post(ctx, "/api/v1/workflows", &body, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -152,6 +177,61 @@ func (r *WorkflowsResource) Retry(ctx context.Context, id string) (*Workflow, er
 	return &result, nil
 }
 
+// RetryOptions configures RetryFrom.
+type RetryOptions struct {
+	// SkipCompleted keeps downstream jobs that already completed instead of
+	// re-running them.
+	SkipCompleted bool `json:"skip_completed,omitempty"`
+}
+
+// RetryFrom re-runs the job with key nodeKey and every job downstream of it,
+// reusing the results of upstream jobs, so a long pipeline does not have to
+// start over when a late step fails. Retry restarts every failed job instead.
+func (r *WorkflowsResource) RetryFrom(ctx context.Context, workflowID, nodeKey string, opts RetryOptions) (*Workflow, error) {
+	if nodeKey == "" {
+		return nil, fmt.Errorf("node key is required")
+	}
+	body := struct {
+		JobKey string `json:"job_key"`
+		RetryOptions
+	}{JobKey: nodeKey, RetryOptions: opts}
+
+	var result Workflow
+	if err := r.base.Post(ctx, fmt.Sprintf("/api/v1/workflows/%s/retry-from", workflowID), &body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SLAStatus describes how close a workflow is to missing a deadline.
+type SLAStatus string
+
+const (
+	SLAStatusAtRisk   SLAStatus = "at_risk"
+	SLAStatusBreached SLAStatus = "breached"
+)
+
+// WorkflowSLABreach is a workflow deadline or job SLA that is at risk or
+// already missed. JobKey and JobID are empty for the workflow timeout itself.
+type WorkflowSLABreach struct {
+	WorkflowID   string    `json:"workflow_id"`
+	WorkflowName string    `json:"workflow_name"`
+	JobKey       string    `json:"job_key,omitempty"`
+	JobID        string    `json:"job_id,omitempty"`
+	Status       SLAStatus `json:"status"`
+	Deadline     time.Time `json:"deadline"`
+}
+
+// ListBreachingSLA retrieves running workflows whose timeout or job SLAs are
+// at risk or already missed, so callers can cancel or escalate them.
+func (r *WorkflowsResource) ListBreachingSLA(ctx context.Context) ([]WorkflowSLABreach, error) {
+	var result []WorkflowSLABreach
+	if err := r.base.Get(ctx, "/api/v1/workflows/sla-breaches", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // WorkflowJobsResource provides access to workflow job operations.
 type WorkflowJobsResource struct {
 	base *Base
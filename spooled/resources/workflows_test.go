@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWorkflows_ListBreachingSLA(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("GET /api/v1/workflows/sla-breaches", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"workflow_id":"wf-1","workflow_name":"etl","status":"breached","deadline":"2024-01-01T12:00:00Z"},
+			{"workflow_id":"wf-2","workflow_name":"etl","job_key":"load","job_id":"job-9","status":"at_risk","deadline":"2024-01-01T13:00:00Z"}
+		]`))
+	})
+
+	breaches, err := NewWorkflowsResource(api.transport()).ListBreachingSLA(context.Background())
+	if err != nil {
+		t.Fatalf("ListBreachingSLA: %v", err)
+	}
+	if len(breaches) != 2 {
+		t.Fatalf("len(breaches) = %d, want 2", len(breaches))
+	}
+	if breaches[0].Status != SLAStatusBreached || breaches[0].JobKey != "" {
+		t.Errorf("breaches[0] = %+v, want workflow-level breach", breaches[0])
+	}
+	if breaches[1].Status != SLAStatusAtRisk || breaches[1].JobKey != "load" {
+		t.Errorf("breaches[1] = %+v, want at-risk job load", breaches[1])
+	}
+}
+
+func TestWorkflows_RetryFrom(t *testing.T) {
+	api := newFakeAPI(t)
+	api.respond("POST /api/v1/workflows/wf-1/retry-from", http.StatusOK, map[string]any{"id": "wf-1", "name": "etl", "status": "running"})
+	workflows := NewWorkflowsResource(api.transport())
+
+	wf, err := workflows.RetryFrom(context.Background(), "wf-1", "transform", RetryOptions{SkipCompleted: true})
+	if err != nil {
+		t.Fatalf("RetryFrom: %v", err)
+	}
+	var body map[string]any
+	api.received("POST /api/v1/workflows/wf-1/retry-from")[0].decode(t, &body)
+	if body["job_key"] != "transform" || body["skip_completed"] != true {
+		t.Errorf("body = %v, want job_key=transform skip_completed=true", body)
+	}
+	if wf.Status != WorkflowStatusRunning {
+		t.Errorf("Status = %q, want %q", wf.Status, WorkflowStatusRunning)
+	}
+
+	if _, err := workflows.RetryFrom(context.Background(), "wf-1", "", RetryOptions{}); err == nil {
+		t.Error("expected error for empty node key")
+	}
+}
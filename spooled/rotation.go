@@ -0,0 +1,169 @@
+package spooled
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// DefaultRotationGrace is how long RotateAPIKey keeps the old key by default.
+const DefaultRotationGrace = 10 * time.Minute
+
+// RotationOptions configures RotateAPIKey.
+type RotationOptions struct {
+	// Name is the name of the new key (default: the old key's name).
+	Name string
+	// Grace is how long the old key stays valid after the swap, so other
+	// processes sharing it can pick up the new one (default: DefaultRotationGrace).
+	Grace time.Duration
+	// Persist stores the new key, e.g. in a secret manager, before it is
+	// swapped in. Returning an error aborts the rotation and deletes the new key.
+	Persist func(ctx context.Context, key *resources.CreateAPIKeyResponse) error
+	// OnOldKeyDeleted is called once the grace period is over with the result
+	// of deleting the old key (optional).
+	OnOldKeyDeleted func(keyID string, err error)
+}
+
+// Rotation is the result of RotateAPIKey.
+type Rotation struct {
+	// NewKey is the key the client now uses. NewKey.Key is only available here.
+	NewKey *resources.CreateAPIKeyResponse
+	// OldKeyID is the ID of the replaced key.
+	OldKeyID string
+	// DeleteAt is when the old key is scheduled for deletion.
+	DeleteAt time.Time
+
+	timer *time.Timer
+}
+
+// CancelDeletion keeps the old key. It returns false if the deletion has
+// already started.
+func (r *Rotation) CancelDeletion() bool {
+	return r.timer.Stop()
+}
+
+// RotateAPIKey replaces the client's API key without downtime: it creates a
+// key with the same queues and rate limit as the current one, verifies that
+// the new key authenticates, persists it through opts.Persist, swaps it into
+// the client's REST transport, gRPC connection, and shared realtime client,
+// and deletes the old key after opts.Grace.
+//
+// The shared realtime client (see Client.Realtime) reconnects with the new
+// key on its own. Realtime clients created separately keep the old key until
+// they are given the new one with realtime.WebSocketClient.SetCredentials.
+//
+// Example:
+//
+//	rotation, err := spooled.RotateAPIKey(ctx, client, spooled.RotationOptions{
+//		Grace: time.Hour,
+//		Persist: func(ctx context.Context, key *resources.CreateAPIKeyResponse) error {
+//			return secrets.Put(ctx, "spooled-api-key", key.Key)
+//		},
+//	})
+func RotateAPIKey(ctx context.Context, c *Client, opts RotationOptions) (*Rotation, error) {
+	if c.transport.APIKey() == "" {
+		return nil, fmt.Errorf("client is not authenticated with an API key")
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultRotationGrace
+	}
+
+	me, err := c.Auth().Me(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("identify current API key: %w", err)
+	}
+	if me.APIKeyID == "" {
+		return nil, fmt.Errorf("client is not authenticated with an API key")
+	}
+	old, err := c.APIKeys().Get(ctx, me.APIKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("get current API key: %w", err)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = old.Name
+	}
+	created, err := c.APIKeys().Create(ctx, &resources.CreateAPIKeyRequest{
+		Name:      name,
+		Queues:    old.Queues,
+		RateLimit: old.RateLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create API key: %w", err)
+	}
+
+	// From here on a failure must not leave an unused key behind
+	abort := func(err error) (*Rotation, error) {
+		if delErr := c.APIKeys().Delete(context.WithoutCancel(ctx), created.ID); delErr != nil {
+			c.debug("failed to delete new API key after aborted rotation", "key_id", created.ID, "error", delErr)
+		}
+		return nil, err
+	}
+
+	if err := c.verifyAPIKey(ctx, created); err != nil {
+		return abort(fmt.Errorf("verify new API key: %w", err))
+	}
+	if opts.Persist != nil {
+		if err := opts.Persist(ctx, created); err != nil {
+			return abort(fmt.Errorf("persist new API key: %w", err))
+		}
+	}
+
+	c.setAPIKey(created.Key)
+	c.debug("API key rotated", "old_key_id", old.ID, "new_key_id", created.ID)
+
+	rotation := &Rotation{
+		NewKey:   created,
+		OldKeyID: old.ID,
+		DeleteAt: time.Now().Add(opts.Grace),
+	}
+	rotation.timer = time.AfterFunc(opts.Grace, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := c.APIKeys().Delete(ctx, old.ID)
+		if err != nil {
+			c.debug("failed to delete rotated API key", "key_id", old.ID, "error", err)
+		}
+		if opts.OnOldKeyDeleted != nil {
+			opts.OnOldKeyDeleted(old.ID, err)
+		}
+	})
+	return rotation, nil
+}
+
+// verifyAPIKey checks that key authenticates against the client's API.
+func (c *Client) verifyAPIKey(ctx context.Context, key *resources.CreateAPIKeyResponse) error {
+	probe, err := c.withAPIKey(key.Key)
+	if err != nil {
+		return err
+	}
+	defer probe.Close()
+
+	me, err := probe.Auth().Me(ctx)
+	if err != nil {
+		return err
+	}
+	if me.APIKeyID != "" && me.APIKeyID != key.ID {
+		return fmt.Errorf("authenticated as key %s, want %s", me.APIKeyID, key.ID)
+	}
+	return nil
+}
+
+// setAPIKey swaps the API key used by the REST transport, gRPC client, and
+// realtime client. Access tokens issued for the old key are dropped, and the
+// realtime connection reconnects with the new key.
+func (c *Client) setAPIKey(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg.APIKey = key
+	c.transport.SetAPIKey(key)
+	if c.grpcClient != nil {
+		c.grpcClient.SetAPIKey(key)
+	}
+	if c.realtimeClient != nil {
+		c.realtimeClient.SetCredentials("", key)
+	}
+}
@@ -0,0 +1,141 @@
+package spooled
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestRotateAPIKey(t *testing.T) {
+	const newKey = "sp_test_abcdefghijklmnopqrstuvwxyzabcd"
+	var deleted atomic.Value
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/auth/me":
+			id := "key-old"
+			if auth == newKey {
+				id = "key-new"
+			}
+			_, _ = w.Write([]byte(`{"organization_id":"org-1","api_key_id":"` + id + `"}`))
+		case "GET /api/v1/api-keys/key-old":
+			_, _ = w.Write([]byte(`{"id":"key-old","name":"producer","queues":["emails"],"is_active":true}`))
+		case "POST /api/v1/api-keys":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"key-new","key":"` + newKey + `","name":"producer"}`))
+		case "DELETE /api/v1/api-keys/key-old":
+			deleted.Store(auth)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var persisted string
+	done := make(chan string, 1)
+	rotation, err := RotateAPIKey(context.Background(), client, RotationOptions{
+		Grace: 10 * time.Millisecond,
+		Persist: func(ctx context.Context, key *resources.CreateAPIKeyResponse) error {
+			persisted = key.Key
+			return nil
+		},
+		OnOldKeyDeleted: func(keyID string, err error) {
+			if err != nil {
+				t.Errorf("delete old key: %v", err)
+			}
+			done <- keyID
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rotation.OldKeyID != "key-old" || rotation.NewKey.ID != "key-new" {
+		t.Errorf("rotation = %s -> %s, want key-old -> key-new", rotation.OldKeyID, rotation.NewKey.ID)
+	}
+	if persisted != newKey {
+		t.Errorf("persisted = %q, want %q", persisted, newKey)
+	}
+	if got := client.GetConfig().APIKey; got != newKey {
+		t.Errorf("APIKey = %q, want %q", got, newKey)
+	}
+
+	select {
+	case id := <-done:
+		if id != "key-old" {
+			t.Errorf("deleted key = %q, want %q", id, "key-old")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("old key was not deleted")
+	}
+	// The old key is deleted using the new one
+	if auth, _ := deleted.Load().(string); auth != newKey {
+		t.Errorf("delete authenticated with %q, want %q", auth, newKey)
+	}
+}
+
+// Run with -race: the key is swapped while requests, realtime, and config
+// reads are in flight.
+func TestRotateAPIKey_ConcurrentRequests(t *testing.T) {
+	const newKey = "sp_test_abcdefghijklmnopqrstuvwxyzabcd"
+	const oldToken = "token-for-old-key"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/auth/me":
+			id := "key-old"
+			if auth == newKey {
+				id = "key-new"
+			}
+			_, _ = w.Write([]byte(`{"organization_id":"org-1","api_key_id":"` + id + `"}`))
+		case "GET /api/v1/api-keys/key-old":
+			_, _ = w.Write([]byte(`{"id":"key-old","name":"producer","is_active":true}`))
+		case "POST /api/v1/api-keys":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"key-new","key":"` + newKey + `","name":"producer"}`))
+		case "GET /health":
+			_, _ = w.Write([]byte(`{"status":"healthy"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}, WithAccessToken(oldToken))
+	client.Realtime()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				_, _ = client.Health().Get(ctx)
+				_ = client.GetConfig()
+				client.Realtime()
+			}
+		}()
+	}
+
+	rotation, err := RotateAPIKey(context.Background(), client, RotationOptions{Grace: time.Hour})
+	cancel()
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rotation.CancelDeletion()
+
+	// The access token was issued for the old key and must not outlive it
+	me, err := client.Auth().Me(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if me.APIKeyID != "key-new" {
+		t.Errorf("request after rotation authenticated as %q, want key-new", me.APIKeyID)
+	}
+}
@@ -0,0 +1,221 @@
+// Package sdkevents provides a client-wide event bus for SDK internals.
+//
+// Events are emitted for retries, circuit breaker transitions, token refreshes,
+// gRPC reconnects, endpoint failover, clock skew, API deprecations and version
+// mismatches, worker lifecycle changes, slow jobs, queue lag, and destructive
+// operations, so a single subscriber can feed everything the SDK does into an
+// observability pipeline:
+//
+//	client.OnEvent(func(e sdkevents.Event) {
+//		log.Printf("[%s] %+v", e.Type, e.Data)
+//	})
+package sdkevents
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of SDK event.
+type Type string
+
+const (
+	TypeRequestRetry       Type = "request.retry"
+	TypeCircuitStateChange Type = "circuit.state_change"
+	TypeTokenRefreshed     Type = "auth.token_refreshed"
+	TypeTokenRefreshFailed Type = "auth.token_refresh_failed"
+	TypeGRPCReconnect      Type = "grpc.reconnect"
+	TypeWorkerStarted      Type = "worker.started"
+	TypeWorkerStopped      Type = "worker.stopped"
+	TypeWorkerError        Type = "worker.error"
+	TypeWorkerSummary      Type = "worker.summary"
+	TypeWorkerJobSlow      Type = "worker.job_slow"
+	TypeClockSkewDetected  Type = "clock.skew_detected"
+	TypeEndpointFailover   Type = "endpoint.failover"
+	TypeDeprecationWarning Type = "api.deprecation_warning"
+	TypeAPIVersionMismatch Type = "api.version_mismatch"
+)
+
+// TypeDestructiveOperation is emitted for every attempt at a destructive
+// operation; see DestructiveOperationData.
+const TypeDestructiveOperation Type = "audit.destructive_operation"
+
+// Queue lag events are emitted by Client.WatchLag when a queue's lag crosses
+// its thresholds; see QueueLagData.
+const (
+	TypeQueueLagExceeded  Type = "queue.lag_exceeded"
+	TypeQueueLagRecovered Type = "queue.lag_recovered"
+)
+
+// Event is emitted by SDK internals.
+type Event struct {
+	Type      Type
+	Timestamp time.Time
+	Data      any
+}
+
+// RequestRetryData is emitted before a REST request is retried.
+type RequestRetryData struct {
+	Method  string
+	Path    string
+	Attempt int
+	Delay   time.Duration
+	Error   error
+}
+
+// CircuitStateChangeData is emitted when the circuit breaker changes state.
+type CircuitStateChangeData struct {
+	From string
+	To   string
+}
+
+// TokenRefreshData is emitted after a token refresh attempt.
+type TokenRefreshData struct {
+	// Method is "refresh_token" or "api_key".
+	Method    string
+	ExpiresIn int
+	Error     error
+}
+
+// GRPCReconnectData is emitted when a gRPC connection becomes ready again after a failure.
+type GRPCReconnectData struct {
+	Address       string
+	PreviousState string
+}
+
+// WorkerLifecycleData is emitted on worker start, stop, and error. A
+// worker.summary event follows worker.stopped and carries a worker.RunSummary;
+// worker.job_slow events carry a worker.JobSlowData.
+type WorkerLifecycleData struct {
+	WorkerID  string
+	QueueName string
+	Reason    string
+	Error     error
+}
+
+// ClockSkewData is emitted when the client clock drifts from the server clock
+// by more than the configured threshold.
+type ClockSkewData struct {
+	// Skew is the server clock minus the local clock.
+	Skew      time.Duration
+	Threshold time.Duration
+}
+
+// EndpointFailoverData is emitted when requests move to a different base URL.
+type EndpointFailoverData struct {
+	From string
+	To   string
+}
+
+// DeprecationData is emitted the first time a response marks an endpoint as
+// deprecated or removed.
+type DeprecationData struct {
+	Method string
+	Path   string
+	// Sunset is when the endpoint will be removed (zero if unknown).
+	Sunset  time.Time
+	Link    string
+	Removed bool
+}
+
+// APIVersionMismatchData is emitted the first time the server applies a
+// different API version than the one pinned with WithAPIVersion.
+type APIVersionMismatchData struct {
+	Requested string
+	Served    string
+}
+
+// DestructiveOperationData is emitted for every attempt at a destructive
+// operation such as purging the DLQ or deleting a queue, including dry runs
+// and attempts blocked by protection.
+type DestructiveOperationData struct {
+	Operation string
+	// Target is the queue name, or "*" for all queues.
+	Target    string
+	DryRun    bool
+	Confirmed bool
+	// Protected is set when the client requires confirmation.
+	Protected bool
+	// Affected is the number of jobs purged, or that would be for a dry run.
+	// It is 0 for queue deletions.
+	Affected int
+	Error    error
+}
+
+// QueueLagData is emitted when a queue's consumer lag exceeds or falls back
+// within the thresholds passed to Client.WatchLag.
+type QueueLagData struct {
+	QueueName        string
+	PendingJobs      int
+	OldestPendingAge time.Duration
+	// EstimatedDrainTime is 0 when Stalled.
+	EstimatedDrainTime time.Duration
+	Stalled            bool
+	MaxAge             time.Duration
+	MaxDrainTime       time.Duration
+}
+
+// Handler is a callback for SDK events.
+type Handler func(Event)
+
+// Bus fans events out to subscribed handlers.
+// A nil *Bus is valid and drops all events.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+	logger   func(msg string, keysAndValues ...any)
+}
+
+// NewBus creates a new event bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// SetLogger sets a debug logger used to report handler panics.
+func (b *Bus) SetLogger(logger func(msg string, keysAndValues ...any)) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logger = logger
+}
+
+// Subscribe registers a handler for all events.
+func (b *Bus) Subscribe(handler Handler) {
+	if b == nil || handler == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Emit delivers an event synchronously to all handlers.
+// A zero Timestamp is set to the current time.
+func (b *Bus) Emit(eventType Type, data any) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := make([]Handler, len(b.handlers))
+	copy(handlers, b.handlers)
+	logger := b.logger
+	b.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return
+	}
+
+	event := Event{Type: eventType, Timestamp: time.Now(), Data: data}
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil && logger != nil {
+					logger("event handler panic", "type", eventType, "panic", r)
+				}
+			}()
+			handler(event)
+		}()
+	}
+}
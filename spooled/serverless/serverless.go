@@ -0,0 +1,158 @@
+// Package serverless runs Spooled job handlers in serverless functions such as
+// AWS Lambda.
+//
+// A job is delivered to the function by a completion webhook or a queue bridge
+// (e.g. SQS) that has already claimed it. Handler runs the same
+// worker.JobHandler used by long-running workers, with the same JobContext
+// API, and completes or fails the job over REST:
+//
+//	h := serverless.NewHandler(client, processOrder, worker.Options{})
+//
+//	// SQS bridge
+//	lambda.Start(h.HandleSQS)
+//
+//	// Webhook delivery through a function URL or API Gateway
+//	http.Handle("/jobs", h)
+package serverless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
+)
+
+// Job is a claimed job delivered to a serverless function, encoded as JSON
+// with the fields of resources.ClaimedJob plus the claiming worker's ID.
+type Job struct {
+	resources.ClaimedJob
+	// WorkerID is the worker that claimed the job; Complete and Fail are
+	// reported on its behalf.
+	WorkerID string `json:"worker_id"`
+}
+
+// Handler adapts a worker.JobHandler to serverless invocations.
+type Handler struct {
+	backend   worker.Backend
+	handler   worker.JobHandler
+	opts      worker.Options
+	useNumber bool
+}
+
+// NewHandler creates a Handler that runs handler for each delivered job and
+// reports the outcome through client's REST API. opts configures the job
+// runtime as for a worker (LeaseDuration, AckMode, ResultValidator, Logger,
+// TracePropagator); queue and polling options are ignored.
+func NewHandler(client *spooled.Client, handler worker.JobHandler, opts worker.Options) *Handler {
+	return &Handler{
+		backend:   worker.NewRESTBackend(client.Jobs(), client.Workers()),
+		handler:   handler,
+		opts:      opts,
+		useNumber: client.GetConfig().UseNumber,
+	}
+}
+
+// Invoke runs the handler for job and completes or fails it. It returns the
+// handler's result and error after the outcome has been reported.
+func (h *Handler) Invoke(ctx context.Context, job *Job) (map[string]any, error) {
+	return worker.RunJob(ctx, h.backend, job.WorkerID, job.ClaimedJob, h.handler, h.opts)
+}
+
+// Decode parses a job delivered as JSON, honoring the client's UseNumber setting.
+func (h *Handler) Decode(data []byte) (*Job, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if h.useNumber {
+		dec.UseNumber()
+	}
+	var job Job
+	if err := dec.Decode(&job); err != nil {
+		return nil, fmt.Errorf("invalid job: %w", err)
+	}
+	if job.ID == "" {
+		return nil, errors.New("invalid job: missing id")
+	}
+	return &job, nil
+}
+
+// SQSEvent is an SQS-triggered Lambda event. It matches the JSON of
+// events.SQSEvent in github.com/aws/aws-lambda-go, so HandleSQS can be passed
+// to lambda.Start directly.
+type SQSEvent struct {
+	Records []SQSMessage `json:"Records"`
+}
+
+// SQSMessage is one SQS message; Body holds a JSON-encoded Job.
+type SQSMessage struct {
+	MessageID string `json:"messageId"`
+	Body      string `json:"body"`
+}
+
+// SQSBatchResponse reports messages that should be redelivered.
+type SQSBatchResponse struct {
+	BatchItemFailures []SQSBatchItemFailure `json:"batchItemFailures"`
+}
+
+// SQSBatchItemFailure identifies a message to redeliver.
+type SQSBatchItemFailure struct {
+	ItemIdentifier string `json:"itemIdentifier"`
+}
+
+// HandleSQS runs the handler for each message in the batch. Handler errors are
+// reported to Spooled, which applies the job's retry policy, so only messages
+// that are not valid jobs are returned for redelivery. Enable
+// ReportBatchItemFailures on the event source mapping.
+func (h *Handler) HandleSQS(ctx context.Context, event SQSEvent) (SQSBatchResponse, error) {
+	resp := SQSBatchResponse{BatchItemFailures: []SQSBatchItemFailure{}}
+	for _, msg := range event.Records {
+		job, err := h.Decode([]byte(msg.Body))
+		if err != nil {
+			resp.BatchItemFailures = append(resp.BatchItemFailures, SQSBatchItemFailure{ItemIdentifier: msg.MessageID})
+			continue
+		}
+		_, _ = h.Invoke(ctx, job)
+	}
+	return resp, nil
+}
+
+// webhookResponse is the body ServeHTTP answers with.
+type webhookResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ServeHTTP handles a job delivered as a JSON POST body. It responds 200 once
+// the outcome has been reported, whether the job completed or failed, and 400
+// if the body is not a valid job.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	job, err := h.Decode(buf.Bytes())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := webhookResponse{JobID: job.ID, Status: "completed"}
+	if _, err := h.Invoke(r.Context(), job); err != nil {
+		resp.Status = "failed"
+		resp.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,191 @@
+package serverless
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/spooledtest"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
+)
+
+func newClient(t *testing.T, srv *spooledtest.Server, opts ...spooled.Option) *spooled.Client {
+	t.Helper()
+	client, err := srv.Client(opts...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// claim enqueues a job, claims it as worker-1, and returns it encoded as a
+// bridge or webhook would deliver it.
+func claim(t *testing.T, client *spooled.Client, payload map[string]any) (string, []byte) {
+	t.Helper()
+	ctx := context.Background()
+	noRetries := 0
+	created, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "orders", Payload: payload, MaxRetries: &noRetries})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	claimed, err := client.Jobs().Claim(ctx, &resources.ClaimJobsRequest{QueueName: "orders", WorkerID: "worker-1"})
+	if err != nil || len(claimed.Jobs) != 1 {
+		t.Fatalf("Claim() = %+v, %v", claimed, err)
+	}
+	data, err := json.Marshal(Job{ClaimedJob: claimed.Jobs[0], WorkerID: "worker-1"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return created.ID, data
+}
+
+// process completes jobs with a "total" payload and fails all others.
+func process(ctx *worker.JobContext) (map[string]any, error) {
+	total, err := ctx.Payload.Int64("total")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"charged": total}, nil
+}
+
+func TestHandler_Invoke(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client := newClient(t, srv)
+	h := NewHandler(client, process, worker.Options{})
+
+	id, data := claim(t, client, map[string]any{"total": 42})
+	job, err := h.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if job.ID != id || job.WorkerID != "worker-1" || job.QueueName != "orders" {
+		t.Errorf("Decode() = %+v", job)
+	}
+	result, err := h.Invoke(context.Background(), job)
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if result["charged"] != int64(42) {
+		t.Errorf("result = %v, want charged=42", result)
+	}
+	if got, _ := srv.Job(id); got.Status != resources.JobStatusCompleted {
+		t.Errorf("job status = %s, want completed", got.Status)
+	}
+
+	id, data = claim(t, client, map[string]any{})
+	job, _ = h.Decode(data)
+	if _, err := h.Invoke(context.Background(), job); err == nil {
+		t.Error("Invoke() should return the handler's error")
+	}
+	if got, _ := srv.Job(id); got.Status != resources.JobStatusDeadletter || got.LastError == nil {
+		t.Errorf("job = %s (%v), want a reported failure", got.Status, got.LastError)
+	}
+}
+
+func TestHandler_Decode(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+
+	h := NewHandler(newClient(t, srv), process, worker.Options{})
+	for _, body := range []string{`not json`, `{"queue_name":"orders"}`} {
+		if _, err := h.Decode([]byte(body)); err == nil {
+			t.Errorf("Decode(%s) should fail", body)
+		}
+	}
+
+	body := []byte(`{"id":"job-1","worker_id":"worker-1","payload":{"total":9007199254740993}}`)
+	h = NewHandler(newClient(t, srv, spooled.WithUseNumber(true)), process, worker.Options{})
+	job, err := h.Decode(body)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if n, ok := job.Payload["total"].(json.Number); !ok || n.String() != "9007199254740993" {
+		t.Errorf("payload total = %#v, want an exact json.Number", job.Payload["total"])
+	}
+}
+
+func TestHandler_HandleSQS(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client := newClient(t, srv)
+	h := NewHandler(client, process, worker.Options{})
+
+	okID, okBody := claim(t, client, map[string]any{"total": 1})
+	failID, failBody := claim(t, client, map[string]any{})
+	resp, err := h.HandleSQS(context.Background(), SQSEvent{Records: []SQSMessage{
+		{MessageID: "m1", Body: string(okBody)},
+		{MessageID: "m2", Body: "garbage"},
+		{MessageID: "m3", Body: string(failBody)},
+	}})
+	if err != nil {
+		t.Fatalf("HandleSQS: %v", err)
+	}
+	// Handler errors are reported to Spooled; only undecodable messages are redelivered
+	if len(resp.BatchItemFailures) != 1 || resp.BatchItemFailures[0].ItemIdentifier != "m2" {
+		t.Errorf("BatchItemFailures = %+v, want [m2]", resp.BatchItemFailures)
+	}
+	if got, _ := srv.Job(okID); got.Status != resources.JobStatusCompleted {
+		t.Errorf("job %s status = %s, want completed", okID, got.Status)
+	}
+	if got, _ := srv.Job(failID); got.Status != resources.JobStatusDeadletter {
+		t.Errorf("job %s status = %s, want deadletter", failID, got.Status)
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+	defer srv.Close()
+	client := newClient(t, srv)
+	h := NewHandler(client, process, worker.Options{})
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/jobs", strings.NewReader(body)))
+		return rec
+	}
+
+	okID, okBody := claim(t, client, map[string]any{"total": 1})
+	failID, failBody := claim(t, client, map[string]any{})
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus string
+		wantID     string
+	}{
+		{"completed", string(okBody), "completed", okID},
+		{"failed", string(failBody), "failed", failID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodPost, tt.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			var resp webhookResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if resp.JobID != tt.wantID || resp.Status != tt.wantStatus {
+				t.Errorf("response = %+v, want %s %s", resp, tt.wantID, tt.wantStatus)
+			}
+			if (resp.Error != "") != (tt.wantStatus == "failed") {
+				t.Errorf("response error = %q", resp.Error)
+			}
+		})
+	}
+
+	if rec := serve(http.MethodPost, `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid job status = %d, want 400", rec.Code)
+	}
+	rec := serve(http.MethodGet, "")
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("GET status = %d, Allow = %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
@@ -0,0 +1,308 @@
+package spooledtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// queueState is the server-side state of a queue. Queues exist once a job
+// or schedule uses them, or once they are paused.
+type queueState struct {
+	createdAt time.Time
+	paused    bool
+	pausedAt  time.Time
+	reason    *string
+}
+
+// queue returns the state of the named queue, creating it if needed.
+func (s *Server) queue(name string) *queueState {
+	q, ok := s.queues[name]
+	if !ok {
+		q = &queueState{createdAt: s.now}
+		s.queues[name] = q
+	}
+	return q
+}
+
+// listJobs lists jobs in creation order, filtered by the queue_name and
+// status query parameters.
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	jobs := s.filterJobs(func(job *resources.Job) bool {
+		return matches(query, "queue_name", job.QueueName) && matches(query, "status", string(job.Status))
+	})
+	writeJSON(w, http.StatusOK, paginate(jobs, query))
+}
+
+func (s *Server) bulkEnqueue(w http.ResponseWriter, r *http.Request) {
+	var req resources.BulkEnqueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.QueueName == "" {
+		writeError(w, http.StatusBadRequest, "validation_error", "queue_name and a JSON body are required")
+		return
+	}
+	resp := resources.BulkEnqueueResponse{
+		Succeeded: []resources.BulkJobSuccess{},
+		Failed:    []resources.BulkJobFailure{},
+		Total:     len(req.Jobs),
+	}
+	for i, item := range req.Jobs {
+		priority := item.Priority
+		if priority == nil {
+			priority = req.DefaultPriority
+		}
+		id, created := s.addJob(&resources.CreateJobRequest{
+			QueueName:      req.QueueName,
+			Payload:        item.Payload,
+			Priority:       priority,
+			MaxRetries:     req.DefaultMaxRetries,
+			TimeoutSeconds: req.DefaultTimeoutSeconds,
+			IdempotencyKey: item.IdempotencyKey,
+			ScheduledAt:    item.ScheduledAt,
+		})
+		resp.Succeeded = append(resp.Succeeded, resources.BulkJobSuccess{Index: i, JobID: id, Created: created})
+	}
+	resp.SuccessCount = len(resp.Succeeded)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// cancelJob cancels a job that has not finished. Finished jobs fail with 409
+// Conflict.
+func (s *Server) cancelJob(w http.ResponseWriter, id string) {
+	job, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("job %s not found", id))
+		return
+	}
+	switch job.Status {
+	case resources.JobStatusPending, resources.JobStatusScheduled, resources.JobStatusProcessing:
+	default:
+		writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("job %s is %s and cannot be cancelled", id, job.Status))
+		return
+	}
+	job.Status = resources.JobStatusCancelled
+	job.AssignedWorkerID = nil
+	job.LeaseExpiresAt = nil
+	job.UpdatedAt = s.now
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// retryJob makes a failed, dead-lettered, or cancelled job pending again with
+// a fresh set of retries. Other jobs fail with 409 Conflict.
+func (s *Server) retryJob(w http.ResponseWriter, id string) {
+	job, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("job %s not found", id))
+		return
+	}
+	switch job.Status {
+	case resources.JobStatusFailed, resources.JobStatusDeadletter, resources.JobStatusCancelled:
+	default:
+		writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("job %s is %s and cannot be retried", id, job.Status))
+		return
+	}
+	s.requeue(job)
+	writeJSON(w, http.StatusOK, job)
+}
+
+// requeue makes job pending again with a fresh set of retries.
+func (s *Server) requeue(job *resources.Job) {
+	job.Status = resources.JobStatusPending
+	job.RetryCount = 0
+	job.ScheduledAt = nil
+	job.UpdatedAt = s.now
+}
+
+// listDLQ lists dead-lettered jobs, filtered by the queue_name query
+// parameter.
+func (s *Server) listDLQ(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	jobs := s.filterJobs(func(job *resources.Job) bool {
+		return job.Status == resources.JobStatusDeadletter && matches(query, "queue_name", job.QueueName)
+	})
+	writeJSON(w, http.StatusOK, paginate(jobs, query))
+}
+
+func (s *Server) retryDLQ(w http.ResponseWriter, r *http.Request) {
+	var req resources.RetryDLQRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	resp := resources.RetryDLQResponse{RetriedJobs: []string{}}
+	for _, id := range s.order {
+		job := s.jobs[id]
+		if job.Status != resources.JobStatusDeadletter ||
+			(req.QueueName != nil && job.QueueName != *req.QueueName) ||
+			(len(req.JobIDs) > 0 && !slices.Contains(req.JobIDs, id)) {
+			continue
+		}
+		s.requeue(job)
+		resp.RetriedJobs = append(resp.RetriedJobs, id)
+	}
+	resp.RetriedCount = len(resp.RetriedJobs)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) purgeDLQ(w http.ResponseWriter, r *http.Request) {
+	var req resources.PurgeDLQRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	kept := s.order[:0]
+	purged := 0
+	for _, id := range s.order {
+		job := s.jobs[id]
+		if job.Status == resources.JobStatusDeadletter && (req.QueueName == nil || job.QueueName == *req.QueueName) {
+			delete(s.jobs, id)
+			delete(s.jobBackoffs, id)
+			purged++
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.order = kept
+	writeJSON(w, http.StatusOK, resources.PurgeDLQResponse{PurgedCount: purged})
+}
+
+// serveQueues handles the queue endpoints; parts is the path after
+// "queues".
+func (s *Server) serveQueues(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 0:
+		s.listQueues(w)
+		return
+	case len(parts) == 2 && r.Method == http.MethodPost && parts[1] == "pause":
+		s.pauseQueue(w, r, parts[0])
+		return
+	case len(parts) == 2 && r.Method == http.MethodPost && parts[1] == "resume":
+		s.resumeQueue(w, parts[0])
+		return
+	}
+
+	if len(parts) == 0 || r.Method != http.MethodGet || len(parts) > 2 || (len(parts) == 2 && parts[1] != "stats") {
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+		return
+	}
+	q, ok := s.queues[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("queue %s not found", parts[0]))
+		return
+	}
+	if len(parts) == 2 {
+		s.queueStats(w, parts[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, resources.QueueConfig{
+		ID:             "queue-" + parts[0],
+		QueueName:      parts[0],
+		MaxRetries:     DefaultMaxRetries,
+		DefaultTimeout: int(DefaultLeaseDuration / time.Second),
+		Enabled:        !q.paused,
+		Settings:       map[string]any{},
+		CreatedAt:      q.createdAt,
+		UpdatedAt:      q.createdAt,
+	})
+}
+
+func (s *Server) listQueues(w http.ResponseWriter) {
+	names := make([]string, 0, len(s.queues))
+	for name := range s.queues {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	items := make([]resources.QueueListItem, len(names))
+	for i, name := range names {
+		items[i] = resources.QueueListItem{
+			QueueName:      name,
+			MaxRetries:     DefaultMaxRetries,
+			DefaultTimeout: int(DefaultLeaseDuration / time.Second),
+			Enabled:        !s.queues[name].paused,
+		}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// queueStats reports job counts; the 24h counts cover every job the server
+// has seen.
+func (s *Server) queueStats(w http.ResponseWriter, name string) {
+	stats := resources.QueueStats{QueueName: name}
+	for _, job := range s.jobs {
+		if job.QueueName != name {
+			continue
+		}
+		switch job.Status {
+		case resources.JobStatusPending:
+			stats.PendingJobs++
+		case resources.JobStatusProcessing:
+			stats.ProcessingJobs++
+		case resources.JobStatusCompleted:
+			stats.CompletedJobs24h++
+		case resources.JobStatusFailed, resources.JobStatusDeadletter:
+			stats.FailedJobs24h++
+		}
+	}
+	for _, queueName := range s.workers {
+		if queueName == name {
+			stats.ActiveWorkers++
+		}
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// pauseQueue stops jobs in the queue from being claimed until it is resumed.
+func (s *Server) pauseQueue(w http.ResponseWriter, r *http.Request, name string) {
+	var req resources.PauseQueueRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	q := s.queue(name)
+	if !q.paused {
+		q.paused, q.pausedAt = true, s.now
+	}
+	q.reason = req.Reason
+	writeJSON(w, http.StatusOK, resources.PauseQueueResponse{QueueName: name, Paused: true, PausedAt: q.pausedAt, Reason: q.reason})
+}
+
+func (s *Server) resumeQueue(w http.ResponseWriter, name string) {
+	q := s.queue(name)
+	resp := resources.ResumeQueueResponse{QueueName: name, Resumed: q.paused}
+	if q.paused {
+		resp.PausedDurationSecs = int(s.now.Sub(q.pausedAt) / time.Second)
+	}
+	q.paused, q.reason = false, nil
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// filterJobs returns copies of the jobs keep accepts, in creation order.
+func (s *Server) filterJobs(keep func(*resources.Job) bool) []resources.Job {
+	jobs := []resources.Job{}
+	for _, id := range s.order {
+		if job := s.jobs[id]; keep(job) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs
+}
+
+// matches reports whether the query parameter key is unset or equal to value.
+func matches(query url.Values, key, value string) bool {
+	want := query.Get(key)
+	return want == "" || want == value
+}
+
+// paginate applies the limit and offset query parameters to items.
+func paginate[T any](items []T, query url.Values) []T {
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	offset = min(max(offset, 0), len(items))
+	items = items[offset:]
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 0 {
+		items = items[:min(limit, len(items))]
+	}
+	return items
+}
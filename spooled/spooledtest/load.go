@@ -0,0 +1,384 @@
+// Package spooledtest provides utilities for testing and capacity planning
+// against a Spooled server.
+//
+// Server is an in-memory Spooled API with a virtual clock, for unit tests of
+// producers and workers.
+//
+// LoadGenerator produces synthetic load and reports the throughput and
+// latency achieved:
+//
+//	report, err := spooledtest.LoadGenerator(client, spooledtest.LoadSpec{
+//		Queues:      []string{"load-a", "load-b"},
+//		RatePerSec:  200,
+//		PayloadSize: 1024,
+//		Duration:    time.Minute,
+//		RampUp:      10 * time.Second,
+//		FailureRate: 0.01,
+//		Process:     true,
+//	}).Run(ctx)
+//	fmt.Println(report)
+package spooledtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// MarkerKey is the payload key holding load test markers, which LoadHandler
+// reads to inject failures and processing time.
+const MarkerKey = "_loadtest"
+
+// LoadSpec describes the load to generate.
+type LoadSpec struct {
+	// Queues receive jobs in turn (required).
+	Queues []string
+	// RatePerSec is the steady enqueue rate across all queues (required).
+	RatePerSec float64
+	// PayloadSize is the approximate size in bytes of each job's payload.
+	PayloadSize int
+	// Duration is how long to generate load (required).
+	Duration time.Duration
+	// RampUp raises the rate linearly from zero to RatePerSec over this long
+	// (optional).
+	RampUp time.Duration
+	// BurstEvery and BurstSize enqueue BurstSize extra jobs at once every
+	// BurstEvery, on top of the steady rate (optional).
+	BurstEvery time.Duration
+	BurstSize  int
+	// FailureRate is the fraction of jobs, from 0 to 1, marked to fail in
+	// LoadHandler.
+	FailureRate float64
+	// ProcessingTime is how long LoadHandler spends on each job.
+	ProcessingTime time.Duration
+	// Process runs a worker with LoadHandler for each queue, so the report
+	// covers processing as well as enqueueing. Otherwise jobs are left for
+	// workers run elsewhere.
+	Process bool
+	// Concurrency is each worker's concurrency when Process is set (default: 10).
+	Concurrency int
+	// MaxInFlight bounds concurrent enqueue requests (default: 64).
+	MaxInFlight int
+	// DrainTimeout is how long to wait for workers to finish the generated
+	// jobs once load stops (default: 30s).
+	DrainTimeout time.Duration
+}
+
+// LatencyStats summarizes observed latencies.
+type LatencyStats struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// LoadReport is the outcome of a load run.
+type LoadReport struct {
+	Enqueued      int
+	EnqueueErrors int
+	// Processed and Failed count jobs handled by the generator's workers.
+	Processed int
+	Failed    int
+	// Elapsed is how long load was generated.
+	Elapsed time.Duration
+	// EnqueueThroughput is successful enqueues per second.
+	EnqueueThroughput float64
+	// ProcessThroughput is handled jobs per second, including the drain.
+	ProcessThroughput float64
+	// EnqueueLatency is the round-trip time of enqueue requests.
+	EnqueueLatency LatencyStats
+	// PickupLatency is the time from enqueue until a worker started the job.
+	PickupLatency LatencyStats
+	// Errors counts enqueue errors by message.
+	Errors map[string]int
+}
+
+// String formats the report for logs.
+func (r *LoadReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "enqueued %d (%d errors) in %s: %.1f jobs/s\n", r.Enqueued, r.EnqueueErrors, r.Elapsed.Round(time.Millisecond), r.EnqueueThroughput)
+	fmt.Fprintf(&b, "enqueue latency: %s\n", r.EnqueueLatency)
+	if r.Processed+r.Failed > 0 {
+		fmt.Fprintf(&b, "processed %d, failed %d: %.1f jobs/s\n", r.Processed, r.Failed, r.ProcessThroughput)
+		fmt.Fprintf(&b, "pickup latency: %s\n", r.PickupLatency)
+	}
+	return b.String()
+}
+
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("p50 %s, p95 %s, p99 %s, max %s (n=%d)", s.P50, s.P95, s.P99, s.Max, s.Count)
+}
+
+// Generator runs a LoadSpec against a client.
+type Generator struct {
+	client *spooled.Client
+	spec   LoadSpec
+
+	mu             sync.Mutex
+	enqueueLatency []time.Duration
+	pickupLatency  []time.Duration
+	enqueued       int
+	processed      int
+	failed         int
+	errors         map[string]int
+}
+
+// LoadGenerator creates a Generator for spec. Call Run to generate the load.
+func LoadGenerator(client *spooled.Client, spec LoadSpec) *Generator {
+	if spec.Concurrency <= 0 {
+		spec.Concurrency = 10
+	}
+	if spec.MaxInFlight <= 0 {
+		spec.MaxInFlight = 64
+	}
+	if spec.DrainTimeout <= 0 {
+		spec.DrainTimeout = 30 * time.Second
+	}
+	return &Generator{client: client, spec: spec, errors: map[string]int{}}
+}
+
+// Run generates load for spec.Duration, or until ctx is cancelled, and
+// returns the report. With Process set, it waits up to DrainTimeout for the
+// workers to handle the generated jobs.
+func (g *Generator) Run(ctx context.Context) (*LoadReport, error) {
+	spec := g.spec
+	if len(spec.Queues) == 0 || spec.RatePerSec <= 0 || spec.Duration <= 0 {
+		return nil, fmt.Errorf("queues, rate, and duration are required")
+	}
+	if spec.FailureRate < 0 || spec.FailureRate > 1 {
+		return nil, fmt.Errorf("failure rate must be between 0 and 1")
+	}
+
+	var workers []*spooled.SpooledWorker
+	if spec.Process {
+		for _, q := range spec.Queues {
+			w := spooled.NewSpooledWorker(g.client, spooled.SpooledWorkerOptions{QueueName: q, Concurrency: spec.Concurrency})
+			w.Process(g.handle)
+			if err := w.Start(); err != nil {
+				for _, started := range workers {
+					_ = started.Stop()
+				}
+				return nil, fmt.Errorf("start worker for %s: %w", q, err)
+			}
+			workers = append(workers, w)
+		}
+	}
+
+	start := time.Now()
+	g.generate(ctx, start)
+	elapsed := time.Since(start)
+
+	if spec.Process {
+		deadline := time.Now().Add(spec.DrainTimeout)
+		for time.Now().Before(deadline) && ctx.Err() == nil {
+			g.mu.Lock()
+			done := g.processed+g.failed >= g.enqueued
+			g.mu.Unlock()
+			if done {
+				break
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		for _, w := range workers {
+			_ = w.Stop()
+		}
+	}
+	total := time.Since(start)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	report := &LoadReport{
+		Enqueued:          g.enqueued,
+		Processed:         g.processed,
+		Failed:            g.failed,
+		Elapsed:           elapsed,
+		EnqueueThroughput: float64(g.enqueued) / elapsed.Seconds(),
+		EnqueueLatency:    latencyStats(g.enqueueLatency),
+		PickupLatency:     latencyStats(g.pickupLatency),
+		Errors:            g.errors,
+	}
+	for _, n := range g.errors {
+		report.EnqueueErrors += n
+	}
+	if spec.Process {
+		report.ProcessThroughput = float64(g.processed+g.failed) / total.Seconds()
+	}
+	return report, nil
+}
+
+// generate enqueues jobs at the spec's rate until Duration elapses.
+func (g *Generator) generate(ctx context.Context, start time.Time) {
+	spec := g.spec
+	ctx, cancel := context.WithTimeout(ctx, spec.Duration)
+	defer cancel()
+
+	sem := make(chan struct{}, spec.MaxInFlight)
+	var wg sync.WaitGroup
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	filler := strings.Repeat("x", max(spec.PayloadSize-64, 0))
+
+	sent := 0
+	bursts := 0
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+
+		elapsed := time.Since(start)
+		due := int(expectedJobs(spec, elapsed)) - sent
+		if spec.BurstEvery > 0 && spec.BurstSize > 0 {
+			if n := int(elapsed / spec.BurstEvery); n > bursts {
+				due += (n - bursts) * spec.BurstSize
+				sent -= (n - bursts) * spec.BurstSize
+				bursts = n
+			}
+		}
+		for i := 0; i < due; i++ {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+			queue := spec.Queues[(sent+i)%len(spec.Queues)]
+			fail := rng.Float64() < spec.FailureRate
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				g.enqueue(ctx, queue, filler, fail)
+			}()
+		}
+		sent += due
+	}
+}
+
+// expectedJobs is how many steady-rate jobs should have been sent after
+// elapsed, following the ramp-up.
+func expectedJobs(spec LoadSpec, elapsed time.Duration) float64 {
+	t := elapsed.Seconds()
+	ramp := spec.RampUp.Seconds()
+	if ramp <= 0 {
+		return spec.RatePerSec * t
+	}
+	if t < ramp {
+		return spec.RatePerSec * t * t / (2 * ramp)
+	}
+	return spec.RatePerSec * (ramp/2 + t - ramp)
+}
+
+func (g *Generator) enqueue(ctx context.Context, queue, filler string, fail bool) {
+	marker := map[string]any{"enqueued_at": time.Now().UnixNano()}
+	if fail {
+		marker["fail"] = true
+	}
+	if g.spec.ProcessingTime > 0 {
+		marker["sleep_ms"] = g.spec.ProcessingTime.Milliseconds()
+	}
+	payload := map[string]any{MarkerKey: marker}
+	if filler != "" {
+		payload["data"] = filler
+	}
+
+	sent := time.Now()
+	_, err := g.client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: queue, Payload: payload})
+	latency := time.Since(sent)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err != nil {
+		if ctx.Err() == nil {
+			g.errors[err.Error()]++
+		}
+		return
+	}
+	g.enqueued++
+	g.enqueueLatency = append(g.enqueueLatency, latency)
+}
+
+// handle is the generator's worker handler: LoadHandler plus bookkeeping.
+func (g *Generator) handle(ctx context.Context, job *resources.Job) (any, error) {
+	if enqueuedAt, ok := markerInt(job.Payload, "enqueued_at"); ok {
+		pickup := time.Since(time.Unix(0, enqueuedAt))
+		g.mu.Lock()
+		g.pickupLatency = append(g.pickupLatency, pickup)
+		g.mu.Unlock()
+	}
+	result, err := LoadHandler(ctx, job)
+	g.mu.Lock()
+	if err != nil {
+		g.failed++
+	} else {
+		g.processed++
+	}
+	g.mu.Unlock()
+	return result, err
+}
+
+// LoadHandler handles jobs created by a Generator, honoring their markers:
+// it sleeps for the job's processing time and fails jobs marked to fail. Use
+// it to process generated load with workers run outside the generator.
+func LoadHandler(ctx context.Context, job *resources.Job) (any, error) {
+	if ms, ok := markerInt(job.Payload, "sleep_ms"); ok && ms > 0 {
+		select {
+		case <-time.After(time.Duration(ms) * time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	marker, _ := job.Payload[MarkerKey].(map[string]any)
+	if fail, _ := marker["fail"].(bool); fail {
+		return nil, fmt.Errorf("injected load test failure")
+	}
+	return map[string]any{"ok": true}, nil
+}
+
+// markerInt reads an integer marker, whether it was decoded as a float64 or
+// a json.Number.
+func markerInt(payload map[string]any, key string) (int64, bool) {
+	marker, _ := payload[MarkerKey].(map[string]any)
+	switch v := marker[key].(type) {
+	case float64:
+		return int64(v), true
+	case interface{ Int64() (int64, error) }:
+		n, err := v.Int64()
+		return n, err == nil
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// latencyStats computes percentiles of d.
+func latencyStats(d []time.Duration) LatencyStats {
+	if len(d) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]time.Duration(nil), d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	pct := func(p float64) time.Duration {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+	return LatencyStats{
+		Count: len(sorted),
+		P50:   pct(0.50),
+		P95:   pct(0.95),
+		P99:   pct(0.99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
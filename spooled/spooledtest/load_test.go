@@ -0,0 +1,67 @@
+package spooledtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestLoadGenerator(t *testing.T) {
+	var mu sync.Mutex
+	queues := map[string]int{}
+	failMarked := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req resources.CreateJobRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		queues[req.QueueName]++
+		if marker, _ := req.Payload[MarkerKey].(map[string]any); marker["fail"] == true {
+			failMarked++
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job","created":true}`))
+	}))
+	defer server.Close()
+
+	client, err := spooled.NewClient(spooled.WithAPIKey("sp_test_123456789012345678901234567890"), spooled.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	report, err := LoadGenerator(client, LoadSpec{
+		Queues:      []string{"load-a", "load-b"},
+		RatePerSec:  200,
+		PayloadSize: 256,
+		Duration:    500 * time.Millisecond,
+		FailureRate: 1,
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if report.Enqueued < 60 || report.Enqueued > 110 || report.EnqueueErrors != 0 {
+		t.Errorf("report = %+v, want about 100 enqueues without errors", report)
+	}
+	if queues["load-a"] == 0 || queues["load-b"] == 0 || failMarked != queues["load-a"]+queues["load-b"] {
+		t.Errorf("queues = %v, fail markers = %d", queues, failMarked)
+	}
+	if report.EnqueueLatency.Count != report.Enqueued || report.EnqueueLatency.Max == 0 {
+		t.Errorf("EnqueueLatency = %+v", report.EnqueueLatency)
+	}
+
+	job := &resources.Job{Payload: map[string]any{MarkerKey: map[string]any{"fail": true}}}
+	if _, err := LoadHandler(context.Background(), job); err == nil {
+		t.Error("LoadHandler did not inject the marked failure")
+	}
+}
@@ -0,0 +1,239 @@
+package spooledtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/cron"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// serveSchedules handles the schedule endpoints; parts is the path after
+// "schedules".
+func (s *Server) serveSchedules(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 0:
+		s.createSchedule(w, r)
+		return
+	case r.Method == http.MethodGet && len(parts) == 0:
+		s.listSchedules(w, r)
+		return
+	case len(parts) == 0 || len(parts) > 2:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+		return
+	}
+
+	schedule, ok := s.schedules[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("schedule %s not found", parts[0]))
+		return
+	}
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		writeJSON(w, http.StatusOK, schedule)
+	case r.Method == http.MethodPut && action == "":
+		s.updateSchedule(w, r, schedule)
+	case r.Method == http.MethodDelete && action == "":
+		s.deleteSchedule(schedule.ID)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && action == "pause":
+		schedule.IsActive = false
+		schedule.UpdatedAt = s.now
+		writeJSON(w, http.StatusOK, schedule)
+	case r.Method == http.MethodPost && action == "resume":
+		if !schedule.IsActive {
+			schedule.IsActive = true
+			schedule.UpdatedAt = s.now
+			// Runs missed while paused are skipped
+			schedule.NextRunAt = nextRun(schedule, s.now)
+		}
+		writeJSON(w, http.StatusOK, schedule)
+	case r.Method == http.MethodPost && action == "trigger":
+		id := s.runSchedule(schedule, s.now)
+		writeJSON(w, http.StatusOK, resources.TriggerScheduleResponse{JobID: id, TriggeredAt: s.now})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	}
+}
+
+func (s *Server) createSchedule(w http.ResponseWriter, r *http.Request) {
+	var req resources.CreateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" || req.QueueName == "" {
+		writeError(w, http.StatusBadRequest, "validation_error", "name, queue_name, and a JSON body are required")
+		return
+	}
+	if _, err := cron.Parse(req.CronExpression); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	timezone := "UTC"
+	if req.Timezone != nil {
+		timezone = *req.Timezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", fmt.Sprintf("unknown timezone %q", timezone))
+		return
+	}
+
+	s.seq++
+	schedule := &resources.Schedule{
+		ID:              "schedule-" + strconv.Itoa(s.seq),
+		Name:            req.Name,
+		Description:     req.Description,
+		CronExpression:  req.CronExpression,
+		Timezone:        timezone,
+		QueueName:       req.QueueName,
+		PayloadTemplate: req.PayloadTemplate,
+		MaxRetries:      DefaultMaxRetries,
+		IsActive:        true,
+		Tags:            req.Tags,
+		Metadata:        req.Metadata,
+		CreatedAt:       s.now,
+		UpdatedAt:       s.now,
+	}
+	if req.Priority != nil {
+		schedule.Priority = *req.Priority
+	}
+	if req.MaxRetries != nil {
+		schedule.MaxRetries = *req.MaxRetries
+	}
+	if req.TimeoutSeconds != nil {
+		schedule.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	schedule.NextRunAt = nextRun(schedule, s.now)
+	s.schedules[schedule.ID] = schedule
+	s.scheduleOrder = append(s.scheduleOrder, schedule.ID)
+	s.queue(schedule.QueueName)
+	writeJSON(w, http.StatusCreated, schedule)
+}
+
+// updateSchedule applies the fields set in the request body.
+func (s *Server) updateSchedule(w http.ResponseWriter, r *http.Request, schedule *resources.Schedule) {
+	var req resources.UpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	updated := *schedule
+	if req.CronExpression != nil {
+		if _, err := cron.Parse(*req.CronExpression); err != nil {
+			writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+			return
+		}
+		updated.CronExpression = *req.CronExpression
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			writeError(w, http.StatusBadRequest, "validation_error", fmt.Sprintf("unknown timezone %q", *req.Timezone))
+			return
+		}
+		updated.Timezone = *req.Timezone
+	}
+	setIf(&updated.Name, req.Name)
+	setIf(&updated.QueueName, req.QueueName)
+	setIf(&updated.Priority, req.Priority)
+	setIf(&updated.MaxRetries, req.MaxRetries)
+	setIf(&updated.TimeoutSeconds, req.TimeoutSeconds)
+	setIf(&updated.IsActive, req.IsActive)
+	if req.Description != nil {
+		updated.Description = req.Description
+	}
+	if req.PayloadTemplate != nil {
+		updated.PayloadTemplate = req.PayloadTemplate
+	}
+	if req.Tags != nil {
+		updated.Tags = req.Tags
+	}
+	if req.Metadata != nil {
+		updated.Metadata = req.Metadata
+	}
+	updated.UpdatedAt = s.now
+	if updated.IsActive {
+		updated.NextRunAt = nextRun(&updated, s.now)
+	}
+	*schedule = updated
+	s.queue(schedule.QueueName)
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// setIf sets *dst to *src if src is not nil.
+func setIf[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// listSchedules lists schedules in creation order, filtered by the
+// queue_name and is_active query parameters.
+func (s *Server) listSchedules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	schedules := []resources.Schedule{}
+	for _, id := range s.scheduleOrder {
+		schedule := s.schedules[id]
+		if matches(query, "queue_name", schedule.QueueName) && matches(query, "is_active", strconv.FormatBool(schedule.IsActive)) {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(schedules, query))
+}
+
+func (s *Server) deleteSchedule(id string) {
+	delete(s.schedules, id)
+	for i, scheduled := range s.scheduleOrder {
+		if scheduled == id {
+			s.scheduleOrder = append(s.scheduleOrder[:i], s.scheduleOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// fireSchedules enqueues a job for every run of an active schedule that is
+// due at s.now.
+func (s *Server) fireSchedules() {
+	for _, id := range s.scheduleOrder {
+		schedule := s.schedules[id]
+		for schedule.IsActive && schedule.NextRunAt != nil && !schedule.NextRunAt.After(s.now) {
+			runAt := *schedule.NextRunAt
+			s.runSchedule(schedule, runAt)
+			schedule.NextRunAt = nextRun(schedule, runAt)
+		}
+	}
+}
+
+// runSchedule enqueues the schedule's job for the run at runAt and returns
+// its ID.
+func (s *Server) runSchedule(schedule *resources.Schedule, runAt time.Time) string {
+	priority, maxRetries, timeout := schedule.Priority, schedule.MaxRetries, schedule.TimeoutSeconds
+	id, _ := s.addJob(&resources.CreateJobRequest{
+		QueueName:      schedule.QueueName,
+		Payload:        maps.Clone(schedule.PayloadTemplate),
+		Priority:       &priority,
+		MaxRetries:     &maxRetries,
+		TimeoutSeconds: &timeout,
+	})
+	schedule.RunCount++
+	schedule.LastRunAt = &runAt
+	return id
+}
+
+// nextRun returns the schedule's first run after t, or nil if it never runs
+// again. The expression and timezone were validated when the schedule was
+// created.
+func nextRun(schedule *resources.Schedule, t time.Time) *time.Time {
+	expr, _ := cron.Parse(schedule.CronExpression)
+	loc, _ := time.LoadLocation(schedule.Timezone)
+	next := expr.Next(t.In(loc))
+	if next.IsZero() {
+		return nil
+	}
+	next = next.UTC()
+	return &next
+}
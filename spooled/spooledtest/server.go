@@ -0,0 +1,494 @@
+package spooledtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
+)
+
+// Mock server defaults.
+const (
+	DefaultMaxRetries    = 3
+	DefaultLeaseDuration = 30 * time.Second
+)
+
+// ServerOptions configures a mock Server.
+type ServerOptions struct {
+	// Start is the virtual clock's initial time (default: the current time,
+	// truncated to the second).
+	Start time.Time
+	// RetryBackoff is the delay before a failed job's retryCount'th retry
+	// (default: DefaultRetryBackoff). A job's own CreateJobRequest.RetryBackoff
+	// takes precedence.
+	RetryBackoff func(retryCount int) time.Duration
+}
+
+// DefaultRetryBackoff doubles from one second: 1s, 2s, 4s, and so on, up to
+// an hour.
+func DefaultRetryBackoff(retryCount int) time.Duration {
+	if retryCount < 1 {
+		retryCount = 1
+	}
+	if retryCount > 12 {
+		return time.Hour
+	}
+	return min(time.Second<<(retryCount-1), time.Hour)
+}
+
+// Server is an in-memory Spooled API for tests of code built on the SDK. It
+// implements the core REST endpoints:
+//
+//   - jobs: create, bulk enqueue, get, list, cancel, and retry
+//   - the worker lifecycle: registering workers, and claiming (including
+//     canary claims), completing, failing, and heartbeating jobs
+//   - queues: list, get, pause, and resume
+//   - the dead letter queue: list, retry, and purge
+//   - schedules: create, list, get, update, delete, pause, resume, and
+//     trigger
+//
+// Other endpoints respond 404 Not Found. Point a client at the server with
+// spooled.WithBaseURL(srv.URL), spooled.WithHTTPClient(srv.HTTPClient()), or
+// Client.
+//
+// Time on the server is virtual and only moves with AdvanceTime, which fires
+// scheduled jobs and due schedules, expires leases, and releases retries
+// whose backoff has elapsed, so tests of delayed jobs and lease-expiry
+// recovery run in milliseconds:
+//
+//	srv := spooledtest.NewServer(spooledtest.ServerOptions{})
+//	defer srv.Close()
+//	client, _ := srv.Client()
+//	client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", ScheduledAt: ptr(srv.Now().Add(time.Hour))})
+//	srv.AdvanceTime(time.Hour) // the job is now pending
+type Server struct {
+	// URL is the server's base URL, for spooled.WithBaseURL.
+	URL string
+
+	http    *httptest.Server
+	backoff func(int) time.Duration
+
+	mu   sync.Mutex
+	now  time.Time
+	seq  int
+	jobs map[string]*resources.Job
+	// jobBackoffs holds the RetryBackoff of jobs created with one.
+	jobBackoffs map[string]resources.Backoff
+	order       []string
+	idempotency map[string]string
+	workers     map[string]string
+	queues      map[string]*queueState
+	schedules   map[string]*resources.Schedule
+	// scheduleOrder lists schedule IDs in creation order.
+	scheduleOrder []string
+}
+
+// NewServer starts a mock Server. Call Close when done.
+func NewServer(opts ServerOptions) *Server {
+	s := &Server{
+		backoff:     opts.RetryBackoff,
+		now:         opts.Start,
+		jobs:        make(map[string]*resources.Job),
+		jobBackoffs: make(map[string]resources.Backoff),
+		idempotency: make(map[string]string),
+		workers:     make(map[string]string),
+		queues:      make(map[string]*queueState),
+		schedules:   make(map[string]*resources.Schedule),
+	}
+	if s.backoff == nil {
+		s.backoff = DefaultRetryBackoff
+	}
+	if s.now.IsZero() {
+		s.now = time.Now().UTC().Truncate(time.Second)
+	}
+	s.http = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.http.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.http.Close()
+}
+
+// Client returns a client for the server. opts are applied after the base URL
+// and a test API key.
+func (s *Server) Client(opts ...spooled.Option) (*spooled.Client, error) {
+	return spooled.NewClient(append([]spooled.Option{
+		spooled.WithAPIKey("sp_test_spooledtest0000000000000000000"),
+		spooled.WithBaseURL(s.URL),
+	}, opts...)...)
+}
+
+// HTTPClient returns an HTTP client that serves every request in-process
+// from the server, whatever its host, for spooled.WithHTTPClient. Requests
+// never touch the network, so the client's base URL can be left as is.
+func (s *Server) HTTPClient() *http.Client {
+	return &http.Client{Transport: handlerTransport{http.HandlerFunc(s.serveHTTP)}}
+}
+
+// handlerTransport is an http.RoundTripper that serves requests with a
+// handler.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// Now returns the server's virtual time.
+func (s *Server) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// AdvanceTime moves the virtual clock forward by d. Scheduled jobs and
+// retries that come due become pending, active schedules enqueue a job for
+// every run that comes due, and jobs whose lease expires are retried, or
+// dead-lettered once out of retries.
+func (s *Server) AdvanceTime(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+	s.tick()
+}
+
+// Job returns a copy of a job's current state.
+func (s *Server) Job(id string) (resources.Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return resources.Job{}, false
+	}
+	return *job, true
+}
+
+// tick applies the state changes that are due at s.now.
+func (s *Server) tick() {
+	s.fireSchedules()
+	for _, id := range s.order {
+		job := s.jobs[id]
+		switch job.Status {
+		case resources.JobStatusScheduled:
+			if job.ScheduledAt == nil || !job.ScheduledAt.After(s.now) {
+				job.Status = resources.JobStatusPending
+			}
+		case resources.JobStatusProcessing:
+			if job.LeaseExpiresAt != nil && !job.LeaseExpiresAt.After(s.now) {
+				s.retry(job, "lease expired")
+			}
+		}
+	}
+}
+
+// retry records a failed attempt and schedules the next one, or dead-letters
+// the job once it is out of retries.
+func (s *Server) retry(job *resources.Job, reason string) {
+	job.LastError = &reason
+	job.AssignedWorkerID = nil
+	job.LeaseExpiresAt = nil
+	job.RetryCount++
+	if job.RetryCount > job.MaxRetries {
+		job.Status = resources.JobStatusDeadletter
+		return
+	}
+	delay := s.backoff(job.RetryCount)
+	if b, ok := s.jobBackoffs[job.ID]; ok {
+		delay = jobBackoff(b, job.RetryCount)
+	}
+	next := s.now.Add(delay)
+	job.ScheduledAt = &next
+	job.Status = resources.JobStatusScheduled
+	if !next.After(s.now) {
+		job.Status = resources.JobStatusPending
+	}
+}
+
+// jobBackoff is the delay before the retryCount'th retry of a job with its
+// own backoff: Initial * Multiplier^(retryCount-1), capped at Max.
+func jobBackoff(b resources.Backoff, retryCount int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(max(retryCount, 1)-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		return b.Max
+	}
+	return time.Duration(min(delay, float64(time.Hour)))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/api/v1/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+		return
+	}
+	parts := strings.Split(path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && path == "jobs":
+		s.createJob(w, r)
+	case r.Method == http.MethodGet && path == "jobs":
+		s.listJobs(w, r)
+	case r.Method == http.MethodPost && path == "jobs/bulk":
+		s.bulkEnqueue(w, r)
+	case r.Method == http.MethodPost && path == "jobs/claim":
+		s.claimJobs(w, r)
+	case r.Method == http.MethodGet && path == "jobs/dlq":
+		s.listDLQ(w, r)
+	case r.Method == http.MethodPost && path == "jobs/dlq/retry":
+		s.retryDLQ(w, r)
+	case r.Method == http.MethodPost && path == "jobs/dlq/purge":
+		s.purgeDLQ(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "jobs":
+		s.getJob(w, parts[1])
+	case r.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "jobs":
+		s.cancelJob(w, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "jobs" && parts[2] == "retry":
+		s.retryJob(w, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "jobs":
+		s.updateJob(w, r, parts[1], parts[2])
+	case parts[0] == "queues":
+		s.serveQueues(w, r, parts[1:])
+	case parts[0] == "schedules":
+		s.serveSchedules(w, r, parts[1:])
+	case r.Method == http.MethodPost && path == "workers/register":
+		s.registerWorker(w, r)
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "workers" && parts[2] == "heartbeat":
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case r.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "workers":
+		delete(s.workers, parts[1])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	}
+}
+
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) {
+	var req resources.CreateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.QueueName == "" {
+		writeError(w, http.StatusBadRequest, "validation_error", "queue_name and a JSON body are required")
+		return
+	}
+	id, created := s.addJob(&req)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, resources.CreateJobResponse{ID: id, Created: created})
+}
+
+// addJob stores a new job, or returns the ID of the job req duplicates by ID
+// or idempotency key.
+func (s *Server) addJob(req *resources.CreateJobRequest) (id string, created bool) {
+	if req.IdempotencyKey != nil {
+		if id, ok := s.idempotency[*req.IdempotencyKey]; ok {
+			return id, false
+		}
+	}
+
+	s.seq++
+	id = "job-" + strconv.Itoa(s.seq)
+	if req.ID != nil {
+		id = *req.ID
+		if _, ok := s.jobs[id]; ok {
+			return id, false
+		}
+	}
+	job := &resources.Job{
+		ID:          id,
+		QueueName:   req.QueueName,
+		Status:      resources.JobStatusPending,
+		Payload:     req.Payload,
+		MaxRetries:  DefaultMaxRetries,
+		CreatedAt:   s.now,
+		UpdatedAt:   s.now,
+		ScheduledAt: req.ScheduledAt,
+		ExpiresAt:   req.ExpiresAt,
+		Tags:        req.Tags,
+	}
+	if req.MaxRetries != nil {
+		job.MaxRetries = *req.MaxRetries
+	}
+	if req.Priority != nil {
+		job.Priority = *req.Priority
+	}
+	if req.TimeoutSeconds != nil {
+		job.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.RetryBackoff != nil {
+		s.jobBackoffs[id] = *req.RetryBackoff
+	}
+	if job.ScheduledAt != nil && job.ScheduledAt.After(s.now) {
+		job.Status = resources.JobStatusScheduled
+	}
+	s.jobs[id] = job
+	s.order = append(s.order, id)
+	if req.IdempotencyKey != nil {
+		s.idempotency[*req.IdempotencyKey] = id
+	}
+	s.queue(req.QueueName)
+	return id, true
+}
+
+func (s *Server) getJob(w http.ResponseWriter, id string) {
+	job, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("job %s not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) claimJobs(w http.ResponseWriter, r *http.Request) {
+	var req resources.ClaimJobsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	limit := 1
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	lease := DefaultLeaseDuration
+	if req.LeaseDurationSec != nil {
+		lease = time.Duration(*req.LeaseDurationSec) * time.Second
+	}
+
+	var ready []*resources.Job
+	for _, id := range s.order {
+		job := s.jobs[id]
+		if req.CanaryFraction != nil && !worker.InCanary(id, *req.CanaryFraction) {
+			continue
+		}
+		if job.QueueName == req.QueueName && job.Status == resources.JobStatusPending && !s.queue(job.QueueName).paused {
+			ready = append(ready, job)
+		}
+	}
+	// Highest priority first, then oldest
+	sort.SliceStable(ready, func(i, j int) bool { return ready[i].Priority > ready[j].Priority })
+
+	claimed := []resources.ClaimedJob{}
+	for _, job := range ready[:min(limit, len(ready))] {
+		startedAt, expiresAt := s.now, s.now.Add(lease)
+		workerID := req.WorkerID
+		job.Status = resources.JobStatusProcessing
+		job.StartedAt = &startedAt
+		job.LeaseExpiresAt = &expiresAt
+		job.AssignedWorkerID = &workerID
+		claimed = append(claimed, resources.ClaimedJob{
+			ID:             job.ID,
+			QueueName:      job.QueueName,
+			Payload:        job.Payload,
+			RetryCount:     job.RetryCount,
+			MaxRetries:     job.MaxRetries,
+			TimeoutSeconds: job.TimeoutSeconds,
+			LeaseExpiresAt: &expiresAt,
+			Tags:           job.Tags,
+		})
+	}
+	writeJSON(w, http.StatusOK, resources.ClaimJobsResponse{Jobs: claimed})
+}
+
+// updateJob handles complete, fail, heartbeat, and progress for a job held by
+// a worker. Requests for jobs the worker no longer holds, such as after its
+// lease expired, fail with 409 Conflict.
+func (s *Server) updateJob(w http.ResponseWriter, r *http.Request, id, action string) {
+	job, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("job %s not found", id))
+		return
+	}
+	var req struct {
+		WorkerID         string         `json:"worker_id"`
+		Result           map[string]any `json:"result"`
+		Error            string         `json:"error"`
+		LeaseDurationSec *int           `json:"lease_duration_secs"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	if job.Status != resources.JobStatusProcessing ||
+		(req.WorkerID != "" && job.AssignedWorkerID != nil && *job.AssignedWorkerID != req.WorkerID) {
+		writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("job %s is not held by this worker", id))
+		return
+	}
+
+	switch action {
+	case "complete":
+		completedAt := s.now
+		job.Status = resources.JobStatusCompleted
+		job.Result = req.Result
+		job.CompletedAt = &completedAt
+		job.LeaseExpiresAt = nil
+		writeJSON(w, http.StatusOK, map[string]any{"success": true})
+	case "fail":
+		s.retry(job, req.Error)
+		writeJSON(w, http.StatusOK, map[string]any{"success": true})
+	case "heartbeat":
+		lease := DefaultLeaseDuration
+		if req.LeaseDurationSec != nil {
+			lease = time.Duration(*req.LeaseDurationSec) * time.Second
+		}
+		expiresAt := s.now.Add(lease)
+		job.LeaseExpiresAt = &expiresAt
+		writeJSON(w, http.StatusOK, resources.RenewLeaseResponse{Success: true, LeaseExpiresAt: &expiresAt})
+	case "progress":
+		writeJSON(w, http.StatusOK, map[string]any{"success": true})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	}
+}
+
+func (s *Server) registerWorker(w http.ResponseWriter, r *http.Request) {
+	var req resources.RegisterWorkerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	s.seq++
+	id := "worker-" + strconv.Itoa(s.seq)
+	if req.WorkerID != nil {
+		id = *req.WorkerID
+	}
+	s.workers[id] = req.QueueName
+	writeJSON(w, http.StatusOK, resources.RegisterWorkerResponse{
+		ID:                   id,
+		QueueName:            req.QueueName,
+		LeaseDurationSecs:    int(DefaultLeaseDuration / time.Second),
+		HeartbeatIntervalSec: int(DefaultLeaseDuration / time.Second / 2),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"code": code, "message": message})
+}
@@ -0,0 +1,334 @@
+package spooledtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
+)
+
+func waitForStatus(t *testing.T, srv *Server, id string, status resources.JobStatus) resources.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, _ := srv.Job(id)
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServer_ScheduledJobsAndRetryBackoff(t *testing.T) {
+	srv := NewServer(ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	runAt := srv.Now().Add(time.Hour)
+	resp, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{"n": 1}, ScheduledAt: &runAt})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var attempts atomic.Int32
+	w := spooled.NewSpooledWorker(client, spooled.SpooledWorkerOptions{QueueName: "emails", PollInterval: 5 * time.Millisecond})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) {
+		if attempts.Add(1) == 1 {
+			return nil, errors.New("smtp timeout")
+		}
+		return map[string]any{"sent": true}, nil
+	})
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer w.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	if attempts.Load() != 0 {
+		t.Fatal("scheduled job ran before its time")
+	}
+
+	srv.AdvanceTime(time.Hour)
+	job := waitForStatus(t, srv, resp.ID, resources.JobStatusScheduled)
+	if job.RetryCount != 1 || job.LastError == nil || *job.LastError != "smtp timeout" {
+		t.Errorf("after failure: %+v, want retry 1 with the error", job)
+	}
+	if want := srv.Now().Add(time.Second); !job.ScheduledAt.Equal(want) {
+		t.Errorf("retry scheduled at %v, want %v", job.ScheduledAt, want)
+	}
+
+	srv.AdvanceTime(time.Second)
+	job = waitForStatus(t, srv, resp.ID, resources.JobStatusCompleted)
+	if job.Result["sent"] != true || attempts.Load() != 2 {
+		t.Errorf("completed job = %+v after %d attempts", job, attempts.Load())
+	}
+}
+
+func TestServer_LeaseExpiry(t *testing.T) {
+	srv := NewServer(ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	resp, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "reports", Payload: map[string]any{}, MaxRetries: ptr(1)})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	claim := func(worker string) []resources.ClaimedJob {
+		t.Helper()
+		claimed, err := client.Jobs().Claim(ctx, &resources.ClaimJobsRequest{QueueName: "reports", WorkerID: worker, LeaseDurationSec: ptr(30)})
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		return claimed.Jobs
+	}
+
+	// A crashed worker's job is released when its lease expires.
+	if jobs := claim("crashed"); len(jobs) != 1 {
+		t.Fatalf("claimed %d jobs, want 1", len(jobs))
+	}
+	srv.AdvanceTime(29 * time.Second)
+	if job, _ := srv.Job(resp.ID); job.Status != resources.JobStatusProcessing {
+		t.Fatalf("status before lease expiry = %s", job.Status)
+	}
+	srv.AdvanceTime(time.Second)
+	if job, _ := srv.Job(resp.ID); job.Status != resources.JobStatusScheduled || job.RetryCount != 1 {
+		t.Fatalf("after lease expiry: %+v, want a scheduled retry", job)
+	}
+	err = client.Jobs().Complete(ctx, resp.ID, &resources.CompleteJobRequest{WorkerID: "crashed"})
+	var conflict *httpx.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Errorf("late Complete error = %v, want conflict", err)
+	}
+
+	// Out of retries, the next expiry dead-letters it.
+	srv.AdvanceTime(time.Second)
+	if jobs := claim("crashed-again"); len(jobs) != 1 || jobs[0].RetryCount != 1 {
+		t.Fatalf("reclaimed %+v, want the retried job", jobs)
+	}
+	srv.AdvanceTime(30 * time.Second)
+	if job, _ := srv.Job(resp.ID); job.Status != resources.JobStatusDeadletter || *job.LastError != "lease expired" {
+		t.Errorf("after second expiry: %+v, want deadletter", job)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestServer_CanaryClaims(t *testing.T) {
+	srv := NewServer(ServerOptions{})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 100; i++ {
+		resp, err := client.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: "resize", Payload: map[string]any{"i": i}})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, resp.ID)
+	}
+
+	var processed sync.Map
+	w := spooled.NewSpooledWorker(client, spooled.SpooledWorkerOptions{
+		QueueName:      "resize",
+		PollInterval:   5 * time.Millisecond,
+		ExitWhenIdle:   30 * time.Millisecond,
+		CanaryFraction: 0.25,
+	})
+	w.Process(func(ctx context.Context, job *resources.Job) (any, error) {
+		processed.Store(job.ID, true)
+		return nil, nil
+	})
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	summary, err := w.RunUntilDrained(runCtx)
+	if err != nil {
+		t.Fatalf("RunUntilDrained: %v", err)
+	}
+	if !summary.Canary {
+		t.Error("summary should be marked Canary")
+	}
+
+	sampled := 0
+	for _, id := range ids {
+		_, done := processed.Load(id)
+		if in := worker.InCanary(id, 0.25); done != in {
+			t.Errorf("job %s processed = %v, in canary sample = %v", id, done, in)
+		} else if in {
+			sampled++
+		}
+	}
+	if sampled == 0 || sampled == len(ids) {
+		t.Errorf("canary processed %d of %d jobs, want a sample", sampled, len(ids))
+	}
+}
+
+func TestServer_QueuesDLQAndJobAdmin(t *testing.T) {
+	srv := NewServer(ServerOptions{RetryBackoff: func(int) time.Duration { return 0 }})
+	defer srv.Close()
+	// Served in-process: the default base URL is never dialed
+	client, err := spooled.NewClient(
+		spooled.WithAPIKey("sp_test_123456789012345678901234567890"),
+		spooled.WithHTTPClient(srv.HTTPClient()),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	bulk, err := client.Jobs().BulkEnqueue(ctx, &resources.BulkEnqueueRequest{
+		QueueName:         "emails",
+		Jobs:              []resources.BulkJobItem{{Payload: map[string]any{"n": 1}}, {Payload: map[string]any{"n": 2}}, {Payload: map[string]any{"n": 3}}},
+		DefaultMaxRetries: ptr(0),
+	})
+	if err != nil || bulk.SuccessCount != 3 {
+		t.Fatalf("BulkEnqueue = %+v, %v", bulk, err)
+	}
+	doomed, kept, cancelled := bulk.Succeeded[0].JobID, bulk.Succeeded[1].JobID, bulk.Succeeded[2].JobID
+
+	if err := client.Jobs().Cancel(ctx, cancelled); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	var conflict *httpx.ConflictError
+	if err := client.Jobs().Cancel(ctx, cancelled); !errors.As(err, &conflict) {
+		t.Errorf("cancelling twice: err = %v, want a conflict", err)
+	}
+
+	if _, err := client.Queues().Pause(ctx, "emails", nil); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	claim := &resources.ClaimJobsRequest{QueueName: "emails", WorkerID: "w1", Limit: ptr(1)}
+	if resp, err := client.Jobs().Claim(ctx, claim); err != nil || len(resp.Jobs) != 0 {
+		t.Fatalf("Claim on a paused queue = %+v, %v; want no jobs", resp, err)
+	}
+	if _, err := client.Queues().Resume(ctx, "emails"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	resp, err := client.Jobs().Claim(ctx, claim)
+	if err != nil || len(resp.Jobs) != 1 || resp.Jobs[0].ID != doomed {
+		t.Fatalf("Claim = %+v, %v; want %s", resp, err, doomed)
+	}
+	if err := client.Jobs().Fail(ctx, doomed, &resources.FailJobRequest{WorkerID: "w1", Error: "bounced"}); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+
+	dead, err := client.Jobs().DLQ().List(ctx, &resources.ListDLQParams{QueueName: ptr("emails")})
+	if err != nil || len(dead) != 1 || dead[0].ID != doomed {
+		t.Fatalf("DLQ().List = %+v, %v; want %s", dead, err, doomed)
+	}
+	pending := resources.JobStatusPending
+	jobs, err := client.Jobs().List(ctx, &resources.ListJobsParams{QueueName: ptr("emails"), Status: &pending})
+	if err != nil || len(jobs) != 1 || jobs[0].ID != kept {
+		t.Fatalf("List(pending) = %+v, %v; want %s", jobs, err, kept)
+	}
+	stats, err := client.Queues().GetStats(ctx, "emails")
+	if err != nil || stats.PendingJobs != 1 || stats.FailedJobs24h != 1 {
+		t.Fatalf("GetStats = %+v, %v", stats, err)
+	}
+
+	retried, err := client.Jobs().DLQ().Retry(ctx, &resources.RetryDLQRequest{QueueName: ptr("emails")})
+	if err != nil || retried.RetriedCount != 1 {
+		t.Fatalf("DLQ().Retry = %+v, %v", retried, err)
+	}
+	if job, _ := srv.Job(doomed); job.Status != resources.JobStatusPending {
+		t.Errorf("retried job is %s, want pending", job.Status)
+	}
+	if job, err := client.Jobs().Retry(ctx, cancelled); err != nil || job.Status != resources.JobStatusPending {
+		t.Errorf("Retry(cancelled) = %+v, %v", job, err)
+	}
+
+	queues, err := client.Queues().List(ctx)
+	if err != nil || len(queues) != 1 || queues[0].QueueName != "emails" || !queues[0].Enabled {
+		t.Errorf("Queues().List = %+v, %v", queues, err)
+	}
+	var notFound *httpx.NotFoundError
+	if _, err := client.Queues().Get(ctx, "missing"); !errors.As(err, &notFound) {
+		t.Errorf("Get(missing): err = %v, want a not found error", err)
+	}
+}
+
+func TestServer_Schedules(t *testing.T) {
+	start := time.Date(2026, 1, 5, 8, 30, 0, 0, time.UTC)
+	srv := NewServer(ServerOptions{Start: start})
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	schedule, err := client.Schedules().Create(ctx, &resources.CreateScheduleRequest{
+		Name:            "hourly-report",
+		CronExpression:  "0 0 * * * *",
+		QueueName:       "reports",
+		PayloadTemplate: map[string]any{"kind": "hourly"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if want := start.Add(30 * time.Minute); schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(want) {
+		t.Fatalf("NextRunAt = %v, want %v", schedule.NextRunAt, want)
+	}
+	var invalid *httpx.ValidationError
+	if _, err := client.Schedules().Create(ctx, &resources.CreateScheduleRequest{Name: "bad", CronExpression: "nope", QueueName: "reports"}); !errors.As(err, &invalid) {
+		t.Errorf("invalid cron: err = %v, want a validation error", err)
+	}
+
+	srv.AdvanceTime(3 * time.Hour)
+	jobs, err := client.Jobs().List(ctx, &resources.ListJobsParams{QueueName: ptr("reports")})
+	if err != nil || len(jobs) != 3 {
+		t.Fatalf("List = %d jobs, %v; want one per elapsed run", len(jobs), err)
+	}
+	if jobs[0].Payload["kind"] != "hourly" {
+		t.Errorf("payload = %v, want the template", jobs[0].Payload)
+	}
+
+	if _, err := client.Schedules().Pause(ctx, schedule.ID); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	srv.AdvanceTime(2 * time.Hour)
+	triggered, err := client.Schedules().Trigger(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	got, err := client.Schedules().Get(ctx, schedule.ID)
+	if err != nil || got.RunCount != 4 || got.IsActive {
+		t.Fatalf("Get = %+v, %v; want 4 runs while paused", got, err)
+	}
+	if job, ok := srv.Job(triggered.JobID); !ok || job.QueueName != "reports" {
+		t.Errorf("triggered job = %+v", job)
+	}
+
+	if err := client.Schedules().Delete(ctx, schedule.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if list, err := client.Schedules().List(ctx, nil); err != nil || len(list) != 0 {
+		t.Errorf("List after Delete = %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,378 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/worker"
+)
+
+// TransportKind selects the wire protocol used for core job and worker operations.
+type TransportKind string
+
+const (
+	// TransportREST uses the REST API (default).
+	TransportREST TransportKind = "rest"
+	// TransportGRPC uses the gRPC API.
+	TransportGRPC TransportKind = "grpc"
+	// TransportAuto uses gRPC when the server is reachable and falls back to REST.
+	TransportAuto TransportKind = "auto"
+)
+
+// transportProbeTimeout bounds how long TransportAuto waits for gRPC.
+const transportProbeTimeout = 3 * time.Second
+
+// Transport is the set of core job and worker operations shared by the REST
+// and gRPC backends. It satisfies worker.Backend, so the worker runtime can
+// run over either protocol.
+type Transport interface {
+	worker.Backend
+
+	// Kind reports the protocol in use (TransportREST or TransportGRPC).
+	Kind() TransportKind
+	// Enqueue creates a job.
+	Enqueue(ctx context.Context, req *resources.CreateJobRequest) (*resources.CreateJobResponse, error)
+}
+
+// Transport returns the transport selected by WithPreferredTransport.
+//
+// With TransportAuto the gRPC server is probed the first time this is called;
+// if it is not reachable the REST transport is used instead.
+func (c *Client) Transport() (Transport, error) {
+	rest := &restTransport{RESTBackend: worker.NewRESTBackend(c.jobs, c.workers)}
+
+	switch c.cfg.PreferredTransport {
+	case "", TransportREST:
+		return rest, nil
+	case TransportGRPC:
+		grpcClient, err := c.GRPC()
+		if err != nil {
+			return nil, err
+		}
+		return &grpcTransport{client: grpcClient, rest: rest, queueName: c.contextQueueName, guard: c.enqueueGuard, schemas: c.schemaRegistry, policies: c.policies, transforms: c.cfg.PayloadTransforms, propagator: c.tracePropagator(), now: c.ServerTime}, nil
+	case TransportAuto:
+		grpcClient, err := c.GRPC()
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), transportProbeTimeout)
+			err = grpcClient.WaitForReady(ctx)
+			cancel()
+		}
+		if err != nil {
+			c.debug("gRPC unavailable, using REST transport", "error", err)
+			return rest, nil
+		}
+		return &grpcTransport{client: grpcClient, rest: rest, queueName: c.contextQueueName, guard: c.enqueueGuard, schemas: c.schemaRegistry, policies: c.policies, transforms: c.cfg.PayloadTransforms, propagator: c.tracePropagator(), now: c.ServerTime}, nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q", c.cfg.PreferredTransport)
+	}
+}
+
+// restTransport implements Transport over the REST API.
+type restTransport struct {
+	*worker.RESTBackend
+}
+
+func (t *restTransport) Kind() TransportKind { return TransportREST }
+
+func (t *restTransport) Enqueue(ctx context.Context, req *resources.CreateJobRequest) (*resources.CreateJobResponse, error) {
+	return t.Jobs.Create(ctx, req)
+}
+
+// resolvedQueueBackend returns the backend for a SpooledWorker, whose
+// queue name already carries the client's prefix. Calls that name the queue
+// bypass the prefix, so it is not added a second time.
+func resolvedQueueBackend(t Transport) worker.Backend {
+	b := resolvedBackend{t}
+	if sb, ok := t.(worker.StreamingBackend); ok {
+		return resolvedStreamingBackend{b, sb}
+	}
+	return b
+}
+
+type resolvedBackend struct {
+	Transport
+}
+
+func (b resolvedBackend) RegisterWorker(ctx context.Context, req *resources.RegisterWorkerRequest) (*resources.RegisterWorkerResponse, error) {
+	return b.Transport.RegisterWorker(WithoutQueuePrefix(ctx), req)
+}
+
+func (b resolvedBackend) ClaimJobs(ctx context.Context, req *resources.ClaimJobsRequest) (*resources.ClaimJobsResponse, error) {
+	return b.Transport.ClaimJobs(WithoutQueuePrefix(ctx), req)
+}
+
+type resolvedStreamingBackend struct {
+	resolvedBackend
+	stream worker.StreamingBackend
+}
+
+func (b resolvedStreamingBackend) OpenJobStream(ctx context.Context, req *resources.ClaimJobsRequest) (worker.JobStream, error) {
+	return b.stream.OpenJobStream(WithoutQueuePrefix(ctx), req)
+}
+
+// grpcTransport implements Transport over gRPC.
+// Operations the gRPC API does not cover are sent over REST.
+type grpcTransport struct {
+	client     *grpc.Client
+	rest       *restTransport
+	queueName  func(context.Context, string) string
+	guard      *resources.EnqueueGuard
+	schemas    *resources.SchemaRegistry
+	policies   *resources.PolicyRegistry
+	transforms *resources.PayloadTransforms
+	propagator propagation.Propagator
+	now        func() time.Time
+}
+
+func (t *grpcTransport) Kind() TransportKind { return TransportGRPC }
+
+func (t *grpcTransport) Enqueue(ctx context.Context, req *resources.CreateJobRequest) (*resources.CreateJobResponse, error) {
+	// As over REST, policies are chosen by the transformed payload
+	transformed := *req
+	transformed.Payload = t.transforms.ApplyOutbound(req.Payload)
+	outbound := t.policies.Apply(&transformed)
+	tags := outbound.Tags
+	if t.propagator != nil {
+		tags = propagation.InjectTags(ctx, t.propagator, tags)
+	}
+	// Fields without a gRPC equivalent, including the trace context carried
+	// in tags, need the REST API, which transforms and applies policies itself
+	if outbound.ExpiresAt != nil || len(tags) > 0 || outbound.ParentJobID != nil || outbound.CompletionWebhook != nil ||
+		outbound.RunbookURL != nil || outbound.OwnerTeam != nil || outbound.ResultTTLSeconds != nil || outbound.RetryBackoff != nil ||
+		outbound.ID != nil || outbound.CompletionWebhookSigning != nil {
+		return t.rest.Enqueue(ctx, req)
+	}
+	req = outbound
+	payload := req.Payload
+	if t.schemas != nil {
+		if err := t.schemas.Validate(ctx, payload); err != nil {
+			return nil, err
+		}
+	}
+	if t.guard != nil {
+		if _, err := t.guard.Admit(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	grpcReq := &grpc.EnqueueRequest{
+		QueueName:   t.queueName(ctx, req.QueueName),
+		Payload:     payload,
+		ScheduledAt: req.ScheduledAt,
+	}
+	if grpcReq.ScheduledAt == nil && req.Delay > 0 {
+		scheduledAt := t.now().Add(req.Delay)
+		grpcReq.ScheduledAt = &scheduledAt
+	}
+	if req.Priority != nil {
+		grpcReq.Priority = int32(*req.Priority)
+	}
+	if req.MaxRetries != nil {
+		grpcReq.MaxRetries = int32(*req.MaxRetries)
+	}
+	if req.TimeoutSeconds != nil {
+		grpcReq.TimeoutSeconds = int32(*req.TimeoutSeconds)
+	}
+	if req.IdempotencyKey != nil {
+		grpcReq.IdempotencyKey = *req.IdempotencyKey
+	}
+
+	resp, err := t.client.Enqueue(ctx, grpcReq)
+	if err != nil {
+		return nil, err
+	}
+	return &resources.CreateJobResponse{ID: resp.JobID, Created: resp.Created}, nil
+}
+
+func (t *grpcTransport) RegisterWorker(ctx context.Context, req *resources.RegisterWorkerRequest) (*resources.RegisterWorkerResponse, error) {
+	grpcReq := &grpc.RegisterWorkerRequest{
+		QueueName: t.queueName(ctx, req.QueueName),
+		Hostname:  req.Hostname,
+		Metadata:  make(map[string]string, len(req.Metadata)+1),
+	}
+	if req.MaxConcurrency != nil {
+		grpcReq.MaxConcurrency = int32(*req.MaxConcurrency)
+	}
+	if req.Version != nil {
+		grpcReq.Version = *req.Version
+	}
+	for k, v := range req.Metadata {
+		grpcReq.Metadata[k] = fmt.Sprint(v)
+	}
+	if req.WorkerType != nil {
+		grpcReq.Metadata["worker_type"] = *req.WorkerType
+	}
+	if req.WorkerID != nil {
+		grpcReq.Metadata["worker_id"] = *req.WorkerID
+	}
+
+	resp, err := t.client.RegisterWorker(ctx, grpcReq)
+	if err != nil {
+		return nil, err
+	}
+	return &resources.RegisterWorkerResponse{
+		ID:                   resp.WorkerID,
+		QueueName:            grpcReq.QueueName,
+		LeaseDurationSecs:    int(resp.LeaseDurationSec),
+		HeartbeatIntervalSec: int(resp.HeartbeatIntervalSec),
+	}, nil
+}
+
+func (t *grpcTransport) WorkerHeartbeat(ctx context.Context, workerID string, req *resources.WorkerHeartbeatRequest) error {
+	grpcReq := &grpc.WorkerHeartbeatRequest{
+		WorkerID:    workerID,
+		CurrentJobs: int32(req.CurrentJobs),
+	}
+	if req.Status != nil {
+		grpcReq.Status = *req.Status
+	}
+	return t.client.WorkerHeartbeat(ctx, grpcReq)
+}
+
+func (t *grpcTransport) DeregisterWorker(ctx context.Context, workerID string) error {
+	return t.client.DeregisterWorker(ctx, workerID)
+}
+
+func (t *grpcTransport) ClaimJobs(ctx context.Context, req *resources.ClaimJobsRequest) (*resources.ClaimJobsResponse, error) {
+	// Canary sampling and job tags, which carry trace context, need the REST API
+	if req.CanaryFraction != nil || t.propagator != nil {
+		return t.rest.ClaimJobs(ctx, req)
+	}
+	grpcReq := &grpc.DequeueRequest{
+		QueueName: t.queueName(ctx, req.QueueName),
+		WorkerID:  req.WorkerID,
+		BatchSize: 1,
+	}
+	if req.Limit != nil {
+		grpcReq.BatchSize = int32(*req.Limit)
+	}
+	if req.LeaseDurationSec != nil {
+		grpcReq.LeaseDurationSec = int32(*req.LeaseDurationSec)
+	}
+
+	resp, err := t.client.Dequeue(ctx, grpcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &resources.ClaimJobsResponse{Jobs: make([]resources.ClaimedJob, 0, len(resp.Jobs))}
+	for _, job := range resp.Jobs {
+		result.Jobs = append(result.Jobs, claimedJobFromGRPC(job, t.transforms))
+	}
+	return result, nil
+}
+
+// claimedJobFromGRPC converts a gRPC job. gRPC jobs carry no tags, so
+// transports that propagate trace context claim over REST instead.
+func claimedJobFromGRPC(job *grpc.Job, transforms *resources.PayloadTransforms) resources.ClaimedJob {
+	return resources.ClaimedJob{
+		ID:             job.ID,
+		QueueName:      job.QueueName,
+		Payload:        transforms.ApplyInbound(job.Payload),
+		RetryCount:     int(job.RetryCount),
+		MaxRetries:     int(job.MaxRetries),
+		TimeoutSeconds: int(job.TimeoutSeconds),
+		LeaseExpiresAt: job.LeaseExpiresAt,
+	}
+}
+
+// OpenJobStream implements worker.StreamingBackend over the ProcessJobs stream.
+func (t *grpcTransport) OpenJobStream(ctx context.Context, req *resources.ClaimJobsRequest) (worker.JobStream, error) {
+	// Canary sampling and job tags, which carry trace context, need the REST API
+	if req.CanaryFraction != nil || t.propagator != nil {
+		return nil, worker.ErrStreamingUnsupported
+	}
+	var leaseDurationSec int32
+	if req.LeaseDurationSec != nil {
+		leaseDurationSec = int32(*req.LeaseDurationSec)
+	}
+	stream, err := t.client.OpenJobStream(ctx, t.queueName(ctx, req.QueueName), req.WorkerID, leaseDurationSec)
+	if err != nil {
+		return nil, err
+	}
+	return grpcJobStream{JobStream: stream, transforms: t.transforms}, nil
+}
+
+// grpcJobStream adapts a grpc.JobStream to worker.JobStream.
+type grpcJobStream struct {
+	*grpc.JobStream
+	transforms *resources.PayloadTransforms
+}
+
+func (s grpcJobStream) Recv() (resources.ClaimedJob, error) {
+	job, err := s.JobStream.Recv()
+	if err != nil {
+		return resources.ClaimedJob{}, err
+	}
+	return claimedJobFromGRPC(job, s.transforms), nil
+}
+
+func (t *grpcTransport) CompleteJob(ctx context.Context, jobID string, req *resources.CompleteJobRequest) error {
+	err := t.client.Complete(ctx, &grpc.CompleteRequest{
+		JobID:    jobID,
+		WorkerID: req.WorkerID,
+		Result:   req.Result,
+	})
+	var tooLarge *grpc.MessageTooLargeError
+	if req.Result == nil || !errors.As(err, &tooLarge) {
+		return err
+	}
+
+	// The result does not fit in a gRPC message: upload it in chunks and
+	// complete with a reference instead
+	data, err := json.Marshal(req.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	ref, err := t.rest.Jobs.UploadResultBlob(ctx, jobID, data)
+	if err != nil {
+		return err
+	}
+	return t.client.Complete(ctx, &grpc.CompleteRequest{
+		JobID:    jobID,
+		WorkerID: req.WorkerID,
+		Result: map[string]any{resources.ResultBlobKey: map[string]any{
+			"blob_id": ref.BlobID,
+			"size":    ref.Size,
+			"sha256":  ref.SHA256,
+		}},
+	})
+}
+
+func (t *grpcTransport) FailJob(ctx context.Context, jobID string, req *resources.FailJobRequest) error {
+	// Let the server apply the job's retry policy, as the REST endpoint does
+	return t.client.Fail(ctx, &grpc.FailRequest{
+		JobID:    jobID,
+		WorkerID: req.WorkerID,
+		Error:    req.Error,
+		Retry:    true,
+	})
+}
+
+func (t *grpcTransport) RenewLease(ctx context.Context, jobID string, req *resources.RenewLeaseRequest) (*resources.RenewLeaseResponse, error) {
+	resp, err := t.client.RenewLease(ctx, &grpc.RenewLeaseRequest{
+		JobID:         jobID,
+		WorkerID:      req.WorkerID,
+		ExtensionSecs: int32(req.LeaseDurationSec),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resources.RenewLeaseResponse{Success: resp.Success, LeaseExpiresAt: resp.NewExpiresAt}, nil
+}
+
+// UpdateProgress has no gRPC equivalent and is sent over REST.
+func (t *grpcTransport) UpdateProgress(ctx context.Context, jobID string, req *resources.UpdateProgressRequest) error {
+	return t.rest.UpdateProgress(ctx, jobID, req)
+}
+
+// ExtendTimeout has no gRPC equivalent and is sent over REST.
+func (t *grpcTransport) ExtendTimeout(ctx context.Context, jobID string, extra time.Duration) (*resources.ExtendTimeoutResponse, error) {
+	return t.rest.ExtendTimeout(ctx, jobID, extra)
+}
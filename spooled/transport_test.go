@@ -0,0 +1,247 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/grpc/pb"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// fakeQueueService is a gRPC QueueService that records enqueues and hands
+// out one job per Dequeue.
+type fakeQueueService struct {
+	pb.UnimplementedQueueServiceServer
+	mu       sync.Mutex
+	enqueued []*pb.EnqueueRequest
+	dequeues int
+}
+
+func (s *fakeQueueService) Enqueue(_ context.Context, req *pb.EnqueueRequest) (*pb.EnqueueResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enqueued = append(s.enqueued, req)
+	return &pb.EnqueueResponse{JobId: "grpc-job", Created: true}, nil
+}
+
+func (s *fakeQueueService) Dequeue(_ context.Context, req *pb.DequeueRequest) (*pb.DequeueResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dequeues++
+	return &pb.DequeueResponse{Jobs: []*pb.Job{{Id: "grpc-job", QueueName: req.QueueName}}}, nil
+}
+
+func (s *fakeQueueService) counts() (enqueues, dequeues int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.enqueued), s.dequeues
+}
+
+func (s *fakeQueueService) received() []*pb.EnqueueRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*pb.EnqueueRequest(nil), s.enqueued...)
+}
+
+// restRecorder is a REST API that records job creates and answers claims
+// with claimResponse.
+type restRecorder struct {
+	*httptest.Server
+	mu      sync.Mutex
+	creates []map[string]any
+	claims  int
+}
+
+func newRESTRecorder(t *testing.T, claimResponse string) *restRecorder {
+	t.Helper()
+	rec := &restRecorder{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/jobs":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			rec.creates = append(rec.creates, body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"rest-job","created":true}`))
+		case "/api/v1/jobs/claim":
+			rec.claims++
+			_, _ = w.Write([]byte(claimResponse))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (r *restRecorder) received() (creates []map[string]any, claims int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.creates...), r.claims
+}
+
+// newGRPCTransport starts a fake gRPC server and returns a TransportGRPC
+// transport that sends REST fallbacks to rest.
+func newGRPCTransport(t *testing.T, rest *restRecorder, opts ...Option) (Transport, *fakeQueueService) {
+	t.Helper()
+	client, service := newGRPCClient(t, rest, opts...)
+	transport, err := client.Transport()
+	if err != nil {
+		t.Fatalf("Transport: %v", err)
+	}
+	return transport, service
+}
+
+// newGRPCClient starts a fake gRPC server and returns a client that prefers
+// gRPC and sends REST calls to rest.
+func newGRPCClient(t *testing.T, rest *restRecorder, opts ...Option) (*Client, *fakeQueueService) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	service := &fakeQueueService{}
+	srv := grpclib.NewServer()
+	pb.RegisterQueueServiceServer(srv, service)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	client, err := NewClient(append([]Option{
+		WithAPIKey(testAPIKey),
+		WithBaseURL(rest.URL),
+		WithGRPCAddress(lis.Addr().String()),
+		WithPreferredTransport(TransportGRPC),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, service
+}
+
+func TestGRPCTransport_TracePropagation(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	rest := newRESTRecorder(t, `{"jobs":[{"id":"rest-job","queue_name":"emails","payload":{},`+
+		`"tags":{"_trace":{"traceparent":"`+traceparent+`"}}}]}`)
+	transport, service := newGRPCTransport(t, rest, WithTracePropagation(true))
+
+	// Without trace context there is nothing to carry, so gRPC is used
+	ctx := context.Background()
+	if _, err := transport.Enqueue(ctx, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if enqueues, _ := service.counts(); enqueues != 1 {
+		t.Fatalf("gRPC enqueues = %d, want 1", enqueues)
+	}
+
+	traced := propagation.ContextWithHeaders(ctx, map[string]string{"traceparent": traceparent})
+	resp, err := transport.Enqueue(traced, &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{}})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if resp.ID != "rest-job" {
+		t.Errorf("traced job ID = %s, want the REST job", resp.ID)
+	}
+	creates, _ := rest.received()
+	if len(creates) != 1 {
+		t.Fatalf("REST creates = %d, want 1", len(creates))
+	}
+	tags, _ := creates[0]["tags"].(map[string]any)
+	if trace, _ := tags[propagation.TagKey].(map[string]any); trace["traceparent"] != traceparent {
+		t.Errorf("tags = %v, want the caller's traceparent", tags)
+	}
+	if enqueues, _ := service.counts(); enqueues != 1 {
+		t.Errorf("gRPC enqueues = %d, want the traced job sent over REST", enqueues)
+	}
+
+	// Claims go over REST too, so the worker can restore the trace
+	claimed, err := transport.ClaimJobs(ctx, &resources.ClaimJobsRequest{QueueName: "emails", WorkerID: "worker-1"})
+	if err != nil || len(claimed.Jobs) != 1 {
+		t.Fatalf("ClaimJobs() = %+v, %v", claimed, err)
+	}
+	restored := propagation.ExtractTags(ctx, propagation.W3C{}, claimed.Jobs[0].Tags)
+	if got := propagation.HeadersFromContext(restored)["traceparent"]; got != traceparent {
+		t.Errorf("restored traceparent = %q, want %q", got, traceparent)
+	}
+	if _, dequeues := service.counts(); dequeues != 0 {
+		t.Errorf("gRPC dequeues = %d, want 0", dequeues)
+	}
+}
+
+func TestGRPCTransport_ClaimWithoutPropagation(t *testing.T) {
+	rest := newRESTRecorder(t, `{"jobs":[]}`)
+	transport, service := newGRPCTransport(t, rest)
+
+	claimed, err := transport.ClaimJobs(context.Background(), &resources.ClaimJobsRequest{QueueName: "emails", WorkerID: "worker-1"})
+	if err != nil || len(claimed.Jobs) != 1 || claimed.Jobs[0].ID != "grpc-job" {
+		t.Fatalf("ClaimJobs() = %+v, %v", claimed, err)
+	}
+	if _, claims := rest.received(); claims != 0 {
+		t.Errorf("REST claims = %d, want 0", claims)
+	}
+	if _, dequeues := service.counts(); dequeues != 1 {
+		t.Errorf("gRPC dequeues = %d, want 1", dequeues)
+	}
+}
+
+func TestTransport_PolicyFromTransformedPayload(t *testing.T) {
+	setType := WithPayloadTransform(func(payload map[string]any) map[string]any {
+		payload[resources.PolicyTypeKey] = "send-email"
+		return payload
+	}, nil)
+	policy := resources.Policy{MaxRetries: 7, Timeout: 30 * time.Second}
+
+	t.Run("rest", func(t *testing.T) {
+		rest := newRESTRecorder(t, `{"jobs":[]}`)
+		client, err := NewClient(WithAPIKey(testAPIKey), WithBaseURL(rest.URL), setType)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer client.Close()
+		client.Policies().Register("send-email", policy)
+		transport, err := client.Transport()
+		if err != nil {
+			t.Fatalf("Transport: %v", err)
+		}
+		if _, err := transport.Enqueue(context.Background(), &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{}}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		creates, _ := rest.received()
+		if len(creates) != 1 || creates[0]["max_retries"] != float64(7) || creates[0]["timeout_seconds"] != float64(30) {
+			t.Errorf("REST creates = %v, want the send-email policy applied", creates)
+		}
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		rest := newRESTRecorder(t, `{"jobs":[]}`)
+		client, service := newGRPCClient(t, rest, setType)
+		client.Policies().Register("send-email", policy)
+		transport, err := client.Transport()
+		if err != nil {
+			t.Fatalf("Transport: %v", err)
+		}
+		if _, err := transport.Enqueue(context.Background(), &resources.CreateJobRequest{QueueName: "emails", Payload: map[string]any{}}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		enqueued := service.received()
+		if len(enqueued) != 1 {
+			t.Fatalf("gRPC enqueues = %d, want 1", len(enqueued))
+		}
+		if req := enqueued[0]; req.MaxRetries != 7 || req.TimeoutSeconds != 30 ||
+			req.Payload.AsMap()[resources.PolicyTypeKey] != "send-email" {
+			t.Errorf("gRPC enqueue = %v, want the send-email policy applied", req)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package spooled
+
+import (
+	"context"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// CreateTypedJob creates a job in queue whose payload is encoded from a
+// struct, so producers and worker.ProcessTyped handlers can share one type.
+// To also set a priority, retries, or other options, encode the payload with
+// payload.Encode and call Jobs().Create.
+//
+//	resp, err := spooled.CreateTypedJob(ctx, client, "emails", SendEmail{To: "a@example.com"})
+func CreateTypedJob[T any](ctx context.Context, c *Client, queue string, p T) (*resources.CreateJobResponse, error) {
+	m, err := payload.Encode(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.Jobs().Create(ctx, &resources.CreateJobRequest{QueueName: queue, Payload: m})
+}
@@ -0,0 +1,41 @@
+package spooled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+)
+
+func TestCreateTypedJob(t *testing.T) {
+	type sendEmail struct {
+		To     string `json:"to"`
+		UserID int64  `json:"user_id"`
+	}
+
+	var body map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		_ = dec.Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "job-1", "created": true})
+	})
+
+	in := sendEmail{To: "a@example.com", UserID: 1<<53 + 1}
+	resp, err := CreateTypedJob(context.Background(), client, "emails", in)
+	if err != nil || resp.ID != "job-1" {
+		t.Fatalf("CreateTypedJob = %+v, %v", resp, err)
+	}
+	p := body["payload"].(map[string]any)
+	if body["queue_name"] != "emails" || p["to"] != "a@example.com" || p["user_id"] != json.Number("9007199254740993") {
+		t.Errorf("request body = %v", body)
+	}
+
+	if _, err := CreateTypedJob(context.Background(), client, "emails", "not an object"); !errors.Is(err, payload.ErrNotObject) {
+		t.Errorf("CreateTypedJob(string) error = %v, want ErrNotObject", err)
+	}
+}
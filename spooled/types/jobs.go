@@ -89,6 +89,7 @@ type ListJobsParams struct {
 	Offset    *int       `json:"offset,omitempty"`
 	OrderBy   *string    `json:"order_by,omitempty"`
 	OrderDir  *string    `json:"order_dir,omitempty"`
+	Cursor    *string    `json:"cursor,omitempty"`
 }
 
 // JobStats represents job statistics.
@@ -215,6 +216,7 @@ type BulkJobSuccess struct {
 type BulkJobFailure struct {
 	Index int    `json:"index"`
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
 }
 
 // DLQ types
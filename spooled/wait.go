@@ -0,0 +1,98 @@
+package spooled
+
+import (
+	"sync"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+)
+
+// waitEvents are the job events that wake Jobs().WaitForCompletion and
+// Workflows().Wait.
+var waitEvents = []realtime.EventType{
+	realtime.EventJobStarted,
+	realtime.EventJobProgress,
+	realtime.EventJobRetrying,
+	realtime.EventJobCompleted,
+	realtime.EventJobFailed,
+	realtime.EventJobDeadletter,
+}
+
+// realtimeJobWatcher wakes Jobs().WaitForCompletion and Workflows().Wait on
+// realtime events for the awaited jobs. It uses the client's shared realtime
+// connection, so waits only poll unless Realtime() has been connected.
+type realtimeJobWatcher struct {
+	client *Client
+	// once registers the event handlers on the shared connection.
+	once sync.Once
+
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+// WatchJob implements resources.JobWatcher.
+func (w *realtimeJobWatcher) WatchJob(jobID string) (<-chan struct{}, func(), bool) {
+	w.client.mu.RLock()
+	rt := w.client.realtimeClient
+	w.client.mu.RUnlock()
+	if rt == nil || rt.State() != realtime.StateConnected {
+		return nil, nil, false
+	}
+	w.once.Do(func() {
+		for _, eventType := range waitEvents {
+			rt.OnJobEvent(eventType, w.notify)
+		}
+	})
+
+	filter := realtime.SubscriptionFilter{JobID: jobID}
+	ch := make(chan struct{}, 1)
+	w.mu.Lock()
+	if w.waiters == nil {
+		w.waiters = make(map[string]map[chan struct{}]struct{})
+	}
+	first := len(w.waiters[jobID]) == 0
+	if first {
+		w.waiters[jobID] = make(map[chan struct{}]struct{})
+	}
+	w.waiters[jobID][ch] = struct{}{}
+	w.mu.Unlock()
+
+	stop := func() {
+		w.mu.Lock()
+		delete(w.waiters[jobID], ch)
+		last := len(w.waiters[jobID]) == 0
+		if last {
+			delete(w.waiters, jobID)
+		}
+		w.mu.Unlock()
+		if last {
+			if err := rt.Unsubscribe(filter); err != nil {
+				w.client.debug("job watch unsubscribe failed", "job_id", jobID, "error", err)
+			}
+		}
+	}
+	if first {
+		if err := rt.Subscribe(filter); err != nil {
+			w.client.debug("job watch unavailable, polling", "job_id", jobID, "error", err)
+			w.mu.Lock()
+			delete(w.waiters[jobID], ch)
+			if len(w.waiters[jobID]) == 0 {
+				delete(w.waiters, jobID)
+			}
+			w.mu.Unlock()
+			return nil, nil, false
+		}
+	}
+	return ch, stop, true
+}
+
+// notify wakes the waiters for an event's job without blocking.
+func (w *realtimeJobWatcher) notify(event *realtime.JobEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.waiters[event.JobID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
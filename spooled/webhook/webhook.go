@@ -0,0 +1,239 @@
+// Package webhook verifies requests Spooled sends to outgoing webhook URLs,
+// so receivers exposed to the public internet only act on authentic,
+// fresh deliveries:
+//
+//	verifier := webhook.NewVerifier(secret, webhook.Options{Store: webhook.NewMemoryStore()})
+//
+//	http.HandleFunc("/spooled", func(w http.ResponseWriter, r *http.Request) {
+//		body, err := verifier.VerifyRequest(r)
+//		if errors.Is(err, webhook.ErrReplayDetected) {
+//			w.WriteHeader(http.StatusOK) // already handled
+//			return
+//		}
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusUnauthorized)
+//			return
+//		}
+//		// handle body
+//	})
+//
+// Each delivery is signed with the webhook's secret over its timestamp,
+// delivery ID, and body. The timestamp bounds how long a captured request can
+// be replayed, and the optional NonceStore rejects a delivery ID seen before
+// within that window.
+//
+// Completion webhooks configured with resources.WebhookSigningJWS carry a
+// detached JWS instead, which Verifier checks the same way.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	// SignatureHeader holds one or more comma-separated "v1=<hex>"
+	// HMAC-SHA256 signatures; more than one is sent while a secret rotates.
+	SignatureHeader = "X-Spooled-Signature"
+	// TimestampHeader holds the Unix time the delivery was signed.
+	TimestampHeader = "X-Spooled-Timestamp"
+	// DeliveryIDHeader holds a nonce unique to each delivery attempt.
+	DeliveryIDHeader = "X-Spooled-Delivery-Id"
+	// JWSHeader holds a compact HS256 JWS with a detached payload (the
+	// body), for deliveries signed in the JWS format. Its protected header
+	// carries the signing time in "iat" and the delivery ID in "jti".
+	JWSHeader = "X-Spooled-JWS"
+)
+
+// DefaultTolerance is how far a delivery's timestamp may be from the
+// receiver's clock.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when a delivery is unsigned or no
+	// signature matches the secret.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	// ErrTimestampOutOfRange is returned when a delivery's timestamp is
+	// missing or further from now than the tolerance.
+	ErrTimestampOutOfRange = errors.New("webhook: timestamp outside tolerance")
+	// ErrReplayDetected is returned when a delivery ID has been seen before.
+	ErrReplayDetected = errors.New("webhook: replay detected")
+)
+
+// NonceStore records delivery IDs to detect replays. Share one store across
+// receiver instances, e.g. backed by Redis SET with NX and PX, so a delivery
+// replayed to another instance is rejected too.
+type NonceStore interface {
+	// Add records id until ttl elapses and reports whether it was new.
+	Add(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
+
+// Options configures a Verifier.
+type Options struct {
+	// Tolerance is how far a delivery's timestamp may be from now (default:
+	// DefaultTolerance).
+	Tolerance time.Duration
+	// Store rejects delivery IDs seen before (optional). Without it, a
+	// captured delivery can be replayed until its timestamp expires.
+	Store NonceStore
+}
+
+// Verifier checks the signature, timestamp, and delivery ID of webhook
+// deliveries.
+type Verifier struct {
+	secrets [][]byte
+	opts    Options
+}
+
+// NewVerifier creates a Verifier for deliveries signed with secret or any of
+// moreSecrets, e.g. the previous secret while it is being rotated out.
+func NewVerifier(secret string, opts Options, moreSecrets ...string) *Verifier {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	v := &Verifier{opts: opts}
+	for _, s := range append([]string{secret}, moreSecrets...) {
+		if s != "" {
+			v.secrets = append(v.secrets, []byte(s))
+		}
+	}
+	return v
+}
+
+// Verify checks a delivery's headers and raw body, signed with either the
+// HMAC headers or a JWS. The delivery ID is only
+// recorded once the signature and timestamp are valid, so forged requests
+// cannot poison the store. It returns ErrInvalidSignature,
+// ErrTimestampOutOfRange, or ErrReplayDetected, or a store error.
+func (v *Verifier) Verify(ctx context.Context, header http.Header, body []byte) error {
+	var id string
+	var sent int64
+	if jws := header.Get(JWSHeader); jws != "" {
+		claims, err := v.verifyJWS(jws, body)
+		if err != nil {
+			return err
+		}
+		id, sent = claims.ID, claims.IssuedAt
+	} else {
+		id = header.Get(DeliveryIDHeader)
+		ts := header.Get(TimestampHeader)
+		var err error
+		if sent, err = strconv.ParseInt(ts, 10, 64); err != nil {
+			return ErrTimestampOutOfRange
+		}
+		if !v.validSignature(header.Get(SignatureHeader), ts, id, body) {
+			return ErrInvalidSignature
+		}
+	}
+	age := time.Since(time.Unix(sent, 0))
+	if age > v.opts.Tolerance || age < -v.opts.Tolerance {
+		return ErrTimestampOutOfRange
+	}
+
+	if v.opts.Store == nil {
+		return nil
+	}
+	if id == "" {
+		return fmt.Errorf("%w: missing %s", ErrReplayDetected, DeliveryIDHeader)
+	}
+	// Timestamps older than the tolerance are rejected anyway, so IDs only
+	// need to be remembered for the rest of the window
+	fresh, err := v.opts.Store.Add(ctx, id, 2*v.opts.Tolerance)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to record delivery: %w", err)
+	}
+	if !fresh {
+		return ErrReplayDetected
+	}
+	return nil
+}
+
+// VerifyRequest reads r's body and verifies it, returning the body. The body
+// is restored so r can still be read by later handlers.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to read body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := v.Verify(r.Context(), r.Header, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// validSignature reports whether any signature in header matches a secret.
+func (v *Verifier) validSignature(header, ts, id string, body []byte) bool {
+	for _, sig := range strings.Split(header, ",") {
+		hexSig, ok := strings.CutPrefix(strings.TrimSpace(sig), "v1=")
+		if !ok {
+			continue
+		}
+		got, err := hex.DecodeString(hexSig)
+		if err != nil {
+			continue
+		}
+		for _, secret := range v.secrets {
+			if hmac.Equal(got, mac(secret, ts, id, body)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Sign returns the SignatureHeader value for a delivery, e.g. to test a
+// receiver.
+func Sign(secret string, timestamp time.Time, deliveryID string, body []byte) string {
+	return "v1=" + hex.EncodeToString(mac([]byte(secret), strconv.FormatInt(timestamp.Unix(), 10), deliveryID, body))
+}
+
+// mac computes the HMAC-SHA256 of "timestamp.id.body".
+func mac(secret []byte, ts, id string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts + "." + id + "."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// MemoryStore is an in-process NonceStore. It only detects replays to the
+// same process; use a shared store when running several receivers.
+type MemoryStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{expires: make(map[string]time.Time)}
+}
+
+// Add records id until ttl elapses and reports whether it was new. Expired
+// IDs are pruned as new ones are added.
+func (s *MemoryStore) Add(_ context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, exp := range s.expires {
+		if now.After(exp) {
+			delete(s.expires, k)
+		}
+	}
+	if _, seen := s.expires[id]; seen {
+		return false, nil
+	}
+	s.expires[id] = now.Add(ttl)
+	return true, nil
+}
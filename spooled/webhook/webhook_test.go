@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifier(t *testing.T) {
+	body := []byte(`{"event":"job.completed","job_id":"job-1"}`)
+	delivery := func(secret, id string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+		h.Set(DeliveryIDHeader, id)
+		h.Set(SignatureHeader, Sign(secret, at, id, body))
+		return h
+	}
+	ctx := context.Background()
+	v := NewVerifier("whsec_new", Options{Store: NewMemoryStore()}, "whsec_old")
+
+	if err := v.Verify(ctx, delivery("whsec_new", "d-1", time.Now()), body); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_new", "d-1", time.Now()), body); !errors.Is(err, ErrReplayDetected) {
+		t.Errorf("replayed delivery: got %v, want ErrReplayDetected", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_old", "d-2", time.Now()), body); err != nil {
+		t.Errorf("delivery signed with the previous secret: %v", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_other", "d-3", time.Now()), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong secret: got %v, want ErrInvalidSignature", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_new", "d-4", time.Now().Add(-10*time.Minute)), body); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("stale delivery: got %v, want ErrTimestampOutOfRange", err)
+	}
+
+	// A forged request must not consume the delivery ID
+	forged := delivery("whsec_new", "d-5", time.Now())
+	if err := v.Verify(ctx, forged, []byte(`{"tampered":true}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered body: got %v, want ErrInvalidSignature", err)
+	}
+	if err := v.Verify(ctx, forged, body); err != nil {
+		t.Errorf("genuine delivery after forged one: %v", err)
+	}
+}
+
+func TestVerifier_JWS(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"event":"job.completed","job_id":"job-1"}`)
+	v := NewVerifier("whsec_partner", Options{Store: NewMemoryStore()})
+	delivery := func(secret, id string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(JWSHeader, SignJWS(secret, at, id, body))
+		return h
+	}
+	if err := v.Verify(ctx, delivery("whsec_partner", "d-1", time.Now()), body); err != nil {
+		t.Fatalf("Verify(JWS) = %v", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_partner", "d-1", time.Now()), body); !errors.Is(err, ErrReplayDetected) {
+		t.Errorf("replayed JWS: got %v, want ErrReplayDetected", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_other", "d-2", time.Now()), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong secret: got %v, want ErrInvalidSignature", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_partner", "d-3", time.Now()), []byte(`{"tampered":true}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered body: got %v, want ErrInvalidSignature", err)
+	}
+	if err := v.Verify(ctx, delivery("whsec_partner", "d-4", time.Now().Add(-time.Hour)), body); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("stale JWS: got %v, want ErrTimestampOutOfRange", err)
+	}
+	// The protected header is signed, so its claims cannot be swapped.
+	jws := SignJWS("whsec_partner", time.Now(), "d-5", body)
+	_, sig, _ := strings.Cut(jws, "..")
+	forged := SignJWS("whsec_other", time.Now(), "d-6", body)
+	protected, _, _ := strings.Cut(forged, "..")
+	h := http.Header{}
+	h.Set(JWSHeader, protected+".."+sig)
+	if err := v.Verify(ctx, h, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("swapped header: got %v, want ErrInvalidSignature", err)
+	}
+}
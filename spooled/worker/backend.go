@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// Backend is the set of job and worker operations the runtime depends on.
+// It lets the worker run over REST or gRPC without knowing which is in use.
+type Backend interface {
+	RegisterWorker(ctx context.Context, req *resources.RegisterWorkerRequest) (*resources.RegisterWorkerResponse, error)
+	WorkerHeartbeat(ctx context.Context, workerID string, req *resources.WorkerHeartbeatRequest) error
+	DeregisterWorker(ctx context.Context, workerID string) error
+	ClaimJobs(ctx context.Context, req *resources.ClaimJobsRequest) (*resources.ClaimJobsResponse, error)
+	CompleteJob(ctx context.Context, jobID string, req *resources.CompleteJobRequest) error
+	FailJob(ctx context.Context, jobID string, req *resources.FailJobRequest) error
+	RenewLease(ctx context.Context, jobID string, req *resources.RenewLeaseRequest) (*resources.RenewLeaseResponse, error)
+	UpdateProgress(ctx context.Context, jobID string, req *resources.UpdateProgressRequest) error
+	ExtendTimeout(ctx context.Context, jobID string, extra time.Duration) (*resources.ExtendTimeoutResponse, error)
+}
+
+// StreamingBackend is a Backend that can push jobs to a worker over a
+// long-lived stream instead of being polled. Options.Streaming uses it.
+type StreamingBackend interface {
+	Backend
+	// OpenJobStream opens a job stream for a registered worker. req carries
+	// the queue, worker ID, and lease duration; its Limit is unused. Cancel
+	// ctx to close the stream. It returns ErrStreamingUnsupported when the
+	// request needs polling, in which case the worker falls back to it.
+	OpenJobStream(ctx context.Context, req *resources.ClaimJobsRequest) (JobStream, error)
+}
+
+// JobStream delivers jobs pushed by the server.
+type JobStream interface {
+	// Request asks the server for up to n more jobs.
+	Request(n int) error
+	// Recv blocks until the next job arrives or the stream ends.
+	Recv() (resources.ClaimedJob, error)
+	// Close tells the server no more requests will be sent.
+	Close() error
+}
+
+// ErrStreamingUnsupported is returned by StreamingBackend.OpenJobStream when
+// jobs for the request cannot be streamed.
+var ErrStreamingUnsupported = errors.New("job streaming not supported for this request")
+
+// RESTBackend implements Backend using the REST resources.
+type RESTBackend struct {
+	Jobs    *resources.JobsResource
+	Workers *resources.WorkersResource
+}
+
+// NewRESTBackend creates a Backend backed by the REST API.
+func NewRESTBackend(jobs *resources.JobsResource, workers *resources.WorkersResource) *RESTBackend {
+	return &RESTBackend{Jobs: jobs, Workers: workers}
+}
+
+// RegisterWorker registers a worker.
+func (b *RESTBackend) RegisterWorker(ctx context.Context, req *resources.RegisterWorkerRequest) (*resources.RegisterWorkerResponse, error) {
+	return b.Workers.Register(ctx, req)
+}
+
+// WorkerHeartbeat sends a worker heartbeat.
+func (b *RESTBackend) WorkerHeartbeat(ctx context.Context, workerID string, req *resources.WorkerHeartbeatRequest) error {
+	return b.Workers.Heartbeat(ctx, workerID, req)
+}
+
+// DeregisterWorker deregisters a worker.
+func (b *RESTBackend) DeregisterWorker(ctx context.Context, workerID string) error {
+	return b.Workers.Deregister(ctx, workerID)
+}
+
+// ClaimJobs claims jobs for a worker.
+func (b *RESTBackend) ClaimJobs(ctx context.Context, req *resources.ClaimJobsRequest) (*resources.ClaimJobsResponse, error) {
+	return b.Jobs.Claim(ctx, req)
+}
+
+// CompleteJob marks a job as completed.
+func (b *RESTBackend) CompleteJob(ctx context.Context, jobID string, req *resources.CompleteJobRequest) error {
+	return b.Jobs.Complete(ctx, jobID, req)
+}
+
+// FailJob marks a job as failed.
+func (b *RESTBackend) FailJob(ctx context.Context, jobID string, req *resources.FailJobRequest) error {
+	return b.Jobs.Fail(ctx, jobID, req)
+}
+
+// RenewLease extends the lease on a job.
+func (b *RESTBackend) RenewLease(ctx context.Context, jobID string, req *resources.RenewLeaseRequest) (*resources.RenewLeaseResponse, error) {
+	return b.Jobs.RenewLease(ctx, jobID, req)
+}
+
+// UpdateProgress updates the progress of a job.
+func (b *RESTBackend) UpdateProgress(ctx context.Context, jobID string, req *resources.UpdateProgressRequest) error {
+	return b.Jobs.UpdateProgress(ctx, jobID, req)
+}
+
+// ExtendTimeout extends a job's execution timeout.
+func (b *RESTBackend) ExtendTimeout(ctx context.Context, jobID string, extra time.Duration) (*resources.ExtendTimeoutResponse, error) {
+	return b.Jobs.ExtendTimeout(ctx, jobID, extra)
+}
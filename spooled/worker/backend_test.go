@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// fakeBackend is an in-memory Backend. ClaimJobs hands out the jobs added
+// with push; completions, failures, and claims are recorded. CompleteJob
+// returns completeErr when it is set.
+type fakeBackend struct {
+	mu          sync.Mutex
+	pending     []resources.ClaimedJob
+	claims      int
+	completed   map[string]*resources.CompleteJobRequest
+	failed      map[string]*resources.FailJobRequest
+	completeErr error
+}
+
+func newFakeBackend(jobs ...resources.ClaimedJob) *fakeBackend {
+	return &fakeBackend{
+		pending:   jobs,
+		completed: make(map[string]*resources.CompleteJobRequest),
+		failed:    make(map[string]*resources.FailJobRequest),
+	}
+}
+
+// push queues a job for the next claim.
+func (b *fakeBackend) push(job resources.ClaimedJob) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = append(b.pending, job)
+}
+
+func (b *fakeBackend) claimCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.claims
+}
+
+// results returns the completed and failed jobs by ID.
+func (b *fakeBackend) results() (completed map[string]*resources.CompleteJobRequest, failed map[string]*resources.FailJobRequest) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	completed = make(map[string]*resources.CompleteJobRequest, len(b.completed))
+	for id, req := range b.completed {
+		completed[id] = req
+	}
+	failed = make(map[string]*resources.FailJobRequest, len(b.failed))
+	for id, req := range b.failed {
+		failed[id] = req
+	}
+	return completed, failed
+}
+
+func (b *fakeBackend) RegisterWorker(_ context.Context, req *resources.RegisterWorkerRequest) (*resources.RegisterWorkerResponse, error) {
+	return &resources.RegisterWorkerResponse{ID: "worker-1", QueueName: req.QueueName}, nil
+}
+
+func (b *fakeBackend) WorkerHeartbeat(context.Context, string, *resources.WorkerHeartbeatRequest) error {
+	return nil
+}
+
+func (b *fakeBackend) DeregisterWorker(context.Context, string) error {
+	return nil
+}
+
+func (b *fakeBackend) ClaimJobs(_ context.Context, req *resources.ClaimJobsRequest) (*resources.ClaimJobsResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.claims++
+	n := len(b.pending)
+	if req.Limit != nil {
+		n = min(n, *req.Limit)
+	}
+	jobs := b.pending[:n:n]
+	b.pending = b.pending[n:]
+	return &resources.ClaimJobsResponse{Jobs: jobs}, nil
+}
+
+func (b *fakeBackend) CompleteJob(_ context.Context, jobID string, req *resources.CompleteJobRequest) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.completeErr != nil {
+		return b.completeErr
+	}
+	b.completed[jobID] = req
+	return nil
+}
+
+func (b *fakeBackend) FailJob(_ context.Context, jobID string, req *resources.FailJobRequest) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failed[jobID] = req
+	return nil
+}
+
+func (b *fakeBackend) RenewLease(context.Context, string, *resources.RenewLeaseRequest) (*resources.RenewLeaseResponse, error) {
+	return &resources.RenewLeaseResponse{Success: true}, nil
+}
+
+func (b *fakeBackend) UpdateProgress(context.Context, string, *resources.UpdateProgressRequest) error {
+	return nil
+}
+
+func (b *fakeBackend) ExtendTimeout(_ context.Context, jobID string, _ time.Duration) (*resources.ExtendTimeoutResponse, error) {
+	return &resources.ExtendTimeoutResponse{JobID: jobID}, nil
+}
+
+// startWorker starts a worker on backend with handler, stopping it when the
+// test ends.
+func startWorker(t *testing.T, backend Backend, opts Options, handler JobHandler) *Worker {
+	t.Helper()
+	if opts.QueueName == "" {
+		opts.QueueName = "emails"
+	}
+	w := NewWorkerWithBackend(backend, opts)
+	w.Process(handler)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+	return w
+}
+
+// waitFor fails the test if cond does not hold within a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
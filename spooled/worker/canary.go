@@ -0,0 +1,30 @@
+package worker
+
+import "hash/fnv"
+
+// canaryBuckets is the resolution of canary fractions.
+const canaryBuckets = 10000
+
+// InCanary reports whether a job falls in the canary sample for fraction
+// (0-1). Jobs are assigned by a hash of their ID, so every worker and the
+// server agree on the sample, and a larger fraction includes every job of a
+// smaller one.
+func InCanary(jobID string, fraction float64) bool {
+	if fraction <= 0 {
+		return false
+	}
+	if fraction >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(jobID))
+	return float64(h.Sum32()%canaryBuckets) < fraction*canaryBuckets
+}
+
+// canaryLabel marks canary workers in RunSummary.String.
+func canaryLabel(canary bool) string {
+	if canary {
+		return " canary=true"
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// runtime/metrics names used when the OS does not report resident memory.
+const (
+	metricMemoryTotal    = "/memory/classes/total:bytes"
+	metricMemoryReleased = "/memory/classes/heap/released:bytes"
+	metricGoroutines     = "/sched/goroutines:goroutines"
+)
+
+// processSampler gathers process metrics for worker heartbeats.
+// CPU usage is computed from the process CPU time used since the previous
+// sample, as reported by the OS (see processCPUTime).
+type processSampler struct {
+	mu       sync.Mutex
+	samples  []metrics.Sample
+	lastCPU  time.Duration
+	lastTime time.Time
+}
+
+func newProcessSampler() *processSampler {
+	s := &processSampler{
+		samples: []metrics.Sample{
+			{Name: metricMemoryTotal},
+			{Name: metricMemoryReleased},
+			{Name: metricGoroutines},
+		},
+	}
+	s.sample(nil) // prime the CPU baseline
+	return s
+}
+
+// sample reads the current process metrics. activeJobs is reported as-is.
+func (s *processSampler) sample(activeJobs map[string]int) *resources.WorkerProcessMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics.Read(s.samples)
+	m := &resources.WorkerProcessMetrics{
+		Goroutines:        int(s.samples[2].Value.Uint64()),
+		ActiveJobsByQueue: activeJobs,
+	}
+
+	if rss, ok := processRSS(); ok {
+		m.RSSBytes = rss
+	} else {
+		// Memory the Go runtime holds from the OS; a lower bound on RSS
+		m.RSSBytes = s.samples[0].Value.Uint64() - s.samples[1].Value.Uint64()
+	}
+
+	now := time.Now()
+	if cpu, ok := processCPUTime(); ok {
+		if !s.lastTime.IsZero() {
+			if wall := now.Sub(s.lastTime); wall > 0 && cpu >= s.lastCPU {
+				m.CPUPercent = float64(cpu-s.lastCPU) / float64(wall) * 100
+			}
+		}
+		s.lastCPU = cpu
+		s.lastTime = now
+	}
+	return m
+}
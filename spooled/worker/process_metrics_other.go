@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package worker
+
+import "time"
+
+// processCPUTime reports that process CPU time is unavailable on this platform.
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
+
+// processRSS reports that resident memory is unavailable on this platform.
+func processRSS() (uint64, bool) {
+	return 0, false
+}
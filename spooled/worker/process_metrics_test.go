@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestProcessSampler(t *testing.T) {
+	if _, ok := processCPUTime(); !ok {
+		t.Skip("process CPU time is unavailable on this platform")
+	}
+	// CPU time must be measured without waiting for a garbage collection
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	s := newProcessSampler()
+	start := time.Now()
+	var n uint64
+	for time.Since(start) < 100*time.Millisecond {
+		n++
+	}
+	m := s.sample(map[string]int{"emails": 2})
+
+	if m.CPUPercent < 10 {
+		t.Errorf("CPUPercent = %.1f after a busy loop (%d iterations), want at least 10", m.CPUPercent, n)
+	}
+	if m.RSSBytes == 0 {
+		t.Error("RSSBytes = 0")
+	}
+	if m.Goroutines == 0 {
+		t.Error("Goroutines = 0")
+	}
+	if m.ActiveJobsByQueue["emails"] != 2 {
+		t.Errorf("ActiveJobsByQueue = %v", m.ActiveJobsByQueue)
+	}
+
+	// An idle process reports little CPU
+	time.Sleep(100 * time.Millisecond)
+	if m := s.sample(nil); m.CPUPercent > 50 {
+		t.Errorf("CPUPercent = %.1f while idle", m.CPUPercent)
+	}
+}
+
+func TestProcessRSS(t *testing.T) {
+	rss, ok := processRSS()
+	if !ok {
+		t.Skip("resident memory is unavailable on this platform")
+	}
+	// Touch a large allocation so it becomes resident
+	buf := make([]byte, 64<<20)
+	for i := range buf {
+		buf[i] = 1
+	}
+	after, _ := processRSS()
+	if after < rss+32<<20 {
+		t.Errorf("RSS grew from %d to %d after touching 64 MiB", rss, after)
+	}
+	_ = buf[len(buf)-1]
+}
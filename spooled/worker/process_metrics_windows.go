@@ -0,0 +1,26 @@
+//go:build windows
+
+package worker
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and kernel CPU time used by the process.
+func processCPUTime() (time.Duration, bool) {
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0, false
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(process, &creation, &exit, &kernel, &user); err != nil {
+		return 0, false
+	}
+	return time.Duration(kernel.Nanoseconds() + user.Nanoseconds()), true
+}
+
+// processRSS reports that resident memory is unavailable on Windows.
+func processRSS() (uint64, bool) {
+	return 0, false
+}
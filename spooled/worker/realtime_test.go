@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+)
+
+// fakeRealtime is a connected RealtimeClient that delivers the job events
+// passed to emit.
+type fakeRealtime struct {
+	mu       sync.Mutex
+	filters  []realtime.SubscriptionFilter
+	handlers map[int]fakeJobHandler
+	nextID   int
+}
+
+type fakeJobHandler struct {
+	eventType realtime.EventType
+	handler   realtime.JobEventHandler
+}
+
+func (r *fakeRealtime) Connect() error                  { return nil }
+func (r *fakeRealtime) Disconnect() error               { return nil }
+func (r *fakeRealtime) State() realtime.ConnectionState { return realtime.StateConnected }
+
+func (r *fakeRealtime) Subscribe(filter realtime.SubscriptionFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters = append(r.filters, filter)
+	return nil
+}
+
+func (r *fakeRealtime) Unsubscribe(realtime.SubscriptionFilter) error { return nil }
+
+func (r *fakeRealtime) OnEvent(realtime.EventHandler) func() { return func() {} }
+
+func (r *fakeRealtime) OnJobEvent(eventType realtime.EventType, handler realtime.JobEventHandler) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handlers == nil {
+		r.handlers = make(map[int]fakeJobHandler)
+	}
+	r.nextID++
+	id := r.nextID
+	r.handlers[id] = fakeJobHandler{eventType, handler}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.handlers, id)
+	}
+}
+
+func (r *fakeRealtime) OnQueueEvent(realtime.EventType, realtime.QueueEventHandler) func() {
+	return func() {}
+}
+
+func (r *fakeRealtime) OnWorkerEvent(realtime.EventType, realtime.WorkerEventHandler) func() {
+	return func() {}
+}
+
+func (r *fakeRealtime) OnStateChange(realtime.StateChangeHandler) func() { return func() {} }
+
+// emit delivers event to the handlers registered for eventType.
+func (r *fakeRealtime) emit(eventType realtime.EventType, event *realtime.JobEvent) {
+	r.mu.Lock()
+	var handlers []realtime.JobEventHandler
+	for _, h := range r.handlers {
+		if h.eventType == eventType {
+			handlers = append(handlers, h.handler)
+		}
+	}
+	r.mu.Unlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// subscriptions returns the filters subscribed so far.
+func (r *fakeRealtime) subscriptions() []realtime.SubscriptionFilter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]realtime.SubscriptionFilter(nil), r.filters...)
+}
+
+func (r *fakeRealtime) handlerCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.handlers)
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// RunJob runs handler for a single job that has already been claimed by
+// workerID, outside a polling Worker. The handler gets the same JobContext as
+// under a Worker, and the job is completed or failed through backend exactly as
+// a Worker would, including lease renewal, timeouts, AckMode, and
+// ResultValidator from opts. QueueName, Concurrency, polling options, and the
+// OnStart and OnStop hooks are ignored.
+//
+// It returns the handler's result and error once the outcome has been
+// reported. It is the building block for serverless adapters such as the
+// spooled/serverless package.
+func RunJob(ctx context.Context, backend Backend, workerID string, job resources.ClaimedJob, handler JobHandler, opts Options) (map[string]any, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("no job handler")
+	}
+	if job.ID == "" {
+		return nil, fmt.Errorf("job ID is required")
+	}
+
+	w := NewWorkerWithBackend(backend, opts)
+	w.workerID = workerID
+	w.handler = handler
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	defer w.cancel()
+	w.state.Store(StateRunning)
+
+	var result map[string]any
+	var jobErr error
+	w.OnEvent(func(event Event) {
+		switch data := event.Data.(type) {
+		case JobCompletedData:
+			result = data.Result
+		case JobFailedData:
+			jobErr = data.Error
+		case WorkerErrorData:
+			// AckEarly could not commit the job, so the handler never ran
+			jobErr = data.Error
+		}
+	})
+
+	w.processJob(job)
+	w.wg.Wait()
+	w.state.Store(StateStopped)
+	return result, jobErr
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultStatsWindow is the rolling window Stats reports on when
+// Options.StatsWindow is not set.
+const DefaultStatsWindow = 5 * time.Minute
+
+// maxStatsSamples caps the samples kept per queue, so high-throughput queues
+// report on the most recent jobs of the window.
+const maxStatsSamples = 10000
+
+// minSlowSamples is how many recent jobs a queue needs before
+// SlowJobPercentile is used as a threshold.
+const minSlowSamples = 20
+
+// HandlerStats summarizes jobs whose handler returned within the window.
+type HandlerStats struct {
+	Jobs   int
+	Failed int
+	// Throughput is jobs per second over the window, or over the worker's
+	// uptime if it is shorter.
+	Throughput float64
+	// ErrorRate is Failed / Jobs.
+	ErrorRate float64
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+}
+
+// Stats is a rolling view of a worker's recent performance. The top-level
+// figures cover all queues.
+type Stats struct {
+	HandlerStats
+	Window time.Duration
+	Active int
+	Queues map[string]HandlerStats
+	// Canary is set for workers with a CanaryFraction.
+	Canary bool
+}
+
+// jobSample is one finished job.
+type jobSample struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+// handlerStats keeps recent job samples per queue.
+type handlerStats struct {
+	mu      sync.Mutex
+	window  time.Duration
+	samples map[string][]jobSample
+	// slow caches the percentile threshold per queue for a second.
+	slow map[string]slowThreshold
+}
+
+type slowThreshold struct {
+	value    time.Duration
+	computed time.Time
+}
+
+func newHandlerStats(window time.Duration) *handlerStats {
+	if window <= 0 {
+		window = DefaultStatsWindow
+	}
+	return &handlerStats{
+		window:  window,
+		samples: make(map[string][]jobSample),
+		slow:    make(map[string]slowThreshold),
+	}
+}
+
+// record adds a finished job and drops samples that left the window.
+func (s *handlerStats) record(queue string, duration time.Duration, failed bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := append(s.prune(s.samples[queue], now), jobSample{at: now, duration: duration, failed: failed})
+	if len(samples) > maxStatsSamples {
+		samples = slices.Delete(samples, 0, len(samples)-maxStatsSamples)
+	}
+	s.samples[queue] = samples
+}
+
+// prune drops samples older than the window. Samples are in time order.
+func (s *handlerStats) prune(samples []jobSample, now time.Time) []jobSample {
+	cutoff := now.Add(-s.window)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	return slices.Delete(samples, 0, i)
+}
+
+// percentile returns the p-th percentile (0-100) of the queue's recent
+// durations, or 0 until the queue has enough samples.
+func (s *handlerStats) percentile(queue string, p float64) time.Duration {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.slow[queue]; ok && now.Sub(cached.computed) < time.Second {
+		return cached.value
+	}
+	samples := s.prune(s.samples[queue], now)
+	s.samples[queue] = samples
+	var value time.Duration
+	if len(samples) >= minSlowSamples {
+		value = percentileOf(sortedDurations(samples), p)
+	}
+	s.slow[queue] = slowThreshold{value: value, computed: now}
+	return value
+}
+
+// snapshot summarizes the window; uptime bounds the throughput interval.
+func (s *handlerStats) snapshot(uptime time.Duration) Stats {
+	now := time.Now()
+	interval := s.window
+	if uptime > 0 && uptime < interval {
+		interval = uptime
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := Stats{Window: s.window, Queues: make(map[string]HandlerStats)}
+	var all []jobSample
+	for queue, samples := range s.samples {
+		samples = s.prune(samples, now)
+		s.samples[queue] = samples
+		if len(samples) == 0 {
+			continue
+		}
+		stats.Queues[queue] = summarize(samples, interval)
+		all = append(all, samples...)
+	}
+	stats.HandlerStats = summarize(all, interval)
+	return stats
+}
+
+func summarize(samples []jobSample, interval time.Duration) HandlerStats {
+	var hs HandlerStats
+	if len(samples) == 0 {
+		return hs
+	}
+	hs.Jobs = len(samples)
+	for _, sample := range samples {
+		if sample.failed {
+			hs.Failed++
+		}
+	}
+	hs.ErrorRate = float64(hs.Failed) / float64(hs.Jobs)
+	if interval > 0 {
+		hs.Throughput = float64(hs.Jobs) / interval.Seconds()
+	}
+	durations := sortedDurations(samples)
+	hs.P50 = percentileOf(durations, 50)
+	hs.P95 = percentileOf(durations, 95)
+	hs.P99 = percentileOf(durations, 99)
+	hs.Max = durations[len(durations)-1]
+	return hs
+}
+
+func sortedDurations(samples []jobSample) []time.Duration {
+	durations := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		durations[i] = sample.duration
+	}
+	slices.Sort(durations)
+	return durations
+}
+
+// percentileOf returns the nearest-rank p-th percentile of sorted durations.
+func percentileOf(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// streamLoop claims jobs over the backend's job stream, falling back to
+// polling when the backend cannot stream.
+func (w *Worker) streamLoop() {
+	if !w.stream() {
+		// pollLoop takes over this goroutine's wait group slot
+		w.pollLoop()
+		return
+	}
+	w.wg.Done()
+}
+
+// stream consumes job streams until the worker stops, reopening a dropped
+// stream with backoff. It reports false, without having claimed anything,
+// when streaming is unavailable.
+func (w *Worker) stream() bool {
+	sb, ok := w.backend.(StreamingBackend)
+	if !ok {
+		w.log("Backend does not support streaming; polling instead")
+		return false
+	}
+	if w.opts.CanaryFraction > 0 {
+		w.log("CanaryFraction requires polling; streaming disabled")
+		return false
+	}
+
+	delay := w.opts.StreamReconnectDelay
+	for {
+		received, err := w.consumeStream(sb)
+		if w.ctx.Err() != nil {
+			return true
+		}
+		if errors.Is(err, ErrStreamingUnsupported) {
+			w.log("Job streaming unavailable; polling instead")
+			return false
+		}
+		if received > 0 {
+			delay = w.opts.StreamReconnectDelay
+		}
+
+		// A dropped stream says nothing about whether the queue is empty
+		w.idleSince.Store(0)
+		w.stats.pollErrors.Add(1)
+		w.log("Job stream closed, reconnecting in %v: %v", delay, err)
+		w.emit(Event{
+			Type:      EventWorkerError,
+			Timestamp: time.Now(),
+			Data:      WorkerErrorData{Error: fmt.Errorf("job stream: %w", err)},
+		})
+
+		select {
+		case <-w.ctx.Done():
+			return true
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > w.opts.MaxStreamReconnectDelay {
+			delay = w.opts.MaxStreamReconnectDelay
+		}
+	}
+}
+
+// consumeStream opens a job stream and processes the jobs it delivers until
+// the stream ends, returning how many jobs it received.
+//
+// Jobs are requested as credit: the stream is asked for as many jobs as the
+// worker has free slots, and again whenever a job finishes, so the server
+// never pushes more than Concurrency allows.
+func (w *Worker) consumeStream(sb StreamingBackend) (int, error) {
+	w.mu.RLock()
+	workerID := w.workerID
+	w.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+
+	leaseDuration := w.opts.LeaseDuration
+	stream, err := sb.OpenJobStream(ctx, &resources.ClaimJobsRequest{
+		QueueName:        w.opts.QueueName,
+		WorkerID:         workerID,
+		LeaseDurationSec: &leaseDuration,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	var (
+		// requested counts jobs asked for but not yet delivered
+		requested atomic.Int64
+		// delivered counts jobs since the last idle check
+		delivered atomic.Int64
+		sendErr   = make(chan error, 1)
+		demandEnd = make(chan struct{})
+	)
+
+	// Only this goroutine sends on the stream
+	go func() {
+		defer close(demandEnd)
+		for {
+			free := int64(w.opts.Concurrency) - int64(w.jobCount.Load()) - requested.Load()
+			if free > 0 {
+				if err := stream.Request(int(free)); err != nil {
+					sendErr <- err
+					cancel()
+					return
+				}
+				requested.Add(free)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			case <-w.pollTicker.C:
+				if w.opts.ExitWhenIdle > 0 && w.checkIdle(int(delivered.Swap(0))) {
+					return
+				}
+			}
+		}
+	}()
+	defer func() {
+		cancel()
+		<-demandEnd
+	}()
+
+	received := 0
+	for {
+		job, err := stream.Recv()
+		if err != nil {
+			select {
+			case err = <-sendErr:
+			default:
+			}
+			return received, err
+		}
+		received++
+		delivered.Add(1)
+		w.stats.claims.Add(1)
+		w.processJob(job)
+
+		// The job now holds a slot; stop counting it as requested (unless
+		// the server pushed more than was asked for) and let the demand loop
+		// recompute its credit
+		for n := requested.Load(); n > 0 && !requested.CompareAndSwap(n, n-1); n = requested.Load() {
+		}
+		w.wakeup()
+	}
+}
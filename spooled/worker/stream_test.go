@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// streamingBackend pushes jobs from a channel. The first stream drops after
+// delivering one job, so the worker has to reconnect.
+type streamingBackend struct {
+	*fakeBackend
+	jobs chan resources.ClaimedJob
+
+	mu        sync.Mutex
+	opens     int
+	requested []int
+}
+
+func (b *streamingBackend) OpenJobStream(ctx context.Context, req *resources.ClaimJobsRequest) (JobStream, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.opens++
+	return &fakeJobStream{b: b, ctx: ctx, drop: b.opens == 1, credit: make(chan struct{}, 100)}, nil
+}
+
+// fakeJobStream only delivers jobs that have been requested, as a server would.
+type fakeJobStream struct {
+	b        *streamingBackend
+	ctx      context.Context
+	drop     bool
+	received int
+	credit   chan struct{}
+}
+
+func (s *fakeJobStream) Request(n int) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.b.requested = append(s.b.requested, n)
+	for i := 0; i < n; i++ {
+		s.credit <- struct{}{}
+	}
+	return nil
+}
+
+func (s *fakeJobStream) Recv() (resources.ClaimedJob, error) {
+	if s.drop && s.received == 1 {
+		return resources.ClaimedJob{}, errors.New("stream reset")
+	}
+	select {
+	case <-s.credit:
+	case <-s.ctx.Done():
+		return resources.ClaimedJob{}, s.ctx.Err()
+	}
+	select {
+	case job := <-s.b.jobs:
+		s.received++
+		return job, nil
+	case <-s.ctx.Done():
+		return resources.ClaimedJob{}, s.ctx.Err()
+	}
+}
+
+func (s *fakeJobStream) Close() error { return nil }
+
+func TestWorker_Streaming(t *testing.T) {
+	backend := &streamingBackend{
+		fakeBackend: newFakeBackend(),
+		jobs:        make(chan resources.ClaimedJob, 2),
+	}
+	backend.jobs <- resources.ClaimedJob{ID: "job-1", QueueName: "resize"}
+	backend.jobs <- resources.ClaimedJob{ID: "job-2", QueueName: "resize"}
+
+	w := NewWorkerWithBackend(backend, Options{
+		QueueName:            "resize",
+		Concurrency:          2,
+		PollInterval:         5 * time.Millisecond,
+		ExitWhenIdle:         50 * time.Millisecond,
+		Streaming:            true,
+		StreamReconnectDelay: 5 * time.Millisecond,
+	})
+	w.Process(func(ctx *JobContext) (map[string]any, error) { return nil, nil })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	summary, err := w.RunUntilDrained(ctx)
+	if err != nil {
+		t.Fatalf("RunUntilDrained: %v", err)
+	}
+
+	if claims := backend.claimCount(); claims != 0 {
+		t.Errorf("claims = %d, want the streaming worker not to poll", claims)
+	}
+	if completed, _ := backend.results(); len(completed) != 2 || completed["job-1"] == nil || completed["job-2"] == nil {
+		t.Errorf("completed = %v, want both jobs", completed)
+	}
+	backend.mu.Lock()
+	if backend.opens != 2 {
+		t.Errorf("streams opened = %d, want 2 (one reconnect)", backend.opens)
+	}
+	if len(backend.requested) == 0 || backend.requested[0] != 2 {
+		t.Errorf("requested = %v, want the first request to ask for Concurrency jobs", backend.requested)
+	}
+	backend.mu.Unlock()
+	if summary.JobsProcessed != 2 {
+		t.Errorf("summary = %s, want 2 jobs processed", summary)
+	}
+}
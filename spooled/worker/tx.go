@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TxHandler processes a job inside a database transaction.
+type TxHandler func(ctx context.Context, tx *sql.Tx, job *JobContext) error
+
+// WithTx returns a JobHandler that runs fn in a transaction on db. The
+// transaction is committed when fn returns nil, and only then is the job
+// completed; if fn fails or panics, or the commit fails, the transaction is
+// rolled back and the job fails, to be retried while retries remain.
+//
+// The job is completed after the commit, so if completing it fails (e.g. the
+// worker loses its connection) it is delivered again with the writes already
+// committed. Make the writes idempotent, e.g. by inserting job.JobID into a
+// table with a unique constraint in the same transaction.
+//
+// WithTx gives no guarantees with AckEarly, which completes the job before
+// the transaction starts.
+func WithTx(db *sql.DB, fn TxHandler) JobHandler {
+	return WithTxOptions(db, nil, fn)
+}
+
+// WithTxOptions is WithTx with transaction options, e.g. an isolation level.
+func WithTxOptions(db *sql.DB, opts *sql.TxOptions, fn TxHandler) JobHandler {
+	return func(job *JobContext) (result map[string]any, err error) {
+		ctx := job.Context
+		tx, err := db.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("begin transaction: %w", err)
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) && err != nil {
+					err = fmt.Errorf("%w (rollback: %v)", err, rbErr)
+				}
+			}
+		}()
+
+		if err := fn(ctx, tx, job); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit transaction: %w", err)
+		}
+		committed = true
+		return nil, nil
+	}
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+)
+
+// ProcessTyped adapts a handler that takes its payload as T and returns its
+// result as R into a JobHandler. The payload is decoded with T's json tags; a
+// payload that does not decode fails the job without calling fn. A result that
+// is not a JSON object is returned as {"result": value}.
+//
+//	w.Process(worker.ProcessTyped(func(ctx *worker.JobContext, p SendEmail) (Receipt, error) {
+//		return send(ctx.Context, p)
+//	}))
+func ProcessTyped[T, R any](fn func(ctx *JobContext, payload T) (R, error)) JobHandler {
+	return func(ctx *JobContext) (map[string]any, error) {
+		in, err := payload.Decode[T](ctx.Payload)
+		if err != nil {
+			return nil, err
+		}
+		out, err := fn(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		result, err := payload.Encode(out)
+		if errors.Is(err, payload.ErrNotObject) {
+			return map[string]any{"result": out}, nil
+		}
+		return result, err
+	}
+}
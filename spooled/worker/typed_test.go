@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+)
+
+func TestProcessTyped(t *testing.T) {
+	type sendEmail struct {
+		To     string `json:"to"`
+		UserID int64  `json:"user_id"`
+	}
+	type receipt struct {
+		MessageID string `json:"message_id"`
+	}
+
+	in := sendEmail{To: "a@example.com", UserID: 1<<53 + 1}
+	p := map[string]any{"to": "a@example.com", "user_id": json.Number("9007199254740993")}
+
+	var got sendEmail
+	handler := ProcessTyped(func(ctx *JobContext, p sendEmail) (receipt, error) {
+		got = p
+		return receipt{MessageID: "m-1"}, nil
+	})
+	result, err := handler(&JobContext{Payload: payload.Map(p)})
+	if err != nil || got != in || result["message_id"] != "m-1" {
+		t.Errorf("ProcessTyped handler = %v, %v with payload %+v", result, err, got)
+	}
+
+	if _, err := handler(&JobContext{Payload: payload.Map{"user_id": "nope"}}); err == nil {
+		t.Error("ProcessTyped should fail a payload that does not decode")
+	}
+
+	count := ProcessTyped(func(ctx *JobContext, p sendEmail) (int, error) { return 3, nil })
+	result, err = count(&JobContext{Payload: payload.Map{}})
+	if err != nil || result["result"] != 3 {
+		t.Errorf("non-object result = %v, %v; want wrapped under \"result\"", result, err)
+	}
+}
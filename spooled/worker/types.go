@@ -3,7 +3,16 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/payload"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
 )
 
 // State represents the worker state.
@@ -24,13 +33,32 @@ type Options struct {
 	QueueName string
 	// Hostname is the worker hostname (default: auto-detected)
 	Hostname string
+	// WorkerID is the identity to register as. Required when the client uses
+	// a key bound to a worker identity, such as one from CreateWorkerKey.
+	WorkerID string
 	// WorkerType is an identifier for this worker type
 	WorkerType string
 	// Concurrency is the maximum concurrent jobs (1-100, default: 5)
 	Concurrency int
 	// PollInterval is the polling interval (default: 1s)
 	PollInterval time.Duration
-	// LeaseDuration is the job lease duration in seconds (5-3600, default: 30)
+	// Streaming receives jobs over a long-lived stream instead of polling,
+	// so busy workers do not pay a request per claim. It requires a
+	// StreamingBackend, such as the client's gRPC transport; otherwise, or
+	// when CanaryFraction is set, the worker polls. A dropped stream is
+	// reopened with backoff. PollInterval still paces ExitWhenIdle checks.
+	Streaming bool
+	// StreamReconnectDelay is the initial delay before reopening a dropped
+	// stream; it doubles up to MaxStreamReconnectDelay (default:
+	// DefaultStreamReconnectDelay).
+	StreamReconnectDelay time.Duration
+	// MaxStreamReconnectDelay caps the stream reconnect delay (default:
+	// DefaultMaxStreamReconnectDelay).
+	MaxStreamReconnectDelay time.Duration
+	// LeaseDuration is the job lease duration in seconds (5-3600, default: 30).
+	// The lease is renewed by heartbeats while a handler runs, but never past
+	// the job's TimeoutSeconds: at that point the handler's context is
+	// cancelled with ErrJobTimeout and renewal stops.
 	LeaseDuration int
 	// HeartbeatFraction is the heartbeat interval as a fraction of lease duration (default: 0.5)
 	HeartbeatFraction float64
@@ -44,8 +72,93 @@ type Options struct {
 	Debug bool
 	// Logger is a custom logger function
 	Logger func(msg string, args ...any)
+	// Events receives worker lifecycle events on the client-wide event bus (optional)
+	Events *sdkevents.Bus
+	// ResultValidator checks a handler's result before the job is completed (optional).
+	// A non-nil error fails the job instead of completing it.
+	ResultValidator func(result map[string]any) error
+	// EventTriggeredPolling polls immediately when a job.created event arrives for
+	// the queue instead of waiting for PollInterval. Requires Realtime.
+	// Regular polling continues as a fallback.
+	EventTriggeredPolling bool
+	// Realtime is the realtime client used for event-triggered polling.
+	// The client may be shared with other subscribers; the worker connects it if needed
+	// but never disconnects it.
+	Realtime realtime.RealtimeClient
+	// TracePropagator restores trace context injected into job tags at enqueue
+	// time into JobContext.Context (optional).
+	TracePropagator propagation.Propagator
+	// ReportProcessMetrics adds memory, CPU, goroutine, and active job counts
+	// to worker heartbeats.
+	ReportProcessMetrics bool
+	// AckMode controls when jobs are completed (default: AckOnSuccess).
+	AckMode AckMode
+	// ExitWhenIdle stops the worker once no jobs are running and every poll
+	// has come back empty for this long (default: run until Stop). Use it for
+	// cron-triggered or serverless batch workers; wait on Done or use
+	// RunUntilDrained.
+	ExitWhenIdle time.Duration
+	// SchemaRegistry validates claimed payloads that carry a schema ID before
+	// the handler runs; jobs that do not match fail with a
+	// *resources.SchemaValidationError (optional).
+	SchemaRegistry *resources.SchemaRegistry
+	// SlowJobThreshold emits EventJobSlow for jobs still running after this
+	// long (optional).
+	SlowJobThreshold time.Duration
+	// SlowJobPercentile emits EventJobSlow for jobs running longer than this
+	// percentile (0-100, e.g. 99) of their queue's recent durations, once the
+	// queue has enough history (optional). With SlowJobThreshold also set,
+	// the larger of the two applies.
+	SlowJobPercentile float64
+	// StatsWindow is the rolling window Worker.Stats reports on (default:
+	// DefaultStatsWindow).
+	StatsWindow time.Duration
+	// CanaryFraction makes this worker a canary that claims only this
+	// fraction (0-1) of the queue's jobs, selected by job ID as InCanary does,
+	// so a new handler version can be rolled out against a sample of
+	// production traffic (optional). The worker registers with "canary"
+	// metadata, and its summaries and stats are marked Canary, so canary and
+	// stable metrics can be told apart. If the server ignores the fraction
+	// and hands the worker a job outside the sample, the worker stops with
+	// ErrCanaryUnsupported instead of running it.
+	CanaryFraction float64
+	// OnStart runs before the worker registers and claims jobs, for example
+	// to warm caches or open database pools. An error aborts Start.
+	OnStart func(ctx context.Context) error
+	// OnStop runs once in-flight jobs have drained on shutdown, for example
+	// to flush buffers. Its error is returned from Stop. ctx expires after
+	// ShutdownTimeout.
+	OnStop func(ctx context.Context) error
 }
 
+// Stream reconnect backoff defaults for Options.Streaming.
+const (
+	DefaultStreamReconnectDelay    = 1 * time.Second
+	DefaultMaxStreamReconnectDelay = 30 * time.Second
+)
+
+// DefaultExitWhenIdle is the idle period RunUntilDrained uses when
+// Options.ExitWhenIdle is not set.
+const DefaultExitWhenIdle = 30 * time.Second
+
+// AckMode controls when the worker completes (acknowledges) a job.
+//
+// A job acked before its handler returns, through AckEarly or
+// JobContext.Ack, is completed without a result: the handler's result is
+// never sent to the server, so CreateOrGetResult reports the job completed
+// with an empty result, and a rejection by Options.ResultValidator (like any
+// later handler error) is only reported through events and logs.
+type AckMode string
+
+const (
+	// AckOnSuccess completes a job after its handler returns successfully and
+	// fails it otherwise. Jobs are delivered at least once.
+	AckOnSuccess AckMode = "on_success"
+	// AckEarly completes a job before its handler runs. Jobs are delivered at
+	// most once: handler errors are reported through events and logs only.
+	AckEarly AckMode = "early"
+)
+
 // DefaultOptions returns options with sensible defaults.
 func DefaultOptions() Options {
 	return Options{
@@ -67,12 +180,15 @@ type JobContext struct {
 	JobID string
 	// QueueName is the queue this job belongs to
 	QueueName string
-	// Payload is the job payload data
-	Payload map[string]any
+	// Payload is the job payload data. Use its typed accessors, such as
+	// Payload.Int64, to read numbers whether or not UseNumber is enabled.
+	Payload payload.Map
 	// RetryCount is the current retry attempt number
 	RetryCount int
 	// MaxRetries is the maximum number of retries
 	MaxRetries int
+	// Tags are the job tags, when the API returns them on claim
+	Tags map[string]any
 	// Progress reports job progress (0-100)
 	Progress func(percent float64, message string) error
 	// Log logs a message at the specified level
@@ -81,6 +197,58 @@ type JobContext struct {
 	// Internal fields
 	workerID string
 	worker   *Worker
+	job      *activeJob
+	ackOnce  sync.Once
+	ackErr   error
+	acked    atomic.Bool
+}
+
+// Ack completes the job immediately, before the handler returns. Call it
+// before non-idempotent side effects to get at-most-once delivery for this
+// job. After a successful Ack the handler's result and error are only
+// reported through events and logs. Calling Ack more than once is a no-op.
+func (c *JobContext) Ack() error {
+	c.ackOnce.Do(func() {
+		c.ackErr = c.worker.ackJob(c.JobID, nil)
+		if c.ackErr == nil {
+			c.acked.Store(true)
+		}
+	})
+	return c.ackErr
+}
+
+// Acked reports whether the job has already been completed via Ack or AckEarly.
+func (c *JobContext) Acked() bool {
+	return c.acked.Load()
+}
+
+// Deadline returns when the job's execution timeout (TimeoutSeconds, measured
+// from claim) expires; ok is false if the job has no timeout. Context is
+// cancelled with ErrJobTimeout at the deadline, so long-running handlers should
+// checkpoint or call ExtendTimeout before then.
+func (c *JobContext) Deadline() (deadline time.Time, ok bool) {
+	if c.job == nil {
+		return time.Time{}, false
+	}
+	c.job.mu.Lock()
+	defer c.job.mu.Unlock()
+	return c.job.deadline, !c.job.deadline.IsZero()
+}
+
+// ExtendTimeout adds extra to the job's execution timeout on the server and
+// moves Deadline back accordingly. It returns ErrJobTimeout if the deadline
+// has already passed.
+func (c *JobContext) ExtendTimeout(extra time.Duration) error {
+	if deadline, ok := c.Deadline(); ok && !time.Now().Before(deadline) {
+		return ErrJobTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context, 10*time.Second)
+	defer cancel()
+	if _, err := c.worker.backend.ExtendTimeout(ctx, c.JobID, extra); err != nil {
+		return err
+	}
+	return c.job.extendDeadline(extra)
 }
 
 // JobHandler is a function that processes a job.
@@ -101,6 +269,8 @@ const (
 	EventJobProgress     EventType = "job:progress"
 	EventJobHeartbeat    EventType = "job:heartbeat"
 	EventWorkerHeartbeat EventType = "worker:heartbeat"
+	EventWorkerSummary   EventType = "worker:summary"
+	EventJobSlow         EventType = "job:slow"
 )
 
 // Event is emitted by the worker during processing.
@@ -156,6 +326,14 @@ type JobFailedData struct {
 	WillRetry bool
 }
 
+// JobSlowData is emitted when a job has been running longer than its slow
+// threshold. It is emitted at most once per job, while the job is running.
+type JobSlowData struct {
+	JobID     string
+	QueueName string
+	Threshold time.Duration
+}
+
 // JobProgressData is emitted when job progress is updated.
 type JobProgressData struct {
 	JobID   string
@@ -163,5 +341,33 @@ type JobProgressData struct {
 	Message string
 }
 
+// RunSummary is an end-of-run report for a worker. Stop emits it as the final
+// EventWorkerSummary event; Worker.Summary returns it at any time.
+type RunSummary struct {
+	WorkerID  string
+	QueueName string
+	StartedAt time.Time
+	// StoppedAt is zero while the worker is running.
+	StoppedAt time.Time
+	Uptime    time.Duration
+	// JobsProcessed counts jobs whose handler returned, including failures.
+	JobsProcessed int64
+	JobsFailed    int64
+	// AvgDuration is the mean handler duration of processed jobs.
+	AvgDuration time.Duration
+	// Claims counts jobs claimed from the queue.
+	Claims     int64
+	PollErrors int64
+	// Canary is set for workers with a CanaryFraction.
+	Canary bool
+}
+
+// String returns a one-line report suitable for logs.
+func (s RunSummary) String() string {
+	return fmt.Sprintf("worker %s on %s: processed=%d failed=%d avg=%s claims=%d poll_errors=%d uptime=%s",
+		s.WorkerID, s.QueueName, s.JobsProcessed, s.JobsFailed, s.AvgDuration.Round(time.Millisecond),
+		s.Claims, s.PollErrors, s.Uptime.Round(time.Second)) + canaryLabel(s.Canary)
+}
+
 // EventHandler is a callback for worker events.
 type EventHandler func(event Event)
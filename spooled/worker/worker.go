@@ -2,13 +2,18 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/propagation"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
 	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/sdkevents"
 )
 
 // activeJob tracks an in-progress job.
@@ -18,12 +23,63 @@ type activeJob struct {
 	cancel    context.CancelFunc
 	startTime time.Time
 	heartbeat *time.Ticker
+	slowTimer *time.Timer
+
+	// deadline is when the job's execution timeout expires; timer cancels ctx
+	// with ErrJobTimeout at that point. Both are unset if the job has no timeout.
+	mu       sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// ErrJobTimeout is the cause of JobContext.Context cancellation when a job
+// exceeds its execution timeout.
+var ErrJobTimeout = errors.New("job execution timeout exceeded")
+
+// ErrCanaryUnsupported stops a canary worker that claimed jobs outside its
+// CanaryFraction sample, which means the server ignores canary claims.
+var ErrCanaryUnsupported = errors.New("server ignored CanaryFraction: claimed jobs outside the canary sample")
+
+// extendDeadline moves the job's deadline back by extra. It returns
+// ErrJobTimeout if the deadline has already passed.
+func (aj *activeJob) extendDeadline(extra time.Duration) error {
+	aj.mu.Lock()
+	defer aj.mu.Unlock()
+
+	if aj.timer == nil {
+		return nil
+	}
+	if !aj.timer.Stop() {
+		return ErrJobTimeout
+	}
+	aj.deadline = aj.deadline.Add(extra)
+	aj.timer.Reset(time.Until(aj.deadline))
+	return nil
+}
+
+// runStats accumulates the counters reported in RunSummary.
+type runStats struct {
+	startedAt     time.Time
+	stoppedAt     time.Time
+	processed     atomic.Int64
+	failed        atomic.Int64
+	totalDuration atomic.Int64 // nanoseconds
+	claims        atomic.Int64
+	pollErrors    atomic.Int64
 }
 
-// Worker processes jobs from a Spooled queue using REST polling.
+// recordJob counts a job whose handler has returned.
+func (s *runStats) recordJob(duration time.Duration, failed bool) {
+	s.processed.Add(1)
+	s.totalDuration.Add(int64(duration))
+	if failed {
+		s.failed.Add(1)
+	}
+}
+
+// Worker processes jobs from a Spooled queue by polling a Backend.
 type Worker struct {
-	jobs    *resources.JobsResource
-	workers *resources.WorkersResource
+	backend Backend
 	opts    Options
 
 	state    atomic.Value // State
@@ -32,20 +88,39 @@ type Worker struct {
 
 	activeJobs sync.Map // map[string]*activeJob
 	jobCount   atomic.Int32
+	stats      runStats
+	recent     *handlerStats
+	// idleSince is when polls started coming back empty with no jobs running
+	// (unix nanoseconds, 0 while busy). Used by ExitWhenIdle.
+	idleSince atomic.Int64
+	done      chan struct{}
+	stopErr   error
 
 	pollTicker      *time.Ticker
 	heartbeatTicker *time.Ticker
-	eventHandlers   []EventHandler
+	wake            chan struct{}
+	// stopRealtime removes the handler attachRealtime registered.
+	stopRealtime   func()
+	processMetrics *processSampler
+	eventHandlers  []EventHandler
 
 	mu       sync.RWMutex
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
 	stopOnce sync.Once
+	// fault is the error that stopped the worker, such as
+	// ErrCanaryUnsupported. It is reported by Stop and Err.
+	fault error
 }
 
 // NewWorker creates a new REST polling worker.
 func NewWorker(jobs *resources.JobsResource, workers *resources.WorkersResource, opts Options) *Worker {
+	return NewWorkerWithBackend(NewRESTBackend(jobs, workers), opts)
+}
+
+// NewWorkerWithBackend creates a new polling worker that uses the given backend.
+func NewWorkerWithBackend(backend Backend, opts Options) *Worker {
 	defaults := DefaultOptions()
 
 	if opts.Concurrency == 0 {
@@ -54,6 +129,12 @@ func NewWorker(jobs *resources.JobsResource, workers *resources.WorkersResource,
 	if opts.PollInterval == 0 {
 		opts.PollInterval = defaults.PollInterval
 	}
+	if opts.StreamReconnectDelay == 0 {
+		opts.StreamReconnectDelay = DefaultStreamReconnectDelay
+	}
+	if opts.MaxStreamReconnectDelay == 0 {
+		opts.MaxStreamReconnectDelay = DefaultMaxStreamReconnectDelay
+	}
 	if opts.LeaseDuration == 0 {
 		opts.LeaseDuration = defaults.LeaseDuration
 	}
@@ -75,9 +156,14 @@ func NewWorker(jobs *resources.JobsResource, workers *resources.WorkersResource,
 	}
 
 	w := &Worker{
-		jobs:    jobs,
-		workers: workers,
+		backend: backend,
 		opts:    opts,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		recent:  newHandlerStats(opts.StatsWindow),
+	}
+	if opts.ReportProcessMetrics {
+		w.processMetrics = newProcessSampler()
 	}
 	w.state.Store(StateIdle)
 
@@ -108,6 +194,14 @@ func (w *Worker) Start(ctx context.Context) error {
 	w.ctx, w.cancel = context.WithCancel(ctx)
 	w.mu.Unlock()
 
+	if w.opts.OnStart != nil {
+		if err := w.opts.OnStart(ctx); err != nil {
+			w.cancel()
+			w.state.Store(StateError)
+			return fmt.Errorf("worker OnStart: %w", err)
+		}
+	}
+
 	// Register worker with API
 	concurrency := w.opts.Concurrency
 	version := w.opts.Version
@@ -116,22 +210,36 @@ func (w *Worker) Start(ctx context.Context) error {
 	for k, v := range w.opts.Metadata {
 		metadata[k] = v
 	}
+	if w.opts.CanaryFraction > 0 {
+		metadata["canary"] = "true"
+		metadata["canary_fraction"] = strconv.FormatFloat(w.opts.CanaryFraction, 'f', -1, 64)
+	}
 
-	resp, err := w.workers.Register(ctx, &resources.RegisterWorkerRequest{
+	req := &resources.RegisterWorkerRequest{
 		QueueName:      w.opts.QueueName,
 		Hostname:       w.opts.Hostname,
 		MaxConcurrency: &concurrency,
 		Version:        &version,
 		WorkerType:     &workerType,
 		Metadata:       metadata,
-	})
+	}
+	if w.opts.WorkerID != "" {
+		req.WorkerID = &w.opts.WorkerID
+	}
+	resp, err := w.backend.RegisterWorker(ctx, req)
 	if err != nil {
+		w.cancel()
 		w.state.Store(StateError)
+		// Release what OnStart set up
+		if stopErr := w.runOnStop(); stopErr != nil {
+			w.log("%v", stopErr)
+		}
 		return fmt.Errorf("failed to register worker: %w", err)
 	}
 
 	w.mu.Lock()
 	w.workerID = resp.ID
+	w.stats.startedAt = time.Now()
 	w.state.Store(StateRunning)
 	w.mu.Unlock()
 
@@ -141,14 +249,30 @@ func (w *Worker) Start(ctx context.Context) error {
 		Data:      WorkerStartedData{WorkerID: w.workerID, QueueName: w.opts.QueueName},
 	})
 
-	// Start polling
+	// Wake up on push events
+	if w.opts.EventTriggeredPolling {
+		if w.opts.Realtime != nil {
+			w.attachRealtime(w.opts.Realtime)
+		} else {
+			w.log("EventTriggeredPolling enabled without a realtime client; using polling only")
+		}
+	}
+
+	// Create both tickers before starting the loops: with ExitWhenIdle the
+	// poll loop may stop the worker, which reads them
+	heartbeatInterval := time.Duration(float64(w.opts.LeaseDuration)*w.opts.HeartbeatFraction) * time.Second
 	w.pollTicker = time.NewTicker(w.opts.PollInterval)
+	w.heartbeatTicker = time.NewTicker(heartbeatInterval)
+
+	// Start claiming jobs
 	w.wg.Add(1)
-	go w.pollLoop()
+	if w.opts.Streaming {
+		go w.streamLoop()
+	} else {
+		go w.pollLoop()
+	}
 
 	// Start worker heartbeat
-	heartbeatInterval := time.Duration(float64(w.opts.LeaseDuration)*w.opts.HeartbeatFraction) * time.Second
-	w.heartbeatTicker = time.NewTicker(heartbeatInterval)
 	w.wg.Add(1)
 	go w.workerHeartbeatLoop()
 
@@ -158,14 +282,56 @@ func (w *Worker) Start(ctx context.Context) error {
 
 // Stop gracefully stops the worker.
 func (w *Worker) Stop() error {
+	return w.stop("graceful shutdown")
+}
+
+// Done returns a channel that is closed once the worker has stopped, whether
+// through Stop or ExitWhenIdle.
+func (w *Worker) Done() <-chan struct{} {
+	return w.done
+}
+
+// Err returns the error from stopping the worker, such as an OnStop failure,
+// once Done is closed.
+func (w *Worker) Err() error {
+	select {
+	case <-w.done:
+		return w.stopErr
+	default:
+		return nil
+	}
+}
+
+// RunUntilDrained starts the worker, processes jobs until the queue has stayed
+// empty for Options.ExitWhenIdle (DefaultExitWhenIdle if unset), then stops
+// and returns the run summary. Cancelling ctx stops the worker early.
+func (w *Worker) RunUntilDrained(ctx context.Context) (RunSummary, error) {
+	if w.opts.ExitWhenIdle <= 0 {
+		w.opts.ExitWhenIdle = DefaultExitWhenIdle
+	}
+	if err := w.Start(ctx); err != nil {
+		return w.Summary(), err
+	}
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		if err := w.Stop(); err != nil {
+			return w.Summary(), err
+		}
+	}
+	return w.Summary(), w.Err()
+}
+
+func (w *Worker) stop(reason string) error {
 	var err error
 	w.stopOnce.Do(func() {
-		err = w.doStop()
+		err = w.doStop(reason)
 	})
 	return err
 }
 
-func (w *Worker) doStop() error {
+func (w *Worker) doStop(reason string) error {
 	w.mu.Lock()
 	state := w.state.Load().(State)
 	if state != StateRunning {
@@ -197,6 +363,13 @@ func (w *Worker) doStop() error {
 		return true
 	})
 
+	// The realtime client may outlive the worker. Its subscription is left
+	// in place, since other workers sharing the client may rely on it.
+	if w.stopRealtime != nil {
+		w.stopRealtime()
+		w.stopRealtime = nil
+	}
+
 	// Cancel worker context
 	if w.cancel != nil {
 		w.cancel()
@@ -216,25 +389,114 @@ func (w *Worker) doStop() error {
 		w.log("Shutdown timeout reached, forcing stop")
 	}
 
+	stopErr := w.runOnStop()
+	if stopErr != nil {
+		w.log("%v", stopErr)
+	}
+
 	// Deregister worker
 	if workerID != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := w.workers.Deregister(ctx, workerID); err != nil {
+		if err := w.backend.DeregisterWorker(ctx, workerID); err != nil {
 			w.log("Failed to deregister worker: %v", err)
 		}
 	}
 
+	w.mu.Lock()
+	w.stats.stoppedAt = time.Now()
+	w.mu.Unlock()
 	w.state.Store(StateStopped)
 	w.emit(Event{
 		Type:      EventWorkerStopped,
 		Timestamp: time.Now(),
-		Data:      WorkerStoppedData{WorkerID: workerID, Reason: "graceful shutdown"},
+		Data:      WorkerStoppedData{WorkerID: workerID, Reason: reason},
+	})
+
+	summary := w.Summary()
+	w.log("Run summary: %s", summary)
+	w.emit(Event{
+		Type:      EventWorkerSummary,
+		Timestamp: time.Now(),
+		Data:      summary,
 	})
+	w.mu.RLock()
+	stopErr = errors.Join(w.fault, stopErr)
+	w.mu.RUnlock()
+	w.stopErr = stopErr
+	close(w.done)
 
+	return stopErr
+}
+
+// runOnStop runs Options.OnStop, if set, bounded by ShutdownTimeout.
+func (w *Worker) runOnStop() error {
+	if w.opts.OnStop == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.ShutdownTimeout)
+	defer cancel()
+	if err := w.opts.OnStop(ctx); err != nil {
+		return fmt.Errorf("worker OnStop: %w", err)
+	}
 	return nil
 }
 
+// Summary returns the worker's run report so far. After Stop it covers the
+// whole run, which is useful for batch deployments that log a final report.
+func (w *Worker) Summary() RunSummary {
+	w.mu.RLock()
+	summary := RunSummary{
+		WorkerID:  w.workerID,
+		QueueName: w.opts.QueueName,
+		StartedAt: w.stats.startedAt,
+		StoppedAt: w.stats.stoppedAt,
+		Canary:    w.opts.CanaryFraction > 0,
+	}
+	w.mu.RUnlock()
+
+	summary.JobsProcessed = w.stats.processed.Load()
+	summary.JobsFailed = w.stats.failed.Load()
+	summary.Claims = w.stats.claims.Load()
+	summary.PollErrors = w.stats.pollErrors.Load()
+	if summary.JobsProcessed > 0 {
+		summary.AvgDuration = time.Duration(w.stats.totalDuration.Load() / summary.JobsProcessed)
+	}
+	switch {
+	case summary.StartedAt.IsZero():
+	case summary.StoppedAt.IsZero():
+		summary.Uptime = time.Since(summary.StartedAt)
+	default:
+		summary.Uptime = summary.StoppedAt.Sub(summary.StartedAt)
+	}
+	return summary
+}
+
+// Stats returns rolling throughput, latency percentiles, and error rate over
+// Options.StatsWindow, overall and per queue, to spot degrading handlers.
+func (w *Worker) Stats() Stats {
+	stats := w.recent.snapshot(w.Summary().Uptime)
+	stats.Active = w.ActiveJobCount()
+	stats.Canary = w.opts.CanaryFraction > 0
+	return stats
+}
+
+// recordJob counts a job whose handler has returned.
+func (w *Worker) recordJob(queue string, duration time.Duration, failed bool) {
+	w.stats.recordJob(duration, failed)
+	w.recent.record(queue, duration, failed)
+}
+
+// slowThreshold returns how long a job on queue may run before it is
+// reported as slow, or 0 if slow-job detection is off.
+func (w *Worker) slowThreshold(queue string) time.Duration {
+	threshold := w.opts.SlowJobThreshold
+	if w.opts.SlowJobPercentile > 0 {
+		threshold = max(threshold, w.recent.percentile(queue, w.opts.SlowJobPercentile))
+	}
+	return threshold
+}
+
 // State returns the current worker state.
 func (w *Worker) State() State {
 	return w.state.Load().(State)
@@ -271,10 +533,48 @@ func (w *Worker) pollLoop() {
 			return
 		case <-w.pollTicker.C:
 			w.poll()
+		case <-w.wake:
+			w.poll()
 		}
 	}
 }
 
+// attachRealtime subscribes to job.created events for the worker's queue and
+// triggers an immediate poll when one arrives.
+func (w *Worker) attachRealtime(rt realtime.RealtimeClient) {
+	w.stopRealtime = rt.OnJobEvent(realtime.EventJobCreated, func(event *realtime.JobEvent) {
+		if event.QueueName != "" && event.QueueName != w.opts.QueueName {
+			return
+		}
+		if w.state.Load().(State) != StateRunning {
+			return
+		}
+		w.wakeup()
+	})
+
+	if rt.State() != realtime.StateConnected {
+		if err := rt.Connect(); err != nil {
+			w.log("Realtime connect failed, falling back to polling: %v", err)
+			return
+		}
+	}
+	if err := rt.Subscribe(realtime.SubscriptionFilter{
+		QueueName: w.opts.QueueName,
+		Events:    []string{string(realtime.EventJobCreated)},
+	}); err != nil {
+		// SSE clients are filtered at connect time; events still arrive if the filter matches
+		w.log("Realtime subscribe failed: %v", err)
+	}
+}
+
+// wakeup triggers an immediate poll without blocking.
+func (w *Worker) wakeup() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
 func (w *Worker) poll() {
 	if w.state.Load().(State) != StateRunning {
 		return
@@ -301,13 +601,20 @@ func (w *Worker) poll() {
 	limit := availableSlots
 	leaseDuration := w.opts.LeaseDuration
 
-	result, err := w.jobs.Claim(ctx, &resources.ClaimJobsRequest{
+	req := &resources.ClaimJobsRequest{
 		QueueName:        w.opts.QueueName,
 		WorkerID:         workerID,
 		Limit:            &limit,
 		LeaseDurationSec: &leaseDuration,
-	})
+	}
+	if w.opts.CanaryFraction > 0 {
+		req.CanaryFraction = &w.opts.CanaryFraction
+	}
+	result, err := w.backend.ClaimJobs(ctx, req)
 	if err != nil {
+		// A failed poll says nothing about whether the queue is empty
+		w.idleSince.Store(0)
+		w.stats.pollErrors.Add(1)
 		w.log("Poll failed: %v", err)
 		w.emit(Event{
 			Type:      EventWorkerError,
@@ -317,12 +624,71 @@ func (w *Worker) poll() {
 		return
 	}
 
+	if w.opts.ExitWhenIdle > 0 && w.checkIdle(len(result.Jobs)) {
+		return
+	}
+
 	// Process claimed jobs
+	w.stats.claims.Add(int64(len(result.Jobs)))
+	if w.opts.CanaryFraction > 0 && !w.checkCanarySample(result.Jobs) {
+		return
+	}
 	for _, job := range result.Jobs {
 		w.processJob(job)
 	}
 }
 
+// checkCanarySample reports whether every claimed job is in the canary
+// sample. Otherwise the server ignores CanaryFraction and would hand the
+// canary any share of the queue, so the worker stops with
+// ErrCanaryUnsupported without running the batch. Claimed jobs cannot be
+// handed back; they are retried once their lease expires.
+func (w *Worker) checkCanarySample(jobs []resources.ClaimedJob) bool {
+	for _, job := range jobs {
+		if InCanary(job.ID, w.opts.CanaryFraction) {
+			continue
+		}
+		w.log("Job %s is outside the canary sample; stopping worker: %v", job.ID, ErrCanaryUnsupported)
+		w.emit(Event{
+			Type:      EventWorkerError,
+			Timestamp: time.Now(),
+			Data:      WorkerErrorData{Error: ErrCanaryUnsupported},
+		})
+		w.mu.Lock()
+		w.fault = ErrCanaryUnsupported
+		w.mu.Unlock()
+		// Stop waits for the poll loop, so it cannot run on this goroutine
+		go func() { _ = w.stop("canary sample not honored") }()
+		return false
+	}
+	return true
+}
+
+// checkIdle updates the idle clock after a poll that claimed the given number
+// of jobs, and stops the worker once it has been idle for ExitWhenIdle. It
+// reports whether the worker is stopping.
+func (w *Worker) checkIdle(claimed int) bool {
+	if claimed > 0 || w.jobCount.Load() > 0 {
+		w.idleSince.Store(0)
+		return false
+	}
+
+	now := time.Now()
+	since := w.idleSince.Load()
+	if since == 0 {
+		w.idleSince.Store(now.UnixNano())
+		return false
+	}
+	if now.Sub(time.Unix(0, since)) < w.opts.ExitWhenIdle {
+		return false
+	}
+
+	w.log("Queue idle for %v, stopping worker", w.opts.ExitWhenIdle)
+	// Stop waits for the poll loop, so it cannot run on this goroutine
+	go func() { _ = w.stop("idle") }()
+	return true
+}
+
 func (w *Worker) processJob(job resources.ClaimedJob) {
 	w.jobCount.Add(1)
 
@@ -333,7 +699,11 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 	})
 
 	// Create job context
-	jobCtx, jobCancel := context.WithCancel(w.ctx)
+	jobCtx, cancelCause := context.WithCancelCause(w.ctx)
+	jobCancel := func() { cancelCause(nil) }
+	if w.opts.TracePropagator != nil {
+		jobCtx = propagation.ExtractTags(jobCtx, w.opts.TracePropagator, job.Tags)
+	}
 	aj := &activeJob{
 		jobID:     job.ID,
 		ctx:       jobCtx,
@@ -341,6 +711,25 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 		startTime: time.Now(),
 	}
 
+	// Enforce the execution timeout locally. Cancelling the context also stops
+	// lease renewal, so a timed-out job is never kept alive by heartbeats.
+	if job.TimeoutSeconds > 0 {
+		timeout := time.Duration(job.TimeoutSeconds) * time.Second
+		aj.deadline = aj.startTime.Add(timeout)
+		aj.timer = time.AfterFunc(timeout, func() { cancelCause(ErrJobTimeout) })
+	}
+
+	if threshold := w.slowThreshold(job.QueueName); threshold > 0 {
+		aj.slowTimer = time.AfterFunc(threshold, func() {
+			w.log("Job slow: id=%s threshold=%v", job.ID, threshold)
+			w.emit(Event{
+				Type:      EventJobSlow,
+				Timestamp: time.Now(),
+				Data:      JobSlowData{JobID: job.ID, QueueName: job.QueueName, Threshold: threshold},
+			})
+		})
+	}
+
 	w.activeJobs.Store(job.ID, aj)
 
 	// Start job heartbeat
@@ -355,10 +744,20 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 		defer func() {
 			w.activeJobs.Delete(job.ID)
 			w.jobCount.Add(-1)
+			if w.opts.Streaming {
+				// Ask the stream for a job to fill the freed slot
+				w.wakeup()
+			}
 			jobCancel()
 			if aj.heartbeat != nil {
 				aj.heartbeat.Stop()
 			}
+			if aj.timer != nil {
+				aj.timer.Stop()
+			}
+			if aj.slowTimer != nil {
+				aj.slowTimer.Stop()
+			}
 		}()
 
 		w.emit(Event{
@@ -375,8 +774,10 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 			Payload:    job.Payload,
 			RetryCount: job.RetryCount,
 			MaxRetries: job.MaxRetries,
+			Tags:       job.Tags,
 			workerID:   w.workerID,
 			worker:     w,
+			job:        aj,
 			Progress: func(percent float64, message string) error {
 				return w.updateProgress(job.ID, percent, message)
 			},
@@ -385,13 +786,55 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 			},
 		}
 
+		// Reject payloads from incompatible producers before the handler sees them
+		if w.opts.SchemaRegistry != nil {
+			if err := w.opts.SchemaRegistry.Validate(jobCtx, job.Payload); err != nil {
+				var verr *resources.SchemaValidationError
+				if errors.As(err, &verr) {
+					verr.JobID = job.ID
+				}
+				w.recordJob(job.QueueName, time.Since(aj.startTime), true)
+				w.failJob(job.ID, err, time.Since(aj.startTime))
+				return
+			}
+		}
+
+		// At-most-once: commit the job before any side effects
+		if w.opts.AckMode == AckEarly {
+			if err := jctx.Ack(); err != nil {
+				// Leave the job to lease expiry rather than run it unacknowledged
+				w.log("Failed to ack job %s early, skipping: %v", job.ID, err)
+				w.emit(Event{
+					Type:      EventWorkerError,
+					Timestamp: time.Now(),
+					Data:      WorkerErrorData{Error: fmt.Errorf("ack job %s: %w", job.ID, err)},
+				})
+				return
+			}
+		}
+
 		// Call handler
 		w.mu.RLock()
 		handler := w.handler
 		w.mu.RUnlock()
 
 		result, err := handler(jctx)
+		if err != nil && errors.Is(context.Cause(jobCtx), ErrJobTimeout) && !errors.Is(err, ErrJobTimeout) {
+			err = fmt.Errorf("%w: %w", ErrJobTimeout, err)
+		}
+		if err == nil && w.opts.ResultValidator != nil {
+			if verr := w.opts.ResultValidator(result); verr != nil {
+				err = fmt.Errorf("invalid job result: %w", verr)
+			}
+		}
 		duration := time.Since(aj.startTime)
+		w.recordJob(job.QueueName, duration, err != nil)
+
+		if jctx.Acked() {
+			// Already completed; only report the outcome
+			w.reportAckedJob(job.ID, result, err, duration)
+			return
+		}
 
 		if err != nil {
 			// Job failed
@@ -403,7 +846,8 @@ func (w *Worker) processJob(job resources.ClaimedJob) {
 	}()
 }
 
-func (w *Worker) completeJob(jobID string, result map[string]any, duration time.Duration) {
+// ackJob completes a job on the server.
+func (w *Worker) ackJob(jobID string, result map[string]any) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -411,10 +855,14 @@ func (w *Worker) completeJob(jobID string, result map[string]any, duration time.
 	workerID := w.workerID
 	w.mu.RUnlock()
 
-	if err := w.jobs.Complete(ctx, jobID, &resources.CompleteJobRequest{
+	return w.backend.CompleteJob(ctx, jobID, &resources.CompleteJobRequest{
 		WorkerID: workerID,
 		Result:   result,
-	}); err != nil {
+	})
+}
+
+func (w *Worker) completeJob(jobID string, result map[string]any, duration time.Duration) {
+	if err := w.ackJob(jobID, result); err != nil {
 		w.log("Failed to complete job %s: %v", jobID, err)
 	}
 
@@ -431,6 +879,35 @@ func (w *Worker) completeJob(jobID string, result map[string]any, duration time.
 	w.log("Job completed: id=%s duration=%v", jobID, duration)
 }
 
+// reportAckedJob emits the outcome of a job that was acknowledged before its
+// handler returned. The server already considers it completed.
+func (w *Worker) reportAckedJob(jobID string, result map[string]any, jobErr error, duration time.Duration) {
+	if jobErr != nil {
+		w.emit(Event{
+			Type:      EventJobFailed,
+			Timestamp: time.Now(),
+			Data: JobFailedData{
+				JobID:    jobID,
+				Error:    jobErr,
+				Duration: duration,
+			},
+		})
+		w.log("Acked job failed, not retried: id=%s error=%v duration=%v", jobID, jobErr, duration)
+		return
+	}
+
+	w.emit(Event{
+		Type:      EventJobCompleted,
+		Timestamp: time.Now(),
+		Data: JobCompletedData{
+			JobID:    jobID,
+			Result:   result,
+			Duration: duration,
+		},
+	})
+	w.log("Job completed: id=%s duration=%v", jobID, duration)
+}
+
 func (w *Worker) failJob(jobID string, jobErr error, duration time.Duration) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -439,7 +916,7 @@ func (w *Worker) failJob(jobID string, jobErr error, duration time.Duration) {
 	workerID := w.workerID
 	w.mu.RUnlock()
 
-	if err := w.jobs.Fail(ctx, jobID, &resources.FailJobRequest{
+	if err := w.backend.FailJob(ctx, jobID, &resources.FailJobRequest{
 		WorkerID: workerID,
 		Error:    jobErr.Error(),
 	}); err != nil {
@@ -463,7 +940,7 @@ func (w *Worker) updateProgress(jobID string, percent float64, message string) e
 	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
 	defer cancel()
 
-	if err := w.jobs.UpdateProgress(ctx, jobID, &resources.UpdateProgressRequest{
+	if err := w.backend.UpdateProgress(ctx, jobID, &resources.UpdateProgressRequest{
 		Progress: percent,
 		Message:  message,
 	}); err != nil {
@@ -502,7 +979,7 @@ func (w *Worker) renewJobLease(jobID string) {
 	workerID := w.workerID
 	w.mu.RUnlock()
 
-	if _, err := w.jobs.RenewLease(ctx, jobID, &resources.RenewLeaseRequest{
+	if _, err := w.backend.RenewLease(ctx, jobID, &resources.RenewLeaseRequest{
 		WorkerID:         workerID,
 		LeaseDurationSec: w.opts.LeaseDuration,
 	}); err != nil {
@@ -547,10 +1024,14 @@ func (w *Worker) sendWorkerHeartbeat() {
 	}
 
 	currentJobs := int(w.jobCount.Load())
-	if err := w.workers.Heartbeat(ctx, workerID, &resources.WorkerHeartbeatRequest{
+	req := &resources.WorkerHeartbeatRequest{
 		CurrentJobs: currentJobs,
 		Status:      &status,
-	}); err != nil {
+	}
+	if w.processMetrics != nil {
+		req.Metrics = w.processMetrics.sample(map[string]int{w.opts.QueueName: currentJobs})
+	}
+	if err := w.backend.WorkerHeartbeat(ctx, workerID, req); err != nil {
 		w.log("Failed to send worker heartbeat: %v", err)
 	} else {
 		w.emit(Event{
@@ -562,6 +1043,8 @@ func (w *Worker) sendWorkerHeartbeat() {
 }
 
 func (w *Worker) emit(event Event) {
+	w.publishLifecycle(event)
+
 	w.mu.RLock()
 	handlers := make([]EventHandler, len(w.eventHandlers))
 	copy(handlers, w.eventHandlers)
@@ -579,6 +1062,36 @@ func (w *Worker) emit(event Event) {
 	}
 }
 
+// publishLifecycle forwards worker lifecycle events to the client-wide event bus.
+func (w *Worker) publishLifecycle(event Event) {
+	if w.opts.Events == nil {
+		return
+	}
+	switch data := event.Data.(type) {
+	case WorkerStartedData:
+		w.opts.Events.Emit(sdkevents.TypeWorkerStarted, sdkevents.WorkerLifecycleData{
+			WorkerID:  data.WorkerID,
+			QueueName: data.QueueName,
+		})
+	case WorkerStoppedData:
+		w.opts.Events.Emit(sdkevents.TypeWorkerStopped, sdkevents.WorkerLifecycleData{
+			WorkerID:  data.WorkerID,
+			QueueName: w.opts.QueueName,
+			Reason:    data.Reason,
+		})
+	case RunSummary:
+		w.opts.Events.Emit(sdkevents.TypeWorkerSummary, data)
+	case JobSlowData:
+		w.opts.Events.Emit(sdkevents.TypeWorkerJobSlow, data)
+	case WorkerErrorData:
+		w.opts.Events.Emit(sdkevents.TypeWorkerError, sdkevents.WorkerLifecycleData{
+			WorkerID:  w.WorkerID(),
+			QueueName: w.opts.QueueName,
+			Error:     data.Error,
+		})
+	}
+}
+
 func (w *Worker) log(format string, args ...any) {
 	if w.opts.Logger != nil {
 		w.opts.Logger(format, args...)
@@ -0,0 +1,358 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/realtime"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// txLog records what the fake driver's transactions did.
+type txLog struct {
+	mu        sync.Mutex
+	events    []string
+	commitErr error
+}
+
+func (l *txLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *txLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.events, ",")
+}
+
+// txConnector opens fake connections that only support transactions.
+type txConnector struct{ log *txLog }
+
+func (c txConnector) Connect(context.Context) (driver.Conn, error) { return txConn(c), nil }
+func (c txConnector) Driver() driver.Driver                        { return nil }
+
+type txConn struct{ log *txLog }
+
+func (c txConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c txConn) Close() error                        { return nil }
+func (c txConn) Begin() (driver.Tx, error) {
+	c.log.add("begin")
+	return fakeTx(c), nil
+}
+
+type fakeTx struct{ log *txLog }
+
+func (t fakeTx) Commit() error {
+	t.log.add("commit")
+	return t.log.commitErr
+}
+
+func (t fakeTx) Rollback() error {
+	t.log.add("rollback")
+	return nil
+}
+
+func TestWithTx(t *testing.T) {
+	errHandler := errors.New("insert failed")
+	errCommit := errors.New("serialization failure")
+	tests := []struct {
+		name      string
+		fn        TxHandler
+		commitErr error
+		wantErr   error
+		wantLog   string
+	}{
+		{
+			name:    "commit",
+			fn:      func(ctx context.Context, tx *sql.Tx, job *JobContext) error { return nil },
+			wantLog: "begin,commit",
+		},
+		{
+			name:    "handler error",
+			fn:      func(ctx context.Context, tx *sql.Tx, job *JobContext) error { return errHandler },
+			wantErr: errHandler,
+			wantLog: "begin,rollback",
+		},
+		{
+			name:      "commit error",
+			fn:        func(ctx context.Context, tx *sql.Tx, job *JobContext) error { return nil },
+			commitErr: errCommit,
+			wantErr:   errCommit,
+			wantLog:   "begin,commit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &txLog{commitErr: tt.commitErr}
+			db := sql.OpenDB(txConnector{log})
+			defer db.Close()
+
+			var gotJob string
+			handler := WithTx(db, func(ctx context.Context, tx *sql.Tx, job *JobContext) error {
+				gotJob = job.JobID
+				return tt.fn(ctx, tx, job)
+			})
+			_, err := handler(&JobContext{Context: context.Background(), JobID: "job-1"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("handler error = %v, want %v", err, tt.wantErr)
+			}
+			if gotJob != "job-1" {
+				t.Errorf("fn got job %q, want job-1", gotJob)
+			}
+			if got := log.String(); got != tt.wantLog {
+				t.Errorf("transaction = %s, want %s", got, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestWithTx_PanicRollsBack(t *testing.T) {
+	log := &txLog{}
+	db := sql.OpenDB(txConnector{log})
+	defer db.Close()
+
+	handler := WithTx(db, func(ctx context.Context, tx *sql.Tx, job *JobContext) error {
+		panic("boom")
+	})
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the handler's panic", r)
+			}
+		}()
+		_, _ = handler(&JobContext{Context: context.Background(), JobID: "job-1"})
+	}()
+	if got := log.String(); got != "begin,rollback" {
+		t.Errorf("transaction = %s, want begin,rollback", got)
+	}
+}
+
+func TestWorker_StopRemovesRealtimeHandler(t *testing.T) {
+	rt := &fakeRealtime{}
+	opts := Options{EventTriggeredPolling: true, Realtime: rt}
+	for i := range 2 {
+		w := startWorker(t, newFakeBackend(), opts, func(ctx *JobContext) (map[string]any, error) {
+			return nil, nil
+		})
+		if got := rt.handlerCount(); got != 1 {
+			t.Fatalf("worker %d: %d realtime handlers while running, want 1", i, got)
+		}
+		if err := w.Stop(); err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+		if got := rt.handlerCount(); got != 0 {
+			t.Errorf("worker %d: %d realtime handlers after Stop, want 0", i, got)
+		}
+	}
+}
+
+func TestWorker_EventTriggeredPolling(t *testing.T) {
+	rt := &fakeRealtime{}
+	backend := newFakeBackend()
+	processed := make(chan string, 1)
+	opts := Options{PollInterval: time.Hour, EventTriggeredPolling: true, Realtime: rt}
+	startWorker(t, backend, opts, func(ctx *JobContext) (map[string]any, error) {
+		processed <- ctx.JobID
+		return nil, nil
+	})
+	waitFor(t, "the first poll", func() bool { return backend.claimCount() == 1 })
+	if filters := rt.subscriptions(); len(filters) != 1 || filters[0].QueueName != "emails" {
+		t.Errorf("subscriptions = %+v, want the emails queue", filters)
+	}
+
+	// Jobs created in other queues do not trigger a poll
+	backend.push(resources.ClaimedJob{ID: "job-1", QueueName: "emails"})
+	rt.emit(realtime.EventJobCreated, &realtime.JobEvent{JobID: "job-2", QueueName: "reports"})
+	time.Sleep(50 * time.Millisecond)
+	if got := backend.claimCount(); got != 1 {
+		t.Errorf("claims = %d after an event for another queue, want 1", got)
+	}
+
+	// A job created in the queue is claimed long before PollInterval
+	rt.emit(realtime.EventJobCreated, &realtime.JobEvent{JobID: "job-1", QueueName: "emails"})
+	select {
+	case id := <-processed:
+		if id != "job-1" {
+			t.Errorf("processed %s, want job-1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job-1 not processed after its job.created event")
+	}
+}
+
+// collectEvents returns a channel that receives the worker's events of the
+// given types.
+func collectEvents(w *Worker, types ...EventType) chan Event {
+	events := make(chan Event, 100)
+	w.OnEvent(func(event Event) {
+		for _, t := range types {
+			if event.Type == t {
+				events <- event
+			}
+		}
+	})
+	return events
+}
+
+// nextEvent returns the next event from events, failing the test if none
+// arrives within a few seconds.
+func nextEvent(t *testing.T, events chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a worker event")
+		return Event{}
+	}
+}
+
+func TestWorker_ErrorAfterAck(t *testing.T) {
+	errHandler := errors.New("send failed")
+	tests := []struct {
+		name    string
+		ackMode AckMode
+		ack     bool
+	}{
+		{"explicit ack", AckOnSuccess, true},
+		{"ack early", AckEarly, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			opts := Options{PollInterval: 10 * time.Millisecond, AckMode: tt.ackMode}
+			w := startWorker(t, backend, opts, func(ctx *JobContext) (map[string]any, error) {
+				if tt.ack {
+					if err := ctx.Ack(); err != nil {
+						return nil, err
+					}
+				}
+				return map[string]any{"sent": false}, errHandler
+			})
+			events := collectEvents(w, EventJobCompleted, EventJobFailed)
+			backend.push(resources.ClaimedJob{ID: "job-1", QueueName: "emails"})
+
+			// The failure is reported locally, but the job stays completed
+			event := nextEvent(t, events)
+			if data, ok := event.Data.(JobFailedData); !ok || !errors.Is(data.Error, errHandler) {
+				t.Fatalf("event = %+v, want job:failed with the handler error", event)
+			}
+			completed, failed := backend.results()
+			if req := completed["job-1"]; req == nil || req.Result != nil {
+				t.Errorf("completed = %v, want job-1 acked without a result", completed)
+			}
+			if len(failed) != 0 {
+				t.Errorf("FailJob called for %v after the ack", failed)
+			}
+		})
+	}
+}
+
+func TestWorker_AckEarlyFailureSkipsHandler(t *testing.T) {
+	errAck := errors.New("connection reset")
+	backend := newFakeBackend()
+	backend.completeErr = errAck
+	called := make(chan string, 1)
+	opts := Options{PollInterval: 10 * time.Millisecond, AckMode: AckEarly}
+	w := startWorker(t, backend, opts, func(ctx *JobContext) (map[string]any, error) {
+		called <- ctx.JobID
+		return nil, nil
+	})
+	events := collectEvents(w, EventWorkerError, EventJobCompleted, EventJobFailed)
+	backend.push(resources.ClaimedJob{ID: "job-1", QueueName: "emails"})
+
+	// The job is left to lease expiry instead of running unacknowledged
+	event := nextEvent(t, events)
+	if data, ok := event.Data.(WorkerErrorData); !ok || !errors.Is(data.Error, errAck) {
+		t.Fatalf("event = %+v, want worker:error with the ack error", event)
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case id := <-called:
+		t.Errorf("handler called for %s after the early ack failed", id)
+	default:
+	}
+	if _, failed := backend.results(); len(failed) != 0 {
+		t.Errorf("FailJob called for %v", failed)
+	}
+	if len(events) != 0 {
+		t.Errorf("unexpected event %+v", <-events)
+	}
+}
+
+func TestWorker_ResultValidator(t *testing.T) {
+	backend := newFakeBackend()
+	opts := Options{
+		PollInterval: 10 * time.Millisecond,
+		ResultValidator: func(result map[string]any) error {
+			if _, ok := result["message_id"]; !ok {
+				return errors.New("missing message_id")
+			}
+			return nil
+		},
+	}
+	w := startWorker(t, backend, opts, func(ctx *JobContext) (map[string]any, error) {
+		if ctx.JobID == "job-1" {
+			return map[string]any{"message_id": "m-1"}, nil
+		}
+		return map[string]any{"status": "sent"}, nil
+	})
+	events := collectEvents(w, EventJobCompleted, EventJobFailed)
+	backend.push(resources.ClaimedJob{ID: "job-1", QueueName: "emails"})
+	backend.push(resources.ClaimedJob{ID: "job-2", QueueName: "emails"})
+	nextEvent(t, events)
+	nextEvent(t, events)
+
+	completed, failed := backend.results()
+	if req := completed["job-1"]; req == nil || req.Result["message_id"] != "m-1" {
+		t.Errorf("completed = %v, want job-1 with its result", completed)
+	}
+	if req := failed["job-2"]; req == nil || req.Error != "invalid job result: missing message_id" {
+		t.Errorf("failed = %+v, want job-2 failed with the validator's message", failed["job-2"])
+	}
+	if _, ok := completed["job-2"]; ok {
+		t.Error("job-2 completed despite its rejected result")
+	}
+}
+
+func TestWorker_StopSummary(t *testing.T) {
+	backend := newFakeBackend()
+	w := startWorker(t, backend, Options{PollInterval: 10 * time.Millisecond}, func(ctx *JobContext) (map[string]any, error) {
+		if ctx.JobID == "job-2" {
+			return nil, errors.New("bounced")
+		}
+		return nil, nil
+	})
+	events := collectEvents(w, EventJobCompleted, EventJobFailed)
+	summaries := collectEvents(w, EventWorkerSummary)
+	for _, id := range []string{"job-1", "job-2", "job-3"} {
+		backend.push(resources.ClaimedJob{ID: id, QueueName: "emails"})
+	}
+	for range 3 {
+		nextEvent(t, events)
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	summary := w.Summary()
+	if summary.WorkerID != "worker-1" || summary.QueueName != "emails" ||
+		summary.JobsProcessed != 3 || summary.JobsFailed != 1 || summary.Claims != 3 {
+		t.Errorf("Summary() = %+v, want 3 processed, 1 failed, 3 claims", summary)
+	}
+	if summary.StoppedAt.IsZero() || summary.Uptime != summary.StoppedAt.Sub(summary.StartedAt) {
+		t.Errorf("Summary() = %+v, want the uptime of a stopped run", summary)
+	}
+	event := nextEvent(t, summaries)
+	if emitted, ok := event.Data.(RunSummary); !ok || emitted != summary {
+		t.Errorf("worker:summary event = %+v, want %+v", event.Data, summary)
+	}
+}
@@ -0,0 +1,129 @@
+package spooled
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/internal/httpx"
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+// WorkerConfigError lists every problem SpooledWorker.Validate found.
+type WorkerConfigError struct {
+	QueueName string
+	Problems  []string
+}
+
+func (e *WorkerConfigError) Error() string {
+	return fmt.Sprintf("worker for queue %q is misconfigured: %s", e.QueueName, strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the worker's configuration against the API before it
+// starts, so mistakes surface together at startup instead of one at a time at
+// the first claim. It checks that:
+//
+//   - exactly one handler is registered and the options are in range
+//   - the credentials are accepted, have the worker scopes, and may access
+//     the queue (and, for worker keys, match WorkerID)
+//   - the queue exists or is declared with WithQueueConfig
+//   - Concurrency does not exceed the plan's active job limit and the plan
+//     has room for another worker
+//   - the lease outlasts ExpectedJobDuration
+//
+// It returns a *WorkerConfigError listing every problem found, or nil. Plan
+// limits are skipped for keys without organization access.
+func (w *SpooledWorker) Validate(ctx context.Context) error {
+	opts := w.opts
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case w.handlers == 0:
+		problem("no handler registered; call Process before Start")
+	case w.handlers > 1:
+		problem("Process called %d times; only the last handler is used", w.handlers)
+	}
+	if opts.QueueName == "" {
+		problem("QueueName is required")
+	}
+	if opts.Concurrency < 0 || opts.PollInterval < 0 || opts.LeaseDuration < 0 {
+		problem("Concurrency, PollInterval, and LeaseDuration must not be negative")
+	}
+	if opts.CanaryFraction < 0 || opts.CanaryFraction > 1 {
+		problem("CanaryFraction %v must be between 0 and 1", opts.CanaryFraction)
+	}
+	if opts.Streaming {
+		switch {
+		case w.client.cfg.PreferredTransport == "" || w.client.cfg.PreferredTransport == TransportREST:
+			problem("Streaming requires the gRPC transport; the worker will poll over REST")
+		case opts.CanaryFraction > 0:
+			problem("Streaming cannot be combined with CanaryFraction; the worker will poll")
+		}
+	}
+	lease := time.Duration(opts.LeaseDuration) * time.Second
+	if opts.LeaseDuration == 0 {
+		lease = 30 * time.Second
+	}
+	if opts.ExpectedJobDuration > lease {
+		problem("LeaseDuration %s is shorter than ExpectedJobDuration %s; jobs may be reclaimed while still running", lease, opts.ExpectedJobDuration)
+	}
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = 5
+	}
+
+	me, err := w.client.Auth().Me(ctx)
+	if err != nil {
+		problem("credentials check failed: %v", err)
+		return &WorkerConfigError{QueueName: opts.QueueName, Problems: problems}
+	}
+	queue := w.client.queueName(opts.QueueName)
+
+	if len(me.Scopes) > 0 {
+		var missing []string
+		for _, scope := range resources.WorkerScopes {
+			if !slices.Contains(me.Scopes, scope) {
+				missing = append(missing, string(scope))
+			}
+		}
+		if len(missing) > 0 {
+			problem("API key lacks worker scopes %s", strings.Join(missing, ", "))
+		}
+	}
+	if me.WorkerID != "" && me.WorkerID != opts.WorkerID {
+		problem("API key is bound to worker %q but WorkerID is %q", me.WorkerID, opts.WorkerID)
+	}
+	if len(me.Queues) > 0 && opts.QueueName != "" && !slices.Contains(me.Queues, queue) {
+		problem("API key is restricted to queues %s", strings.Join(me.Queues, ", "))
+	}
+
+	// Scoped keys cannot read queue configuration
+	if len(me.Scopes) == 0 && opts.QueueName != "" {
+		if _, err := w.client.Queues().Get(ctx, opts.QueueName); httpx.IsNotFoundError(err) {
+			if _, declared := w.client.cfg.QueueConfigs[opts.QueueName]; !declared {
+				problem("queue %q does not exist; check the name or declare it with WithQueueConfig", queue)
+			}
+		} else if err != nil {
+			problem("cannot read queue %q: %v", queue, err)
+		}
+	}
+
+	if usage, err := w.client.Organizations().Usage(ctx, me.OrganizationID); err == nil {
+		if limit := usage.Limits.MaxActiveJobs; limit != nil && concurrency > *limit {
+			problem("Concurrency %d exceeds the plan's %d active jobs", concurrency, *limit)
+		}
+		if workers := usage.Usage.Workers; workers.Limit != nil && workers.Current >= *workers.Limit {
+			problem("plan worker limit reached (%d/%d)", workers.Current, *workers.Limit)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &WorkerConfigError{QueueName: opts.QueueName, Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package spooled
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spooled-cloud/spooled-sdk-go/spooled/resources"
+)
+
+func TestSpooledWorker_Validate(t *testing.T) {
+	me := `{"organization_id":"org-1","api_key_id":"key-1","queues":[]}`
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/auth/me":
+			_, _ = w.Write([]byte(me))
+		case "/api/v1/queues/emails":
+			_, _ = w.Write([]byte(`{"queue_name":"emails","enabled":true}`))
+		case "/api/v1/organizations/org-1/usage":
+			_, _ = w.Write([]byte(`{"plan":"free","limits":{"max_active_jobs":4},"usage":{"workers":{"current":1,"limit":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not_found","message":"not found"}`))
+		}
+	})
+
+	handler := func(context.Context, *resources.Job) (any, error) { return nil, nil }
+
+	w := NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "emails", Concurrency: 4})
+	w.Process(handler)
+	if err := w.Validate(context.Background()); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	me = `{"organization_id":"org-1","api_key_id":"key-1","queues":["emails"],"worker_id":"emails-1","scopes":["jobs:claim"]}`
+	w = NewSpooledWorker(client, SpooledWorkerOptions{
+		QueueName:           "emials",
+		Concurrency:         10,
+		ExpectedJobDuration: time.Minute,
+		ValidateOnStart:     true,
+	})
+	w.Process(handler)
+	w.Process(handler)
+	err := w.Start()
+	var configErr *WorkerConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Start() error = %v, want *WorkerConfigError", err)
+	}
+	want := []string{"Process called 2 times", "ExpectedJobDuration", "lacks worker scopes", "bound to worker", "restricted to queues", "Concurrency 10 exceeds"}
+	if len(configErr.Problems) != len(want) {
+		t.Fatalf("Problems = %q, want %d", configErr.Problems, len(want))
+	}
+	for i, substr := range want {
+		if !strings.Contains(configErr.Problems[i], substr) {
+			t.Errorf("Problems[%d] = %q, want it to mention %q", i, configErr.Problems[i], substr)
+		}
+	}
+
+	me = `{"organization_id":"org-1","api_key_id":"key-1","queues":[]}`
+	w = NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "emials", Concurrency: 4})
+	if err := w.Validate(context.Background()); !errors.As(err, &configErr) || len(configErr.Problems) != 2 ||
+		!strings.Contains(configErr.Problems[1], "does not exist") {
+		t.Errorf("Validate() error = %v, want missing handler and missing queue", err)
+	}
+
+	w = NewSpooledWorker(client, SpooledWorkerOptions{QueueName: "emails", Streaming: true})
+	w.Process(handler)
+	if err := w.Validate(context.Background()); err == nil || !strings.Contains(err.Error(), "gRPC transport") {
+		t.Errorf("Validate() error = %v, want a gRPC transport problem", err)
+	}
+}